@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/autonomous-dev/cli/internal/cli"
 	"github.com/autonomous-dev/cli/pkg/version"
@@ -29,15 +31,55 @@ Example usage:
 }
 
 func main() {
+	// Add global flags
+	cli.RegisterGlobalFlags(rootCmd)
+
 	// Add commands
 	rootCmd.AddCommand(cli.InitCmd())
+	rootCmd.AddCommand(cli.BootstrapCmd())
 	rootCmd.AddCommand(cli.StartCmd())
 	rootCmd.AddCommand(cli.StatusCmd())
+	rootCmd.AddCommand(cli.LogsCmd())
+	rootCmd.AddCommand(cli.ReportCmd())
 	rootCmd.AddCommand(cli.DashboardCmd())
+	rootCmd.AddCommand(cli.BadgeCmd())
 	rootCmd.AddCommand(cli.ConfigCmd())
+	rootCmd.AddCommand(cli.DoctorCmd())
+	rootCmd.AddCommand(cli.TemplateCmd())
+	rootCmd.AddCommand(cli.ServeCmd())
+	rootCmd.AddCommand(cli.QueueCmd())
+	rootCmd.AddCommand(cli.HistoryCmd())
+	rootCmd.AddCommand(cli.RunnersCmd())
+	rootCmd.AddCommand(cli.TelemetryCmd())
+	rootCmd.AddCommand(cli.ContextCmd())
+	rootCmd.AddCommand(cli.IssuesCmd())
+	rootCmd.AddCommand(cli.BranchesCmd())
+	rootCmd.AddCommand(cli.PRsCmd())
+	rootCmd.AddCommand(cli.EstimateCmd())
+	rootCmd.AddCommand(cli.OutboxCmd())
+	rootCmd.AddCommand(cli.PauseCmd())
+	rootCmd.AddCommand(cli.ResumeCmd())
+	rootCmd.AddCommand(cli.ReplanCmd())
+	rootCmd.AddCommand(cli.TraceCmd())
+	rootCmd.AddCommand(cli.ScheduleCmd())
+	rootCmd.AddCommand(cli.AttachCmd())
+	rootCmd.AddCommand(cli.PermissionsCmd())
+	rootCmd.AddCommand(cli.PluginsCmd())
+
+	// Plugins come last so built-in commands always win
+	cli.AddPlugins(rootCmd)
 
 	// Execute
-	if err := rootCmd.Execute(); err != nil {
+	started := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	cli.RecordTelemetry(cmd, time.Since(started), err)
+	cli.PrintSummary(cmd, err)
+	var pluginExit *cli.PluginExitError
+	if errors.As(err, &pluginExit) {
+		// The plugin reported its own failure
+		os.Exit(pluginExit.Code)
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
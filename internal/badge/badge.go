@@ -0,0 +1,157 @@
+package badge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/github"
+)
+
+// Window is the period the success rate covers
+const Window = 30 * 24 * time.Hour
+
+// Dir is the directory of the endpoint files in a published site
+const Dir = "badges"
+
+// Badge names, also the names of their endpoint files
+const (
+	Status      = "status"
+	SuccessRate = "success-rate"
+	Instances   = "instances"
+)
+
+// Names lists the badges in the order of the README snippet
+var Names = []string{Status, SuccessRate, Instances}
+
+// cacheSeconds is how long shields.io may cache an endpoint
+const cacheSeconds = 300
+
+// Stats summarizes recent runs for the badges
+type Stats struct {
+	// Latest is the conclusion of the newest run, or its status while it
+	// runs; empty without runs
+	Latest string
+	// Succeeded and Finished count completed runs created within Window
+	Succeeded int
+	Finished  int
+	// Active is the number of instances in progress
+	Active int
+}
+
+// Compute summarizes runs, newest first, with active instances in progress
+func Compute(runs []github.WorkflowRun, active int, now time.Time) Stats {
+	s := Stats{Active: active}
+	if len(runs) > 0 {
+		s.Latest = runs[0].Conclusion
+		if runs[0].Status != "completed" {
+			s.Latest = runs[0].Status
+		}
+	}
+	for _, run := range runs {
+		if run.Status != "completed" || now.Sub(run.CreatedAt) > Window {
+			continue
+		}
+		// Cancelled and skipped runs neither succeeded nor failed
+		switch run.Conclusion {
+		case "success":
+			s.Succeeded++
+			s.Finished++
+		case "failure", "timed_out", "startup_failure":
+			s.Finished++
+		}
+	}
+	return s
+}
+
+// Rate returns the percentage of finished runs that succeeded; ok is false
+// when none finished within Window
+func (s Stats) Rate() (rate float64, ok bool) {
+	if s.Finished == 0 {
+		return 0, false
+	}
+	return float64(s.Succeeded) / float64(s.Finished) * 100, true
+}
+
+// Endpoint is the JSON a shields.io endpoint badge is rendered from, see
+// https://shields.io/badges/endpoint-badge
+type Endpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	CacheSeconds  int    `json:"cacheSeconds,omitempty"`
+}
+
+// Endpoints renders every badge of the stats, keyed by name
+func Endpoints(s Stats) map[string]Endpoint {
+	status := Endpoint{Label: "autonomous run", Message: s.Latest, Color: "lightgrey"}
+	switch s.Latest {
+	case "":
+		status.Message = "no runs"
+	case "success":
+		status.Message, status.Color = "passing", "brightgreen"
+	case "failure", "timed_out", "startup_failure":
+		status.Message, status.Color = "failing", "red"
+	case "in_progress", "queued", "waiting", "pending":
+		status.Message, status.Color = "running", "blue"
+	}
+
+	rate := Endpoint{Label: "success (30d)", Message: "n/a", Color: "lightgrey"}
+	if r, ok := s.Rate(); ok {
+		rate.Message = fmt.Sprintf("%.0f%%", r)
+		switch {
+		case r >= 90:
+			rate.Color = "brightgreen"
+		case r >= 75:
+			rate.Color = "green"
+		case r >= 50:
+			rate.Color = "yellow"
+		default:
+			rate.Color = "red"
+		}
+	}
+
+	instances := Endpoint{Label: "instances", Message: fmt.Sprintf("%d active", s.Active), Color: "lightgrey"}
+	if s.Active > 0 {
+		instances.Color = "blue"
+	}
+
+	endpoints := map[string]Endpoint{Status: status, SuccessRate: rate, Instances: instances}
+	for name, e := range endpoints {
+		e.SchemaVersion = 1
+		e.CacheSeconds = cacheSeconds
+		endpoints[name] = e
+	}
+	return endpoints
+}
+
+// Files renders the endpoint files of the stats, keyed by path within a site
+func Files(s Stats) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for name, e := range Endpoints(s) {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s badge: %w", name, err)
+		}
+		files[Dir+"/"+name+".json"] = data
+	}
+	return files, nil
+}
+
+// Markdown returns a README snippet showing every badge. baseURL is where
+// the site with the endpoint files is served; the badges link to link.
+func Markdown(baseURL, link string) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	var b strings.Builder
+	for i, name := range Names {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		endpoint := baseURL + "/" + Dir + "/" + name + ".json"
+		fmt.Fprintf(&b, "[![%s](https://img.shields.io/endpoint?url=%s)](%s)", name, url.QueryEscape(endpoint), link)
+	}
+	return b.String()
+}
@@ -0,0 +1,319 @@
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultMaxFileBytes truncates files larger than this in the bundle
+const DefaultMaxFileBytes = 32768
+
+// Entry is the cached state of one source file
+type Entry struct {
+	Hash    string    `json:"hash"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	// Section is the rendered bundle section, reused while the hash is unchanged
+	Section string `json:"section"`
+}
+
+// Manifest records source files of the last build and their hashes at the
+// last run, so only changed sections are regenerated
+type Manifest struct {
+	BuiltAt time.Time        `json:"built_at"`
+	Files   map[string]Entry `json:"files"`
+	// RunHashes are the file hashes when the last run was started
+	RunHashes map[string]string `json:"run_hashes,omitempty"`
+	RunAt     time.Time         `json:"run_at,omitempty"`
+}
+
+// Delta lists source files that changed since the last run
+type Delta struct {
+	Since    time.Time
+	Added    []string
+	Modified []string
+	Removed  []string
+}
+
+// Empty reports whether nothing changed
+func (d *Delta) Empty() bool {
+	return len(d.Added) == 0 && len(d.Modified) == 0 && len(d.Removed) == 0
+}
+
+// Markdown renders the delta as a list for the coordination issue
+func (d *Delta) Markdown() string {
+	if d.Empty() {
+		return ""
+	}
+
+	var b strings.Builder
+	write := func(label string, paths []string) {
+		for _, path := range paths {
+			fmt.Fprintf(&b, "- %s `%s`\n", label, path)
+		}
+	}
+	write("Added", d.Added)
+	write("Modified", d.Modified)
+	write("Removed", d.Removed)
+	return b.String()
+}
+
+// Result describes a bundle build
+type Result struct {
+	Bundle      string
+	Delta       Delta
+	Files       int
+	Regenerated int
+}
+
+// Options controls a build
+type Options struct {
+	// MaxFileBytes truncates large files (default DefaultMaxFileBytes)
+	MaxFileBytes int
+	// Now is the build time
+	Now time.Time
+}
+
+// DefaultDir is where the bundle and its manifest are stored
+func DefaultDir() string {
+	return filepath.Join(".autonomous-dev", "context")
+}
+
+// BundlePath returns the path of the bundle in dir
+func BundlePath(dir string) string {
+	return filepath.Join(dir, "bundle.md")
+}
+
+func manifestPath(dir string) string {
+	return filepath.Join(dir, "manifest.json")
+}
+
+// Build refreshes the bundle in dir from sources. Files whose size and
+// modification time are unchanged are not re-read, and files whose content
+// hash is unchanged reuse their rendered section.
+func Build(dir string, sources []string, opts Options) (*Result, error) {
+	if opts.MaxFileBytes <= 0 {
+		opts.MaxFileBytes = DefaultMaxFileBytes
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	previous, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := Resolve(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		BuiltAt:   opts.Now,
+		Files:     make(map[string]Entry, len(paths)),
+		RunHashes: previous.RunHashes,
+		RunAt:     previous.RunAt,
+	}
+	result := &Result{Files: len(paths)}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+
+		old, cached := previous.Files[path]
+		if cached && old.Size == info.Size() && old.ModTime.Equal(info.ModTime()) {
+			manifest.Files[path] = old
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		sum := sha256.Sum256(content)
+		entry := Entry{Hash: hex.EncodeToString(sum[:]), Size: info.Size(), ModTime: info.ModTime()}
+
+		if cached && old.Hash == entry.Hash {
+			entry.Section = old.Section
+		} else {
+			entry.Section = renderSection(path, content, opts.MaxFileBytes)
+			result.Regenerated++
+		}
+		manifest.Files[path] = entry
+	}
+
+	result.Delta = delta(manifest)
+	result.Bundle = render(manifest, paths)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create context directory: %w", err)
+	}
+	if err := os.WriteFile(BundlePath(dir), []byte(result.Bundle), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write context bundle: %w", err)
+	}
+	if err := saveManifest(dir, manifest); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MarkRun records the hashes of the last build as the baseline for the delta
+// of the next run
+func MarkRun(dir string, at time.Time) error {
+	m, err := loadManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	m.RunHashes = make(map[string]string, len(m.Files))
+	for path, entry := range m.Files {
+		m.RunHashes[path] = entry.Hash
+	}
+	m.RunAt = at
+
+	return saveManifest(dir, m)
+}
+
+// Resolve expands sources (files, directories and glob patterns) into a
+// sorted list of files
+func Resolve(sources []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(path string) {
+		path = filepath.ToSlash(filepath.Clean(path))
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	for _, source := range sources {
+		matches, err := filepath.Glob(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid context source %q: %w", source, err)
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, fmt.Errorf("failed to stat %s: %w", match, err)
+			}
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+			err = filepath.WalkDir(match, func(path string, d os.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.IsDir() {
+					if path != match && strings.HasPrefix(d.Name(), ".") {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				add(path)
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to walk %s: %w", match, err)
+			}
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// delta compares the build with the hashes recorded at the last run. Without
+// a previous run there is no baseline and the delta is empty.
+func delta(m *Manifest) Delta {
+	d := Delta{Since: m.RunAt}
+	if m.RunHashes == nil {
+		return d
+	}
+
+	for path, entry := range m.Files {
+		hash, ok := m.RunHashes[path]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, path)
+		case hash != entry.Hash:
+			d.Modified = append(d.Modified, path)
+		}
+	}
+	for path := range m.RunHashes {
+		if _, ok := m.Files[path]; !ok {
+			d.Removed = append(d.Removed, path)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Modified)
+	sort.Strings(d.Removed)
+
+	return d
+}
+
+func render(m *Manifest, paths []string) string {
+	var b strings.Builder
+	b.WriteString("# Context Bundle\n\n")
+	for _, path := range paths {
+		b.WriteString(m.Files[path].Section)
+	}
+	return b.String()
+}
+
+func renderSection(path string, content []byte, maxBytes int) string {
+	text := string(content)
+	truncated := ""
+	if len(text) > maxBytes {
+		cut := maxBytes
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut]
+		truncated = fmt.Sprintf("\n… (%d bytes truncated)", len(content)-cut)
+	}
+	return fmt.Sprintf("## %s\n\n````\n%s%s\n````\n\n", path, strings.TrimRight(text, "\n"), truncated)
+}
+
+func loadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(manifestPath(dir))
+	if os.IsNotExist(err) {
+		return &Manifest{Files: map[string]Entry{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read context manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse context manifest: %w", err)
+	}
+	if m.Files == nil {
+		m.Files = map[string]Entry{}
+	}
+	return &m, nil
+}
+
+func saveManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal context manifest: %w", err)
+	}
+	if err := os.WriteFile(manifestPath(dir), data, 0644); err != nil {
+		return fmt.Errorf("failed to write context manifest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache keeps provider responses on disk, keyed by a hash of the request, so
+// repeated calls with identical inputs do not spend tokens again
+type Cache struct {
+	dir string
+	ttl time.Duration
+	// Refresh skips cached values while still storing new ones
+	Refresh bool
+}
+
+// entry is one cached response
+type entry struct {
+	CreatedAt time.Time `json:"created_at"`
+	Value     string    `json:"value"`
+}
+
+// DefaultDir returns the directory of the local response cache
+func DefaultDir() string {
+	return filepath.Join(".autonomous-dev", "cache")
+}
+
+// Open returns a cache in dir whose entries expire after ttl
+func Open(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+// Key hashes the parts of a request into a cache key
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		// Length prefixes keep ("ab", "c") and ("a", "bc") apart
+		fmt.Fprintf(h, "%d:%s;", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached value of a key unless it is missing or expired.
+// A nil or refreshing cache never hits.
+func (c *Cache) Get(key string) (string, bool) {
+	if c == nil || c.Refresh {
+		return "", false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return "", false
+	}
+	if time.Since(e.CreatedAt) > c.ttl {
+		return "", false
+	}
+	return e.Value, true
+}
+
+// Put stores the value of a key. A nil cache stores nothing.
+func (c *Cache) Put(key, value string) error {
+	if c == nil {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.Marshal(entry{CreatedAt: time.Now().UTC(), Value: value})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+	if err := os.WriteFile(c.path(key), data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
@@ -0,0 +1,131 @@
+package canary
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/autonomous-dev/cli/pkg/protocol"
+)
+
+// Instance is the instance that runs the trial subtask
+const Instance = 1
+
+// Trial is a run started with a single canary instance. The remaining
+// instances are dispatched once the canary passes its quality gates.
+type Trial struct {
+	// Issue is the coordination issue, or 0 in dispatch mode
+	Issue int `json:"issue,omitempty"`
+	// RunID is the state branch run in dispatch mode
+	RunID string `json:"run_id,omitempty"`
+	Task  string `json:"task"`
+	// Instances is the instance count of the full fan-out
+	Instances int `json:"instances"`
+	// Inputs are the workflow inputs the fan-out is dispatched with
+	Inputs    map[string]string `json:"inputs,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Name identifies the trial's run for display
+func (t Trial) Name() string {
+	if t.Issue > 0 {
+		return fmt.Sprintf("issue #%d", t.Issue)
+	}
+	return "run " + t.RunID
+}
+
+// DefaultPath returns the file recording runs waiting on their canary
+func DefaultPath() string {
+	return filepath.Join(".autonomous-dev", "canaries.json")
+}
+
+// Load reads the trials at path. A missing file has none.
+func Load(path string) ([]Trial, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read canaries: %w", err)
+	}
+
+	var trials []Trial
+	if err := json.Unmarshal(data, &trials); err != nil {
+		return nil, fmt.Errorf("failed to parse canaries %s: %w", path, err)
+	}
+	return trials, nil
+}
+
+// Save writes the trials to path
+func Save(path string, trials []Trial) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(trials, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal canaries: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write canaries: %w", err)
+	}
+	return nil
+}
+
+// Verdict is the outcome of a canary so far
+type Verdict string
+
+const (
+	Pending Verdict = "pending"
+	Passed  Verdict = "passed"
+	Failed  Verdict = "failed"
+)
+
+// Check judges the canary instance from the run's coordination messages.
+// It passes once it reports completion and every result it posted meets the
+// quality gates: outcome completed, no failing tests and, with minCoverage
+// set, reported coverage of at least minCoverage percent. The reason
+// explains a failure.
+func Check(snap *protocol.Snapshot, minCoverage float64) (Verdict, string) {
+	if err, ok := snap.Rejected[Instance]; ok {
+		return Failed, fmt.Sprintf("result rejected: %v", err)
+	}
+
+	var results []*protocol.Result
+	for _, r := range snap.Results {
+		if r.InstanceID == Instance {
+			results = append(results, r)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].TaskID < results[j].TaskID })
+
+	for _, r := range results {
+		if r.Outcome != "completed" {
+			return Failed, fmt.Sprintf("task %s ended %s", r.TaskID, r.Outcome)
+		}
+		if r.Tests != nil && r.Tests.Failed > 0 {
+			return Failed, fmt.Sprintf("task %s has %d failing test(s)", r.TaskID, r.Tests.Failed)
+		}
+		if minCoverage > 0 {
+			if r.Coverage == nil {
+				return Failed, fmt.Sprintf("task %s reported no coverage (%.1f%% required)", r.TaskID, minCoverage)
+			}
+			if *r.Coverage < minCoverage {
+				return Failed, fmt.Sprintf("task %s coverage %.1f%% is below %.1f%%", r.TaskID, *r.Coverage, minCoverage)
+			}
+		}
+	}
+
+	st, ok := snap.Statuses[Instance]
+	switch {
+	case !ok:
+		return Pending, ""
+	case st.Status == "failed":
+		return Failed, "instance failed"
+	case st.Status == "completed":
+		return Passed, ""
+	}
+	return Pending, ""
+}
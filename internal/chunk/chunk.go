@@ -0,0 +1,161 @@
+package chunk
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxBody is GitHub's limit for issue and comment bodies
+const MaxBody = 65536
+
+const (
+	// footer ends every chunk but the last; reassembly cuts at it
+	footerMarker = "\n<!-- CHUNK:END -->"
+	footer       = footerMarker + "\n_Continued in the next comment…_"
+)
+
+// headerPattern matches the continuation marker that starts each chunk
+var headerPattern = regexp.MustCompile(`^<!-- CHUNK:([0-9a-f]+):(\d+)/(\d+) -->\n`)
+
+// Split splits a body into pieces of at most limit bytes, each marked so
+// that Join can reassemble them. Bodies within the limit are returned as is.
+// Pieces end at line breaks where possible and never inside a UTF-8 sequence.
+func Split(body string, limit int) []string {
+	if limit <= 0 {
+		limit = MaxBody
+	}
+	if len(body) <= limit {
+		return []string{body}
+	}
+
+	sum := sha1.Sum([]byte(body))
+	id := hex.EncodeToString(sum[:4])
+
+	// Reserve room for the largest header, assuming fewer than 1000 pieces
+	room := limit - len(header(id, 999, 999)) - len(footer)
+	if room < 1 {
+		room = 1
+	}
+
+	var pieces []string
+	for rest := body; rest != ""; {
+		if len(rest) <= room {
+			pieces = append(pieces, rest)
+			break
+		}
+		cut := cutPoint(rest, room)
+		pieces = append(pieces, rest[:cut])
+		rest = rest[cut:]
+	}
+
+	chunks := make([]string, len(pieces))
+	for i, piece := range pieces {
+		chunks[i] = header(id, i+1, len(pieces)) + piece
+		if i < len(pieces)-1 {
+			chunks[i] += footer
+		}
+	}
+	return chunks
+}
+
+// Join reassembles chunked bodies. Unchunked bodies pass through; a chunked
+// body appears once, at the position of its last piece. Pieces of an
+// incomplete body are joined as far as they are available.
+func Join(bodies []string) []string {
+	type group struct {
+		total  int
+		pieces map[int]string
+		last   int
+	}
+	groups := make(map[string]*group)
+	keys := make([]string, len(bodies))
+
+	for i, body := range bodies {
+		id, index, total, piece, ok := parse(body)
+		if !ok {
+			continue
+		}
+		g, exists := groups[id]
+		if !exists {
+			g = &group{total: total, pieces: make(map[int]string)}
+			groups[id] = g
+		}
+		g.pieces[index] = piece
+		g.last = i
+		keys[i] = id
+	}
+
+	result := make([]string, 0, len(bodies))
+	for i, body := range bodies {
+		id := keys[i]
+		if id == "" {
+			result = append(result, body)
+			continue
+		}
+		g := groups[id]
+		if g.last != i {
+			continue
+		}
+		var b strings.Builder
+		for n := 1; n <= g.total; n++ {
+			b.WriteString(g.pieces[n])
+		}
+		result = append(result, b.String())
+	}
+	return result
+}
+
+// Unmark removes the marker of a chunk, so Join passes the body through
+// instead of reassembling it with other pieces
+func Unmark(body string) string {
+	if m := headerPattern.FindString(body); m != "" {
+		return body[len(m):]
+	}
+	return body
+}
+
+func header(id string, index, total int) string {
+	return fmt.Sprintf("<!-- CHUNK:%s:%d/%d -->\n", id, index, total)
+}
+
+// parse extracts the marker and original piece of a chunk
+func parse(body string) (id string, index, total int, piece string, ok bool) {
+	m := headerPattern.FindStringSubmatch(body)
+	if m == nil {
+		return "", 0, 0, "", false
+	}
+	index, _ = strconv.Atoi(m[2])
+	total, _ = strconv.Atoi(m[3])
+	if index < 1 || index > total {
+		return "", 0, 0, "", false
+	}
+
+	piece = body[len(m[0]):]
+	if index < total {
+		if end := strings.LastIndex(piece, footerMarker); end >= 0 {
+			piece = piece[:end]
+		}
+	}
+	return m[1], index, total, piece, true
+}
+
+// cutPoint returns where to end a piece of at most max bytes: after the last
+// line break in the second half, else at a rune boundary
+func cutPoint(s string, max int) int {
+	if i := strings.LastIndexByte(s[:max], '\n'); i >= max/2 {
+		return i + 1
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	if cut == 0 {
+		cut = max
+	}
+	return cut
+}
@@ -0,0 +1,100 @@
+package chunk
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitJoinRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		limit int
+	}{
+		{"within limit", "short body", 100},
+		{"exactly the limit", strings.Repeat("x", 100), 100},
+		{"lines", strings.Repeat("line of a long log\n", 200), 500},
+		{"no line breaks", strings.Repeat("abcdefghij", 300), 400},
+		{"multi-byte runes", strings.Repeat("日本語のログ ", 400), 300},
+		{"footer marker in body", strings.Repeat("text\n<!-- CHUNK:END -->\n", 100), 400},
+		{"header marker in body", "<!-- CHUNK:abc:1/2 -->\n" + strings.Repeat("y", 1000), 300},
+		{"default limit", strings.Repeat("z", MaxBody+10), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pieces := Split(tt.body, tt.limit)
+			limit := tt.limit
+			if limit <= 0 {
+				limit = MaxBody
+			}
+			for i, piece := range pieces {
+				if len(piece) > limit {
+					t.Errorf("piece %d has %d bytes, limit %d", i+1, len(piece), limit)
+				}
+				if !utf8.ValidString(piece) {
+					t.Errorf("piece %d is not valid UTF-8", i+1)
+				}
+			}
+			if len(tt.body) <= limit && (len(pieces) != 1 || pieces[0] != tt.body) {
+				t.Errorf("body within the limit was split into %d pieces", len(pieces))
+			}
+
+			joined := Join(pieces)
+			if len(joined) != 1 || joined[0] != tt.body {
+				t.Errorf("Join(Split(body)) = %d bodies, want the body back", len(joined))
+			}
+		})
+	}
+}
+
+func TestJoinInterleaved(t *testing.T) {
+	a := Split(strings.Repeat("first message\n", 100), 500)
+	b := Split(strings.Repeat("second message\n", 100), 500)
+	if len(a) < 2 || len(b) < 2 {
+		t.Fatalf("bodies were not split: %d and %d pieces", len(a), len(b))
+	}
+
+	// Pieces of two bodies interleaved with unchunked comments
+	bodies := []string{"before", a[0], b[0]}
+	bodies = append(bodies, a[1:]...)
+	bodies = append(bodies, "between")
+	bodies = append(bodies, b[1:]...)
+
+	want := []string{"before", strings.Repeat("first message\n", 100), "between", strings.Repeat("second message\n", 100)}
+	got := Join(bodies)
+	if len(got) != len(want) {
+		t.Fatalf("Join returned %d bodies, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("body %d = %.40q..., want %.40q...", i, got[i], want[i])
+		}
+	}
+}
+
+func TestJoinIncomplete(t *testing.T) {
+	pieces := Split(strings.Repeat("0123456789\n", 100), 300)
+	got := Join(pieces[:2])
+	if len(got) != 1 {
+		t.Fatalf("Join returned %d bodies, want 1", len(got))
+	}
+	if want := strings.Repeat("0123456789\n", 100); !strings.HasPrefix(want, got[0]) || got[0] == want {
+		t.Errorf("incomplete body %q is not a proper prefix", got[0])
+	}
+}
+
+func TestUnmark(t *testing.T) {
+	pieces := Split(strings.Repeat("line\n", 200), 300)
+	unmarked := Unmark(pieces[0])
+	if strings.HasPrefix(unmarked, "<!-- CHUNK:") {
+		t.Errorf("Unmark kept the header: %.40q", unmarked)
+	}
+	got := Join([]string{unmarked, pieces[1]})
+	if len(got) != 2 || got[0] != unmarked {
+		t.Errorf("unmarked piece was joined with others: %d bodies", len(got))
+	}
+	if Unmark("plain") != "plain" {
+		t.Error("Unmark changed an unchunked body")
+	}
+}
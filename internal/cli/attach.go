@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/coordination"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// maxAttachmentBytes matches GitHub's limit for files attached to issues
+const maxAttachmentBytes = 25 << 20
+
+var (
+	attachIssue int
+	attachRun   string
+	attachGist  bool
+)
+
+func AttachCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attach <file>...",
+		Short: "Attach files to a run's coordination issue",
+		Long: `Upload mockups, specs and other files referenced by a task and link them
+from the coordination issue (or state branch run in dispatch mode), so
+every instance can read them.
+
+Files are committed to the state branch (coordination.state_branch), under
+attachments/issue-N/ or runs/<id>/attachments/, and linked from a comment.
+Instances read them with:
+  git fetch origin <state branch> && git show FETCH_HEAD:<path>
+
+With --gist, text files are uploaded to a secret gist instead.`,
+		Example: `  autonomous-dev attach --issue 42 mockup.png spec.pdf
+  autonomous-dev attach --issue 42 --gist api-notes.md`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: runAttach,
+	}
+
+	cmd.Flags().IntVar(&attachIssue, "issue", 0, "Coordination issue number")
+	cmd.Flags().StringVar(&attachRun, "run", "", "State branch run ID in dispatch mode (default latest)")
+	cmd.Flags().BoolVar(&attachGist, "gist", false, "Upload text files to a secret gist instead of the state branch")
+
+	return cmd
+}
+
+// attachment is a local file to upload
+type attachment struct {
+	name    string
+	content []byte
+	// url links the uploaded file
+	url string
+	// location tells instances where to read it
+	location string
+}
+
+func runAttach(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := newClient(cfg)
+
+	store, err := coordinationStore(client, cfg, attachIssue, attachRun)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		if cfg.Coordination.Dispatch() {
+			return fmt.Errorf("no runs found on %s", cfg.Coordination.Branch())
+		}
+		return fmt.Errorf("--issue is required")
+	}
+
+	// Read everything before uploading anything
+	files := make([]*attachment, 0, len(args))
+	seen := map[string]bool{}
+	for _, arg := range args {
+		name := filepath.Base(arg)
+		if seen[name] {
+			return fmt.Errorf("more than one file is named %s", name)
+		}
+		seen[name] = true
+
+		content, err := os.ReadFile(arg)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", arg, err)
+		}
+		if len(content) > maxAttachmentBytes {
+			return fmt.Errorf("%s is larger than %d MB", arg, maxAttachmentBytes>>20)
+		}
+		if attachGist && !utf8.Valid(content) {
+			return fmt.Errorf("%s is a binary file; gists only hold text, attach it without --gist", arg)
+		}
+		files = append(files, &attachment{name: name, content: content})
+	}
+
+	if attachGist {
+		err = uploadGist(client, store, files)
+	} else {
+		err = uploadToBranch(client, cfg, store, files)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := store.Post(attachmentsComment(files)); err != nil {
+		return fmt.Errorf("failed to link attachments: %w", err)
+	}
+
+	for _, f := range files {
+		fmt.Printf("%s Attached %s\n", green(iconOK), f.name)
+		fmt.Printf("  %s\n", cyan(f.url))
+	}
+	summarize("attached", len(files))
+	return nil
+}
+
+// uploadToBranch commits the files to the state branch in one commit
+func uploadToBranch(client *github.Client, cfg *config.Config, store coordination.Store, files []*attachment) error {
+	branch := cfg.Coordination.Branch()
+	dir := path.Join("attachments", fmt.Sprintf("issue-%d", attachIssue))
+	if s, ok := store.(*coordination.BranchStore); ok {
+		dir = path.Join(coordination.RunDir(s.RunID()), "attachments")
+	}
+
+	contents := make(map[string][]byte, len(files))
+	for _, f := range files {
+		contents[path.Join(dir, f.name)] = f.content
+	}
+	sha, err := client.CommitFiles(branch, "Attach files to "+store.Location(), contents)
+	if err != nil {
+		return fmt.Errorf("failed to upload attachments to %s: %w", branch, err)
+	}
+
+	// Commit URLs keep working after the branch moves on
+	for _, f := range files {
+		file := path.Join(dir, f.name)
+		f.url = fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s?raw=true", cfg.GitHub.Owner, cfg.GitHub.Repo, sha, file)
+		f.location = fmt.Sprintf("`git show %s:%s`", shortSHA(sha), file)
+	}
+	return nil
+}
+
+// uploadGist uploads the files to one secret gist
+func uploadGist(client *github.Client, store coordination.Store, files []*attachment) error {
+	contents := make(map[string]string, len(files))
+	for _, f := range files {
+		contents[f.name] = string(f.content)
+	}
+	url, raw, err := client.CreateGist("Attachments for "+store.Location(), contents)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		f.url = raw[f.name]
+		if f.url == "" {
+			f.url = url
+		}
+		f.location = fmt.Sprintf("`curl -sL %s`", f.url)
+	}
+	return nil
+}
+
+// attachmentsComment lists the attachments, embedding images
+func attachmentsComment(files []*attachment) string {
+	var b strings.Builder
+	b.WriteString("📎 **Attachments**\n\n")
+	for _, f := range files {
+		fmt.Fprintf(&b, "- [%s](%s): %s\n", f.name, f.url, f.location)
+	}
+	for _, f := range files {
+		if strings.HasPrefix(mime.TypeByExtension(path.Ext(f.name)), "image/") {
+			fmt.Fprintf(&b, "\n![%s](%s)\n", f.name, f.url)
+		}
+	}
+	return b.String()
+}
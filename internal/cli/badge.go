@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/badge"
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/dashboard"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	badgeOutput  string
+	badgePublish bool
+	badgeBranch  string
+	badgeURL     string
+)
+
+// badgeRunWindow is how many recent workflow runs the badges summarize
+const badgeRunWindow = 100
+
+func BadgeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "badge",
+		Short: "Generate README badges of autonomous runs",
+		Long: `Generate shields.io endpoint badges showing the status of the latest
+autonomous run, the success rate of runs in the last 30 days and the number
+of instances in progress, and print a README snippet that shows them.
+
+The endpoint files (badges/status.json, badges/success-rate.json and
+badges/instances.json) are written to --output, next to the site of
+'dashboard --generate'. With --publish they are committed to the gh-pages
+branch instead; 'serve --badge-addr' serves them live.
+
+The snippet points at GitHub Pages unless --url names where the files are
+served.`,
+		Example: `  autonomous-dev badge --publish
+  autonomous-dev badge --url https://ci.example.com/`,
+		RunE: runBadge,
+	}
+
+	cmd.Flags().StringVarP(&badgeOutput, "output", "o", filepath.Join(".autonomous-dev", "site"), "Directory to write the endpoint files to")
+	cmd.Flags().BoolVar(&badgePublish, "publish", false, "Commit the endpoint files to --branch instead of writing them")
+	cmd.Flags().StringVar(&badgeBranch, "branch", "gh-pages", "Branch to publish to")
+	cmd.Flags().StringVar(&badgeURL, "url", "", "Base URL the endpoint files are served from (default GitHub Pages)")
+
+	return cmd
+}
+
+func runBadge(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := newClient(cfg)
+
+	stats, err := badgeStats(client)
+	if err != nil {
+		return err
+	}
+	files, err := badge.Files(stats)
+	if err != nil {
+		return err
+	}
+
+	if badgePublish {
+		sha, err := client.CommitFiles(badgeBranch, "Update autonomous-dev badges", files)
+		if err != nil {
+			return fmt.Errorf("failed to publish badges: %w", err)
+		}
+		fmt.Printf("%s Published badges to %s (%s)\n", green(iconOK), badgeBranch, sha[:7])
+	} else {
+		if err := dashboard.WriteDir(badgeOutput, files); err != nil {
+			return fmt.Errorf("failed to write badges: %w", err)
+		}
+		fmt.Printf("%s Wrote badges to %s\n", green(iconOK), filepath.Join(badgeOutput, badge.Dir))
+	}
+
+	endpoints := badge.Endpoints(stats)
+	for _, name := range badge.Names {
+		fmt.Printf("  %s: %s\n", endpoints[name].Label, endpoints[name].Message)
+		summarize(name, endpoints[name].Message)
+	}
+
+	baseURL := badgeURL
+	if baseURL == "" {
+		baseURL = fmt.Sprintf("https://%s.github.io/%s/", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+	fmt.Println()
+	fmt.Println(bold("README snippet:"))
+	fmt.Println(badge.Markdown(baseURL, workflowPageURL(cfg)))
+	return nil
+}
+
+// badgeStats summarizes the recent workflow runs and counts the instances
+// of unfinished runs that are in progress
+func badgeStats(client *github.Client) (badge.Stats, error) {
+	runs, err := client.ListWorkflowRuns(badgeRunWindow)
+	if err != nil {
+		return badge.Stats{}, err
+	}
+
+	active := 0
+	for _, run := range runs {
+		if run.Status == "completed" {
+			continue
+		}
+		jobs, err := client.GetWorkflowJobs(run.ID)
+		if err != nil {
+			return badge.Stats{}, fmt.Errorf("failed to get workflow jobs: %w", err)
+		}
+		for _, job := range jobs {
+			if job.InstanceNumber() > 0 && job.Status == "in_progress" {
+				active++
+			}
+		}
+	}
+	return badge.Compute(runs, active, time.Now()), nil
+}
+
+// workflowPageURL returns the Actions page of the autonomous-dev workflow
+func workflowPageURL(cfg *config.Config) string {
+	file := config.DefaultConfig().Workflow.File
+	if cfg.Workflow.File != "" {
+		file = cfg.Workflow.File
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/actions/workflows/%s", cfg.GitHub.Owner, cfg.GitHub.Repo, path.Base(filepath.ToSlash(file)))
+}
+
+// badgeServer serves the latest badge endpoint files for serve --badge-addr
+type badgeServer struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// update recomputes the endpoint files
+func (s *badgeServer) update(client *github.Client) error {
+	stats, err := badgeStats(client)
+	if err != nil {
+		return err
+	}
+	files, err := badge.Files(stats)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.files = files
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *badgeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	files := s.files
+	s.mu.Unlock()
+
+	if files == nil {
+		http.Error(w, "badges not computed yet", http.StatusServiceUnavailable)
+		return
+	}
+	data, ok := files[strings.TrimPrefix(r.URL.Path, "/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
@@ -0,0 +1,287 @@
+package cli
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/queue"
+	"github.com/autonomous-dev/cli/internal/tracker"
+	"github.com/fatih/color"
+)
+
+// batchColumns are the columns a --from-file CSV may have. Every row needs
+// a task, an issue or a from ticket; empty cells take the start flags.
+var batchColumns = []string{"task", "instances", "agent", "priority", "environment", "sha", "issue", "from"}
+
+// batchRow is one run of a batch start
+type batchRow struct {
+	// where locates the row for messages, e.g. "tasks.csv:3" or "#12"
+	where string
+	req   runRequest
+	// from is the ticket to import the task from
+	from string
+}
+
+// readBatchFile parses a --from-file CSV into rows based on base. The first
+// record is the header; lines starting with # are comments.
+func readBatchFile(path string, base runRequest, maxInstances int) ([]batchRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(batchColumns, name) {
+			return nil, fmt.Errorf("%s: unknown column %q (expected %s)", path, name, strings.Join(batchColumns, ", "))
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["task"]; !ok {
+		_, issue := columns["issue"]
+		_, from := columns["from"]
+		if !issue && !from {
+			return nil, fmt.Errorf("%s: the header needs a task, issue or from column", path)
+		}
+	}
+
+	var rows []batchRow
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		line, _ := r.FieldPos(0)
+		row := batchRow{where: fmt.Sprintf("%s:%d", path, line), req: base}
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		if v := cell("task"); v != "" {
+			row.req.Task = v
+		}
+		if v := cell("instances"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > maxInstances {
+				return nil, fmt.Errorf("%s: invalid instances %q (expected 1 to %d)", row.where, v, maxInstances)
+			}
+			row.req.Instances = n
+		}
+		if v := cell("agent"); v != "" {
+			row.req.Agent = v
+		}
+		if v := cell("priority"); v != "" {
+			if row.req.Priority, err = queue.ParsePriority(v); err != nil {
+				return nil, fmt.Errorf("%s: %w", row.where, err)
+			}
+		}
+		if v := cell("environment"); v != "" {
+			row.req.Environment = v
+		}
+		if v := cell("sha"); v != "" {
+			if !shaPattern.MatchString(v) {
+				return nil, fmt.Errorf("%s: invalid sha %q: expected a commit SHA of 7 to 40 hex digits", row.where, v)
+			}
+			row.req.SHA = strings.ToLower(v)
+		}
+		if v := cell("issue"); v != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(v, "#"))
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%s: invalid issue %q", row.where, v)
+			}
+			row.req.Issue = n
+		}
+		row.from = cell("from")
+
+		if row.req.Task == "" && row.req.Issue == 0 && row.from == "" {
+			return nil, fmt.Errorf("%s: no task, issue or from", row.where)
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s has no tasks", path)
+	}
+	return rows, nil
+}
+
+// resolveBatchRow fills in rows taken from tickets and existing issues. In
+// dispatch mode there is no coordination issue to attach to, so an issue's
+// body becomes the run's details instead.
+func resolveBatchRow(client *github.Client, cfg *config.Config, row *batchRow) error {
+	if row.from != "" {
+		ticket, err := tracker.Fetch(cfg.Trackers, row.from)
+		if err != nil {
+			return fmt.Errorf("%s: %w", row.where, err)
+		}
+		if row.req.Task == "" {
+			row.req.Task = ticket.Title
+		}
+		row.req.Details = tracker.IssueDetails(ticket)
+		row.req.Source = ticket.Ref
+	}
+
+	if row.req.Issue == 0 {
+		return nil
+	}
+	issue, err := client.GetIssue(row.req.Issue)
+	if err != nil {
+		return fmt.Errorf("%s: %w", row.where, err)
+	}
+	if issue.PullRequest {
+		return fmt.Errorf("%s: #%d is a pull request, not an issue", row.where, issue.Number)
+	}
+	if issue.State == "closed" {
+		return fmt.Errorf("%s: issue #%d is closed", row.where, issue.Number)
+	}
+	if row.req.Task == "" {
+		row.req.Task = issue.Title
+	}
+	if cfg.Coordination.Dispatch() {
+		row.req.Details = strings.TrimSpace(fmt.Sprintf("From #%d\n\n%s\n\n%s", issue.Number, issue.Body, row.req.Details))
+		row.req.Issue = 0
+	}
+	return nil
+}
+
+// runBatch starts a run for every row of --from-file or issue of
+// --from-issues. All runs go through the queue, so they are dispatched in
+// priority order, after queued runs of higher priority, for as long as quotas
+// allow; serve dispatches the rest.
+func runBatch(cfg *config.Config, base runRequest) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	var rows []batchRow
+	if startFromFile != "" {
+		var err error
+		if rows, err = readBatchFile(startFromFile, base, cfg.Instances.Max); err != nil {
+			return err
+		}
+	}
+	for _, n := range startFromIssues {
+		row := batchRow{where: fmt.Sprintf("#%d", n), req: base}
+		row.req.Issue = n
+		rows = append(rows, row)
+	}
+
+	client := newClient(cfg)
+	online := cfg.GitHub.Token != ""
+	if online {
+		err := client.Ping()
+		online = !errors.Is(err, github.ErrUnreachable) && !errors.Is(err, github.ErrUnauthorized)
+	}
+
+	// Resolve every row before queueing any, so a bad row queues nothing
+	for i := range rows {
+		if rows[i].from == "" && rows[i].req.Issue == 0 {
+			continue
+		}
+		if !online && rows[i].req.Issue > 0 {
+			return fmt.Errorf("%s: reading issues needs a GitHub token and connectivity", rows[i].where)
+		}
+		if err := resolveBatchRow(client, cfg, &rows[i]); err != nil {
+			return err
+		}
+	}
+
+	if online {
+		if err := checkDispatch(client, cfg); err != nil {
+			return err
+		}
+	}
+
+	// Distinct creation times keep the rows' queue IDs unique and in order
+	store := queue.Open(queue.DefaultDir())
+	now := time.Now().UTC()
+	ids := map[string]bool{}
+	fmt.Println(bold(fmt.Sprintf("Queueing %d runs...", len(rows))))
+	for i, row := range rows {
+		entry := requestEntry(row.req, "batch start")
+		entry.CreatedAt = now.Add(time.Duration(i))
+		if err := store.Add(entry); err != nil {
+			return err
+		}
+		ids[entry.ID] = true
+		label := row.req.Task
+		if row.req.Issue > 0 {
+			label = fmt.Sprintf("#%d %s", row.req.Issue, label)
+		}
+		fmt.Printf("  %s %s (%s priority, %d instances)\n", iconBullet, label, entry.PriorityOf(), row.req.Instances)
+	}
+	fmt.Println()
+
+	if !online {
+		summarize("status", "queued")
+		summarize("queued", len(rows))
+		fmt.Printf("%s GitHub is not reachable; 'autonomous-dev serve' dispatches the %d queued runs once it is\n", yellow(iconPaused), len(rows))
+		return nil
+	}
+
+	dispatchErr := dispatchQueued(client, cfg, newEventBus(client, cfg))
+
+	remaining, err := store.List()
+	if err != nil {
+		return err
+	}
+	var left []string
+	for _, entry := range remaining {
+		if ids[entry.ID] {
+			left = append(left, entry.ID)
+		}
+	}
+	dispatched := len(rows) - len(left)
+
+	summarize("status", "batch")
+	summarize("dispatched", dispatched)
+	fmt.Println()
+	fmt.Printf("%s Dispatched %d of %d runs\n", green(iconOK), dispatched, len(rows))
+	if dispatchErr != nil {
+		summarize("queued", len(left))
+		fmt.Printf("%s %d runs are still queued\n", yellow(iconPaused), len(left))
+		return dispatchErr
+	}
+	if len(left) > 0 && cfg.Quotas.OnLimit == "refuse" {
+		for _, id := range left {
+			if err := store.Remove(id); err != nil {
+				return err
+			}
+		}
+		summarize("queued", 0)
+		return fmt.Errorf("%d runs refused: quota reached", len(left))
+	}
+	summarize("queued", len(left))
+	if len(left) > 0 {
+		fmt.Printf("%s %d runs wait in the queue for quota; run 'autonomous-dev serve' to dispatch them\n", yellow(iconPaused), len(left))
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/scaffold"
+	"github.com/autonomous-dev/cli/internal/template"
+	"github.com/autonomous-dev/cli/scripts"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bootstrapName    string
+	bootstrapStack   string
+	bootstrapOrg     string
+	bootstrapPublic  bool
+	bootstrapSecrets []string
+	bootstrapTask    string
+	bootstrapNoRun   bool
+)
+
+func BootstrapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Create a new project repository and start developing it",
+		Long: `Bootstrap a brand-new project from zero to a working repository:
+
+1. Create the GitHub repository
+2. Push a starter scaffold for the chosen stack, the workflow and the status reporter
+3. Clone it and initialize autonomous-dev
+4. Set repository secrets from environment variables of the same name
+5. Dispatch a first autonomous run to flesh out the skeleton
+
+Stacks: ` + strings.Join(scaffold.Stacks(), ", "),
+		Example: `  autonomous-dev bootstrap --name myapp --stack go-api
+  autonomous-dev bootstrap --name api --stack node-api --org my-org --secret ANTHROPIC_API_KEY`,
+		RunE: runBootstrap,
+	}
+
+	cmd.Flags().StringVar(&bootstrapName, "name", "", "Repository name (required)")
+	cmd.MarkFlagRequired("name")
+	cmd.Flags().StringVar(&bootstrapStack, "stack", "go-api", "Starter stack")
+	cmd.Flags().StringVar(&bootstrapOrg, "org", "", "Create the repository in an organization")
+	cmd.Flags().BoolVar(&bootstrapPublic, "public", false, "Create a public repository")
+	cmd.Flags().StringSliceVar(&bootstrapSecrets, "secret", []string{"ANTHROPIC_API_KEY"}, "Secret to set from the environment variable of the same name (repeatable)")
+	cmd.Flags().StringVar(&bootstrapTask, "task", "", "Task for the first run (default derived from the stack)")
+	cmd.Flags().BoolVar(&bootstrapNoRun, "no-run", false, "Do not dispatch a first run")
+
+	return cmd
+}
+
+func runBootstrap(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	// Fail on unknown stacks before creating anything
+	if err := scaffold.Validate(bootstrapStack); err != nil {
+		return err
+	}
+	if _, err := os.Stat(bootstrapName); err == nil {
+		return fmt.Errorf("directory %s already exists", bootstrapName)
+	}
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return fmt.Errorf("GITHUB_TOKEN environment variable is required")
+	}
+
+	fmt.Println(bold("Bootstrapping"), bootstrapName, fmt.Sprintf("(%s)", scaffold.Description(bootstrapStack)))
+	fmt.Println()
+
+	// 1. Create the repository
+	cfg := config.DefaultConfig()
+	creator := github.NewClient(token, "", "")
+	creator.SetTimeouts(apiTimeouts(cfg))
+	repo, err := creator.CreateRepository(bootstrapName, scaffold.Description(bootstrapStack), !bootstrapPublic, bootstrapOrg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Created repository %s\n", green(iconOK), cyan(repo.URL))
+
+	// 2. Push the scaffold together with what init would generate
+	cfg.GitHub.Owner = repo.Owner
+	cfg.GitHub.Repo = repo.Name
+
+	files, err := scaffold.Files(bootstrapStack, scaffold.Data{Owner: repo.Owner, Name: repo.Name})
+	if err != nil {
+		return err
+	}
+	workflow, err := template.WorkflowTemplate(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to render workflow: %w", err)
+	}
+	files[filepath.ToSlash(cfg.Workflow.File)] = []byte(workflow)
+	files[scripts.StatusReporterPath] = scripts.StatusReporter
+	files[scripts.StatusReporterPowerShellPath] = scripts.StatusReporterPowerShell
+
+	client := github.NewClient(token, repo.Owner, repo.Name)
+	client.SetTimeouts(apiTimeouts(cfg))
+	sha, err := client.CommitFiles(repo.DefaultBranch, "Initial scaffold ("+bootstrapStack+")", files)
+	if err != nil {
+		return fmt.Errorf("failed to push scaffold: %w", err)
+	}
+	fmt.Printf("%s Pushed scaffold (%s)\n", green(iconOK), sha[:7])
+
+	// 3. Clone and initialize locally
+	if _, err := execCommand("git", "clone", "--quiet", repo.CloneURL, bootstrapName); err != nil {
+		fmt.Printf("%s Warning: failed to clone (%v); initializing an empty directory\n", yellow(iconWarn), err)
+		if err := os.MkdirAll(bootstrapName, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", bootstrapName, err)
+		}
+	} else {
+		fmt.Printf("%s Cloned into %s\n", green(iconOK), bootstrapName)
+	}
+	if err := os.Chdir(bootstrapName); err != nil {
+		return fmt.Errorf("failed to enter %s: %w", bootstrapName, err)
+	}
+	if err := cfg.Save(config.LocalPath()); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Printf("%s Created %s\n", green(iconOK), config.LocalPath())
+
+	// 4. Secrets
+	for _, name := range bootstrapSecrets {
+		value := os.Getenv(name)
+		if value == "" {
+			fmt.Printf("%s %s is not set; set it later with: gh secret set %s --repo %s/%s\n", yellow(iconWarn), name, name, repo.Owner, repo.Name)
+			continue
+		}
+		if err := setRepoSecret(repo.Owner+"/"+repo.Name, name, value); err != nil {
+			fmt.Printf("%s Warning: failed to set secret %s: %v\n", yellow(iconWarn), name, err)
+			continue
+		}
+		fmt.Printf("%s Set secret %s\n", green(iconOK), name)
+	}
+
+	if bootstrapNoRun {
+		fmt.Println()
+		fmt.Println(green(iconOK), bold("Project ready:"), bootstrapName)
+		return nil
+	}
+
+	// 5. First run; the workflow must be registered before it can be dispatched
+	cfg.GitHub.Token = token
+	if err := waitForWorkflow(client, time.Minute); err != nil {
+		return err
+	}
+	if err := checkDispatch(client, cfg); err != nil {
+		return err
+	}
+
+	runTask := bootstrapTask
+	if runTask == "" {
+		runTask = fmt.Sprintf("Flesh out the %s skeleton of %s into a working project with tests and documentation", scaffold.Description(bootstrapStack), repo.Name)
+	}
+
+	fmt.Println()
+	issue, run, err := dispatchRun(client, cfg, newEventBus(client, cfg), runRequest{Task: runTask, Instances: cfg.Instances.Default})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println()
+	fmt.Println(green(iconOK), bold("Project bootstrapped!"))
+	fmt.Printf("  Directory: %s\n", bootstrapName)
+	fmt.Printf("  Issue: %s\n", issue.URL)
+	fmt.Printf("  Workflow: %s\n", run.URL)
+	fmt.Println()
+	fmt.Println("Check status:")
+	fmt.Printf("  cd %s && autonomous-dev status\n", bootstrapName)
+
+	return nil
+}
+
+// waitForWorkflow polls until a freshly pushed workflow can be dispatched
+func waitForWorkflow(client *github.Client, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := client.CheckActionsWrite()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("workflow not available after %s: %w", timeout, err)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// setRepoSecret sets an Actions secret with the gh CLI, which handles the
+// required public-key encryption. The value is passed on stdin.
+func setRepoSecret(repository, name, value string) error {
+	cmd := exec.Command("gh", "secret", "set", name, "--repo", repository)
+	cmd.Stdin = strings.NewReader(value)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
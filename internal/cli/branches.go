@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var branchesDryRun bool
+
+// blockingRuleTypes are ruleset rules that stop instances from pushing their branches
+var blockingRuleTypes = []string{"creation", "update", "pull_request", "required_signatures", "required_status_checks", "required_deployments"}
+
+func BranchesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "branches",
+		Short: "Set up branch rules for instance branches",
+		Long: `Instances push work to branches named branches.prefix + "instance-N"
+(default autonomous/instance-N) and open pull requests from them.`,
+	}
+
+	cmd.AddCommand(branchesSetupCmd())
+
+	return cmd
+}
+
+func branchesSetupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Adjust rulesets so instance branches can be pushed and auto-merged",
+		Long: `Adjust the repository so the workflow can push instance branches and,
+with branches.auto_merge, auto-merge their pull requests:
+
+- Repository rulesets that apply to more than the default branch exclude
+  refs/heads/<prefix>**
+- With branches.auto_merge, auto-merge is enabled for the repository and
+  rulesets requiring reviews on the default branch let GitHub Actions bypass
+  them through pull requests
+
+Organization rulesets and classic branch protection are reported but not
+changed. Requires a token with repository administration permission.`,
+		RunE: runBranchesSetup,
+	}
+
+	cmd.Flags().BoolVar(&branchesDryRun, "dry-run", false, "Show the changes without applying them")
+
+	return cmd
+}
+
+func runBranchesSetup(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := newClient(cfg)
+
+	repo, err := client.GetRepository()
+	if err != nil {
+		return err
+	}
+	rulesets, err := client.ListBranchRulesets()
+	if err != nil {
+		return err
+	}
+
+	pattern := cfg.Branches.RefPattern()
+	apply := func(description string, change func() error) error {
+		if branchesDryRun {
+			fmt.Printf("%s Would %s\n", yellow(iconWarn), description)
+			return nil
+		}
+		if err := change(); err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", green(iconOK), strings.ToUpper(description[:1])+description[1:])
+		return nil
+	}
+
+	for _, rs := range rulesets {
+		rs := rs
+		if rs.Enforcement == "disabled" || onlyDefaultBranch(rs, repo.DefaultBranch) || containsString(rs.Exclude, pattern) {
+			continue
+		}
+		if rs.Inherited {
+			fmt.Printf("%s Organization ruleset %q may apply to %s; ask an organization admin to exclude it\n", yellow(iconWarn), rs.Name, pattern)
+			continue
+		}
+		if err := apply(fmt.Sprintf("exclude %s from ruleset %q", pattern, rs.Name), func() error {
+			return client.ExcludeFromRuleset(rs.ID, pattern)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if protected, err := client.ListProtectedBranches(); err == nil {
+		for _, name := range protected {
+			if strings.HasPrefix(name, cfg.Branches.InstancePrefix()) {
+				fmt.Printf("%s Branch %s has classic branch protection; remove it in the repository settings\n", yellow(iconWarn), name)
+			}
+		}
+	}
+
+	if cfg.Branches.AutoMerge {
+		if !repo.AllowAutoMerge {
+			if err := apply("enable auto-merge for the repository", client.EnableAutoMerge); err != nil {
+				return err
+			}
+		}
+		for _, rs := range rulesets {
+			rs := rs
+			if rs.Inherited || rs.Enforcement == "disabled" || rs.RequiredReviews == 0 || rs.ActionsBypass || !rs.Covers(repo.DefaultBranch, repo.DefaultBranch) {
+				continue
+			}
+			if err := apply(fmt.Sprintf("let GitHub Actions bypass ruleset %q through pull requests", rs.Name), func() error {
+				return client.AllowActionsBypass(rs.ID)
+			}); err != nil {
+				return err
+			}
+		}
+		if reviews, err := client.GetRequiredReviews(repo.DefaultBranch); err == nil && reviews > 0 {
+			fmt.Printf("%s Classic protection on %s requires %d review(s); instance pull requests wait for approval before auto-merging\n", yellow(iconWarn), repo.DefaultBranch, reviews)
+		}
+	}
+
+	if branchesDryRun {
+		return nil
+	}
+
+	fmt.Println()
+	if problems := checkBranchFlow(client, cfg); problems > 0 {
+		return fmt.Errorf("%d branch rule problem(s) remain", problems)
+	}
+	return nil
+}
+
+// onlyDefaultBranch reports whether a ruleset targets nothing but the default branch
+func onlyDefaultBranch(rs github.Ruleset, defaultBranch string) bool {
+	if len(rs.Include) == 0 {
+		return false
+	}
+	for _, p := range rs.Include {
+		if p != "~DEFAULT_BRANCH" && p != "refs/heads/"+defaultBranch {
+			return false
+		}
+	}
+	return true
+}
+
+// checkBranchFlow prints rules that would stop instances from pushing their
+// branches or auto-merging their pull requests and returns the problem count
+func checkBranchFlow(client *github.Client, cfg *config.Config) int {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	problems := 0
+	branch := cfg.Branches.InstancePrefix() + "instance-1"
+
+	rules, err := client.GetBranchRules(branch)
+	if err != nil {
+		fmt.Printf("%s Branch rules: %v\n", yellow(iconWarn), err)
+	} else {
+		var blocking []string
+		for _, rule := range rules {
+			if containsString(blockingRuleTypes, rule) {
+				blocking = append(blocking, rule)
+			}
+		}
+		if len(blocking) > 0 {
+			fmt.Printf("%s Branch rules: rulesets block pushes to %s (%s); run 'autonomous-dev branches setup'\n", red(iconFail), branch, strings.Join(blocking, ", "))
+			problems++
+		} else {
+			fmt.Printf("%s Branch rules: %s can be pushed\n", green(iconOK), branch)
+		}
+	}
+
+	if protected, err := client.ListProtectedBranches(); err == nil {
+		for _, name := range protected {
+			if strings.HasPrefix(name, cfg.Branches.InstancePrefix()) {
+				fmt.Printf("%s Branch protection: %s is protected; instances cannot push to it\n", red(iconFail), name)
+				problems++
+			}
+		}
+	}
+
+	if cfg.Branches.AutoMerge {
+		repo, err := client.GetRepository()
+		switch {
+		case err != nil:
+			fmt.Printf("%s Auto-merge: %v\n", yellow(iconWarn), err)
+		case !repo.AllowAutoMerge:
+			fmt.Printf("%s Auto-merge: disabled for the repository; run 'autonomous-dev branches setup'\n", red(iconFail))
+			problems++
+		default:
+			fmt.Printf("%s Auto-merge: enabled\n", green(iconOK))
+			if rulesets, err := client.ListBranchRulesets(); err == nil {
+				for _, rs := range rulesets {
+					if rs.Enforcement == "active" && rs.RequiredReviews > 0 && !rs.ActionsBypass && rs.Covers(repo.DefaultBranch, repo.DefaultBranch) {
+						fmt.Printf("%s Auto-merge: ruleset %q requires %d review(s) on %s; instance pull requests wait for approval\n", yellow(iconWarn), rs.Name, rs.RequiredReviews, repo.DefaultBranch)
+					}
+				}
+			}
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/autonomous-dev/cli/internal/canary"
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/fatih/color"
+)
+
+// recordCanary remembers a run started with a canary so serve and status
+// can dispatch the remaining instances once it passes
+func recordCanary(trial canary.Trial) error {
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	path := canary.DefaultPath()
+	trials, err := canary.Load(path)
+	if err != nil {
+		return err
+	}
+	if err := canary.Save(path, append(trials, trial)); err != nil {
+		return err
+	}
+	fmt.Printf("%s Canary instance started; %d more follow once it passes (run 'autonomous-dev serve' or 'status')\n", cyan(iconWait), trial.Instances-canary.Instance)
+	return nil
+}
+
+// promoteCanaries checks every run waiting on its canary: passed canaries
+// get the remaining instances dispatched, failed ones stop the run. Pending
+// canaries are kept for the next check.
+func promoteCanaries(client *github.Client, cfg *config.Config) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	path := canary.DefaultPath()
+	trials, err := canary.Load(path)
+	if err != nil || len(trials) == 0 {
+		return err
+	}
+
+	var pending []canary.Trial
+	defer func() {
+		if len(pending) != len(trials) {
+			if err := canary.Save(path, pending); err != nil {
+				fmt.Printf("%s %v\n", red(iconFail), err)
+			}
+		}
+	}()
+
+	for i, trial := range trials {
+		verdict, reason, err := checkCanary(client, cfg, trial)
+		if err != nil {
+			pending = append(pending, trials[i:]...)
+			return err
+		}
+
+		switch verdict {
+		case canary.Pending:
+			pending = append(pending, trial)
+		case canary.Failed:
+			fmt.Printf("%s Canary of %s failed: %s; the other %d instances were not dispatched\n", red(iconFail), trial.Name(), reason, trial.Instances-canary.Instance)
+			postCanary(client, cfg, trial, fmt.Sprintf("🐤 **Canary failed**: %s. The remaining %d instances were not dispatched.", reason, trial.Instances-canary.Instance))
+		case canary.Passed:
+			if err := fanOut(client, trial); err != nil {
+				pending = append(pending, trials[i:]...)
+				return err
+			}
+			fmt.Printf("%s Canary of %s passed; dispatched instances %d-%d\n", green(iconOK), trial.Name(), canary.Instance+1, trial.Instances)
+			postCanary(client, cfg, trial, fmt.Sprintf("🐤 **Canary passed**. Dispatched instances %d-%d.", canary.Instance+1, trial.Instances))
+		}
+	}
+	return nil
+}
+
+// checkCanary judges a trial from its coordination messages; a canary
+// workflow run that ended unsuccessfully without a verdict has failed
+func checkCanary(client *github.Client, cfg *config.Config, trial canary.Trial) (canary.Verdict, string, error) {
+	store, err := coordinationStore(client, cfg, trial.Issue, trial.RunID)
+	if err != nil || store == nil {
+		return canary.Pending, "", err
+	}
+	snap, _, err := readCoordination(store)
+	if err != nil {
+		return canary.Pending, "", err
+	}
+	if cfg.Completion.RequireResultSchema {
+		snap.RequireSchema()
+	}
+
+	verdict, reason := canary.Check(snap, cfg.Canary.MinCoverage)
+	if verdict != canary.Pending {
+		return verdict, reason, nil
+	}
+
+	run, err := storeWorkflowRun(client, store)
+	if err != nil {
+		return canary.Pending, "", err
+	}
+	if run != nil && run.Status == "completed" && run.Conclusion != "success" {
+		return canary.Failed, "workflow run " + run.Conclusion, nil
+	}
+	return canary.Pending, "", nil
+}
+
+// fanOut dispatches the instances after the canary for a trial's run
+func fanOut(client *github.Client, trial canary.Trial) error {
+	inputs := map[string]string{"first_instance": strconv.Itoa(canary.Instance + 1)}
+	for name, value := range trial.Inputs {
+		inputs[name] = value
+	}
+
+	var err error
+	if trial.Issue > 0 {
+		_, err = client.TriggerWorkflow(trial.Issue, trial.Instances, inputs)
+	} else {
+		_, err = client.DispatchRun(trial.RunID, trial.Instances, inputs)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to dispatch instances after canary of %s: %w", trial.Name(), err)
+	}
+	return nil
+}
+
+// postCanary reports a canary verdict to the run's coordination issue or
+// state branch run; failing to do so only warns
+func postCanary(client *github.Client, cfg *config.Config, trial canary.Trial, message string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	store, err := coordinationStore(client, cfg, trial.Issue, trial.RunID)
+	if err == nil && store != nil {
+		err = store.Post(message)
+	}
+	if err != nil {
+		fmt.Printf("%s Warning: failed to report canary verdict: %v\n", yellow(iconWarn), err)
+	}
+}
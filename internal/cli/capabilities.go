@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/coordination"
+	"github.com/autonomous-dev/cli/internal/planner"
+	"github.com/autonomous-dev/cli/internal/scheduler"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+	"github.com/fatih/color"
+)
+
+// printCapabilities prints the instances' capability probes and the heavy
+// tasks that should move off constrained runners. With rebalance the moves
+// are posted as new task assignments.
+func printCapabilities(snap *protocol.Snapshot, cfg *config.Config, store coordination.Store, rebalance bool) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	if len(snap.Capabilities) == 0 {
+		if rebalance {
+			fmt.Printf("%s No capability probes reported; nothing to rebalance\n", yellow(iconWarn))
+		}
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println(bold("Capabilities:"))
+	printProbes(snap, cfg)
+
+	// Weigh pending work by estimated complexity; without an analyzer every task weighs 1
+	analyzer, _ := planner.New(cfg.Planner, "", ".", cfg.Instances.Max, responseCache(cfg))
+	plan := scheduler.PlanReassignments(snap, cfg.Capabilities, func(description string) int {
+		return planner.Weight(analyzer, description)
+	})
+	if len(plan) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	for _, move := range plan {
+		fmt.Printf("%s %s: instance %d %s instance %d (heavy task on a constrained runner)\n", yellow(iconWarn), move.TaskID, move.From, iconArrow, move.To)
+	}
+	if !rebalance {
+		fmt.Println("  Move them with: autonomous-dev status --rebalance")
+		return nil
+	}
+
+	for _, move := range plan {
+		moved := *snap.Assignments[move.TaskID]
+		moved.Version = protocol.Version
+		moved.InstanceID = move.To
+		body, err := protocol.Render(move.To, &moved)
+		if err != nil {
+			return err
+		}
+		if err := store.Post(body); err != nil {
+			return fmt.Errorf("failed to reassign %s: %w", move.TaskID, err)
+		}
+		fmt.Printf("%s Reassigned %s to instance %d\n", green(iconOK), move.TaskID, move.To)
+	}
+	return nil
+}
+
+// printProbes prints one line per probed instance and returns how many are constrained
+func printProbes(snap *protocol.Snapshot, cfg *config.Config) int {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	ids := make([]int, 0, len(snap.Capabilities))
+	for id := range snap.Capabilities {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	constrained := 0
+	for _, id := range ids {
+		caps := snap.Capabilities[id]
+		icon := green(iconOK)
+		detail := ""
+		if reasons := scheduler.Constraints(caps, cfg.Capabilities); len(reasons) > 0 {
+			icon = yellow(iconWarn)
+			detail = " - constrained: " + strings.Join(reasons, ", ")
+			constrained++
+		}
+		fmt.Printf("%s Instance %d: %s, %d CPU(s), %d MB%s%s\n", icon, id, probeRunner(caps), caps.CPUs, caps.MemoryMB, probeToolchains(caps), detail)
+	}
+	return constrained
+}
+
+// probeRunner describes the runner a probe ran on
+func probeRunner(caps *protocol.Capabilities) string {
+	switch {
+	case caps.Runner != "" && caps.OS != "":
+		return fmt.Sprintf("%s (%s)", caps.Runner, caps.OS)
+	case caps.Runner != "":
+		return caps.Runner
+	case caps.OS != "":
+		return caps.OS
+	}
+	return "unknown runner"
+}
+
+// probeToolchains lists the probed toolchain versions, sorted by name
+func probeToolchains(caps *protocol.Capabilities) string {
+	if len(caps.Toolchains) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(caps.Toolchains))
+	for name := range caps.Toolchains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := make([]string, 0, len(names))
+	for _, name := range names {
+		tools = append(tools, name+" "+caps.Toolchains[name])
+	}
+	return ", " + strings.Join(tools, ", ")
+}
+
+// runRemoteDoctor reports the capability probes of the latest run's instances
+func runRemoteDoctor() error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := newClient(cfg)
+
+	issue := doctorIssue
+	if issue == 0 && !cfg.Coordination.Dispatch() {
+		run, err := client.GetLatestWorkflowRun()
+		if err != nil {
+			return fmt.Errorf("failed to get workflow run: %w", err)
+		}
+		if run != nil {
+			issue = runIssue(client, run)
+		}
+	}
+	store, err := coordinationStore(client, cfg, issue, "")
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return fmt.Errorf("no run to read probes from (use --issue)")
+	}
+
+	snap, _, err := readCoordination(store)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(bold("Runner capabilities (" + store.Location() + ")"))
+	if len(snap.Capabilities) == 0 {
+		fmt.Printf("%s No capability probes reported yet; instances probe when they start\n", yellow(iconWarn))
+		return nil
+	}
+	constrained := printProbes(snap, cfg)
+
+	fmt.Println()
+	if constrained > 0 {
+		fmt.Printf("%s %d constrained runner(s): heavy tasks (%s) should run elsewhere\n", yellow(iconWarn), constrained, strings.Join(cfg.Capabilities.Keywords(), ", "))
+		return nil
+	}
+	fmt.Println(green(iconOK), bold("All runners meet capabilities thresholds"))
+	return nil
+}
@@ -2,8 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/logstream"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -31,7 +34,8 @@ func configSetCmd() *cobra.Command {
 			key := args[0]
 			value := args[1]
 
-			cfg, err := config.Load(config.ConfigPath())
+			// Edit only the nearest file so package configs keep inheriting
+			cfg, err := config.LoadFile(config.ConfigPath())
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
@@ -44,6 +48,216 @@ func configSetCmd() *cobra.Command {
 				cfg.GitHub.Repo = value
 			case "github.token":
 				cfg.GitHub.Token = value
+			case "github.token_expiry_warn_days":
+				days, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.GitHub.TokenExpiryWarnDays = days
+			case "github.commit_status":
+				enabled, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.GitHub.CommitStatus = enabled
+			case "display.timezone":
+				cfg.Display.Timezone = value
+				if _, err := cfg.Display.Location(); err != nil {
+					return err
+				}
+			case "templates.org_url":
+				cfg.Templates.OrgURL = value
+			case "notifications.webhook_url":
+				cfg.Notifications.WebhookURL = value
+			case "notifications.statuspage.page_id":
+				cfg.Notifications.Statuspage.PageID = value
+			case "notifications.statuspage.component_id":
+				cfg.Notifications.Statuspage.ComponentID = value
+			case "notifications.statuspage.api_key":
+				cfg.Notifications.Statuspage.APIKey = value
+			case "notifications.statuspage.url":
+				cfg.Notifications.Statuspage.URL = value
+			case "runners.labels":
+				cfg.Runners.Labels = splitList(value)
+			case "context.sources":
+				cfg.Context.Sources = splitList(value)
+			case "coordination.mode":
+				if value != config.CoordinationIssues && value != config.CoordinationDispatch {
+					return fmt.Errorf("invalid value for %s: must be %s or %s", key, config.CoordinationIssues, config.CoordinationDispatch)
+				}
+				cfg.Coordination.Mode = value
+			case "coordination.state_branch":
+				cfg.Coordination.StateBranch = value
+			case "coordination.voters":
+				cfg.Coordination.Voters = splitList(value)
+			case "telemetry.enabled":
+				enabled, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.Telemetry.Enabled = enabled
+			case "telemetry.endpoint":
+				cfg.Telemetry.Endpoint = value
+			case "provider.max_turns", "provider.max_tokens_per_instance":
+				limit, err := strconv.Atoi(value)
+				if err != nil || limit < 0 {
+					return fmt.Errorf("invalid value for %s: must be a non-negative integer", key)
+				}
+				if key == "provider.max_turns" {
+					cfg.Provider.MaxTurns = limit
+				} else {
+					cfg.Provider.MaxTokensPerInstance = limit
+				}
+			case "completion.close_on_success":
+				enabled, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.Completion.CloseOnSuccess = enabled
+			case "completion.success_label":
+				cfg.Completion.SuccessLabel = value
+			case "completion.failure_label":
+				cfg.Completion.FailureLabel = value
+			case "completion.require_result_schema":
+				enabled, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.Completion.RequireResultSchema = enabled
+			case "capabilities.min_memory_mb":
+				mb, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.Capabilities.MinMemoryMB = mb
+			case "capabilities.min_cpus":
+				cpus, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.Capabilities.MinCPUs = cpus
+			case "capabilities.heavy_keywords":
+				cfg.Capabilities.HeavyKeywords = splitList(value)
+			case "planner.analyzer":
+				if value != config.AnalyzerHeuristic && value != config.AnalyzerLLM {
+					return fmt.Errorf("invalid value for %s: must be %s or %s", key, config.AnalyzerHeuristic, config.AnalyzerLLM)
+				}
+				cfg.Planner.Analyzer = value
+			case "planner.model":
+				cfg.Planner.Model = value
+			case "planner.api_key":
+				cfg.Planner.APIKey = value
+			case "planner.cache_ttl_hours":
+				hours, err := strconv.Atoi(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.Planner.CacheTTLHours = hours
+			case "branches.prefix":
+				cfg.Branches.Prefix = value
+			case "branches.auto_merge":
+				enabled, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.Branches.AutoMerge = enabled
+			case "prs.draft":
+				enabled, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.PRs.Draft = enabled
+			case "deployments.environment":
+				cfg.Deployments.Environment = value
+			case "deployments.keywords":
+				cfg.Deployments.Keywords = splitList(value)
+			case "logs.level":
+				if _, err := logstream.ParseLevel(value); err != nil {
+					return err
+				}
+				cfg.Logs.Level = value
+			case "logs.format":
+				if value != config.LogFormatJSON && value != config.LogFormatText {
+					return fmt.Errorf("invalid value for %s: must be %s or %s", key, config.LogFormatJSON, config.LogFormatText)
+				}
+				cfg.Logs.Format = value
+			case "logs.status.tail", "logs.status.lines", "logs.status.max_bytes":
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 1 {
+					return fmt.Errorf("invalid value for %s: must be a positive integer", key)
+				}
+				switch key {
+				case "logs.status.tail":
+					cfg.Logs.Status.Tail = n
+				case "logs.status.lines":
+					cfg.Logs.Status.Lines = n
+				default:
+					cfg.Logs.Status.MaxBytes = n
+				}
+			case "logs.status.include":
+				if value != config.PreviewAlways && value != config.PreviewFailures && value != config.PreviewNever {
+					return fmt.Errorf("invalid value for %s: must be %s, %s or %s", key, config.PreviewAlways, config.PreviewFailures, config.PreviewNever)
+				}
+				cfg.Logs.Status.Include = value
+			case "logs.status.truncate":
+				if value != config.PreviewTail && value != config.PreviewHeadTail && value != config.PreviewErrors {
+					return fmt.Errorf("invalid value for %s: must be %s, %s or %s", key, config.PreviewTail, config.PreviewHeadTail, config.PreviewErrors)
+				}
+				cfg.Logs.Status.Truncate = value
+			case "canary.min_coverage":
+				coverage, err := strconv.ParseFloat(value, 64)
+				if err != nil || coverage < 0 || coverage > 100 {
+					return fmt.Errorf("invalid value for %s: must be a percentage between 0 and 100", key)
+				}
+				cfg.Canary.MinCoverage = coverage
+			case "hooks.pre_start":
+				cfg.Hooks.PreStart = value
+			case "hooks.post_complete":
+				cfg.Hooks.PostComplete = value
+			case "audit.secrets":
+				if value != config.AuditBlock && value != config.AuditWarn && value != config.AuditOff {
+					return fmt.Errorf("invalid value for %s: must be %s, %s or %s", key, config.AuditBlock, config.AuditWarn, config.AuditOff)
+				}
+				cfg.Audit.Secrets = value
+			case "network.timeouts.read", "network.timeouts.list", "network.timeouts.dispatch", "network.timeouts.logs":
+				if _, err := config.ParseTimeout(value); err != nil {
+					return err
+				}
+				switch key {
+				case "network.timeouts.read":
+					cfg.Network.Timeouts.Read = value
+				case "network.timeouts.list":
+					cfg.Network.Timeouts.List = value
+				case "network.timeouts.dispatch":
+					cfg.Network.Timeouts.Dispatch = value
+				default:
+					cfg.Network.Timeouts.Logs = value
+				}
+			case "network.polling.idle":
+				if _, err := config.ParseTimeout(value); err != nil {
+					return err
+				}
+				cfg.Network.Polling.Idle = value
+			case "network.polling.jitter":
+				jitter, err := strconv.ParseFloat(value, 64)
+				if err != nil || jitter < 0 || jitter > 1 {
+					return fmt.Errorf("invalid value for %s: must be a share between 0 and 1", key)
+				}
+				cfg.Network.Polling.Jitter = &jitter
+			case "network.polling.fixed":
+				fixed, err := strconv.ParseBool(value)
+				if err != nil {
+					return fmt.Errorf("invalid value for %s: %w", key, err)
+				}
+				cfg.Network.Polling.Fixed = fixed
+			case "trackers.jira.url":
+				cfg.Trackers.Jira.URL = value
+			case "trackers.jira.email":
+				cfg.Trackers.Jira.Email = value
+			case "trackers.jira.token":
+				cfg.Trackers.Jira.Token = value
+			case "trackers.linear.api_key":
+				cfg.Trackers.Linear.APIKey = value
 			default:
 				return fmt.Errorf("unknown config key: %s", key)
 			}
@@ -54,7 +268,7 @@ func configSetCmd() *cobra.Command {
 			}
 
 			green := color.New(color.FgGreen).SprintFunc()
-			fmt.Printf("%s Set %s = %s\n", green("✓"), key, value)
+			fmt.Printf("%s Set %s = %s\n", green(iconOK), key, value)
 
 			return nil
 		},
@@ -83,6 +297,121 @@ func configGetCmd() *cobra.Command {
 				value = cfg.GitHub.Repo
 			case "github.token":
 				value = cfg.GitHub.Token
+			case "github.token_expiry_warn_days":
+				value = fmt.Sprint(cfg.GitHub.TokenExpiryWarnDays)
+			case "github.commit_status":
+				value = fmt.Sprint(cfg.GitHub.CommitStatus)
+			case "display.timezone":
+				value = cfg.Display.Timezone
+			case "templates.org_url":
+				value = cfg.Templates.OrgURL
+			case "notifications.webhook_url":
+				value = cfg.Notifications.WebhookURL
+			case "notifications.statuspage.page_id":
+				value = cfg.Notifications.Statuspage.PageID
+			case "notifications.statuspage.component_id":
+				value = cfg.Notifications.Statuspage.ComponentID
+			case "notifications.statuspage.api_key":
+				value = cfg.Notifications.Statuspage.APIKey
+			case "notifications.statuspage.url":
+				value = cfg.Notifications.Statuspage.URL
+			case "runners.labels":
+				value = strings.Join(cfg.Runners.Labels, ",")
+			case "context.sources":
+				value = strings.Join(cfg.Context.Sources, ",")
+			case "coordination.mode":
+				value = cfg.Coordination.Mode
+			case "coordination.state_branch":
+				value = cfg.Coordination.StateBranch
+			case "coordination.voters":
+				value = strings.Join(cfg.Coordination.Voters, ",")
+			case "telemetry.enabled":
+				value = fmt.Sprint(cfg.Telemetry.Enabled)
+			case "telemetry.endpoint":
+				value = cfg.Telemetry.Endpoint
+			case "provider.max_turns":
+				value = strconv.Itoa(cfg.Provider.MaxTurns)
+			case "provider.max_tokens_per_instance":
+				value = strconv.Itoa(cfg.Provider.MaxTokensPerInstance)
+			case "completion.close_on_success":
+				value = strconv.FormatBool(cfg.Completion.CloseOnSuccess)
+			case "completion.success_label":
+				value = cfg.Completion.SuccessLabel
+			case "completion.failure_label":
+				value = cfg.Completion.FailureLabel
+			case "completion.require_result_schema":
+				value = strconv.FormatBool(cfg.Completion.RequireResultSchema)
+			case "capabilities.min_memory_mb":
+				value = strconv.Itoa(cfg.Capabilities.MinMemory())
+			case "capabilities.min_cpus":
+				value = strconv.Itoa(cfg.Capabilities.MinCPU())
+			case "capabilities.heavy_keywords":
+				value = strings.Join(cfg.Capabilities.Keywords(), ",")
+			case "planner.analyzer":
+				value = cfg.Planner.Analyzer
+				if value == "" {
+					value = config.AnalyzerHeuristic
+				}
+			case "planner.model":
+				value = cfg.Planner.LLMModel()
+			case "planner.api_key":
+				value = cfg.Planner.APIKey
+			case "planner.cache_ttl_hours":
+				value = strconv.Itoa(int(cfg.Planner.CacheTTL().Hours()))
+			case "branches.prefix":
+				value = cfg.Branches.InstancePrefix()
+			case "branches.auto_merge":
+				value = strconv.FormatBool(cfg.Branches.AutoMerge)
+			case "prs.draft":
+				value = strconv.FormatBool(cfg.PRs.Draft)
+			case "deployments.environment":
+				value = cfg.Deployments.Environment
+			case "deployments.keywords":
+				value = strings.Join(cfg.Deployments.Keywords, ",")
+			case "logs.level":
+				value = cfg.Logs.InstanceLevel()
+			case "logs.format":
+				value = cfg.Logs.InstanceFormat()
+			case "logs.status.tail":
+				value = strconv.Itoa(cfg.Logs.Status.TailLines())
+			case "logs.status.lines":
+				value = strconv.Itoa(cfg.Logs.Status.PreviewLines())
+			case "logs.status.max_bytes":
+				value = strconv.Itoa(cfg.Logs.Status.PreviewMaxBytes())
+			case "logs.status.include":
+				value = cfg.Logs.Status.IncludePolicy()
+			case "logs.status.truncate":
+				value = cfg.Logs.Status.Truncation()
+			case "canary.min_coverage":
+				value = strconv.FormatFloat(cfg.Canary.MinCoverage, 'f', -1, 64)
+			case "hooks.pre_start":
+				value = cfg.Hooks.PreStart
+			case "hooks.post_complete":
+				value = cfg.Hooks.PostComplete
+			case "audit.secrets":
+				value = cfg.Audit.SecretsMode()
+			case "network.timeouts.read":
+				value = cfg.Network.Timeouts.ReadTimeout().String()
+			case "network.timeouts.list":
+				value = cfg.Network.Timeouts.ListTimeout().String()
+			case "network.timeouts.dispatch":
+				value = cfg.Network.Timeouts.DispatchTimeout().String()
+			case "network.timeouts.logs":
+				value = cfg.Network.Timeouts.LogsTimeout().String()
+			case "network.polling.idle":
+				value = cfg.Network.Polling.IdleInterval().String()
+			case "network.polling.jitter":
+				value = strconv.FormatFloat(cfg.Network.Polling.JitterShare(), 'f', -1, 64)
+			case "network.polling.fixed":
+				value = strconv.FormatBool(cfg.Network.Polling.Fixed)
+			case "trackers.jira.url":
+				value = cfg.Trackers.Jira.URL
+			case "trackers.jira.email":
+				value = cfg.Trackers.Jira.Email
+			case "trackers.jira.token":
+				value = cfg.Trackers.Jira.Token
+			case "trackers.linear.api_key":
+				value = cfg.Trackers.Linear.APIKey
 			default:
 				return fmt.Errorf("unknown config key: %s", key)
 			}
@@ -112,6 +441,8 @@ func configListCmd() *cobra.Command {
 			fmt.Printf("  owner: %s\n", cyan(cfg.GitHub.Owner))
 			fmt.Printf("  repo: %s\n", cyan(cfg.GitHub.Repo))
 			fmt.Printf("  token: %s\n", maskToken(cfg.GitHub.Token))
+			fmt.Printf("  token_expiry_warn_days: %s\n", cyan(fmt.Sprint(cfg.GitHub.TokenExpiryWarnDays)))
+			fmt.Printf("  commit_status: %s\n", cyan(fmt.Sprint(cfg.GitHub.CommitStatus)))
 			fmt.Println()
 			fmt.Printf("Instances:\n")
 			fmt.Printf("  default: %s\n", cyan(fmt.Sprint(cfg.Instances.Default)))
@@ -120,12 +451,26 @@ func configListCmd() *cobra.Command {
 			fmt.Printf("Workflow:\n")
 			fmt.Printf("  file: %s\n", cyan(cfg.Workflow.File))
 			fmt.Printf("  concurrency: %s\n", cyan(fmt.Sprint(cfg.Workflow.Concurrency)))
+			fmt.Println()
+			fmt.Printf("Display:\n")
+			fmt.Printf("  timezone: %s\n", cyan(cfg.Display.Timezone))
 
 			return nil
 		},
 	}
 }
 
+// splitList parses a comma-separated config value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func maskToken(token string) string {
 	if token == "" || token == "${GITHUB_TOKEN}" {
 		return color.YellowString("(not set)")
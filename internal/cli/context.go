@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/autonomous-dev/cli/internal/bundle"
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func ContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Build the context bundle from repository files",
+		Long: `Build a context bundle from the files, directories and glob patterns in
+context.sources, stored in .autonomous-dev/context/bundle.md.
+
+Each source file's content hash is tracked, so a refresh only regenerates the
+sections of changed files. start refreshes the bundle automatically and adds
+a "Changes Since Last Run" section to the coordination issue listing files
+added, modified or removed since the previous run.`,
+	}
+
+	cmd.AddCommand(contextBuildCmd())
+	cmd.AddCommand(contextShowCmd())
+
+	return cmd
+}
+
+func contextBuildCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "build",
+		Short: "Refresh the context bundle and show changes since the last run",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			green := color.New(color.FgGreen).SprintFunc()
+			bold := color.New(color.Bold).SprintFunc()
+
+			cfg, err := config.Load(config.ConfigPath())
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			if len(cfg.Context.Sources) == 0 {
+				return fmt.Errorf("context.sources is not set (e.g. autonomous-dev config set context.sources README.md,docs)")
+			}
+
+			dir := bundle.DefaultDir()
+			result, err := bundle.Build(dir, cfg.Context.Sources, bundle.Options{MaxFileBytes: cfg.Context.MaxFileBytes})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("%s Built %s (%d files, %d sections regenerated)\n", green(iconOK), bundle.BundlePath(dir), result.Files, result.Regenerated)
+
+			if result.Delta.Since.IsZero() {
+				fmt.Println("No previous run; changes are tracked from the next start")
+				return nil
+			}
+			fmt.Println()
+			fmt.Printf("%s (%s)\n", bold("Changes since last run"), formatTime(result.Delta.Since, cfg))
+			if result.Delta.Empty() {
+				fmt.Println("  None")
+				return nil
+			}
+			fmt.Print(result.Delta.Markdown())
+
+			return nil
+		},
+	}
+}
+
+func contextShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Print the last built context bundle",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content, err := os.ReadFile(bundle.BundlePath(bundle.DefaultDir()))
+			if os.IsNotExist(err) {
+				return fmt.Errorf("no context bundle yet (run 'autonomous-dev context build')")
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read context bundle: %w", err)
+			}
+			fmt.Print(string(content))
+			return nil
+		},
+	}
+}
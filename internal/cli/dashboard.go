@@ -2,26 +2,64 @@ package cli
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/dashboard"
+	"github.com/autonomous-dev/cli/internal/queue"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var (
+	dashboardGenerate bool
+	dashboardOutput   string
+	dashboardRuns     int
+	dashboardBranch   string
+)
+
 func DashboardCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "dashboard",
 		Short: "Open the monitoring dashboard in browser",
 		Long: `Open the autonomous development dashboard in your default browser.
 
 If the dashboard is deployed to GitHub Pages, it will open the deployed URL.
-Otherwise, it will open the local dashboard/index.html file.`,
+Otherwise, it will open the local dashboard/index.html file.
+
+With --generate, a static dashboard site is built from the latest run data
+instead. Use 'dashboard publish' to push it to the gh-pages branch.`,
 		RunE: runDashboard,
 	}
+
+	cmd.Flags().BoolVar(&dashboardGenerate, "generate", false, "Generate the static dashboard site instead of opening it")
+	cmd.Flags().StringVarP(&dashboardOutput, "output", "o", filepath.Join(".autonomous-dev", "site"), "Output directory for --generate")
+	cmd.PersistentFlags().IntVar(&dashboardRuns, "runs", 20, "Number of recent runs to export")
+
+	cmd.AddCommand(dashboardPublishCmd())
+
+	return cmd
+}
+
+func dashboardPublishCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "publish",
+		Short: "Publish the static dashboard to GitHub Pages",
+		Long: `Generate the static dashboard site and commit it to the gh-pages branch
+via the GitHub API. The branch is created if it does not exist.
+
+Enable GitHub Pages for the branch once in Settings → Pages.`,
+		RunE: runDashboardPublish,
+	}
+
+	cmd.Flags().StringVar(&dashboardBranch, "branch", "gh-pages", "Branch to publish to")
+
+	return cmd
 }
 
 func runDashboard(cmd *cobra.Command, args []string) error {
@@ -33,36 +71,106 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if dashboardGenerate {
+		files, err := buildDashboardSite(cfg)
+		if err != nil {
+			return err
+		}
+		if err := dashboard.WriteDir(dashboardOutput, files); err != nil {
+			return fmt.Errorf("failed to write dashboard site: %w", err)
+		}
+		fmt.Printf("%s Generated dashboard in %s\n", green(iconOK), dashboardOutput)
+		return nil
+	}
+
 	// Try GitHub Pages URL first
 	ghPagesURL := fmt.Sprintf("https://%s.github.io/%s/", cfg.GitHub.Owner, cfg.GitHub.Repo)
 
 	// Check if dashboard/index.html exists locally
 	localDashboard := "dashboard/index.html"
 	if _, err := os.Stat(localDashboard); err == nil {
-		fmt.Printf("%s Opening local dashboard: %s\n", green("✓"), localDashboard)
-		return openBrowser("file://" + getAbsPath(localDashboard))
+		fmt.Printf("%s Opening local dashboard: %s\n", green(iconOK), localDashboard)
+		return openBrowser(fileURL(getAbsPath(localDashboard)))
 	}
 
 	// Fallback to GitHub Pages
-	fmt.Printf("%s Opening GitHub Pages dashboard: %s\n", green("✓"), ghPagesURL)
+	fmt.Printf("%s Opening GitHub Pages dashboard: %s\n", green(iconOK), ghPagesURL)
 	return openBrowser(ghPagesURL)
 }
 
+func runDashboardPublish(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	// Load config
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	files, err := buildDashboardSite(cfg)
+	if err != nil {
+		return err
+	}
+
+	client := newClient(cfg)
+	sha, err := client.CommitFiles(dashboardBranch, "Update autonomous-dev dashboard", files)
+	if err != nil {
+		return fmt.Errorf("failed to publish dashboard: %w", err)
+	}
+
+	fmt.Printf("%s Published dashboard to %s (%s)\n", green(iconOK), dashboardBranch, sha[:7])
+	fmt.Printf("  URL: %s\n", cyan(fmt.Sprintf("https://%s.github.io/%s/", cfg.GitHub.Owner, cfg.GitHub.Repo)))
+
+	return nil
+}
+
+func buildDashboardSite(cfg *config.Config) (map[string][]byte, error) {
+	client := newClient(cfg)
+
+	export, err := dashboard.Build(client, cfg.GitHub.Owner+"/"+cfg.GitHub.Repo, dashboardRuns)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export run data: %w", err)
+	}
+	entries, err := queue.Open(queue.DefaultDir()).List()
+	if err != nil {
+		return nil, err
+	}
+	export.Queue = dashboard.ExportQueue(entries)
+
+	return dashboard.Files(export)
+}
+
 func openBrowser(url string) error {
-	var cmd *exec.Cmd
+	name, args, err := browserCommand(runtime.GOOS)
+	if err != nil {
+		return err
+	}
+	return exec.Command(name, append(args, url)...).Start()
+}
 
-	switch runtime.GOOS {
+// browserCommand returns the command that opens a URL in the default browser
+func browserCommand(goos string) (string, []string, error) {
+	switch goos {
 	case "darwin":
-		cmd = exec.Command("open", url)
+		return "open", nil, nil
 	case "linux":
-		cmd = exec.Command("xdg-open", url)
+		return "xdg-open", nil, nil
 	case "windows":
-		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		return "rundll32", []string{"url.dll,FileProtocolHandler"}, nil
 	default:
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+		return "", nil, fmt.Errorf("unsupported platform: %s", goos)
 	}
+}
 
-	return cmd.Start()
+// fileURL converts an absolute path to a file:// URL, e.g. C:\site\index.html
+// to file:///C:/site/index.html on Windows
+func fileURL(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
 }
 
 func getAbsPath(path string) string {
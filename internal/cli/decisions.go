@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/coordination"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+	"github.com/fatih/color"
+)
+
+// printDecisions tallies and prints the decisions posted to a coordination
+// store. Reaction votes are only available on issue comments and only
+// count for voters.
+func printDecisions(client *github.Client, store coordination.Store, snap *protocol.Snapshot, voters []string) error {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	fmt.Println()
+	fmt.Println(bold("Decisions:"))
+	if len(snap.Decisions) == 0 {
+		fmt.Println("  No decisions posted")
+		return nil
+	}
+
+	var reactions map[string]map[string]string
+	if issueStore, ok := store.(*coordination.IssueStore); ok && len(voters) > 0 {
+		var err error
+		reactions, err = decisionReactions(client, issueStore.Issue(), voters)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, id := range snap.DecisionIDs() {
+		tally := snap.Tally(id, reactions[id])
+
+		state := color.YellowString("open")
+		switch {
+		case tally.Winner != "":
+			state = color.GreenString("decided: " + tally.Winner)
+		case tally.Reached():
+			state = color.RedString("tied")
+		}
+
+		fmt.Printf("%s %s (%d/%d votes) %s\n", id, tally.Decision.Question, tally.Votes, tally.Quorum, state)
+		for i, option := range tally.Decision.Options {
+			fmt.Printf("    :%s: %-20s %d\n", protocol.ReactionOptions[i], option, tally.Counts[option])
+		}
+		for _, invalid := range tally.Invalid {
+			fmt.Printf("    %s %s\n", yellow(iconWarn), invalid)
+		}
+	}
+
+	return nil
+}
+
+// decisionReactions collects the reaction votes of voters on decision
+// comments, keyed by decision ID and user. Users who react with more than
+// one option abstain.
+func decisionReactions(client *github.Client, issueNumber int, voters []string) (map[string]map[string]string, error) {
+	allowed := make(map[string]bool, len(voters))
+	for _, voter := range voters {
+		allowed[strings.ToLower(voter)] = true
+	}
+
+	comments, err := client.ListIssueComments(issueNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read coordination issue: %w", err)
+	}
+
+	result := make(map[string]map[string]string)
+	for _, comment := range comments {
+		if !strings.Contains(comment.Body, string(protocol.KindDecision)+":START") {
+			continue
+		}
+		blocks, err := protocol.Parse(comment.Body)
+		if err != nil {
+			continue
+		}
+
+		var ids []string
+		for i := range blocks {
+			var d protocol.Decision
+			if blocks[i].Kind == protocol.KindDecision && blocks[i].Decode(&d) == nil {
+				ids = append(ids, d.DecisionID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		reactions, err := client.ListCommentReactions(comment.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		votes := make(map[string]string)
+		conflicting := make(map[string]bool)
+		for _, r := range reactions {
+			if !isReactionOption(r.Content) || !allowed[strings.ToLower(r.User)] {
+				continue
+			}
+			if previous, ok := votes[r.User]; ok && previous != r.Content {
+				conflicting[r.User] = true
+			}
+			votes[r.User] = r.Content
+		}
+		for user := range conflicting {
+			delete(votes, user)
+		}
+
+		for _, id := range ids {
+			result[id] = votes
+		}
+	}
+
+	return result, nil
+}
+
+func isReactionOption(content string) bool {
+	for _, option := range protocol.ReactionOptions {
+		if option == content {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/overlap"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+)
+
+// maxDiffDirs caps the directories listed in a diff summary
+const maxDiffDirs = 4
+
+// instanceBranch returns the branch an instance reported results on, falling
+// back to its configured branch name
+func instanceBranch(cfg *config.Config, snap *protocol.Snapshot, instance int) string {
+	if snap != nil {
+		for _, taskID := range reportTaskIDs(snap) {
+			if r, ok := snap.Results[taskID]; ok && r.InstanceID == instance && r.Branch != "" {
+				return r.Branch
+			}
+		}
+	}
+	return cfg.Branches.InstanceBranch(instance)
+}
+
+// branchDiffs compares branches with the default branch. Branches that do
+// not exist or cannot be compared are left out.
+func branchDiffs(client *github.Client, branches []string) map[string]*github.DiffStats {
+	diffs := make(map[string]*github.DiffStats)
+	if len(branches) == 0 {
+		return diffs
+	}
+	repo, err := client.GetRepository()
+	if err != nil {
+		return diffs
+	}
+	for _, branch := range branches {
+		if _, done := diffs[branch]; done {
+			continue
+		}
+		if stats, err := client.CompareBranch(repo.DefaultBranch, branch); err == nil && stats != nil {
+			diffs[branch] = stats
+		}
+	}
+	return diffs
+}
+
+// diffSummary describes diff stats, e.g. "3 files, +120 -15 in cmd/, internal/"
+func diffSummary(stats *github.DiffStats) string {
+	files := "files"
+	if stats.Files == 1 {
+		files = "file"
+	}
+	summary := fmt.Sprintf("%d %s, +%d -%d", stats.Files, files, stats.Additions, stats.Deletions)
+
+	var dirs []string
+	for i, dir := range stats.Dirs {
+		if i == maxDiffDirs {
+			dirs = append(dirs, fmt.Sprintf("%d more", len(stats.Dirs)-maxDiffDirs))
+			break
+		}
+		if dir != "." {
+			dir += "/"
+		}
+		dirs = append(dirs, dir)
+	}
+	if len(dirs) > 0 {
+		summary += " in " + strings.Join(dirs, ", ")
+	}
+	return summary
+}
+
+// instanceOverlaps finds instances whose branches add the same code.
+// branches maps instances to their branches; instances sharing a branch are
+// compared once, and branches without a diff are skipped.
+func instanceOverlaps(branches map[int]string, diffs map[string]*github.DiffStats) []overlap.Overlap {
+	ids := make([]int, 0, len(branches))
+	for id := range branches {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var changes []overlap.Change
+	seen := map[string]bool{}
+	for _, id := range ids {
+		branch := branches[id]
+		stats, ok := diffs[branch]
+		if !ok || seen[branch] {
+			continue
+		}
+		seen[branch] = true
+		changes = append(changes, overlap.Change{Instance: id, Patches: stats.Patches})
+	}
+	return overlap.Detect(changes)
+}
+
+// blockOverlaps sets the overlap commit status on every compared instance
+// branch. The later instance of an overlapping pair fails it, so with the
+// check required only one of two duplicate changes can be merged. Statuses
+// are only written when the verdict changed, since status calls this on
+// every refresh.
+func blockOverlaps(client *github.Client, branches map[int]string, diffs map[string]*github.DiffStats, overlaps []overlap.Overlap, targetURL string) error {
+	duplicates := map[string]overlap.Overlap{}
+	for _, o := range overlaps {
+		if _, ok := duplicates[branches[o.B]]; !ok {
+			duplicates[branches[o.B]] = o
+		}
+	}
+
+	for branch := range diffs {
+		state, description := github.StateSuccess, "No overlap with other instances"
+		if o, ok := duplicates[branch]; ok {
+			state = github.StateFailure
+			description = fmt.Sprintf("Duplicates instance %d in %s; merge only one", o.A, strings.Join(o.Dirs, ", "))
+			if len(description) > 140 {
+				description = description[:137] + "..."
+			}
+		}
+		sha, err := client.GetBranchSHA(branch)
+		if err != nil {
+			return err
+		}
+		current, currentDescription, err := client.GetOverlapStatus(sha)
+		if err != nil {
+			return err
+		}
+		if current == state && currentDescription == description {
+			continue
+		}
+		if err := client.SetOverlapStatus(sha, state, description, targetURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
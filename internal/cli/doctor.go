@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/scripts"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorPlatform bool
+	doctorRemote   bool
+	doctorIssue    int
+)
+
+func DoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose configuration and GitHub access",
+		Long: `Run a series of checks against the local configuration and GitHub API:
+- Configuration file is present and valid
+- GitHub token is set, its type, and expiration date
+- Token has Actions write permission to dispatch the workflow
+- Rulesets and branch protection let instances push their branches and,
+  with branches.auto_merge, auto-merge their pull requests
+- Secrets and variables listed under requirements are set
+
+With --platform, check the local platform instead: required tools (git, gh,
+bash, PowerShell on Windows), the browser launcher, generated file paths and
+line endings of generated scripts.
+
+With --remote, report the capability probes instances of the latest run
+(or --issue) posted: runner, CPUs, memory, toolchains and provider
+reachability, flagging runners below the capabilities thresholds.
+
+Problems are reported with a suggested fix.`,
+		RunE: runDoctor,
+	}
+
+	cmd.Flags().BoolVar(&doctorPlatform, "platform", false, "Check the local platform: tools, paths and line endings")
+	cmd.Flags().BoolVar(&doctorRemote, "remote", false, "Report the runners' capability probes from the latest run")
+	cmd.Flags().IntVar(&doctorIssue, "issue", 0, "Coordination issue to read probes from with --remote (default: the latest run's issue)")
+
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	if doctorPlatform {
+		return runPlatformDoctor()
+	}
+	if doctorRemote {
+		return runRemoteDoctor()
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	fmt.Println(bold("Running diagnostics..."))
+	fmt.Println()
+
+	// Load config
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		fmt.Printf("%s Config: %v\n", red(iconFail), err)
+		return fmt.Errorf("doctor found problems (run 'autonomous-dev init' first)")
+	}
+	fmt.Printf("%s Config: %s\n", green(iconOK), config.ConfigPath())
+
+	if cfg.GitHub.Token == "" {
+		fmt.Printf("%s Token: not set (export GITHUB_TOKEN or run 'autonomous-dev config set github.token <token>')\n", red(iconFail))
+		return fmt.Errorf("doctor found problems")
+	}
+
+	client := newClient(cfg)
+
+	problems := 0
+
+	info, err := client.GetTokenInfo()
+	if err != nil {
+		fmt.Printf("%s Token: %v\n", red(iconFail), err)
+		return fmt.Errorf("doctor found problems")
+	}
+	fmt.Printf("%s Token: %s token for %s\n", green(iconOK), info.Kind, info.Login)
+	if len(info.Scopes) > 0 {
+		fmt.Printf("  scopes: %v\n", info.Scopes)
+	}
+
+	if warning := tokenExpiryWarning(info, cfg); warning != "" {
+		fmt.Printf("%s %s\n", yellow(iconWarn), warning)
+	} else if info.ExpiresAt != nil {
+		fmt.Printf("%s Token expires: %s\n", green(iconOK), info.ExpiresAt.Format("2006-01-02"))
+	} else {
+		fmt.Printf("%s Token expires: never\n", green(iconOK))
+	}
+
+	if err := client.CheckActionsWrite(); err != nil {
+		fmt.Printf("%s Actions: %v\n", red(iconFail), err)
+		problems++
+	} else {
+		fmt.Printf("%s Actions: workflow dispatch permitted\n", green(iconOK))
+	}
+
+	problems += checkBranchFlow(client, cfg)
+	problems += checkRequirements(client, cfg)
+
+	fmt.Println()
+	if problems > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", problems)
+	}
+	fmt.Println(green(iconOK), bold("All checks passed"))
+
+	return nil
+}
+
+// platformTool is an executable the CLI or generated scripts rely on
+type platformTool struct {
+	name     string
+	purpose  string
+	required bool
+}
+
+// platformTools lists the tools checked by doctor --platform for an OS
+func platformTools(goos string) []platformTool {
+	tools := []platformTool{
+		{name: "git", purpose: "repository detection and cloning", required: true},
+		{name: "gh", purpose: "repository secrets and the status reporter"},
+		{name: "bash", purpose: "instance-status-reporter.sh", required: goos != "windows"},
+	}
+	if goos == "windows" {
+		tools = append(tools, platformTool{name: "pwsh", purpose: "instance-status-reporter.ps1"})
+	}
+	return tools
+}
+
+// runPlatformDoctor checks that the local platform can run the CLI and its generated files
+func runPlatformDoctor() error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	fmt.Println(bold("Checking platform..."))
+	fmt.Println()
+	fmt.Printf("%s Platform: %s/%s\n", green(iconOK), runtime.GOOS, runtime.GOARCH)
+
+	problems := 0
+
+	for _, tool := range platformTools(runtime.GOOS) {
+		path, err := exec.LookPath(tool.name)
+		switch {
+		case err == nil:
+			fmt.Printf("%s %s: %s\n", green(iconOK), tool.name, path)
+		case tool.required:
+			fmt.Printf("%s %s: not found in PATH (needed for %s)\n", red(iconFail), tool.name, tool.purpose)
+			problems++
+		default:
+			fmt.Printf("%s %s: not found in PATH (needed for %s)\n", yellow(iconWarn), tool.name, tool.purpose)
+		}
+	}
+
+	if name, _, err := browserCommand(runtime.GOOS); err != nil {
+		fmt.Printf("%s Browser: %v (open the dashboard URL manually)\n", yellow(iconWarn), err)
+	} else if _, err := exec.LookPath(name); err != nil {
+		fmt.Printf("%s Browser: %s not found (open the dashboard URL manually)\n", yellow(iconWarn), name)
+	} else {
+		fmt.Printf("%s Browser: %s\n", green(iconOK), name)
+	}
+
+	// Generated files; paths in config use forward slashes on every OS
+	workflowFile := config.DefaultConfig().Workflow.File
+	if cfg, err := config.Load(config.ConfigPath()); err == nil {
+		workflowFile = cfg.Workflow.File
+	}
+	for _, path := range []string{workflowFile, scripts.StatusReporterPath} {
+		local := filepath.FromSlash(path)
+		content, err := os.ReadFile(local)
+		if os.IsNotExist(err) {
+			fmt.Printf("%s %s: not found\n", yellow(iconWarn), local)
+			continue
+		}
+		if err != nil {
+			fmt.Printf("%s %s: %v\n", red(iconFail), local, err)
+			problems++
+			continue
+		}
+		if bytes.Contains(content, []byte("\r\n")) {
+			fmt.Printf("%s %s: CRLF line endings break bash and YAML tooling\n", red(iconFail), local)
+			fmt.Println("  Fix: git config core.autocrlf input, then check the file out again")
+			problems++
+			continue
+		}
+		fmt.Printf("%s %s: LF line endings\n", green(iconOK), local)
+	}
+
+	if runtime.GOOS == "windows" {
+		if out, err := execCommand("git", "config", "--get", "core.autocrlf"); err == nil && strings.TrimSpace(string(out)) == "true" {
+			fmt.Printf("%s git core.autocrlf is true; scripts may be checked out with CRLF line endings\n", yellow(iconWarn))
+		}
+	}
+
+	fmt.Println()
+	if problems > 0 {
+		return fmt.Errorf("doctor found %d problem(s)", problems)
+	}
+	fmt.Println(green(iconOK), bold("Platform checks passed"))
+
+	return nil
+}
+
+// tokenExpiryWarning returns a warning message when the token expires soon
+func tokenExpiryWarning(info *github.TokenInfo, cfg *config.Config) string {
+	if !info.ExpiresWithin(cfg.GitHub.TokenExpiryWarning()) {
+		return ""
+	}
+
+	remaining := time.Until(*info.ExpiresAt)
+	if remaining <= 0 {
+		return fmt.Sprintf("Token expired on %s", info.ExpiresAt.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("Token expires in %d day(s) on %s", int(remaining.Hours()/24), info.ExpiresAt.Format("2006-01-02"))
+}
+
+// preflightToken fails fast when the token cannot dispatch workflows; other
+// errors of the check are left to the dispatch itself
+func preflightToken(client *github.Client) error {
+	if err := client.CheckActionsWrite(); errors.Is(err, github.ErrActionsWritePermission) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/planner"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	estimateTask     string
+	estimateAnalyzer string
+)
+
+func EstimateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "estimate",
+		Short: "Estimate the complexity of a task",
+		Long: `Estimate how complex a task is, which files it is likely to touch and
+how many instances it warrants.
+
+The analyzer is set by planner.analyzer: heuristic (default) matches the
+task's words against repository paths; llm asks a model, passing the path
+matches as hints. The same estimate sizes start --auto-size and weighs
+pending subtasks when status rebalances work.
+
+llm replies are cached in .autonomous-dev/cache/ by a hash of the model and
+prompt for planner.cache_ttl_hours (default 24), so repeated estimates of
+the same task cost no tokens. --no-cache asks the model again and replaces
+the cached reply.`,
+		Example: `  autonomous-dev estimate --task "Migrate the auth service to OAuth"
+  autonomous-dev estimate --task "Fix typo in README" --analyzer heuristic`,
+		RunE: runEstimate,
+	}
+
+	cmd.Flags().StringVarP(&estimateTask, "task", "t", "", "Task description")
+	cmd.Flags().StringVar(&estimateAnalyzer, "analyzer", "", "Analyzer to use: heuristic or llm (default planner.analyzer)")
+	cmd.MarkFlagRequired("task")
+
+	return cmd
+}
+
+func runEstimate(cmd *cobra.Command, args []string) error {
+	bold := color.New(color.Bold).SprintFunc()
+
+	// The heuristic analyzer works without a config; defaults apply
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	analyzer, err := planner.New(cfg.Planner, estimateAnalyzer, ".", cfg.Instances.Max, responseCache(cfg))
+	if err != nil {
+		return err
+	}
+	est, err := analyzer.Analyze(estimateTask)
+	if err != nil {
+		return err
+	}
+
+	source := analyzer.Name()
+	if est.Cached {
+		source += ", cached"
+	}
+	fmt.Println(bold("Estimate (" + source + "):"))
+	fmt.Printf("Complexity: %d/10\n", est.Score)
+	fmt.Printf("Suggested instances: %d\n", est.Instances)
+	if est.Reason != "" {
+		fmt.Printf("Reason: %s\n", est.Reason)
+	}
+	if len(est.Files) > 0 {
+		fmt.Printf("Files likely touched (%d):\n", len(est.Files))
+		fmt.Printf("  %s\n", strings.Join(est.Files, "\n  "))
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/bundle"
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/dashboard"
+	"github.com/autonomous-dev/cli/internal/events"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/history"
+	"github.com/autonomous-dev/cli/internal/hooks"
+	"github.com/autonomous-dev/cli/internal/notify"
+	"github.com/autonomous-dev/cli/internal/tracker"
+	"github.com/fatih/color"
+)
+
+// newEventBus creates an event bus with the built-in reactions subscribed.
+// Commands publish lifecycle events; integrations subscribe here instead of
+// being called from each command.
+func newEventBus(client *github.Client, cfg *config.Config) *events.Bus {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	bus := events.NewBus(func(e events.Event, err error) {
+		fmt.Printf("%s Warning: %s: %v\n", yellow(iconWarn), e.Name(), err)
+	})
+
+	subscribeHistory(bus, history.Open(history.DefaultDir()))
+	if cfg.GitHub.CommitStatus {
+		subscribeCommitStatus(bus, client)
+	}
+	if cfg.Notifications.WebhookURL != "" {
+		subscribeWebhook(bus, notify.NewWebhook(cfg.Notifications.WebhookURL))
+	}
+	if sp := cfg.Notifications.Statuspage; sp.Enabled() {
+		subscribeStatuspage(bus, history.Open(history.DefaultDir()), sp)
+	}
+	subscribeDeployments(bus, client)
+	subscribeDashboard(bus, cfg)
+	if len(cfg.Context.Sources) > 0 {
+		subscribeContext(bus)
+	}
+	if cfg.Trackers.Jira.URL != "" || cfg.Trackers.Linear.APIKey != "" {
+		subscribeTrackers(bus, history.Open(history.DefaultDir()), cfg.Trackers)
+	}
+	if cfg.Hooks.PostComplete != "" {
+		subscribeHooks(bus, cfg)
+	}
+
+	return bus
+}
+
+// publishNew publishes an event unless history shows it was already observed,
+// so polling the same run repeatedly does not repeat reactions
+func publishNew(bus *events.Bus, e events.Event) {
+	seen, err := history.Open(history.DefaultDir()).Has(e.Key())
+	if err == nil && seen {
+		return
+	}
+	bus.Publish(e)
+}
+
+// publishRunEvents publishes progress, failure and completion events for an
+// observed run; issue is the run's issue, or 0 if unknown
+func publishRunEvents(bus *events.Bus, run *github.WorkflowRun, jobs []github.Job, issue int) {
+	now := time.Now()
+	completed, total := countInstanceJobs(jobs)
+
+	bus.Publish(events.RunProgress{
+		RunID:       run.ID,
+		Attempt:     run.Attempt,
+		RunURL:      run.URL,
+		Status:      run.Status,
+		Conclusion:  run.Conclusion,
+		HeadSHA:     run.HeadSHA,
+		BaseSHA:     run.BaseSHA(),
+		Environment: run.Environment(),
+		Completed:   completed,
+		Total:       total,
+		At:          now,
+	})
+
+	for _, job := range jobs {
+		if job.InstanceNumber() == 0 || job.Conclusion != "failure" {
+			continue
+		}
+		publishNew(bus, events.InstanceFailed{
+			RunID:    run.ID,
+			Attempt:  run.Attempt,
+			Issue:    issue,
+			Instance: job.InstanceNumber(),
+			JobName:  job.Name,
+			JobURL:   job.URL,
+			Trace:    run.TraceID(),
+			At:       now,
+		})
+	}
+
+	if run.Status == "completed" {
+		publishNew(bus, events.RunCompleted{
+			RunID:      run.ID,
+			Attempt:    run.Attempt,
+			Issue:      issue,
+			StartedAt:  run.CreatedAt,
+			RunURL:     run.URL,
+			Conclusion: run.Conclusion,
+			HeadSHA:    run.HeadSHA,
+			Completed:  completed,
+			Total:      total,
+			Trace:      run.TraceID(),
+			At:         now,
+		})
+	}
+}
+
+// subscribeHistory records lifecycle events in the local run history
+func subscribeHistory(bus *events.Bus, store *history.Store) {
+	events.On(bus, func(e events.RunStarted) error {
+		return store.Append(history.Record{Key: e.Key(), Event: e.Name(), Issue: e.Issue, Task: e.Task, Source: e.Source, URL: e.IssueURL, Trace: e.Trace, At: e.At})
+	})
+	events.On(bus, func(e events.InstanceFailed) error {
+		return store.Append(history.Record{Key: e.Key(), Event: e.Name(), RunID: e.RunID, Attempt: e.Attempt, Issue: e.Issue, Instance: e.Instance, Conclusion: "failure", URL: e.JobURL, Trace: e.Trace, At: e.At})
+	})
+	events.On(bus, func(e events.RunCompleted) error {
+		return store.Append(history.Record{Key: e.Key(), Event: e.Name(), RunID: e.RunID, Attempt: e.Attempt, Issue: e.Issue, Conclusion: e.Conclusion, URL: e.RunURL, Trace: e.Trace, At: e.At})
+	})
+}
+
+// subscribeCommitStatus mirrors run progress as a commit status on the base commit
+func subscribeCommitStatus(bus *events.Bus, client *github.Client) {
+	events.On(bus, func(e events.RunStarted) error {
+		if e.BaseSHA == "" {
+			return nil
+		}
+		return client.SetCommitStatus(e.BaseSHA, github.StatePending, instanceStatusDescription(0, e.Instances), e.IssueURL)
+	})
+	// The status belongs to the pinned base the pending status was set on;
+	// the run's head commit is the default branch when it was dispatched
+	events.On(bus, func(e events.RunProgress) error {
+		sha := e.BaseSHA
+		if sha == "" {
+			sha = e.HeadSHA
+		}
+		if sha == "" {
+			return nil
+		}
+		state := github.RunCommitState(e.Status, e.Conclusion)
+		return client.SetCommitStatus(sha, state, instanceStatusDescription(e.Completed, e.Total), e.RunURL)
+	})
+}
+
+// subscribeDeployments creates a GitHub deployment for runs that target an
+// environment and mirrors run progress as its deployment status
+func subscribeDeployments(bus *events.Bus, client *github.Client) {
+	events.On(bus, func(e events.RunStarted) error {
+		if e.Environment == "" || e.BaseSHA == "" {
+			return nil
+		}
+		payload := map[string]interface{}{"instances": e.Instances}
+		if e.Issue > 0 {
+			payload["issue"] = e.Issue
+		}
+		if e.StateRun != "" {
+			payload["run_id"] = e.StateRun
+		}
+		d, err := client.CreateDeployment(e.BaseSHA, e.Environment, e.Task, payload)
+		if err != nil {
+			return err
+		}
+		return client.SetDeploymentStatus(d.ID, github.DeploymentQueued, instanceStatusDescription(0, e.Instances), e.IssueURL)
+	})
+	events.On(bus, func(e events.RunProgress) error {
+		if e.Environment == "" || e.BaseSHA == "" {
+			return nil
+		}
+		d, err := client.FindDeployment(e.BaseSHA, e.Environment)
+		if err != nil || d == nil {
+			return err
+		}
+		// Progress is observed on every poll; only record state changes
+		state := github.RunDeploymentState(e.Status, e.Conclusion)
+		if d.State == state {
+			return nil
+		}
+		return client.SetDeploymentStatus(d.ID, state, instanceStatusDescription(e.Completed, e.Total), e.RunURL)
+	})
+}
+
+// subscribeWebhook notifies a webhook when instances or runs fail
+func subscribeWebhook(bus *events.Bus, webhook *notify.Webhook) {
+	events.On(bus, func(e events.InstanceFailed) error {
+		return webhook.Send(fmt.Sprintf("❌ Instance %d failed in run #%d%s%s: %s", e.Instance, e.RunID, attemptSuffix(e.Attempt), traceSuffix(e.Trace), e.JobURL))
+	})
+	events.On(bus, func(e events.RunCompleted) error {
+		if e.Conclusion == "success" {
+			return nil
+		}
+		return webhook.Send(fmt.Sprintf("❌ Run #%d%s%s finished with %s (%d/%d instances complete): %s",
+			e.RunID, attemptSuffix(e.Attempt), traceSuffix(e.Trace), e.Conclusion, e.Completed, e.Total, e.RunURL))
+	})
+}
+
+// subscribeStatuspage sets a status page component from the success rate of
+// recent runs whenever a run completes. It must be subscribed after history
+// so the completed run is counted.
+func subscribeStatuspage(bus *events.Bus, store *history.Store, cfg config.StatuspageConfig) {
+	window, degraded, outage := cfg.Window, cfg.DegradedBelow, cfg.OutageBelow
+	if window <= 0 {
+		window = 10
+	}
+	if degraded <= 0 {
+		degraded = 80
+	}
+	if outage <= 0 {
+		outage = 50
+	}
+	page := notify.NewStatuspage(cfg.PageID, cfg.ComponentID, os.ExpandEnv(cfg.APIKey), cfg.URL)
+
+	events.On(bus, func(e events.RunCompleted) error {
+		rate, runs, err := recentSuccessRate(store, window)
+		if err != nil || runs == 0 {
+			return err
+		}
+		return page.Update(notify.ComponentStatus(rate, degraded, outage), rate)
+	})
+}
+
+// recentSuccessRate is the percentage of the last window completed runs that
+// succeeded, counting only the latest attempt of each run
+func recentSuccessRate(store *history.Store, window int) (rate, runs int, err error) {
+	records, err := store.List()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	seen := make(map[int64]bool)
+	succeeded := 0
+	for i := len(records) - 1; i >= 0 && runs < window; i-- {
+		r := records[i]
+		if r.Event != "run_completed" || seen[r.RunID] {
+			continue
+		}
+		seen[r.RunID] = true
+		runs++
+		if r.Conclusion == "success" {
+			succeeded++
+		}
+	}
+	if runs == 0 {
+		return 0, 0, nil
+	}
+	return succeeded * 100 / runs, runs, nil
+}
+
+// subscribeDashboard regenerates a previously generated local dashboard when a run completes
+func subscribeDashboard(bus *events.Bus, cfg *config.Config) {
+	dir := filepath.Join(".autonomous-dev", "site")
+	events.On(bus, func(e events.RunCompleted) error {
+		if _, err := os.Stat(dir); err != nil {
+			return nil
+		}
+		files, err := buildDashboardSite(cfg)
+		if err != nil {
+			return err
+		}
+		return dashboard.WriteDir(dir, files)
+	})
+}
+
+// subscribeContext makes the context bundle of a started run the baseline
+// for the next run's "changes since last run" section
+func subscribeContext(bus *events.Bus) {
+	events.On(bus, func(e events.RunStarted) error {
+		return bundle.MarkRun(bundle.DefaultDir(), e.At)
+	})
+}
+
+// subscribeHooks runs the post_complete hook when a run completes
+func subscribeHooks(bus *events.Bus, cfg *config.Config) {
+	events.On(bus, func(e events.RunCompleted) error {
+		return hooks.Run(hooks.PostComplete, cfg.Hooks.PostComplete, hooks.Complete{
+			Hook:       hooks.PostComplete,
+			Repository: cfg.GitHub.Owner + "/" + cfg.GitHub.Repo,
+			RunID:      e.RunID,
+			Attempt:    e.Attempt,
+			Issue:      e.Issue,
+			Conclusion: e.Conclusion,
+			Completed:  e.Completed,
+			Total:      e.Total,
+			RunURL:     e.RunURL,
+			HeadSHA:    e.HeadSHA,
+			Trace:      e.Trace,
+		})
+	})
+}
+
+// subscribeTrackers comments the conclusion of runs started with --from on
+// the external ticket they were imported from
+func subscribeTrackers(bus *events.Bus, store *history.Store, cfg config.TrackersConfig) {
+	events.On(bus, func(e events.RunCompleted) error {
+		source, err := runSource(store, e.Trace, e.Issue)
+		if err != nil || source == "" {
+			return err
+		}
+		return tracker.Comment(cfg, source, fmt.Sprintf("autonomous-dev run #%d%s%s finished with %s (%d/%d instances complete): %s",
+			e.RunID, attemptSuffix(e.Attempt), traceSuffix(e.Trace), e.Conclusion, e.Completed, e.Total, e.RunURL))
+	})
+}
+
+// runSource returns the ticket a run was started from with --from, found by
+// the run's trace ID, or by its coordination issue for runs started before
+// trace IDs; "" if it was not imported
+func runSource(store *history.Store, trace string, issue int) (string, error) {
+	if trace == "" && issue == 0 {
+		return "", nil
+	}
+	records, err := store.List()
+	if err != nil {
+		return "", err
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		r := records[i]
+		if r.Event != "run_started" {
+			continue
+		}
+		if (trace != "" && r.Trace == trace) || (trace == "" && issue > 0 && r.Issue == issue) {
+			return r.Source, nil
+		}
+	}
+	return "", nil
+}
+
+// attemptSuffix labels re-run attempts, e.g. " (attempt 2)"; first attempts are unlabeled
+func attemptSuffix(attempt int) string {
+	if attempt <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(" (attempt %d)", attempt)
+}
+
+// traceSuffix labels messages with the run's trace ID, e.g. " [tr-3f9a0c12b7e4]"
+func traceSuffix(trace string) string {
+	if trace == "" {
+		return ""
+	}
+	return " [" + trace + "]"
+}
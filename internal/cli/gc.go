@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/events"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/history"
+	"github.com/fatih/color"
+)
+
+// staleGrace is how long after a run started its workflow run may be
+// missing before the run counts as abandoned
+const staleGrace = 30 * time.Minute
+
+// gcRunWindow is how many recent workflow runs are matched against
+// unsettled runs
+const gcRunWindow = 100
+
+// staleRun is a run history shows as started that turned out to be over
+type staleRun struct {
+	started    history.Record
+	conclusion string
+	reason     string
+	suggestion string
+}
+
+// collectStaleRuns settles runs that history still shows as started. Runs
+// whose workflow run finished unobserved get their completion published;
+// runs whose issue was closed by hand or whose workflow run is gone are
+// recorded as abandoned, and finished runs whose instances stopped without
+// reporting as failed, each with a cleanup suggestion. State branch runs
+// cannot be matched to their workflow run and are left to status.
+func collectStaleRuns(client *github.Client, cfg *config.Config, bus *events.Bus) error {
+	store := history.Open(history.DefaultDir())
+	records, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	var open []history.Record
+	for _, r := range unsettledRuns(records) {
+		if time.Since(r.At) >= staleGrace {
+			open = append(open, r)
+		}
+	}
+	if len(open) == 0 {
+		return nil
+	}
+
+	runs, err := client.ListWorkflowRuns(gcRunWindow)
+	if err != nil {
+		return err
+	}
+
+	var stale []staleRun
+	for _, started := range open {
+		s, err := checkStaleRun(client, cfg, bus, runs, started)
+		if err != nil {
+			return err
+		}
+		if s != nil {
+			stale = append(stale, *s)
+		}
+	}
+
+	yellow := color.New(color.FgYellow).SprintFunc()
+	for _, s := range stale {
+		err := store.Append(history.Record{
+			Key:        fmt.Sprintf("run_abandoned:%d", s.started.Issue),
+			Event:      "run_abandoned",
+			Issue:      s.started.Issue,
+			Task:       s.started.Task,
+			Conclusion: s.conclusion,
+			Reason:     s.reason,
+			URL:        s.started.URL,
+			Trace:      s.started.Trace,
+			At:         time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Run for issue #%d %s: %s\n", yellow(iconWarn), s.started.Issue, s.conclusion, s.reason)
+		fmt.Printf("  %s %s\n", iconArrow, s.suggestion)
+	}
+	return nil
+}
+
+// unsettledRuns returns the run_started records of issue runs with no
+// completion or abandonment recorded since
+func unsettledRuns(records []history.Record) []history.Record {
+	started := map[int]history.Record{}
+	for _, r := range records {
+		if r.Issue == 0 {
+			continue
+		}
+		switch r.Event {
+		case "run_started":
+			started[r.Issue] = r
+		case "run_completed", "run_abandoned":
+			delete(started, r.Issue)
+		}
+	}
+
+	result := make([]history.Record, 0, len(started))
+	for _, r := range started {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Issue < result[j].Issue })
+	return result
+}
+
+// checkStaleRun decides whether a started run is over. It returns nil for
+// runs still in progress and for runs completed normally, whose events it
+// publishes.
+func checkStaleRun(client *github.Client, cfg *config.Config, bus *events.Bus, runs []github.WorkflowRun, started history.Record) (*staleRun, error) {
+	var run *github.WorkflowRun
+	for i := range runs {
+		if runs[i].Issue() == started.Issue {
+			run = &runs[i]
+			break
+		}
+	}
+	// A missing run is only conclusive if the listed runs reach back far
+	// enough to include it
+	covered := len(runs) < gcRunWindow || runs[len(runs)-1].CreatedAt.Before(started.At)
+
+	issue, err := client.GetIssue(started.Issue)
+	if err != nil {
+		return nil, err
+	}
+	closed := issue.State == "closed"
+
+	switch {
+	case run == nil && !covered:
+		return nil, nil
+	case run == nil && closed:
+		return &staleRun{started, "abandoned", "issue closed manually before a workflow run started",
+			"nothing to clean up; start the task again if it is still wanted"}, nil
+	case run == nil:
+		return &staleRun{started, "abandoned", "workflow run deleted or never started",
+			fmt.Sprintf("close issue #%d or start the task again", started.Issue)}, nil
+	case run.Status != "completed" && closed:
+		return &staleRun{started, "abandoned", "issue closed manually while the run was in progress",
+			fmt.Sprintf("cancel workflow run #%d (gh run cancel %d) and delete its instance branches", run.ID, run.ID)}, nil
+	case run.Status != "completed":
+		return nil, nil
+	}
+
+	jobs, err := client.GetWorkflowJobs(run.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow jobs: %w", err)
+	}
+	publishRunEvents(bus, run, jobs, started.Issue)
+
+	lost, err := unreportedInstances(client, cfg, started.Issue, jobs)
+	if err != nil || len(lost) == 0 {
+		return nil, err
+	}
+	return &staleRun{started, "failed", fmt.Sprintf("instance(s) %s stopped without reporting", lost),
+		fmt.Sprintf("review their instance branches and re-run workflow run #%d (gh run rerun %d)", run.ID, run.ID)}, nil
+}
+
+// unreportedInstances lists instances whose job ended without failing but
+// that never reported completed or failed, e.g. "2, 4"
+func unreportedInstances(client *github.Client, cfg *config.Config, issue int, jobs []github.Job) (string, error) {
+	store, err := coordinationStore(client, cfg, issue, "")
+	if err != nil || store == nil {
+		return "", err
+	}
+	snap, _, err := readCoordination(store)
+	if err != nil {
+		return "", err
+	}
+
+	var lost []int
+	for _, job := range jobs {
+		id := job.InstanceNumber()
+		if id == 0 || job.Status != "completed" || job.Conclusion == "failure" || job.Conclusion == "skipped" {
+			continue
+		}
+		if st, ok := snap.Statuses[id]; ok && (st.Status == "completed" || st.Status == "failed") {
+			continue
+		}
+		lost = append(lost, id)
+	}
+	sort.Ints(lost)
+
+	ids := make([]string, len(lost))
+	for i, id := range lost {
+		ids[i] = strconv.Itoa(id)
+	}
+	return strings.Join(ids, ", "), nil
+}
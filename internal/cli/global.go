@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/cache"
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/spf13/cobra"
+)
+
+var (
+	globalUTC     bool
+	globalNoCache bool
+	globalTimeout time.Duration
+)
+
+// RegisterGlobalFlags adds flags shared by all commands to the root command
+func RegisterGlobalFlags(root *cobra.Command) {
+	root.PersistentFlags().BoolVar(&globalUTC, "utc", false, "Show timestamps in UTC instead of the local time zone")
+	root.PersistentFlags().StringVar(&globalPorcelain, "porcelain", "", "End with one machine-parseable summary line: kv (default) or json")
+	root.PersistentFlags().Lookup("porcelain").NoOptDefVal = porcelainKV
+	root.PersistentFlags().BoolVar(&globalNoCache, "no-cache", false, "Call the AI provider even if a cached reply exists")
+	root.PersistentFlags().BoolVar(&config.Lenient, "lenient", false, "Warn about unknown config keys instead of failing")
+	root.PersistentFlags().BoolVar(&globalNoColor, "no-color", false, "Disable colored output (also set by NO_COLOR)")
+	root.PersistentFlags().DurationVar(&globalTimeout, "timeout", 0, "Deadline of each GitHub API call, overriding network.timeouts (e.g. 10s)")
+	root.PersistentFlags().BoolVar(&globalASCII, "ascii", false, "Replace emoji and Unicode icons with plain text")
+	root.PersistentPreRunE = setupOutput
+}
+
+// newClient returns a GitHub API client for the configured repository
+func newClient(cfg *config.Config) *github.Client {
+	client := github.NewClient(cfg.GitHub.Token, cfg.GitHub.Owner, cfg.GitHub.Repo)
+	client.SetTimeouts(apiTimeouts(cfg))
+	return client
+}
+
+// apiTimeouts returns the network.timeouts of cfg, or --timeout for every
+// kind of call when it is set
+func apiTimeouts(cfg *config.Config) github.Timeouts {
+	if globalTimeout > 0 {
+		return github.Timeouts{Read: globalTimeout, List: globalTimeout, Dispatch: globalTimeout, Logs: globalTimeout}
+	}
+	t := &cfg.Network.Timeouts
+	return github.Timeouts{
+		Read:     t.ReadTimeout(),
+		List:     t.ListTimeout(),
+		Dispatch: t.DispatchTimeout(),
+		Logs:     t.LogsTimeout(),
+	}
+}
+
+// responseCache returns the cache of AI provider replies, or nil when
+// planner.cache_ttl_hours disables it. With --no-cache cached replies are
+// not used but are replaced by fresh ones.
+func responseCache(cfg *config.Config) *cache.Cache {
+	ttl := cfg.Planner.CacheTTL()
+	if ttl <= 0 {
+		return nil
+	}
+	c := cache.Open(cache.DefaultDir(), ttl)
+	c.Refresh = globalNoCache
+	return c
+}
+
+// displayLocation returns the time zone used to render timestamps
+func displayLocation(cfg *config.Config) *time.Location {
+	if globalUTC {
+		return time.UTC
+	}
+	if cfg == nil {
+		return time.Local
+	}
+	loc, err := cfg.Display.Location()
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// formatTime renders a timestamp for human-oriented output
+func formatTime(t time.Time, cfg *config.Config) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.In(displayLocation(cfg)).Format("2006-01-02 15:04:05 MST")
+}
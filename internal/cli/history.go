@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/history"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var historyLimit int
+
+func HistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show recorded run history",
+		Long: `Show run lifecycle events recorded locally in .autonomous-dev/history/.
+
+Events are recorded by start, status and serve as runs start, instances
+fail and runs complete. serve also records runs that will never complete
+as run_abandoned, e.g. when their issue was closed by hand.
+
+The history is an append-only journal: each machine appends to its own
+segment file under a lock, so the CLI and serve can record at the same
+time and the directory can be synced between machines. serve compacts it
+periodically; 'history merge' adds records copied from elsewhere.`,
+		Example: `  autonomous-dev history --limit 50
+  autonomous-dev history merge ~/laptop/.autonomous-dev/history
+  autonomous-dev history compact`,
+		RunE: runHistory,
+	}
+
+	cmd.Flags().IntVar(&historyLimit, "limit", 20, "Number of most recent events to show (0 = all)")
+
+	cmd.AddCommand(historyMergeCmd())
+	cmd.AddCommand(historyCompactCmd())
+
+	return cmd
+}
+
+func historyMergeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "merge <path>...",
+		Short: "Merge history recorded on other machines",
+		Long: `Add the records of other history directories, or history.jsonl files
+written by older versions, that this history does not hold yet. Records
+already present are skipped, so merging the same copy twice is harmless.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			green := color.New(color.FgGreen).SprintFunc()
+
+			added, err := history.Open(history.DefaultDir()).Merge(args...)
+			if err != nil {
+				return err
+			}
+			summarize("merged", added)
+			fmt.Printf("%s Merged %d new record(s)\n", green(iconOK), added)
+			return nil
+		},
+	}
+}
+
+func historyCompactCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "compact",
+		Short: "Fold the history's segments into one",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			green := color.New(color.FgGreen).SprintFunc()
+
+			replaced, err := history.Open(history.DefaultDir()).Compact()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s Compacted %d segment(s)\n", green(iconOK), replaced)
+			return nil
+		},
+	}
+}
+
+// historyCompactSegments is how many segments serve lets accumulate before
+// compacting the history
+const historyCompactSegments = 4
+
+// compactHistory compacts the history once it has accumulated segments,
+// e.g. from merges and synced machines
+func compactHistory() error {
+	store := history.Open(history.DefaultDir())
+	segments, err := store.Segments()
+	if err != nil || segments < historyCompactSegments {
+		return err
+	}
+	_, err = store.Compact()
+	return err
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	bold := color.New(color.Bold).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	// History is local, so a missing config only affects time zone display
+	cfg, _ := config.Load(config.ConfigPath())
+
+	records, err := history.Open(history.DefaultDir()).List()
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		fmt.Println("No history recorded yet")
+		return nil
+	}
+
+	if historyLimit > 0 && len(records) > historyLimit {
+		records = records[len(records)-historyLimit:]
+	}
+
+	fmt.Println(bold("History:"))
+	for _, r := range records {
+		subject := ""
+		switch {
+		case r.RunID == 0:
+			subject = fmt.Sprintf("issue #%d", r.Issue)
+		case r.Instance > 0:
+			subject = fmt.Sprintf("run #%d%s instance %d", r.RunID, attemptSuffix(r.Attempt), r.Instance)
+		default:
+			subject = fmt.Sprintf("run #%d%s", r.RunID, attemptSuffix(r.Attempt))
+		}
+		if r.RunID > 0 && r.Issue > 0 {
+			subject += fmt.Sprintf(" (issue #%d)", r.Issue)
+		}
+		subject += traceSuffix(r.Trace)
+
+		fmt.Printf("%s  %-16s %s", formatTime(r.At, cfg), r.Event, subject)
+		if r.Conclusion != "" {
+			fmt.Printf(" %s", statusColor(r.Conclusion))
+		}
+		if r.Reason != "" {
+			fmt.Printf(" (%s)", r.Reason)
+		}
+		if r.Task != "" {
+			fmt.Printf(" - %s", r.Task)
+		}
+		fmt.Println()
+		if r.URL != "" {
+			fmt.Printf("    %s\n", cyan(r.URL))
+		}
+	}
+
+	return nil
+}
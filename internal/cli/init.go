@@ -1,18 +1,25 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/plugin"
 	"github.com/autonomous-dev/cli/internal/template"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var initFromTemplate bool
+
 func InitCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "init",
 		Short: "Initialize autonomous development in current project",
 		Long: `Initialize autonomous development by creating configuration files and
@@ -23,9 +30,18 @@ This command will:
 2. Create .github/workflows/autonomous-dev.yml workflow
 3. Update .gitignore to include .autonomous-dev/ directory
 
-After initialization, you can customize the config and start development.`,
+After initialization, you can customize the config and start development.
+
+With --from-template, a repository generated from a GitHub template is also
+set up: references to the template's owner/repo in workflows and the
+dashboard are rewritten, the template's Actions variables are copied, and a
+setup checklist issue lists the secrets to add (requires GITHUB_TOKEN).`,
 		RunE: runInit,
 	}
+
+	cmd.Flags().BoolVar(&initFromTemplate, "from-template", false, "Set up a repository generated from a template repository")
+
+	return cmd
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -34,8 +50,13 @@ func runInit(cmd *cobra.Command, args []string) error {
 	bold := color.New(color.Bold).SprintFunc()
 
 	// Check if already initialized
-	if config.Exists() {
-		return fmt.Errorf("%s already initialized (found %s)", yellow("Warning:"), config.ConfigPath())
+	if _, err := os.Stat(config.LocalPath()); err == nil {
+		return fmt.Errorf("%s already initialized (found %s)", yellow("Warning:"), config.LocalPath())
+	}
+
+	// Below an initialized repository root, create a package config instead
+	if parent := config.ConfigPath(); parent != config.LocalPath() {
+		return initPackage(parent)
 	}
 
 	// Detect repository info from git
@@ -50,28 +71,37 @@ func runInit(cmd *cobra.Command, args []string) error {
 	cfg.GitHub.Repo = repo
 
 	// Save config
-	if err := cfg.Save(config.ConfigPath()); err != nil {
+	if err := cfg.Save(config.LocalPath()); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
-	fmt.Printf("%s Created %s\n", green("✓"), config.ConfigPath())
+	fmt.Printf("%s Created %s\n", green(iconOK), config.LocalPath())
 
 	// Create workflow file
-	workflowPath := ".github/workflows/autonomous-dev.yml"
+	workflowPath := filepath.FromSlash(cfg.Workflow.File)
 	if err := os.MkdirAll(filepath.Dir(workflowPath), 0755); err != nil {
 		return fmt.Errorf("failed to create .github/workflows directory: %w", err)
 	}
 
-	workflowContent := template.WorkflowTemplate(cfg)
+	workflowContent, err := template.WorkflowTemplate(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to render workflow: %w", err)
+	}
 	if err := os.WriteFile(workflowPath, []byte(workflowContent), 0644); err != nil {
 		return fmt.Errorf("failed to write workflow file: %w", err)
 	}
-	fmt.Printf("%s Created %s\n", green("✓"), workflowPath)
+	fmt.Printf("%s Created %s\n", green(iconOK), workflowPath)
 
 	// Update .gitignore
 	if err := updateGitignore(); err != nil {
-		fmt.Printf("%s Warning: failed to update .gitignore: %v\n", yellow("⚠"), err)
+		fmt.Printf("%s Warning: failed to update .gitignore: %v\n", yellow(iconWarn), err)
 	} else {
-		fmt.Printf("%s Updated .gitignore\n", green("✓"))
+		fmt.Printf("%s Updated .gitignore\n", green(iconOK))
+	}
+
+	if initFromTemplate {
+		if err := setupFromTemplate(cfg); err != nil {
+			return err
+		}
 	}
 
 	// Print next steps
@@ -80,6 +110,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 	fmt.Println("1. Review and edit", config.ConfigPath())
 	fmt.Println("2. Set GITHUB_TOKEN environment variable:")
 	fmt.Println("   export GITHUB_TOKEN=ghp_xxxxxxxxxxxx")
+	printRequirementSetup(cfg)
 	fmt.Println("3. Commit and push the workflow file")
 	fmt.Println("4. Start development:")
 	fmt.Println("   autonomous-dev start --task=\"Your feature description\"")
@@ -96,7 +127,7 @@ func detectGitRepo() (owner, repo string, err error) {
 
 	// Parse GitHub URL
 	// Supports: https://github.com/owner/repo.git and git@github.com:owner/repo.git
-	url := string(output)
+	url := strings.TrimSpace(string(output))
 	owner, repo, err = parseGitHubURL(url)
 	if err != nil {
 		return "", "", err
@@ -151,9 +182,42 @@ func splitPath(path string) []string {
 	return parts
 }
 
+// initPackage creates a package config that inherits everything from the
+// config at parent and scopes instance work to the package directory
+func initPackage(parent string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+
+	content := fmt.Sprintf(`# Package config: settings not set here are inherited from
+# %s
+# Instances are scoped to paths, which defaults to this package's directory.
+# paths:
+#   - apps/web
+`, parent)
+	if err := os.MkdirAll(filepath.Dir(config.LocalPath()), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(config.LocalPath(), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	cfg, err := config.Load(config.LocalPath())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Created package config %s (inherits from %s)\n", green(iconOK), config.LocalPath(), parent)
+	fmt.Printf("  Scope: %s\n", strings.Join(cfg.Paths, ", "))
+	return nil
+}
+
+// gitignoreEntries keep the local state under .autonomous-dev/ out of git
+// while the repository's plugins stay tracked
+var gitignoreEntries = []string{".autonomous-dev/*", "!" + filepath.ToSlash(plugin.Dir()) + "/"}
+
+// updateGitignore adds gitignoreEntries to .gitignore. The former entry
+// ignoring all of .autonomous-dev/, which git cannot re-include plugins
+// from, is replaced.
 func updateGitignore() error {
 	gitignorePath := ".gitignore"
-	entry := ".autonomous-dev/\n"
 
 	// Read existing .gitignore
 	var content []byte
@@ -165,27 +229,209 @@ func updateGitignore() error {
 		}
 	}
 
-	// Check if entry already exists
-	contentStr := string(content)
-	for i := 0; i < len(contentStr); {
-		end := i
-		for end < len(contentStr) && contentStr[end] != '\n' {
-			end++
+	var lines []string
+	if len(content) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	}
+	present := map[string]bool{}
+	kept := lines[:0]
+	for _, line := range lines {
+		trimmed := strings.TrimSuffix(line, "\r")
+		if trimmed == ".autonomous-dev/" || trimmed == ".autonomous-dev" {
+			continue
 		}
-		line := contentStr[i:end]
-		if line == ".autonomous-dev/" {
-			return nil // Already exists
+		present[trimmed] = true
+		kept = append(kept, line)
+	}
+	for _, entry := range gitignoreEntries {
+		if !present[entry] {
+			kept = append(kept, entry)
 		}
-		i = end + 1
 	}
 
-	// Append entry
-	content = append(content, []byte(entry)...)
-	return os.WriteFile(gitignorePath, content, 0644)
+	updated := strings.Join(kept, "\n") + "\n"
+	if updated == string(content) {
+		return nil
+	}
+	return os.WriteFile(gitignorePath, []byte(updated), 0644)
 }
 
+// templateRewriteDirs hold files that may reference the template repository
+var templateRewriteDirs = []string{".github", "dashboard"}
+
+// setupFromTemplate finishes setting up a repository generated from a template
+func setupFromTemplate(cfg *config.Config) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return fmt.Errorf("--from-template requires the GITHUB_TOKEN environment variable")
+	}
+	client := github.NewClient(token, cfg.GitHub.Owner, cfg.GitHub.Repo)
+	client.SetTimeouts(apiTimeouts(cfg))
+
+	origin, err := client.GetTemplateRepository()
+	if err != nil {
+		return err
+	}
+	if origin == nil {
+		return fmt.Errorf("%s/%s was not generated from a template repository", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+	fmt.Printf("%s Generated from template %s/%s\n", green(iconOK), origin.Owner, origin.Name)
+
+	// Rewrite owner/repo references
+	rewritten, err := rewriteRepoReferences(templateRewriteDirs, origin.Owner, origin.Name, cfg.GitHub.Owner, cfg.GitHub.Repo)
+	if err != nil {
+		return err
+	}
+	for _, path := range rewritten {
+		fmt.Printf("%s Rewrote template references in %s\n", green(iconOK), path)
+	}
+
+	// Variables are not copied when generating from a template
+	variables, err := client.ListRepoVariables(origin.Owner, origin.Name)
+	if err != nil {
+		fmt.Printf("%s Warning: failed to read template variables: %v\n", yellow(iconWarn), err)
+	}
+	for _, v := range variables {
+		if err := client.SetRepoVariable(v.Name, v.Value); err != nil {
+			fmt.Printf("%s Warning: %v\n", yellow(iconWarn), err)
+			continue
+		}
+		fmt.Printf("%s Seeded variable %s\n", green(iconOK), v.Name)
+	}
+
+	// Secret values cannot be read or copied; list the ones still missing
+	var required []string
+	for _, r := range cfg.Requirements {
+		if !r.IsVariable() {
+			required = append(required, r.Name)
+		}
+	}
+	if templateSecrets, err := client.ListRepoSecretNames(origin.Owner, origin.Name); err == nil {
+		required = appendMissing(required, templateSecrets...)
+	}
+	existing, _ := client.ListRepoSecretNames(cfg.GitHub.Owner, cfg.GitHub.Repo)
+	var missing []string
+	for _, name := range required {
+		if !containsString(existing, name) {
+			missing = append(missing, name)
+		}
+	}
+
+	issue, err := client.CreateIssue("Finish autonomous-dev setup", templateChecklist(cfg, origin, missing, rewritten))
+	if err != nil {
+		return fmt.Errorf("failed to open setup checklist: %w", err)
+	}
+	fmt.Printf("%s Opened setup checklist %s\n", green(iconOK), cyan(issue.URL))
+
+	return nil
+}
+
+// rewriteRepoReferences replaces fromOwner/fromRepo (including GitHub Pages
+// URLs) with toOwner/toRepo in files under dirs and returns the changed paths
+func rewriteRepoReferences(dirs []string, fromOwner, fromRepo, toOwner, toRepo string) ([]string, error) {
+	replacer := strings.NewReplacer(
+		fromOwner+"/"+fromRepo, toOwner+"/"+toRepo,
+		fromOwner+".github.io/"+fromRepo, toOwner+".github.io/"+toRepo,
+	)
+
+	var changed []string
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if errors.Is(err, os.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			updated := replacer.Replace(string(content))
+			if updated == string(content) {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, []byte(updated), info.Mode().Perm()); err != nil {
+				return err
+			}
+			changed = append(changed, path)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewrite references in %s: %w", dir, err)
+		}
+	}
+
+	return changed, nil
+}
+
+// templateChecklist is the body of the setup checklist issue
+func templateChecklist(cfg *config.Config, origin *github.Repository, missingSecrets, rewritten []string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "This repository was generated from [%s/%s](%s). Complete these steps before the first run.\n\n", origin.Owner, origin.Name, origin.URL)
+
+	b.WriteString("## Secrets\n\n")
+	if len(missingSecrets) == 0 {
+		b.WriteString("- [x] All secrets used by the template are set\n")
+	}
+	for _, name := range missingSecrets {
+		fmt.Fprintf(&b, "- [ ] Set `%s`: `gh secret set %s --repo %s/%s`\n", name, name, cfg.GitHub.Owner, cfg.GitHub.Repo)
+	}
+
+	b.WriteString("\n## Review\n\n")
+	fmt.Fprintf(&b, "- [ ] Review `%s` and commit the workflow\n", config.ConfigPath())
+	for _, path := range rewritten {
+		fmt.Fprintf(&b, "- [ ] Check rewritten references in `%s`\n", filepath.ToSlash(path))
+	}
+	b.WriteString("- [ ] Review copied Actions variables under Settings → Secrets and variables → Actions\n")
+	b.WriteString("- [ ] Allow GitHub Actions to create pull requests under Settings → Actions → General\n")
+
+	b.WriteString("\n## Verify\n\n")
+	b.WriteString("- [ ] `autonomous-dev doctor` passes\n")
+	b.WriteString("- [ ] `autonomous-dev start --task \"...\"` dispatches a run\n")
+
+	return b.String()
+}
+
+// appendMissing appends the values not already in list
+func appendMissing(list []string, values ...string) []string {
+	for _, v := range values {
+		if !containsString(list, v) {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// execCommand runs a command and returns its standard output
 func execCommand(name string, args ...string) ([]byte, error) {
-	// Simple command execution (avoiding os/exec for now)
-	// In production, use os/exec.Command
-	return nil, fmt.Errorf("not implemented")
+	output, err := exec.Command(name, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("%s: %s", name, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return output, nil
 }
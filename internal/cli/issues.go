@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/chunk"
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	issuesLabel      string
+	issuesState      string
+	issuesStaleAfter time.Duration
+	issuesDryRun     bool
+	issuesAdd        []string
+	issuesRemove     []string
+	issuesInstances  int
+)
+
+func IssuesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "issues",
+		Short: "Bulk operations on coordination issues",
+		Long: `List, close, relabel, reassign or migrate coordination issues in bulk.
+
+Issues are selected by --label (default autonomous-dev) and --state: open,
+closed, all, or stale (open issues not updated within --stale-after).
+Use --dry-run to see which issues an operation would touch.`,
+		Example: `  autonomous-dev issues list --state stale
+  autonomous-dev issues close --label autonomous-dev --state stale
+  autonomous-dev issues relabel --add needs-triage --remove autonomous-dev
+  autonomous-dev issues migrate --state all`,
+	}
+
+	cmd.PersistentFlags().StringVar(&issuesLabel, "label", "autonomous-dev", "Select issues with this label")
+	cmd.PersistentFlags().StringVar(&issuesState, "state", "open", "Select issues by state: open, closed, all or stale")
+	cmd.PersistentFlags().DurationVar(&issuesStaleAfter, "stale-after", 72*time.Hour, "Inactivity after which an open issue is stale")
+	cmd.PersistentFlags().BoolVar(&issuesDryRun, "dry-run", false, "Show the selected issues without changing them")
+
+	cmd.AddCommand(issuesListCmd())
+	cmd.AddCommand(issuesCloseCmd())
+	cmd.AddCommand(issuesRelabelCmd())
+	cmd.AddCommand(issuesReassignCmd())
+	cmd.AddCommand(issuesMigrateCmd())
+
+	return cmd
+}
+
+func issuesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the selected issues",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, _, issues, err := selectIssues()
+			if err != nil {
+				return err
+			}
+
+			if len(issues) == 0 {
+				fmt.Println("No matching issues")
+				return nil
+			}
+			for _, issue := range issues {
+				printIssueLine(issue, cfg)
+			}
+			return nil
+		},
+	}
+}
+
+func issuesCloseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "close",
+		Short: "Close the selected issues",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return forEachIssue("Closed", func(client *github.Client, cfg *config.Config, issue github.Issue) error {
+				body := fmt.Sprintf("Closed by `autonomous-dev issues close` (state: %s).", issuesState)
+				if err := postComment(client, issue.Number, body); err != nil {
+					return err
+				}
+				return client.CloseIssue(issue.Number)
+			})
+		},
+	}
+}
+
+func issuesRelabelCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relabel",
+		Short: "Add or remove labels on the selected issues",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(issuesAdd) == 0 && len(issuesRemove) == 0 {
+				return fmt.Errorf("nothing to do: use --add and/or --remove")
+			}
+			return forEachIssue("Relabeled", func(client *github.Client, cfg *config.Config, issue github.Issue) error {
+				if len(issuesAdd) > 0 {
+					if err := client.AddLabels(issue.Number, issuesAdd...); err != nil {
+						return err
+					}
+				}
+				for _, label := range issuesRemove {
+					if !containsString(issue.Labels, label) {
+						continue
+					}
+					if err := client.RemoveLabel(issue.Number, label); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&issuesAdd, "add", nil, "Labels to add")
+	cmd.Flags().StringSliceVar(&issuesRemove, "remove", nil, "Labels to remove")
+
+	return cmd
+}
+
+func issuesReassignCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reassign",
+		Short: "Hand the selected issues to a new workflow run",
+		Long: `Trigger a new workflow run for each selected issue. The new run's
+instances pick up the coordination state already posted on the issue.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return forEachIssue("Reassigned", func(client *github.Client, cfg *config.Config, issue github.Issue) error {
+				instances := issuesInstances
+				if instances <= 0 {
+					instances = cfg.Instances.Default
+				}
+				if _, err := client.TriggerWorkflow(issue.Number, instances, nil); err != nil {
+					return err
+				}
+				return postComment(client, issue.Number, fmt.Sprintf("🔁 Reassigned to a new workflow run with %d instances by `autonomous-dev issues reassign`.", instances))
+			})
+		},
+	}
+
+	cmd.Flags().IntVarP(&issuesInstances, "instances", "n", 0, "Instances of the new run (default instances.default)")
+
+	return cmd
+}
+
+func issuesMigrateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Convert old-format coordination comments to the structured protocol",
+		Long: `Rewrite old-format coordination comments on the selected issues:
+plain "✅ Instance N: Success" / "❌ Instance N: Failed" comments gain an
+INSTANCE_STATUS block, and message blocks written before protocol versioning
+are re-rendered with the current version. Other comments are left unchanged.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return forEachIssue("Migrated", func(client *github.Client, cfg *config.Config, issue github.Issue) error {
+				comments, err := client.ListIssueComments(issue.Number)
+				if err != nil {
+					return err
+				}
+				migrated := 0
+				for _, comment := range comments {
+					body, changed, err := protocol.Migrate(comment.Body, comment.CreatedAt)
+					if err != nil {
+						return fmt.Errorf("comment %d: %w", comment.ID, err)
+					}
+					if !changed {
+						continue
+					}
+					if err := client.UpdateComment(comment.ID, body); err != nil {
+						return err
+					}
+					migrated++
+				}
+				fmt.Printf("  #%d: %d of %d comments migrated\n", issue.Number, migrated, len(comments))
+				return nil
+			})
+		},
+	}
+}
+
+// selectIssues loads config and lists the issues matching the selection flags
+func selectIssues() (*config.Config, *github.Client, []github.Issue, error) {
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	client := newClient(cfg)
+
+	state := issuesState
+	switch state {
+	case "open", "closed", "all":
+	case "stale":
+		state = "open"
+	default:
+		return nil, nil, nil, fmt.Errorf("invalid --state %q (use open, closed, all or stale)", issuesState)
+	}
+
+	issues, err := client.ListIssues(issuesLabel, state)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if issuesState != "stale" {
+		return cfg, client, issues, nil
+	}
+
+	var stale []github.Issue
+	for _, issue := range issues {
+		if isStaleIssue(issue, time.Now()) {
+			stale = append(stale, issue)
+		}
+	}
+	return cfg, client, stale, nil
+}
+
+// isStaleIssue reports whether an open issue has been inactive for --stale-after
+func isStaleIssue(issue github.Issue, now time.Time) bool {
+	return issue.State == "open" && now.Sub(issue.UpdatedAt) > issuesStaleAfter
+}
+
+// forEachIssue applies an operation to every selected issue, or only lists
+// them with --dry-run. Failures are reported per issue and counted.
+func forEachIssue(verb string, apply func(*github.Client, *config.Config, github.Issue) error) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	cfg, client, issues, err := selectIssues()
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		fmt.Println("No matching issues")
+		return nil
+	}
+
+	if issuesDryRun {
+		fmt.Printf("%s Dry run: %d issue(s) would be affected\n", yellow(iconWarn), len(issues))
+		for _, issue := range issues {
+			printIssueLine(issue, cfg)
+		}
+		return nil
+	}
+
+	failed := 0
+	for _, issue := range issues {
+		if err := apply(client, cfg, issue); err != nil {
+			fmt.Printf("%s #%d: %v\n", red(iconFail), issue.Number, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s #%d: %s\n", green(iconOK), verb, issue.Number, issue.Title)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d issues failed", failed, len(issues))
+	}
+	return nil
+}
+
+// postComment comments on an issue, continuing bodies over GitHub's limit in
+// further comments
+func postComment(client *github.Client, number int, body string) error {
+	for _, part := range chunk.Split(body, chunk.MaxBody) {
+		if err := client.CreateComment(number, part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printIssueLine(issue github.Issue, cfg *config.Config) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	marker := ""
+	if isStaleIssue(issue, time.Now()) {
+		marker = " " + yellow("[stale]")
+	}
+	fmt.Printf("#%-5d %-6s %s  %s%s\n", issue.Number, issue.State, formatTime(issue.UpdatedAt, cfg), issue.Title, marker)
+}
@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/logstream"
+	"github.com/autonomous-dev/cli/internal/poll"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsRun      int64
+	logsInstance int
+	logsFollow   bool
+	logsInterval time.Duration
+	logsBuffer   int
+	logsRestart  bool
+	logsLevel    string
+)
+
+// minLogsInterval keeps --follow from exhausting the API rate limit, since
+// every poll downloads the full log of each running job
+const minLogsInterval = 5 * time.Second
+
+func LogsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show instance logs",
+		Long: `Show the logs of the latest (or --run) workflow run's instances.
+
+Without --follow, the last --buffer lines of each instance are printed.
+With --follow, logs are polled until the run completes and only new lines
+are printed: every --interval while lines arrive, less often while
+instances are quiet (see network.polling). Progress is saved in .autonomous-dev/logs, so a restarted
+logs --follow resumes where the previous one stopped; --restart prints
+from the beginning again. Lines arriving faster than they are printed are
+kept in a ring buffer of --buffer lines per instance; older ones are
+skipped.
+
+Instances log structured JSON lines (see logs.level and logs.format in the
+config), shown as "time LEVEL step: message". --level hides lines below a
+level; plain lines from other tools count as info.`,
+		Example: `  autonomous-dev logs --instance 2
+  autonomous-dev logs --follow --level warn
+  autonomous-dev logs --run 456 --level error`,
+		RunE: runLogs,
+	}
+
+	cmd.Flags().Int64Var(&logsRun, "run", 0, "Workflow run ID (default latest)")
+	cmd.Flags().IntVar(&logsInstance, "instance", 0, "Only show this instance")
+	cmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Keep printing new lines until the run completes")
+	cmd.Flags().DurationVar(&logsInterval, "interval", 10*time.Second, "Poll interval with --follow while lines arrive (see network.polling)")
+	cmd.Flags().IntVar(&logsBuffer, "buffer", 500, "Lines buffered per instance")
+	cmd.Flags().BoolVar(&logsRestart, "restart", false, "Ignore saved progress with --follow")
+	cmd.Flags().StringVar(&logsLevel, "level", "", "Only show lines at or above this level: debug, info, warn or error")
+
+	return cmd
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	if logsInterval < minLogsInterval {
+		return fmt.Errorf("--interval must be at least %s", minLogsInterval)
+	}
+	if logsBuffer < 1 {
+		return fmt.Errorf("--buffer must be positive")
+	}
+	if logsLevel != "" {
+		if _, err := logstream.ParseLevel(logsLevel); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := newClient(cfg)
+
+	run, err := logsWorkflowRun(client)
+	if err != nil {
+		return err
+	}
+
+	// Only --follow resumes; a one-off look should not move the cursor
+	cursors, err := logstream.Load(logstream.DefaultDir(), run.ID)
+	if err != nil {
+		return err
+	}
+	if logsRestart || !logsFollow {
+		cursors.Jobs = make(map[int64]*logstream.Cursor)
+	}
+
+	// Poll quickly while lines arrive, slower while instances are quiet
+	poller := newPoller(cfg, logsInterval)
+	rings := make(map[int64]*logstream.Ring)
+	for {
+		jobs, err := client.GetWorkflowJobs(run.ID)
+		if err != nil {
+			if !logsFollow || !transientError(err) {
+				return fmt.Errorf("failed to get workflow jobs: %w", err)
+			}
+			wait := poller.Next(failedPoll(err))
+			fmt.Printf("%s %v; retrying in %s\n", yellow(iconWarn), err, wait.Round(time.Second))
+			time.Sleep(wait)
+			continue
+		}
+
+		printed, wait, limited := pollLogs(client, cfg, jobs, cursors, rings)
+		if logsFollow {
+			if err := cursors.Save(); err != nil {
+				return err
+			}
+		}
+		if limited {
+			wait = poller.Next(poll.Observation{Active: true, RetryAfter: wait})
+			fmt.Printf("%s Rate limited; resuming in %s\n", yellow(iconWait), wait.Round(time.Second))
+			time.Sleep(wait)
+			continue
+		}
+		if !logsFollow || run.Status == "completed" {
+			return nil
+		}
+
+		time.Sleep(poller.Next(poll.Observation{Active: true, Changed: printed > 0}))
+		latest, err := client.GetWorkflowRun(run.ID)
+		if err != nil && !transientError(err) {
+			return err
+		}
+		if err == nil {
+			run = latest
+		}
+	}
+}
+
+// logsWorkflowRun returns the --run workflow run or the latest one
+func logsWorkflowRun(client *github.Client) (*github.WorkflowRun, error) {
+	if logsRun > 0 {
+		return client.GetWorkflowRun(logsRun)
+	}
+	run, err := client.GetLatestWorkflowRun()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run: %w", err)
+	}
+	if run == nil {
+		return nil, fmt.Errorf("no workflow runs found")
+	}
+	return run, nil
+}
+
+// pollLogs prints the new lines of each started job and returns how many
+// it printed. It stops early and returns how long to wait when GitHub rate
+// limits the log downloads.
+func pollLogs(client *github.Client, cfg *config.Config, jobs []github.Job, cursors *logstream.Cursors, rings map[int64]*logstream.Ring) (int, time.Duration, bool) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	printed := 0
+	for _, job := range jobs {
+		n := job.InstanceNumber()
+		if job.Status == "queued" || (logsInstance > 0 && n != logsInstance) {
+			continue
+		}
+		cursor := cursors.Job(job.ID)
+		if cursor.Done {
+			continue
+		}
+
+		logs, err := client.GetJobLogs(job.ID)
+		if wait, ok := github.RateLimitWait(err); ok {
+			return printed, wait, true
+		}
+		if err != nil {
+			// Logs of a job that just started may not be available yet
+			continue
+		}
+
+		ring, ok := rings[job.ID]
+		if !ok {
+			ring = logstream.NewRing(logsBuffer)
+			rings[job.ID] = ring
+		}
+		cursor.Advance(logs, ring)
+		cursor.Done = job.Status == "completed"
+
+		prefix := cyan(fmt.Sprintf("[%s]", job.Name))
+		if n > 0 {
+			prefix = cyan(fmt.Sprintf("[instance %d]", n))
+		}
+		if ring.Dropped > 0 {
+			fmt.Printf("%s %s\n", prefix, yellow(fmt.Sprintf("... %d lines skipped", ring.Dropped)))
+		}
+		for _, line := range ring.Drain() {
+			if logsLevel != "" && !logstream.LineAtLeast(line, logsLevel) {
+				continue
+			}
+			fmt.Printf("%s %s\n", prefix, logLine(line, cfg))
+			printed++
+		}
+	}
+	return printed, 0, false
+}
+
+// logLine renders a structured log line as "time LEVEL step: message",
+// colored by level; other lines are printed as they are
+func logLine(line string, cfg *config.Config) string {
+	e, ok := logstream.ParseEntry(line)
+	if !ok {
+		return line
+	}
+	text := e.String()
+	switch e.Level {
+	case logstream.LevelError:
+		text = color.RedString(text)
+	case logstream.LevelWarn:
+		text = color.YellowString(text)
+	case logstream.LevelDebug:
+		text = color.New(color.Faint).Sprint(text)
+	}
+	if e.Time.IsZero() {
+		return text
+	}
+	return e.Time.In(displayLocation(cfg)).Format("15:04:05") + " " + text
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/events"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/queue"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func OutboxCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "outbox",
+		Short: "Manage starts deferred while GitHub was unreachable",
+		Long: `When start cannot reach GitHub, or no token is set, the request is kept
+locally in .autonomous-dev/outbox/ instead of failing. 'outbox flush'
+dispatches the deferred starts once connectivity and credentials are back;
+'autonomous-dev serve' flushes the outbox on every tick.`,
+	}
+
+	cmd.AddCommand(outboxListCmd())
+	cmd.AddCommand(outboxFlushCmd())
+	cmd.AddCommand(outboxRemoveCmd())
+
+	return cmd
+}
+
+func outboxListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List deferred starts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bold := color.New(color.Bold).SprintFunc()
+			cyan := color.New(color.FgCyan).SprintFunc()
+
+			entries, err := queue.Open(queue.OutboxDir()).List()
+			if err != nil {
+				return err
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("Outbox is empty")
+				return nil
+			}
+
+			fmt.Println(bold("Deferred starts:"))
+			for _, entry := range entries {
+				fmt.Printf("  %s  %-6s  %d instances  %s\n", cyan(entry.ID), entry.PriorityOf(), entry.Instances, entry.Task)
+				if entry.Reason != "" {
+					fmt.Printf("    reason: %s\n", entry.Reason)
+				}
+				if entry.LastError != "" {
+					fmt.Printf("    failed %d time(s): %s\n", entry.Attempts, entry.LastError)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func outboxFlushCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "flush",
+		Short: "Dispatch deferred starts",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			green := color.New(color.FgGreen).SprintFunc()
+
+			cfg, err := config.Load(config.ConfigPath())
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			client := newClient(cfg)
+
+			entries, err := queue.Open(queue.OutboxDir()).List()
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				fmt.Println("Outbox is empty")
+				return nil
+			}
+
+			flushed, err := flushOutbox(client, cfg, newEventBus(client, cfg))
+			summarize("flushed", flushed)
+			if err != nil {
+				return err
+			}
+			if flushed > 0 {
+				fmt.Printf("%s Flushed %d of %d deferred start(s)\n", green(iconOK), flushed, len(entries))
+			}
+			return nil
+		},
+	}
+}
+
+func outboxRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <id>",
+		Short: "Remove a deferred start",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			green := color.New(color.FgGreen).SprintFunc()
+
+			if err := queue.Open(queue.OutboxDir()).Remove(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("%s Removed %s\n", green(iconOK), args[0])
+			return nil
+		},
+	}
+}
+
+// deferRun keeps a start in the outbox because GitHub cannot be used now
+func deferRun(req runRequest, reason string) error {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	entry := requestEntry(req, reason)
+	if err := queue.Open(queue.OutboxDir()).Add(entry); err != nil {
+		return err
+	}
+
+	summarize("status", "deferred")
+	summarize("outbox_id", entry.ID)
+	fmt.Printf("%s Start deferred: %s\n", yellow(iconPaused), reason)
+	fmt.Printf("  Outbox entry: %s\n", entry.ID)
+	fmt.Println()
+	fmt.Println("Dispatch it once GitHub is reachable with:")
+	fmt.Println("  autonomous-dev outbox flush")
+
+	return nil
+}
+
+// flushOutbox dispatches deferred starts in order and returns how many were
+// dispatched. Starts over quota move to the run queue. It stops without an
+// error while GitHub is still unreachable or rejects the token. A start that
+// fails is kept with its error and what it created, and the flush goes on
+// with the next one.
+func flushOutbox(client *github.Client, cfg *config.Config, bus *events.Bus) (int, error) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	outbox := queue.Open(queue.OutboxDir())
+	entries, err := outbox.List()
+	if err != nil || len(entries) == 0 {
+		return 0, err
+	}
+
+	if cfg.GitHub.Token == "" {
+		fmt.Printf("%s Outbox: %d deferred start(s) wait for a GitHub token\n", yellow(iconPaused), len(entries))
+		return 0, nil
+	}
+	if err := client.Ping(); err != nil {
+		if errors.Is(err, github.ErrUnreachable) || errors.Is(err, github.ErrUnauthorized) {
+			fmt.Printf("%s Outbox: %d deferred start(s) wait: %v\n", yellow(iconPaused), len(entries), err)
+			return 0, nil
+		}
+		return 0, err
+	}
+	// Deferred while offline, the starts were never checked
+	if err := checkDispatch(client, cfg); err != nil {
+		return 0, err
+	}
+
+	flushed, failed := 0, 0
+	for _, entry := range entries {
+		req := entryRequest(entry)
+
+		if cfg.Quotas.Enabled() {
+			decision, err := checkQuota(client, cfg)
+			if err != nil {
+				return flushed, fmt.Errorf("failed to check quotas: %w", err)
+			}
+			if !decision.Allowed {
+				if err := queueRun(cfg, req, decision.Reason); err != nil {
+					return flushed, err
+				}
+				if err := outbox.Remove(entry.ID); err != nil {
+					return flushed, err
+				}
+				continue
+			}
+		}
+
+		fmt.Printf("Dispatching deferred start %s\n", entry.ID)
+		if _, _, err := dispatchRun(client, cfg, bus, req); err != nil {
+			keepCreated(entry, err)
+			entry.Attempts++
+			entry.LastError = err.Error()
+			if err := outbox.Update(entry); err != nil {
+				return flushed, err
+			}
+			fmt.Printf("%s Deferred start %s failed (attempt %d): %v\n", yellow(iconWarn), entry.ID, entry.Attempts, err)
+			failed++
+			continue
+		}
+		if err := outbox.Remove(entry.ID); err != nil {
+			return flushed, err
+		}
+		flushed++
+	}
+	if failed > 0 {
+		return flushed, fmt.Errorf("%d deferred start(s) failed; see 'autonomous-dev outbox list'", failed)
+	}
+	return flushed, nil
+}
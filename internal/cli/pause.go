@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/coordination"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pauseIssue  int
+	pauseRun    string
+	pauseCancel bool
+	pauseGrace  time.Duration
+	pauseReason string
+
+	resumeIssue int
+	resumeRun   string
+)
+
+// pausePollInterval is how often pause --cancel checks for checkpoints
+const pausePollInterval = 10 * time.Second
+
+// pauseOptions selects the run to pause and how, as the pause flags do
+type pauseOptions struct {
+	Issue  int
+	Run    string
+	Cancel bool
+	Grace  time.Duration
+	Reason string
+}
+
+func PauseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pause an in-flight run",
+		Long: `Pause a run: instances commit their work to their instance branches,
+report "paused" and idle until 'autonomous-dev resume'. Assignments and
+results stay in the coordination issue (or state branch run).
+
+Idle instances keep their runners busy. With --cancel, the workflow run is
+cancelled once instances checkpointed (or after --grace); resume then
+dispatches a new run that continues from the checkpoints.`,
+		Example: `  autonomous-dev pause --issue 42
+  autonomous-dev pause --issue 42 --cancel --reason "Weekend"`,
+		RunE: runPause,
+	}
+
+	cmd.Flags().IntVar(&pauseIssue, "issue", 0, "Coordination issue number")
+	cmd.Flags().StringVar(&pauseRun, "run", "", "State branch run ID in dispatch mode (default latest)")
+	cmd.Flags().BoolVar(&pauseCancel, "cancel", false, "Cancel the workflow run after instances checkpointed")
+	cmd.Flags().DurationVar(&pauseGrace, "grace", 2*time.Minute, "How long --cancel waits for checkpoints")
+	cmd.Flags().StringVar(&pauseReason, "reason", "", "Reason recorded with the pause")
+
+	return cmd
+}
+
+func ResumeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resume a paused run",
+		Long: `Resume a run paused with 'autonomous-dev pause'. Idle instances continue
+where they stopped. If the workflow run was cancelled, a new run is
+dispatched with the same instance count and base commit; its instances
+restore their checkpoints and keep their assignments.`,
+		Example: `  autonomous-dev resume --issue 42`,
+		RunE:    runResume,
+	}
+
+	cmd.Flags().IntVar(&resumeIssue, "issue", 0, "Coordination issue number")
+	cmd.Flags().StringVar(&resumeRun, "run", "", "State branch run ID in dispatch mode (default latest)")
+
+	return cmd
+}
+
+func runPause(cmd *cobra.Command, args []string) error {
+	return pauseTarget(pauseOptions{Issue: pauseIssue, Run: pauseRun, Cancel: pauseCancel, Grace: pauseGrace, Reason: pauseReason})
+}
+
+// pauseTarget pauses the run of the issue (or state branch run), for the
+// pause command and /pause comments
+func pauseTarget(opts pauseOptions) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	client, store, snap, err := controlTarget(opts.Issue, opts.Run)
+	if err != nil {
+		return err
+	}
+	if snap.Paused() {
+		return fmt.Errorf("%s is already paused", store.Location())
+	}
+
+	run, err := storeWorkflowRun(client, store)
+	if err != nil {
+		return err
+	}
+	if run == nil || run.Status == "completed" {
+		return fmt.Errorf("no active workflow run found for %s", store.Location())
+	}
+	jobs, err := client.GetWorkflowJobs(run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get workflow jobs: %w", err)
+	}
+	_, total := countInstanceJobs(jobs)
+
+	mode := protocol.PauseCheckpoint
+	if opts.Cancel {
+		mode = protocol.PauseCancel
+	}
+	if err := postControl(store, &protocol.Control{
+		Action:    protocol.ActionPause,
+		Mode:      mode,
+		RunID:     run.ID,
+		Instances: total,
+		BaseSHA:   run.BaseSHA(),
+		Reason:    opts.Reason,
+	}); err != nil {
+		return err
+	}
+	summarize("status", "paused")
+	summarize("run_id", run.ID)
+	summarize("url", store.URL())
+	fmt.Printf("%s Asked instances of run #%d to checkpoint and pause (%s)\n", green(iconOK), run.ID, store.Location())
+
+	if !opts.Cancel {
+		fmt.Println()
+		fmt.Println("Resume with:")
+		fmt.Printf("  autonomous-dev resume %s\n", controlFlags(store))
+		return nil
+	}
+
+	// Cancel once every running instance has checkpointed, or after the grace period
+	deadline := time.Now().Add(opts.Grace)
+	for {
+		snap, _, err = readCoordination(store)
+		if err != nil {
+			return err
+		}
+		if waiting := unpausedInstances(snap); waiting == 0 {
+			break
+		} else if time.Now().After(deadline) {
+			fmt.Printf("%s %d instance(s) did not checkpoint within %s\n", yellow(iconWarn), waiting, opts.Grace)
+			break
+		}
+		time.Sleep(pausePollInterval)
+	}
+
+	if err := client.CancelWorkflowRun(run.ID); err != nil {
+		return err
+	}
+	summarize("conclusion", "cancelled")
+	fmt.Printf("%s Cancelled run #%d\n", green(iconOK), run.ID)
+	fmt.Printf("  Assignments: %d, results: %d (kept in %s)\n", len(snap.Assignments), len(snap.Results), store.Location())
+	fmt.Println()
+	fmt.Println("Resume with:")
+	fmt.Printf("  autonomous-dev resume %s\n", controlFlags(store))
+
+	return nil
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	return resumeTarget(resumeIssue, resumeRun)
+}
+
+// resumeTarget resumes the paused run of the issue (or state branch run),
+// for the resume command and /resume comments
+func resumeTarget(issue int, runID string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+
+	client, store, snap, err := controlTarget(issue, runID)
+	if err != nil {
+		return err
+	}
+	if !snap.Paused() {
+		return fmt.Errorf("%s is not paused", store.Location())
+	}
+	pause := snap.Control
+
+	if err := postControl(store, &protocol.Control{Action: protocol.ActionResume, RunID: pause.RunID}); err != nil {
+		return err
+	}
+	summarize("status", "resumed")
+	summarize("url", store.URL())
+	fmt.Printf("%s Resumed %s\n", green(iconOK), store.Location())
+
+	// Idle instances pick up the resume themselves; cancelled runs need a new run
+	run, err := client.GetWorkflowRun(pause.RunID)
+	if err != nil {
+		return err
+	}
+	if pause.Mode != protocol.PauseCancel && run.Status != "completed" {
+		return nil
+	}
+
+	inputs := map[string]string{}
+	if pause.BaseSHA != "" {
+		inputs["base_sha"] = pause.BaseSHA
+	}
+	if env := run.Environment(); env != "" {
+		inputs["environment"] = env
+	}
+	switch s := store.(type) {
+	case *coordination.IssueStore:
+		_, err = client.TriggerWorkflow(s.Issue(), pause.Instances, inputs)
+	case *coordination.BranchStore:
+		_, err = client.DispatchRun(s.RunID(), pause.Instances, inputs)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to trigger workflow: %w", err)
+	}
+	fmt.Printf("%s Dispatched a new run with %d instances to continue from the checkpoints\n", green(iconOK), pause.Instances)
+
+	return nil
+}
+
+// controlTarget loads the config and reads the coordination state of the
+// --issue (or state branch --run) to pause or resume
+func controlTarget(issue int, runID string) (*github.Client, coordination.Store, *protocol.Snapshot, error) {
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	client := newClient(cfg)
+
+	store, err := coordinationStore(client, cfg, issue, runID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if store == nil {
+		if cfg.Coordination.Dispatch() {
+			return nil, nil, nil, fmt.Errorf("no runs found on %s", cfg.Coordination.Branch())
+		}
+		return nil, nil, nil, fmt.Errorf("--issue is required")
+	}
+
+	snap, _, err := readCoordination(store)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return client, store, snap, nil
+}
+
+// postControl posts a control message as the CLI (instance 0)
+func postControl(store coordination.Store, control *protocol.Control) error {
+	control.Version = protocol.Version
+	control.IssuedAt = time.Now().UTC().Format(time.RFC3339)
+
+	body, err := protocol.Render(0, control)
+	if err != nil {
+		return err
+	}
+	if err := store.Post(body); err != nil {
+		return fmt.Errorf("failed to post %s: %w", control.Action, err)
+	}
+	return nil
+}
+
+// unpausedInstances counts instances still working on the run
+func unpausedInstances(snap *protocol.Snapshot) int {
+	waiting := 0
+	for _, st := range snap.Statuses {
+		switch st.Status {
+		case "paused", "completed", "failed", "stale":
+		default:
+			waiting++
+		}
+	}
+	return waiting
+}
+
+// controlFlags returns the flags that select a store again
+func controlFlags(store coordination.Store) string {
+	switch s := store.(type) {
+	case *coordination.IssueStore:
+		return fmt.Sprintf("--issue %d", s.Issue())
+	case *coordination.BranchStore:
+		return "--run " + s.RunID()
+	}
+	return ""
+}
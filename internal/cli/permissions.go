@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/permissions"
+	"github.com/autonomous-dev/cli/internal/queue"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	permissionsAuditLimit  int
+	permissionsAuditUser   string
+	permissionsAuditDenied bool
+)
+
+func PermissionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "permissions",
+		Short: "Show who may run comment commands",
+		Long: `Show who may run each comment command answered by 'serve --commands':
+/auto-fix, /close, /pause and /resume.
+
+The permissions: config maps GitHub users and "@org/team" teams to the
+commands they may run; "*" grants every command. Team membership is
+checked through the API, which needs a token with the read:org scope.
+Without permissions:, collaborators with write access may run every
+command.
+
+Every command, allowed or denied, is recorded in the audit journal under
+.autonomous-dev/audit/; 'permissions audit' shows it.`,
+		Example: `  autonomous-dev permissions
+  autonomous-dev permissions check octocat close
+  autonomous-dev permissions audit --denied`,
+		RunE: runPermissions,
+	}
+
+	cmd.AddCommand(permissionsCheckCmd())
+	cmd.AddCommand(permissionsAuditCmd())
+
+	return cmd
+}
+
+func permissionsCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check <user> <command>",
+		Short: "Check whether a user may run a comment command",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runPermissionsCheck,
+	}
+}
+
+func permissionsAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show the audit trail of comment commands",
+		RunE:  runPermissionsAudit,
+	}
+
+	cmd.Flags().IntVar(&permissionsAuditLimit, "limit", 20, "Number of most recent commands to show (0 = all)")
+	cmd.Flags().StringVar(&permissionsAuditUser, "user", "", "Only show commands of this user")
+	cmd.Flags().BoolVar(&permissionsAuditDenied, "denied", false, "Only show denied commands")
+
+	return cmd
+}
+
+func runPermissions(cmd *cobra.Command, args []string) error {
+	bold := color.New(color.Bold).SprintFunc()
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	policy := permissions.Policy(cfg.Permissions)
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	fmt.Println(bold("Comment commands:"))
+	for _, command := range permissions.Commands {
+		who := "collaborators with write access"
+		if len(policy) > 0 {
+			var names []string
+			for _, p := range policy.Grants(command) {
+				names = append(names, p.String())
+			}
+			who = strings.Join(names, ", ")
+			if who == "" {
+				who = "no one"
+			}
+		}
+		fmt.Printf("  /%-10s %s\n", command, who)
+	}
+	return nil
+}
+
+func runPermissionsCheck(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	user, command := args[0], strings.TrimPrefix(args[1], "/")
+	if _, ok := permissions.Parse("/" + command); !ok {
+		return fmt.Errorf("unknown command %q (expected %s)", command, strings.Join(permissions.Commands, ", "))
+	}
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := permissions.Policy(cfg.Permissions).Validate(); err != nil {
+		return err
+	}
+
+	grant, reason, err := authorize(newClient(cfg), cfg, user, command)
+	if err != nil {
+		return err
+	}
+	summarize("allowed", grant != "")
+	if grant == "" {
+		fmt.Printf("%s %s may not run /%s: %s\n", red(iconFail), user, command, reason)
+		return nil
+	}
+	fmt.Printf("%s %s may run /%s (granted to %s)\n", green(iconOK), user, command, grant)
+	return nil
+}
+
+func runPermissionsAudit(cmd *cobra.Command, args []string) error {
+	bold := color.New(color.Bold).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	// The audit trail is local, so a missing config only affects time zone display
+	cfg, _ := config.Load(config.ConfigPath())
+
+	all, err := permissions.Open(permissions.DefaultDir()).List()
+	if err != nil {
+		return err
+	}
+	var records []permissions.Record
+	for _, r := range all {
+		if permissionsAuditUser != "" && !strings.EqualFold(r.User, permissionsAuditUser) {
+			continue
+		}
+		if permissionsAuditDenied && r.Allowed {
+			continue
+		}
+		records = append(records, r)
+	}
+	if len(records) == 0 {
+		fmt.Println("No comment commands recorded")
+		return nil
+	}
+	if permissionsAuditLimit > 0 && len(records) > permissionsAuditLimit {
+		records = records[len(records)-permissionsAuditLimit:]
+	}
+
+	fmt.Println(bold("Comment commands:"))
+	for _, r := range records {
+		outcome := green("allowed") + " (" + r.Grant + ")"
+		switch {
+		case !r.Allowed:
+			outcome = red("denied") + " (" + r.Reason + ")"
+		case r.Error != "":
+			outcome = red("failed") + " (" + r.Error + ")"
+		}
+		fmt.Printf("%s  #%-5d %-16s %-14s %s\n", formatTime(r.At, cfg), r.Issue, r.User, r.Command, outcome)
+	}
+	return nil
+}
+
+// authorize returns the principal that allows user to run command, or why
+// the command is denied. An error means no grant could be confirmed because
+// a membership check failed.
+func authorize(client *github.Client, cfg *config.Config, user, command string) (string, string, error) {
+	if len(cfg.Permissions) == 0 {
+		level, err := client.PermissionLevel(user)
+		if err != nil {
+			return "", "", err
+		}
+		if level == "admin" || level == "write" {
+			return "collaborators with write access", "", nil
+		}
+		return "", fmt.Sprintf("needs write access to the repository (has %s)", level), nil
+	}
+
+	grants := permissions.Policy(cfg.Permissions).Grants(command)
+	if len(grants) == 0 {
+		return "", "no one is granted /" + command, nil
+	}
+	var names []string
+	var checkErr error
+	for _, p := range grants {
+		names = append(names, p.String())
+		if p.Team == "" {
+			if strings.EqualFold(p.User, user) {
+				return p.String(), "", nil
+			}
+			continue
+		}
+		member, err := client.IsTeamMember(p.Org, p.Team, user)
+		if err != nil {
+			checkErr = err
+			continue
+		}
+		if member {
+			return p.String(), "", nil
+		}
+	}
+	if checkErr != nil {
+		return "", "", checkErr
+	}
+	return "", "only " + strings.Join(names, ", ") + " may run /" + command, nil
+}
+
+// answerCommands runs the comment commands posted since the given time,
+// replies to each on its issue and records it in the audit trail. It
+// returns the time to continue from.
+func answerCommands(client *github.Client, cfg *config.Config, since time.Time) (time.Time, error) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	next := time.Now()
+	comments, err := client.ListCommentsSince(since)
+	if err != nil {
+		return since, err
+	}
+
+	audit := permissions.Open(permissions.DefaultDir())
+	for _, c := range comments {
+		// Edits of comments posted before the last check are not commands
+		if c.CreatedAt.Before(since) {
+			continue
+		}
+		command, ok := permissions.Parse(c.Body)
+		if !ok {
+			continue
+		}
+
+		record := permissions.Record{At: time.Now().UTC(), Issue: c.Issue, Comment: c.ID, User: c.Author, Command: command.String()}
+		grant, reason, err := authorize(client, cfg, c.Author, command.Name)
+		if err != nil {
+			reason = "membership check failed: " + err.Error()
+		}
+		record.Allowed, record.Grant, record.Reason = grant != "", grant, reason
+
+		var reply string
+		if record.Allowed {
+			reply, err = runComment(client, cfg, c, command)
+			if err != nil {
+				record.Error = err.Error()
+				reply = fmt.Sprintf("❌ `%s` from @%s failed: %v", command, c.Author, err)
+				fmt.Printf("%s %s on #%d by %s failed: %v\n", red(iconFail), command, c.Issue, c.Author, err)
+			} else {
+				fmt.Printf("%s %s on #%d by %s (granted to %s)\n", green(iconOK), command, c.Issue, c.Author, grant)
+			}
+		} else {
+			reply = fmt.Sprintf("🔒 @%s is not allowed to run `%s`: %s.", c.Author, command, reason)
+			fmt.Printf("%s Denied %s on #%d by %s: %s\n", yellow(iconWarn), command, c.Issue, c.Author, reason)
+		}
+
+		if err := audit.Append(record); err != nil {
+			return next, err
+		}
+		if err := postComment(client, c.Issue, reply); err != nil {
+			fmt.Printf("%s Warning: failed to reply to %s on #%d: %v\n", yellow(iconWarn), command, c.Issue, err)
+		}
+	}
+	return next, nil
+}
+
+// runComment runs an allowed comment command and returns the reply
+func runComment(client *github.Client, cfg *config.Config, c github.Comment, command permissions.Command) (string, error) {
+	switch command.Name {
+	case permissions.AutoFix:
+		req := runRequest{Instances: cfg.Instances.Default, Priority: queue.PriorityNormal, Issue: c.Issue}
+		if len(command.Args) > 0 {
+			n, err := strconv.Atoi(command.Args[0])
+			if err != nil || n < 1 || n > cfg.Instances.Max {
+				return "", fmt.Errorf("invalid instance count %q (expected 1 to %d)", command.Args[0], cfg.Instances.Max)
+			}
+			req.Instances = n
+		}
+		row := batchRow{where: fmt.Sprintf("#%d", c.Issue), req: req}
+		if err := resolveBatchRow(client, cfg, &row); err != nil {
+			return "", err
+		}
+		// Queued runs are dispatched later in the same tick, as quotas allow
+		if err := queue.Open(queue.DefaultDir()).Add(requestEntry(row.req, fmt.Sprintf("/%s from @%s", command.Name, c.Author))); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("🤖 Queued a run with %d instances for this issue, requested by @%s; it is dispatched as quotas allow.", row.req.Instances, c.Author), nil
+
+	case permissions.Close:
+		if err := client.CloseIssue(c.Issue); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Closed with `/close` by @%s.", c.Author), nil
+
+	case permissions.Pause, permissions.Resume:
+		if cfg.Coordination.Dispatch() {
+			return "", fmt.Errorf("runs coordinated on %s cannot be controlled from issue comments", cfg.Coordination.Branch())
+		}
+		if command.Name == permissions.Pause {
+			if err := pauseTarget(pauseOptions{Issue: c.Issue, Reason: fmt.Sprintf("/pause from @%s", c.Author)}); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("⏸ Pausing as requested by @%s; instances checkpoint and idle until `/resume`.", c.Author), nil
+		}
+		if err := resumeTarget(c.Issue, ""); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("▶️ Resumed as requested by @%s.", c.Author), nil
+	}
+	return "", fmt.Errorf("unknown command %s", command)
+}
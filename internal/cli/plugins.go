@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/plugin"
+	"github.com/autonomous-dev/cli/pkg/version"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// pluginAnnotation marks commands run by a plugin executable; its value is
+// the executable's path
+const pluginAnnotation = "plugin"
+
+// reservedCommands are added by cobra itself and cannot be plugins
+var reservedCommands = []string{"help", "completion"}
+
+// PluginExitError reports a plugin that exited unsuccessfully; the CLI exits
+// with the same status
+type PluginExitError struct {
+	Plugin string
+	Code   int
+}
+
+func (e *PluginExitError) Error() string {
+	return fmt.Sprintf("plugin %s exited with status %d", e.Plugin, e.Code)
+}
+
+func PluginsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "plugins",
+		Short: "List plugin commands",
+		Long: `List the plugin commands found in .autonomous-dev/plugins/ and on PATH.
+
+Any executable named autonomous-dev-<name> becomes the subcommand
+'autonomous-dev <name>', like git and kubectl plugins, so teams can ship
+their own commands without forking the CLI. Plugins in the repository's
+.autonomous-dev/plugins/ win over ones on PATH, and built-in commands win
+over both. 'autonomous-dev init' keeps that directory tracked by git while
+ignoring the rest of .autonomous-dev/, so commit plugins with the
+repository.
+
+Plugins get every argument after their name unparsed, the environment of
+the CLI and:
+  AUTONOMOUS_DEV_BIN      path of the autonomous-dev executable
+  AUTONOMOUS_DEV_CONFIG   path of the config file
+  AUTONOMOUS_DEV_VERSION  version of the CLI
+  AUTONOMOUS_DEV_REPO     owner/repo of the config, if it loads
+The CLI exits with the plugin's exit status.
+
+An optional manifest next to the executable, named like it plus .yaml
+(without .exe on Windows), describes the command for help:
+  short: Generate the quarterly compliance report
+  long: ...
+  usage: "[--since DATE]"
+  example: autonomous-dev compliance-report --since 2024-01-01`,
+		RunE: runPlugins,
+	}
+}
+
+// AddPlugins registers a command for every plugin whose name no built-in
+// command uses. It is called after the built-in commands are added.
+func AddPlugins(root *cobra.Command) {
+	for _, p := range plugin.Discover(plugin.SearchPath()) {
+		if builtinCommand(root, p.Name) {
+			continue
+		}
+		root.AddCommand(pluginCmd(p))
+	}
+}
+
+// builtinCommand reports whether name is taken by a command of the CLI
+func builtinCommand(root *cobra.Command, name string) bool {
+	for _, reserved := range reservedCommands {
+		if name == reserved {
+			return true
+		}
+	}
+	for _, c := range root.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func pluginCmd(p plugin.Plugin) *cobra.Command {
+	short := p.Manifest.Short
+	if short == "" {
+		short = "Plugin " + filepath.Base(p.Path)
+	}
+	use := p.Name
+	if p.Manifest.Usage != "" {
+		use += " " + p.Manifest.Usage
+	}
+	return &cobra.Command{
+		Use:     use,
+		Short:   short,
+		Long:    p.Manifest.Long,
+		Example: p.Manifest.Example,
+		// Flags, --help included, belong to the plugin
+		DisableFlagParsing: true,
+		SilenceErrors:      true,
+		SilenceUsage:       true,
+		Annotations:        map[string]string{pluginAnnotation: p.Path},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPlugin(p, args)
+		},
+	}
+}
+
+// runPlugin runs a plugin executable attached to the terminal
+func runPlugin(p plugin.Plugin, args []string) error {
+	c := exec.Command(p.Path, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = append(os.Environ(), pluginEnv()...)
+
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &PluginExitError{Plugin: p.Name, Code: exitErr.ExitCode()}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run plugin %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// pluginEnv returns the variables telling a plugin about the CLI and the
+// repository it runs in
+func pluginEnv() []string {
+	configPath := config.ConfigPath()
+	if abs, err := filepath.Abs(configPath); err == nil {
+		configPath = abs
+	}
+	env := []string{
+		"AUTONOMOUS_DEV_CONFIG=" + configPath,
+		"AUTONOMOUS_DEV_VERSION=" + version.Version,
+	}
+	if bin, err := os.Executable(); err == nil {
+		env = append(env, "AUTONOMOUS_DEV_BIN="+bin)
+	}
+	if cfg, err := config.Load(config.ConfigPath()); err == nil && cfg.GitHub.Owner != "" {
+		env = append(env, "AUTONOMOUS_DEV_REPO="+cfg.GitHub.Owner+"/"+cfg.GitHub.Repo)
+	}
+	return env
+}
+
+func runPlugins(cmd *cobra.Command, args []string) error {
+	bold := color.New(color.Bold).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	plugins := plugin.Discover(plugin.SearchPath())
+	summarize("plugins", len(plugins))
+	if len(plugins) == 0 {
+		fmt.Printf("No plugins found (executables named %s<name> in %s or on PATH)\n", plugin.Prefix, plugin.Dir())
+		return nil
+	}
+
+	fmt.Println(bold("Plugins:"))
+	for _, p := range plugins {
+		short := p.Manifest.Short
+		if short == "" {
+			short = "(no manifest)"
+		}
+		fmt.Printf("  %-20s %s\n", p.Name, short)
+		fmt.Printf("  %-20s %s\n", "", cyan(p.Path))
+		if p.ManifestErr != nil {
+			fmt.Printf("  %-20s %s %v\n", "", yellow(iconWarn), p.ManifestErr)
+		}
+		if pluginShadowedByBuiltin(cmd.Root(), p.Name) {
+			fmt.Printf("  %-20s %s Ignored: %q is a built-in command\n", "", yellow(iconWarn), p.Name)
+		}
+		for _, path := range p.Shadowed {
+			fmt.Printf("  %-20s %s Shadows %s\n", "", yellow(iconWarn), path)
+		}
+	}
+	return nil
+}
+
+// pluginShadowedByBuiltin reports whether a built-in command hides the
+// plugin of the given name
+func pluginShadowedByBuiltin(root *cobra.Command, name string) bool {
+	c, _, err := root.Find([]string{name})
+	if err != nil || c == root {
+		return false
+	}
+	_, isPlugin := c.Annotations[pluginAnnotation]
+	return !isPlugin
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/poll"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+)
+
+// newPoller returns the adaptive poller of a command that polls every
+// interval while a run is active and changing (see network.polling)
+func newPoller(cfg *config.Config, interval time.Duration) *poll.Poller {
+	p := &cfg.Network.Polling
+	return poll.New(poll.Policy{Min: interval, Max: p.IdleInterval(), Jitter: p.JitterShare(), Fixed: p.Fixed})
+}
+
+// failedPoll is the observation of a poll that failed with err, honoring
+// rate limit resets
+func failedPoll(err error) poll.Observation {
+	wait, _ := github.RateLimitWait(err)
+	return poll.Observation{Active: true, Err: err, RetryAfter: wait}
+}
+
+// transientError reports whether a failed poll is worth retrying: GitHub
+// was unavailable or rate limited the request
+func transientError(err error) bool {
+	_, limited := github.RateLimitWait(err)
+	return limited || github.IsOutage(err)
+}
+
+// runFingerprint summarizes a run, its jobs and, if known, its instances'
+// reports, so pollers can tell whether anything changed between polls
+func runFingerprint(run *github.WorkflowRun, jobs []github.Job, snap *protocol.Snapshot) string {
+	if run == nil {
+		return ""
+	}
+	parts := []string{fmt.Sprintf("%d/%d %s %s", run.ID, run.Attempt, run.Status, run.Conclusion)}
+	for _, job := range jobs {
+		parts = append(parts, fmt.Sprintf("%d %s %s", job.ID, job.Status, job.Conclusion))
+	}
+	if snap != nil {
+		for _, id := range snap.InstanceIDs() {
+			if st, ok := snap.Statuses[id]; ok {
+				parts = append(parts, fmt.Sprintf("i%d %s %s %d", id, st.Status, st.CurrentTask.ID, st.CurrentTask.Progress))
+			}
+		}
+		results := make([]string, 0, len(snap.Results))
+		for id := range snap.Results {
+			results = append(results, id)
+		}
+		sort.Strings(results)
+		parts = append(parts, results...)
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Summary line formats of --porcelain
+const (
+	porcelainKV   = "kv"
+	porcelainJSON = "json"
+)
+
+var globalPorcelain string
+
+// summaryOrder lists well-known summary fields first; others follow sorted
+var summaryOrder = []string{"command", "result", "run_id", "issue", "status", "conclusion", "cost_usd", "url", "error"}
+
+// exitSummary holds the fields of the --porcelain summary line
+var exitSummary = map[string]string{}
+
+// summarize records a field of the --porcelain summary line. Later values
+// replace earlier ones.
+func summarize(key string, value interface{}) {
+	exitSummary[key] = fmt.Sprint(value)
+}
+
+// checkPorcelain validates --porcelain and turns off colors so human output
+// does not carry escape codes into scripts
+func checkPorcelain(cmd *cobra.Command, args []string) error {
+	switch globalPorcelain {
+	case "":
+		return nil
+	case porcelainKV, porcelainJSON:
+		color.NoColor = true
+		return nil
+	default:
+		return fmt.Errorf("invalid --porcelain format %q: must be %s or %s", globalPorcelain, porcelainKV, porcelainJSON)
+	}
+}
+
+// PrintSummary ends the output with one machine-parseable summary line when
+// --porcelain is set: key=value pairs, or a JSON object with --porcelain=json
+func PrintSummary(cmd *cobra.Command, cmdErr error) {
+	if globalPorcelain != porcelainKV && globalPorcelain != porcelainJSON {
+		return
+	}
+
+	if cmd != nil {
+		summarize("command", telemetryCommandName(cmd))
+	}
+	if cmdErr != nil {
+		summarize("result", "error")
+		summarize("error", cmdErr.Error())
+	} else {
+		summarize("result", "ok")
+	}
+
+	if globalPorcelain == porcelainJSON {
+		data, err := json.Marshal(exitSummary)
+		if err == nil {
+			fmt.Println(string(data))
+		}
+		return
+	}
+
+	var pairs []string
+	for _, key := range summaryKeys() {
+		value := exitSummary[key]
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	fmt.Println(strings.Join(pairs, " "))
+}
+
+// summaryKeys returns the recorded summary fields in output order
+func summaryKeys() []string {
+	var keys, rest []string
+	for _, key := range summaryOrder {
+		if _, ok := exitSummary[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	for key := range exitSummary {
+		if !containsString(summaryOrder, key) {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
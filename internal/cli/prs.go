@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	prsIssue int
+	prsForce bool
+)
+
+func PRsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prs",
+		Short: "Operate on a run's pull requests",
+		Long: `Operate on the instance pull requests of a run at once.
+
+With prs.draft (the default for new configs), instances open their pull
+requests as drafts: reviewers are not notified and auto-merge cannot start
+until the run is ready.`,
+	}
+
+	cmd.AddCommand(prsReadyCmd())
+
+	return cmd
+}
+
+func prsReadyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ready",
+		Short: "Mark a run's draft pull requests ready for review",
+		Long: `Mark every open draft instance pull request of a run ready for review.
+
+Pull requests whose checks have not passed (pending, failing, or blocked
+as overlapping another instance's changes) stay drafts unless --force is
+given. With branches.auto_merge, auto-merge is enabled for each pull request
+marked ready.`,
+		Example: `  autonomous-dev prs ready --issue 42
+  autonomous-dev prs ready --issue 42 --force`,
+		RunE: runPRsReady,
+	}
+
+	cmd.Flags().IntVar(&prsIssue, "issue", 0, "Coordination issue number")
+	cmd.Flags().BoolVar(&prsForce, "force", false, "Mark pull requests ready even if their checks have not passed")
+	cmd.MarkFlagRequired("issue")
+
+	return cmd
+}
+
+func runPRsReady(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := newClient(cfg)
+
+	prs, err := runPullRequests(client, prsIssue)
+	if err != nil {
+		return err
+	}
+	if len(prs) == 0 {
+		fmt.Printf("No open instance pull requests for issue #%d\n", prsIssue)
+		return nil
+	}
+
+	ready, held := 0, 0
+	for _, pr := range prs {
+		if !pr.Draft {
+			fmt.Printf("%s PR #%d is already ready for review\n", green(iconOK), pr.Number)
+			continue
+		}
+		if pr.Checks != "success" && !prsForce {
+			fmt.Printf("%s PR #%d stays a draft: checks %s\n", yellow(iconWarn), pr.Number, orNone(pr.Checks))
+			held++
+			continue
+		}
+
+		if err := client.MarkReadyForReview(pr.NodeID); err != nil {
+			return fmt.Errorf("PR #%d: %w", pr.Number, err)
+		}
+		ready++
+		fmt.Printf("%s PR #%d ready for review: %s\n", green(iconOK), pr.Number, pr.Title)
+
+		if cfg.Branches.AutoMerge {
+			if err := client.EnablePullRequestAutoMerge(pr.NodeID); err != nil {
+				fmt.Printf("%s Warning: PR #%d: %v\n", yellow(iconWarn), pr.Number, err)
+			}
+		}
+	}
+
+	summarize("ready", ready)
+	summarize("held", held)
+	if held > 0 {
+		fmt.Printf("\n%d pull request(s) held back; fix their checks or rerun with --force\n", held)
+	}
+	return nil
+}
+
+// runPullRequests returns the open instance pull requests of an issue run,
+// found through their link markers in the issue's cross-references
+func runPullRequests(client *github.Client, issue int) ([]github.Reference, error) {
+	graph, err := client.GetIssueGraph(issue)
+	if err != nil {
+		return nil, err
+	}
+
+	var prs []github.Reference
+	for _, ref := range graph.References {
+		if !ref.PullRequest || ref.State != "open" {
+			continue
+		}
+		link, ok := protocol.ParseLink(ref.Body)
+		if !ok || link.Kind != protocol.LinkInstancePR || !link.Of(issue, "") {
+			continue
+		}
+		prs = append(prs, ref)
+	}
+	sort.Slice(prs, func(i, j int) bool { return prs[i].Number < prs[j].Number })
+	return prs, nil
+}
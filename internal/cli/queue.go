@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/autonomous-dev/cli/internal/queue"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func QueueCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Manage runs waiting for dispatch",
+		Long: fmt.Sprintf(`Runs that exceed quotas or fall outside scheduling windows are queued
+locally in .autonomous-dev/queue/ and dispatched by 'autonomous-dev serve'.
+Entries are dispatched highest priority first (start --priority), oldest
+first within a priority. A run that fails to dispatch is retried on later
+ticks and parked after %d failures until 'queue retry'.`, queue.MaxAttempts),
+	}
+
+	cmd.AddCommand(queueListCmd())
+	cmd.AddCommand(queueRemoveCmd())
+	cmd.AddCommand(queueRetryCmd())
+
+	return cmd
+}
+
+func queueListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List queued runs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bold := color.New(color.Bold).SprintFunc()
+			cyan := color.New(color.FgCyan).SprintFunc()
+			yellow := color.New(color.FgYellow).SprintFunc()
+
+			entries, err := queue.Open(queue.DefaultDir()).List()
+			if err != nil {
+				return err
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("Queue is empty")
+				return nil
+			}
+
+			fmt.Println(bold("Queued runs (dispatch order):"))
+			for _, entry := range entries {
+				fmt.Printf("  %s  %-6s  %d instances  %s\n", cyan(entry.ID), entry.PriorityOf(), entry.Instances, entry.Task)
+				if entry.Reason != "" {
+					fmt.Printf("    reason: %s\n", entry.Reason)
+				}
+				if entry.LastError != "" {
+					fmt.Printf("    failed %d time(s): %s\n", entry.Attempts, entry.LastError)
+				}
+				if entry.Parked() {
+					fmt.Printf("    %s parked; dispatch it again with 'autonomous-dev queue retry %s'\n", yellow(iconPaused), entry.ID)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func queueRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <id>",
+		Short: "Remove a queued run",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			green := color.New(color.FgGreen).SprintFunc()
+
+			if err := queue.Open(queue.DefaultDir()).Remove(args[0]); err != nil {
+				return err
+			}
+
+			fmt.Printf("%s Removed %s\n", green(iconOK), args[0])
+			return nil
+		},
+	}
+}
+
+func queueRetryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry <id>",
+		Short: "Dispatch a parked run again",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			green := color.New(color.FgGreen).SprintFunc()
+
+			store := queue.Open(queue.DefaultDir())
+			entries, err := store.List()
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				if entry.ID != args[0] {
+					continue
+				}
+				entry.Attempts = 0
+				entry.LastError = ""
+				if err := store.Update(entry); err != nil {
+					return err
+				}
+				fmt.Printf("%s %s will be dispatched on the next serve tick\n", green(iconOK), entry.ID)
+				return nil
+			}
+			return fmt.Errorf("queue entry %s not found", args[0])
+		},
+	}
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/planner"
+	"github.com/autonomous-dev/cli/internal/scheduler"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	replanIssue  int
+	replanRun    string
+	replanTask   string
+	replanReason string
+	replanDryRun bool
+)
+
+func ReplanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replan",
+		Short: "Update the task of an in-flight run",
+		Long: `Update the task of a run while its instances work, without restarting it.
+
+The updated task is split into subtasks, one per item of its top-level
+list (or the whole task if it has none), and compared with the subtasks
+already assigned:
+  - assigned subtasks that match a new one are kept, with the new wording
+  - assigned subtasks no new one matches are cancelled
+  - new subtasks no assigned one matches are assigned to the running
+    instances with the least pending work
+
+Subtasks match when at least half of their words are shared. The change is
+posted to the coordination issue (or state branch run) as a TASK_REPLAN
+message, which tells the affected instances to stop cancelled work and pick
+up their new assignments. Subtasks that already finished are never
+cancelled.`,
+		Example: `  autonomous-dev replan --issue 42 --task "- Add the export API
+- Document the export API"
+  autonomous-dev replan --issue 42 --task "$(cat task.md)" --dry-run`,
+		RunE: runReplan,
+	}
+
+	cmd.Flags().IntVar(&replanIssue, "issue", 0, "Coordination issue number")
+	cmd.Flags().StringVar(&replanRun, "run", "", "State branch run ID in dispatch mode (default latest)")
+	cmd.Flags().StringVar(&replanTask, "task", "", "Updated task description")
+	cmd.Flags().StringVar(&replanReason, "reason", "", "Reason recorded with the change")
+	cmd.Flags().BoolVar(&replanDryRun, "dry-run", false, "Show the changes without posting them")
+	cmd.MarkFlagRequired("task")
+
+	return cmd
+}
+
+func runReplan(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	task := strings.TrimSpace(replanTask)
+	if task == "" {
+		return fmt.Errorf("--task must not be empty")
+	}
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client, store, snap, err := controlTarget(replanIssue, replanRun)
+	if err != nil {
+		return err
+	}
+
+	run, err := storeWorkflowRun(client, store)
+	if err != nil {
+		return err
+	}
+	if run == nil || run.Status == "completed" {
+		return fmt.Errorf("no active workflow run found for %s; start a new run with the updated task", store.Location())
+	}
+
+	assigned := make(map[string]string, len(snap.Assignments))
+	for taskID, a := range snap.Assignments {
+		assigned[taskID] = a.Description
+	}
+	diff := planner.DiffPlan(assigned, planner.Subtasks(task))
+
+	replan := &protocol.Replan{Task: task, Reason: replanReason}
+	affected := map[int]bool{}
+	var posts []*protocol.Assignment
+
+	// Finished subtasks stay whether or not the new plan still has them
+	var obsolete []string
+	for _, taskID := range diff.Cancelled {
+		if _, done := snap.Results[taskID]; done {
+			obsolete = append(obsolete, taskID)
+			continue
+		}
+		replan.Cancelled = append(replan.Cancelled, taskID)
+		affected[snap.Assignments[taskID].InstanceID] = true
+	}
+
+	kept := make([]string, 0, len(diff.Kept))
+	for taskID := range diff.Kept {
+		kept = append(kept, taskID)
+	}
+	sort.Strings(kept)
+	for _, taskID := range kept {
+		a := snap.Assignments[taskID]
+		if _, done := snap.Results[taskID]; done || a.Description == diff.Kept[taskID] {
+			continue
+		}
+		updated := *a
+		updated.Version = protocol.Version
+		updated.Description = diff.Kept[taskID]
+		posts = append(posts, &updated)
+		replan.Updated = append(replan.Updated, taskID)
+		affected[a.InstanceID] = true
+	}
+
+	if len(diff.Added) > 0 {
+		instances := workingInstances(snap)
+		if len(instances) == 0 && !replanDryRun {
+			return fmt.Errorf("no running instance of %s reports status to take the %d new subtask(s)", store.Location(), len(diff.Added))
+		}
+		// Weigh pending work by estimated complexity; without an analyzer every task weighs 1
+		analyzer, _ := planner.New(cfg.Planner, "", ".", cfg.Instances.Max, responseCache(cfg))
+		pending := map[int]int{}
+		for _, taskID := range kept {
+			if _, done := snap.Results[taskID]; !done {
+				pending[snap.Assignments[taskID].InstanceID] += planner.Weight(analyzer, diff.Kept[taskID])
+			}
+		}
+		ids := nextTaskIDs(snap, len(diff.Added))
+		for i, description := range diff.Added {
+			a := &protocol.Assignment{Version: protocol.Version, TaskID: ids[i], Description: description}
+			if len(instances) > 0 {
+				a.InstanceID = instances[0]
+				for _, id := range instances[1:] {
+					if pending[id] < pending[a.InstanceID] {
+						a.InstanceID = id
+					}
+				}
+				pending[a.InstanceID] += planner.Weight(analyzer, description)
+				affected[a.InstanceID] = true
+			}
+			if agent, err := scheduler.SelectAgent(cfg.Agents, description, ""); err == nil && agent != nil {
+				a.Agent = agent.Name
+			}
+			posts = append(posts, a)
+			replan.Added = append(replan.Added, a.TaskID)
+		}
+	}
+	for id := range affected {
+		replan.Instances = append(replan.Instances, id)
+	}
+	sort.Ints(replan.Instances)
+
+	fmt.Println(bold("Replan of " + store.Location()))
+	for _, taskID := range kept {
+		a := snap.Assignments[taskID]
+		switch {
+		case a.Description != diff.Kept[taskID] && snap.Results[taskID] == nil:
+			fmt.Printf("%s %s %s instance %d: %s %s\n", cyan(iconBullet), taskID, iconArrow, a.InstanceID, diff.Kept[taskID], cyan("(updated)"))
+		default:
+			fmt.Printf("%s %s %s instance %d: %s\n", green(iconOK), taskID, iconArrow, a.InstanceID, a.Description)
+		}
+	}
+	for _, taskID := range replan.Cancelled {
+		a := snap.Assignments[taskID]
+		fmt.Printf("%s %s %s instance %d: %s %s\n", red(iconFail), taskID, iconArrow, a.InstanceID, a.Description, red("(cancelled)"))
+	}
+	for _, a := range posts[len(replan.Updated):] {
+		fmt.Printf("%s %s %s instance %d: %s %s\n", green(iconBullet), a.TaskID, iconArrow, a.InstanceID, a.Description, green("(new)"))
+	}
+	for _, taskID := range obsolete {
+		fmt.Printf("%s %s already finished but is no longer in the plan; its result stays\n", yellow(iconWarn), taskID)
+	}
+
+	summarize("cancelled", len(replan.Cancelled))
+	summarize("updated", len(replan.Updated))
+	summarize("added", len(replan.Added))
+	if replanDryRun {
+		fmt.Println()
+		fmt.Println("Dry run: nothing was posted")
+		return nil
+	}
+
+	// Assignments go first so instances find their new work when the replan arrives
+	for _, a := range posts {
+		body, err := protocol.Render(a.InstanceID, a)
+		if err != nil {
+			return err
+		}
+		if err := store.Post(body); err != nil {
+			return fmt.Errorf("failed to assign %s: %w", a.TaskID, err)
+		}
+	}
+	replan.Version = protocol.Version
+	replan.IssuedAt = time.Now().UTC().Format(time.RFC3339)
+	body, err := protocol.Render(0, replan)
+	if err != nil {
+		return err
+	}
+	if err := store.Post(body); err != nil {
+		return fmt.Errorf("failed to post replan: %w", err)
+	}
+	summarize("url", store.URL())
+
+	fmt.Println()
+	fmt.Printf("%s Replanned %s: %d cancelled, %d updated, %d new\n", green(iconOK), store.Location(), len(replan.Cancelled), len(replan.Updated), len(replan.Added))
+	return nil
+}
+
+// workingInstances returns the instances whose latest report shows them
+// able to take work, sorted
+func workingInstances(snap *protocol.Snapshot) []int {
+	var ids []int
+	for _, id := range snap.InstanceIDs() {
+		switch snap.Statuses[id].Status {
+		case "completed", "failed", "stale":
+		default:
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// nextTaskIDs returns n unused task IDs continuing the run's task-N numbering
+func nextTaskIDs(snap *protocol.Snapshot, n int) []string {
+	used := map[string]bool{}
+	last := 0
+	for _, tasks := range []map[string]*protocol.Assignment{snap.Assignments, snap.Cancelled} {
+		for taskID := range tasks {
+			used[taskID] = true
+			if num, err := strconv.Atoi(strings.TrimPrefix(taskID, "task-")); err == nil && num > last {
+				last = num
+			}
+		}
+	}
+	ids := make([]string, 0, n)
+	for len(ids) < n {
+		last++
+		if id := fmt.Sprintf("task-%d", last); !used[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
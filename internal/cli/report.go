@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/coordination"
+	"github.com/autonomous-dev/cli/internal/envlock"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportIssue  int
+	reportRun    string
+	reportOutput string
+	reportPost   bool
+	reportLock   string
+)
+
+func ReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Summarize a run as Markdown",
+		Long: `Summarize the coordination state of a run as Markdown: instance reports,
+task assignments with their outcomes, branches and pull requests, and
+follow-ups.
+
+The run is read from the coordination issue given with --issue. In dispatch
+coordination mode the latest run on the state branch is read instead; use
+--run to pick another. With --post the report is added to the run's
+coordination messages; reports over GitHub's comment size limit are split
+across several comments.
+
+With --env-lock, the environment instances reported (runner image,
+toolchain versions, model and agent CLI) is also written to a lockfile that
+'autonomous-dev start --env-lock' reproduces.`,
+		Example: `  autonomous-dev report --issue 42
+  autonomous-dev report --output report.md
+  autonomous-dev report --issue 42 --post
+  autonomous-dev report --issue 42 --env-lock env-lock.json`,
+		RunE: runReport,
+	}
+
+	cmd.Flags().IntVar(&reportIssue, "issue", 0, "Coordination issue number")
+	cmd.Flags().StringVar(&reportRun, "run", "", "State branch run ID in dispatch mode (default latest)")
+	cmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	cmd.Flags().BoolVar(&reportPost, "post", false, "Post the report to the issue or state branch run")
+	cmd.Flags().StringVar(&reportLock, "env-lock", "", "Also write the run's environment to a lockfile")
+
+	return cmd
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client := newClient(cfg)
+
+	store, err := coordinationStore(client, cfg, reportIssue, reportRun)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		if cfg.Coordination.Dispatch() {
+			return fmt.Errorf("no runs found on %s", cfg.Coordination.Branch())
+		}
+		return fmt.Errorf("--issue is required")
+	}
+
+	snap, errs, err := readCoordination(store)
+	if err != nil {
+		return err
+	}
+	if cfg.Completion.RequireResultSchema {
+		errs = append(errs, snap.RequireSchema()...)
+	}
+	var branches []string
+	for _, r := range snap.Results {
+		if r.Branch != "" {
+			branches = append(branches, r.Branch)
+		}
+	}
+	summarize("url", store.URL())
+	baseSHA := storeBaseSHA(client, store)
+	report := renderReport(store.Location(), store.URL(), baseSHA, snap, errs, branchDiffs(client, branches))
+
+	if reportLock != "" {
+		if err := writeEnvLock(reportLock, store.Location(), baseSHA, snap); err != nil {
+			return err
+		}
+	}
+
+	if reportPost {
+		if err := store.Post(report); err != nil {
+			return fmt.Errorf("failed to post report: %w", err)
+		}
+		fmt.Printf("%s Posted report to %s\n", green(iconOK), store.Location())
+		return nil
+	}
+
+	if reportOutput == "" {
+		fmt.Print(report)
+		return nil
+	}
+	if err := os.WriteFile(reportOutput, []byte(report), 0644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	fmt.Printf("%s Wrote %s\n", green(iconOK), reportOutput)
+
+	return nil
+}
+
+// renderReport renders a coordination snapshot as Markdown
+func renderReport(location, url, baseSHA string, snap *protocol.Snapshot, errs []error, diffs map[string]*github.DiffStats) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Run Report\n\n")
+	fmt.Fprintf(&b, "Source: [%s](%s)\n\n", location, url)
+	if baseSHA != "" {
+		fmt.Fprintf(&b, "Base commit: %s\n\n", baseSHA)
+	}
+
+	b.WriteString("## Instances\n\n")
+	if len(snap.Statuses) == 0 {
+		b.WriteString("No status reports.\n\n")
+	} else {
+		b.WriteString("| Instance | Role | Status | Progress | Current task |\n")
+		b.WriteString("|---|---|---|---|---|\n")
+		for _, id := range snap.InstanceIDs() {
+			st := snap.Statuses[id]
+			fmt.Fprintf(&b, "| %d | %s | %s | %d%% | %s |\n", id, st.Role, st.Status, st.CurrentTask.Progress, markdownCell(st.CurrentTask.Description))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## Tasks\n\n")
+	if len(snap.Assignments) == 0 && len(snap.Results) == 0 {
+		b.WriteString("No tasks assigned.\n\n")
+	} else {
+		b.WriteString("| Task | Instance | Description | Outcome | Branch | Changes | Pull requests |\n")
+		b.WriteString("|---|---|---|---|---|---|---|\n")
+		for _, taskID := range reportTaskIDs(snap) {
+			instance, description, outcome, branch, changes, prs := 0, "", "pending", "", "", ""
+			if a, ok := snap.Assignments[taskID]; ok {
+				instance, description = a.InstanceID, a.Description
+			}
+			if r, ok := snap.Results[taskID]; ok {
+				instance, outcome, branch = r.InstanceID, resultOutcome(r), r.Branch
+				var refs []string
+				for _, pr := range r.PullRequests {
+					refs = append(refs, fmt.Sprintf("#%d", pr))
+				}
+				prs = strings.Join(refs, ", ")
+				if stats, ok := diffs[r.Branch]; ok {
+					changes = diffSummary(stats)
+				}
+			}
+			fmt.Fprintf(&b, "| %s | %d | %s | %s | %s | %s | %s |\n", taskID, instance, markdownCell(description), outcome, branch, changes, prs)
+		}
+		b.WriteString("\n")
+	}
+
+	branches := map[int]string{}
+	for _, taskID := range reportTaskIDs(snap) {
+		if r, ok := snap.Results[taskID]; ok && r.Branch != "" {
+			if _, seen := branches[r.InstanceID]; !seen {
+				branches[r.InstanceID] = r.Branch
+			}
+		}
+	}
+	if overlaps := instanceOverlaps(branches, diffs); len(overlaps) > 0 {
+		b.WriteString("## Overlapping changes\n\n")
+		for _, o := range overlaps {
+			fmt.Fprintf(&b, "- %s; merge only one\n", o)
+		}
+		b.WriteString("\n")
+	}
+
+	var summaries, tests, followUps []string
+	for _, taskID := range reportTaskIDs(snap) {
+		if r, ok := snap.Results[taskID]; ok {
+			if r.Summary != "" {
+				summaries = append(summaries, fmt.Sprintf("- **%s:** %s", taskID, markdownCell(r.Summary)))
+			}
+			if r.Notes != "" {
+				summaries = append(summaries, fmt.Sprintf("  - Notes: %s", markdownCell(r.Notes)))
+			}
+			if r.Tests != nil || r.Coverage != nil {
+				tests = append(tests, fmt.Sprintf("- **%s:** %s", taskID, testSummary(r)))
+			}
+			if r.Outcome == protocol.OutcomeLimitReached {
+				followUps = append(followUps, fmt.Sprintf("Continue %s: stopped by %s", taskID, r.Limit))
+			}
+			followUps = append(followUps, r.FollowUps...)
+		}
+	}
+	if len(summaries) > 0 {
+		b.WriteString("## Summaries\n\n")
+		b.WriteString(strings.Join(summaries, "\n"))
+		b.WriteString("\n\n")
+	}
+	if len(tests) > 0 {
+		b.WriteString("## Tests\n\n")
+		b.WriteString(strings.Join(tests, "\n"))
+		b.WriteString("\n\n")
+	}
+	if len(followUps) > 0 {
+		b.WriteString("## Follow-ups\n\n")
+		for _, f := range followUps {
+			fmt.Fprintf(&b, "- %s\n", f)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(errs) > 0 {
+		b.WriteString("## Protocol errors\n\n")
+		for _, err := range errs {
+			fmt.Fprintf(&b, "- %v\n", err)
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// testSummary describes the tests and coverage a result reported
+func testSummary(r *protocol.Result) string {
+	var parts []string
+	if t := r.Tests; t != nil {
+		part := fmt.Sprintf("`%s`: %d passed, %d failed", t.Command, t.Passed, t.Failed)
+		if t.Skipped > 0 {
+			part += fmt.Sprintf(", %d skipped", t.Skipped)
+		}
+		parts = append(parts, part)
+	}
+	if r.Coverage != nil {
+		parts = append(parts, fmt.Sprintf("coverage %.1f%%", *r.Coverage))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// storeBaseSHA returns the base commit of the run behind a coordination
+// store: from the state branch record, or from the title of the issue's
+// latest workflow run. It returns "" when the run was not pinned.
+func storeBaseSHA(client *github.Client, store coordination.Store) string {
+	if s, ok := store.(*coordination.BranchStore); ok {
+		if record, err := s.Record(); err == nil {
+			return record.BaseSHA
+		}
+		return ""
+	}
+	if run, err := storeWorkflowRun(client, store); err == nil && run != nil {
+		return run.BaseSHA()
+	}
+	return ""
+}
+
+// storeWorkflowRun returns the latest workflow run behind a coordination
+// store, or nil if there is none among recent runs. Issue runs are matched by
+// their title, state branch runs by being dispatched after the run record.
+func storeWorkflowRun(client *github.Client, store coordination.Store) (*github.WorkflowRun, error) {
+	runs, err := client.ListWorkflowRuns(30)
+	if err != nil {
+		return nil, err
+	}
+
+	switch s := store.(type) {
+	case *coordination.IssueStore:
+		for i := range runs {
+			if runs[i].Issue() == s.Issue() {
+				return &runs[i], nil
+			}
+		}
+	case *coordination.BranchStore:
+		// Workflow runs do not name their state branch run, so only the
+		// latest record can be matched: by the newest run dispatched after it
+		latest, err := coordination.LatestRunID(client, s.Branch())
+		if err != nil || latest != s.RunID() {
+			return nil, err
+		}
+		record, err := s.Record()
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 && !runs[0].CreatedAt.Before(record.CreatedAt.Add(-time.Minute)) {
+			return &runs[0], nil
+		}
+	}
+	return nil, nil
+}
+
+// resultOutcome labels a result's outcome, naming the limit a task ran into
+func resultOutcome(r *protocol.Result) string {
+	if r.Outcome == protocol.OutcomeLimitReached && r.Limit != "" {
+		return fmt.Sprintf("%s (%s)", r.Outcome, r.Limit)
+	}
+	return r.Outcome
+}
+
+// reportTaskIDs returns assigned task IDs followed by those only reported as results
+func reportTaskIDs(snap *protocol.Snapshot) []string {
+	var unassigned []string
+	for id := range snap.Results {
+		if _, ok := snap.Assignments[id]; !ok {
+			unassigned = append(unassigned, id)
+		}
+	}
+	sort.Strings(unassigned)
+	return append(snap.TaskIDs(), unassigned...)
+}
+
+// markdownCell escapes text for a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// writeEnvLock records the environment instances reported in a lockfile
+func writeEnvLock(path, location, baseSHA string, snap *protocol.Snapshot) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	lock := envlock.FromCapabilities(location, baseSHA, snap.Capabilities)
+	if lock == nil {
+		return fmt.Errorf("no instance of %s reported its environment yet", location)
+	}
+	if err := lock.Save(path); err != nil {
+		return err
+	}
+	// Progress goes to stderr so the report itself can still be piped
+	fmt.Fprintf(os.Stderr, "%s Wrote %s (%s)\n", green(iconOK), path, lock.Summary())
+	for _, d := range lock.Differences {
+		fmt.Fprintf(os.Stderr, "%s Instances differ: %s\n", yellow(iconWarn), d)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/fatih/color"
+)
+
+// missingRequirements returns the configured secrets and variables that are
+// not set in the repository. Listing them needs a token that can read
+// repository secrets.
+func missingRequirements(client *github.Client, cfg *config.Config) ([]config.Requirement, error) {
+	if len(cfg.Requirements) == 0 {
+		return nil, nil
+	}
+
+	secrets, err := client.ListRepoSecretNames(cfg.GitHub.Owner, cfg.GitHub.Repo)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := client.ListRepoVariables(cfg.GitHub.Owner, cfg.GitHub.Repo)
+	if err != nil {
+		return nil, err
+	}
+	var varNames []string
+	for _, v := range vars {
+		varNames = append(varNames, v.Name)
+	}
+
+	var missing []config.Requirement
+	for _, r := range cfg.Requirements {
+		names := secrets
+		if r.IsVariable() {
+			names = varNames
+		}
+		if !containsString(names, r.Name) {
+			missing = append(missing, r)
+		}
+	}
+	return missing, nil
+}
+
+// requirementSetCommand returns the gh command that sets a requirement
+func requirementSetCommand(r config.Requirement) string {
+	if r.IsVariable() {
+		return fmt.Sprintf("gh variable set %s --body <value>", r.Name)
+	}
+	return fmt.Sprintf("gh secret set %s", r.Name)
+}
+
+// requirementLabel describes a requirement, e.g. "secret ANTHROPIC_API_KEY (API key)"
+func requirementLabel(r config.Requirement) string {
+	kind := config.RequirementSecret
+	if r.IsVariable() {
+		kind = config.RequirementVariable
+	}
+	label := kind + " " + r.Name
+	if r.Description != "" {
+		label += " (" + r.Description + ")"
+	}
+	return label
+}
+
+// printRequirementSetup prints how to set each configured requirement
+func printRequirementSetup(cfg *config.Config) {
+	if len(cfg.Requirements) == 0 {
+		return
+	}
+	fmt.Println("   Set the secrets and variables the workflow needs:")
+	for _, r := range cfg.Requirements {
+		optional := ""
+		if r.Optional {
+			optional = " [optional]"
+		}
+		fmt.Printf("   %s  # %s%s\n", requirementSetCommand(r), requirementLabel(r), optional)
+	}
+}
+
+// checkRequirements reports missing requirements for doctor and returns the
+// number of problems: missing mandatory ones, or 1 if they cannot be listed
+func checkRequirements(client *github.Client, cfg *config.Config) int {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	if len(cfg.Requirements) == 0 {
+		return 0
+	}
+	for _, r := range cfg.Requirements {
+		if r.Kind != "" && r.Kind != config.RequirementSecret && r.Kind != config.RequirementVariable {
+			fmt.Printf("%s Requirements: %s has invalid kind %q (must be %s or %s)\n", red(iconFail), r.Name, r.Kind, config.RequirementSecret, config.RequirementVariable)
+			return 1
+		}
+	}
+
+	missing, err := missingRequirements(client, cfg)
+	if err != nil {
+		fmt.Printf("%s Requirements: %v (the token needs permission to read repository secrets)\n", red(iconFail), err)
+		return 1
+	}
+
+	problems := 0
+	for _, r := range missing {
+		if r.Optional {
+			fmt.Printf("%s Requirements: optional %s is not set (%s)\n", yellow(iconWarn), requirementLabel(r), requirementSetCommand(r))
+			continue
+		}
+		fmt.Printf("%s Requirements: %s is not set (%s)\n", red(iconFail), requirementLabel(r), requirementSetCommand(r))
+		problems++
+	}
+	if len(missing) == 0 {
+		var names []string
+		for _, r := range cfg.Requirements {
+			names = append(names, r.Name)
+		}
+		fmt.Printf("%s Requirements: %s set\n", green(iconOK), strings.Join(names, ", "))
+	}
+	return problems
+}
+
+// requireMandatory fails when mandatory requirements are missing. When the
+// token cannot list secrets, start warns and dispatches anyway.
+func requireMandatory(client *github.Client, cfg *config.Config) error {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	missing, err := missingRequirements(client, cfg)
+	if err != nil {
+		fmt.Printf("%s Could not verify required secrets: %v\n", yellow(iconWarn), err)
+		return nil
+	}
+
+	var mandatory []string
+	for _, r := range missing {
+		if !r.Optional {
+			mandatory = append(mandatory, r.Name)
+		}
+	}
+	if len(mandatory) > 0 {
+		return fmt.Errorf("missing required secrets or variables: %s (run 'autonomous-dev doctor' for setup commands)", strings.Join(mandatory, ", "))
+	}
+	return nil
+}
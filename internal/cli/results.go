@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/autonomous-dev/cli/internal/coordination"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+	"github.com/fatih/color"
+)
+
+// failRejected marks instances whose latest result was rejected as failed:
+// the coordinator posts a failed status on their behalf unless they already
+// report failure, so report and gate logic never count a malformed result as done
+func failRejected(store coordination.Store, snap *protocol.Snapshot) error {
+	red := color.New(color.FgRed).SprintFunc()
+
+	ids := make([]int, 0, len(snap.Rejected))
+	for id := range snap.Rejected {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		if id < 1 {
+			continue
+		}
+		failed := &protocol.Status{InstanceID: id, Status: "failed", Role: "worker"}
+		if st, ok := snap.Statuses[id]; ok {
+			if st.Status == "failed" {
+				continue
+			}
+			failed.Role = st.Role
+			failed.CurrentTask = st.CurrentTask
+		}
+		failed.CurrentTask.Description = fmt.Sprintf("Result rejected: %v", snap.Rejected[id])
+
+		body, err := protocol.Render(0, failed)
+		if err != nil {
+			return err
+		}
+		if err := store.Post(body); err != nil {
+			return fmt.Errorf("failed to fail instance %d: %w", id, err)
+		}
+		snap.Statuses[id] = failed
+		fmt.Printf("%s Instance %d failed: its result was rejected\n", red(iconFail), id)
+	}
+	return nil
+}
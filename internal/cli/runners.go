@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runnersLabels []string
+	runnersName   string
+	runnersOS     string
+	runnersArch   string
+)
+
+func RunnersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "runners",
+		Short: "Set up and inspect self-hosted runners",
+		Long: `Manage self-hosted GitHub Actions runners for autonomous development.
+
+Runners are registered with the labels in runners.labels, which the generated
+workflow targets with runs-on: [self-hosted, <labels>].`,
+	}
+
+	cmd.AddCommand(runnersSetupCmd())
+	cmd.AddCommand(runnersListCmd())
+	cmd.AddCommand(runnersStatusCmd())
+
+	return cmd
+}
+
+func runnersSetupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Print commands to register a self-hosted runner",
+		Long: `Create a registration token and print the commands that download,
+register and start a runner with the configured labels.
+
+Run the printed commands on the machine that should become the runner.
+The registration token expires after one hour.`,
+		RunE: runRunnersSetup,
+	}
+
+	cmd.Flags().StringSliceVar(&runnersLabels, "labels", nil, "Runner labels (default runners.labels)")
+	cmd.Flags().StringVar(&runnersName, "name", "", "Runner name (default host name)")
+	cmd.Flags().StringVar(&runnersOS, "os", runtime.GOOS, "Runner OS: linux, darwin or windows")
+	cmd.Flags().StringVar(&runnersArch, "arch", runtime.GOARCH, "Runner architecture: amd64, arm64 or arm")
+
+	return cmd
+}
+
+func runRunnersSetup(cmd *cobra.Command, args []string) error {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	labels := runnersLabels
+	if len(labels) == 0 {
+		labels = cfg.Runners.Labels
+	}
+	if len(labels) == 0 {
+		labels = []string{"autonomous-dev"}
+		fmt.Printf("%s runners.labels is not set; using %q\n", yellow(iconWarn), labels[0])
+		fmt.Println("  Target these runners with: autonomous-dev config set runners.labels autonomous-dev")
+		fmt.Println()
+	}
+
+	osName, ok := map[string]string{"linux": "linux", "darwin": "osx", "windows": "win"}[runnersOS]
+	if !ok {
+		return fmt.Errorf("unsupported runner OS %q (use linux, darwin or windows)", runnersOS)
+	}
+	arch, ok := map[string]string{"amd64": "x64", "arm64": "arm64", "arm": "arm"}[runnersArch]
+	if !ok {
+		return fmt.Errorf("unsupported runner architecture %q (use amd64, arm64 or arm)", runnersArch)
+	}
+
+	client := newClient(cfg)
+
+	download, err := client.GetRunnerDownload(osName, arch)
+	if err != nil {
+		return err
+	}
+	token, err := client.CreateRunnerRegistrationToken()
+	if err != nil {
+		return err
+	}
+
+	repoURL := fmt.Sprintf("https://github.com/%s/%s", cfg.GitHub.Owner, cfg.GitHub.Repo)
+	configArgs := fmt.Sprintf("--url %s --token %s --labels %s --unattended", repoURL, token.Token, strings.Join(labels, ","))
+	if runnersName != "" {
+		configArgs += " --name " + runnersName
+	}
+
+	fmt.Println(bold("Run on the runner machine:"))
+	fmt.Println()
+	if osName == "win" {
+		fmt.Println("  mkdir actions-runner; cd actions-runner")
+		fmt.Printf("  Invoke-WebRequest -Uri %s -OutFile %s\n", download.URL, download.Filename)
+		if download.SHA256 != "" {
+			fmt.Printf("  if ((Get-FileHash -Path %s -Algorithm SHA256).Hash.ToUpper() -ne '%s'.ToUpper()) { throw 'Checksum mismatch' }\n", download.Filename, download.SHA256)
+		}
+		fmt.Printf("  Add-Type -AssemblyName System.IO.Compression.FileSystem; [System.IO.Compression.ZipFile]::ExtractToDirectory(\"$PWD/%s\", \"$PWD\")\n", download.Filename)
+		fmt.Printf("  ./config.cmd %s\n", configArgs)
+		fmt.Println("  ./run.cmd")
+	} else {
+		fmt.Println("  mkdir actions-runner && cd actions-runner")
+		fmt.Printf("  curl -o %s -L %s\n", download.Filename, download.URL)
+		if download.SHA256 != "" {
+			fmt.Printf("  echo \"%s  %s\" | shasum -a 256 -c\n", download.SHA256, download.Filename)
+		}
+		fmt.Printf("  tar xzf ./%s\n", download.Filename)
+		fmt.Printf("  ./config.sh %s\n", configArgs)
+		fmt.Println("  ./run.sh    # or: sudo ./svc.sh install && sudo ./svc.sh start")
+	}
+	fmt.Println()
+	fmt.Printf("Registration token expires at %s\n", formatTime(token.ExpiresAt, cfg))
+
+	if strings.Join(labels, ",") != strings.Join(cfg.Runners.Labels, ",") {
+		fmt.Println()
+		fmt.Printf("%s The workflow targets runners.labels %v; jobs will not run on this runner until they match\n", yellow(iconWarn), cfg.Runners.Labels)
+	}
+
+	return nil
+}
+
+func runnersListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List self-hosted runners",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bold := color.New(color.Bold).SprintFunc()
+
+			cfg, err := config.Load(config.ConfigPath())
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			runners, err := newClient(cfg).ListRunners()
+			if err != nil {
+				return err
+			}
+
+			if len(runners) == 0 {
+				fmt.Println("No self-hosted runners registered")
+				fmt.Println("Register one with: autonomous-dev runners setup")
+				return nil
+			}
+
+			fmt.Println(bold("Runners:"))
+			for _, r := range runners {
+				fmt.Printf("%s %-24s %-8s %s  [%s]\n", runnerIcon(r), r.Name, r.OS, runnerState(r), strings.Join(r.Labels, ", "))
+			}
+
+			return nil
+		},
+	}
+}
+
+func runnersStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Summarize runner availability for autonomous-dev jobs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			green := color.New(color.FgGreen).SprintFunc()
+			yellow := color.New(color.FgYellow).SprintFunc()
+			red := color.New(color.FgRed).SprintFunc()
+
+			cfg, err := config.Load(config.ConfigPath())
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if len(cfg.Runners.Labels) == 0 {
+				fmt.Printf("%s Workflow runs on GitHub-hosted runners (runners.labels is not set)\n", green(iconOK))
+				return nil
+			}
+
+			runners, err := newClient(cfg).ListRunners()
+			if err != nil {
+				return err
+			}
+
+			matching, online, idle := 0, 0, 0
+			for _, r := range runners {
+				if !r.HasLabels(cfg.Runners.Labels) {
+					continue
+				}
+				matching++
+				if r.Status == "online" {
+					online++
+					if !r.Busy {
+						idle++
+					}
+				}
+			}
+
+			fmt.Printf("Labels: %s\n", strings.Join(cfg.Runners.Labels, ", "))
+			fmt.Printf("Matching runners: %d (%d online, %d idle)\n", matching, online, idle)
+			fmt.Println()
+
+			switch {
+			case online == 0:
+				fmt.Printf("%s No matching runner is online; autonomous-dev jobs will queue\n", red(iconFail))
+				fmt.Println("  Register one with: autonomous-dev runners setup")
+			case idle == 0:
+				fmt.Printf("%s All matching runners are busy\n", yellow(iconWarn))
+			case online < cfg.Workflow.Concurrency:
+				fmt.Printf("%s %d runner(s) online for concurrency %d; instances will run in waves\n", yellow(iconWarn), online, cfg.Workflow.Concurrency)
+			default:
+				fmt.Printf("%s Runners ready\n", green(iconOK))
+			}
+
+			return nil
+		},
+	}
+}
+
+func runnerIcon(r github.Runner) string {
+	switch {
+	case r.Status != "online":
+		return color.RedString(iconFail)
+	case r.Busy:
+		return color.YellowString(iconWait)
+	default:
+		return color.GreenString(iconOK)
+	}
+}
+
+func runnerState(r github.Runner) string {
+	switch {
+	case r.Status != "online":
+		return color.RedString("offline")
+	case r.Busy:
+		return color.YellowString("busy   ")
+	default:
+		return color.GreenString("idle   ")
+	}
+}
@@ -0,0 +1,305 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/queue"
+	"github.com/autonomous-dev/cli/internal/schedule"
+	"github.com/autonomous-dev/cli/internal/scheduler"
+	"github.com/autonomous-dev/cli/internal/template"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scheduleName      string
+	scheduleCron      string
+	scheduleTask      string
+	scheduleInstances int
+	scheduleAgent     string
+	scheduleWorkflow  bool
+)
+
+func ScheduleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Manage recurring tasks",
+		Long: `Recurring tasks, such as a nightly dependency audit or a weekly test-flake
+hunt, are defined in the schedules section of the config with a five-field
+cron expression evaluated in UTC.
+
+By default 'autonomous-dev serve' queues each schedule when it is due and
+dispatches it like any queued run. Schedules added with --workflow are
+started by a scheduled trigger workflow instead, so no daemon is needed;
+commit the generated workflow file to activate them.`,
+	}
+
+	cmd.AddCommand(scheduleAddCmd())
+	cmd.AddCommand(scheduleListCmd())
+	cmd.AddCommand(scheduleRemoveCmd())
+
+	return cmd
+}
+
+func scheduleAddCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a recurring task",
+		Example: `  autonomous-dev schedule add --name nightly-audit --cron "0 3 * * *" --task "Audit and update dependencies"
+  autonomous-dev schedule add --name flake-hunt --cron "0 6 * * 1" --task "Find and fix flaky tests" --agent test-specialist --workflow`,
+		RunE: runScheduleAdd,
+	}
+
+	cmd.Flags().StringVar(&scheduleName, "name", "", "Schedule name")
+	cmd.Flags().StringVar(&scheduleCron, "cron", "", "Cron expression in UTC (minute hour day-of-month month day-of-week)")
+	cmd.Flags().StringVar(&scheduleTask, "task", "", "Task description")
+	cmd.Flags().IntVarP(&scheduleInstances, "instances", "n", 0, "Number of instances (default from config)")
+	cmd.Flags().StringVar(&scheduleAgent, "agent", "", "Agent to use (default: selected by skills)")
+	cmd.Flags().BoolVar(&scheduleWorkflow, "workflow", false, "Start from a scheduled trigger workflow instead of serve")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("cron")
+	cmd.MarkFlagRequired("task")
+
+	return cmd
+}
+
+func runScheduleAdd(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+
+	cfg, err := config.LoadFile(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	for _, s := range cfg.Schedules {
+		if s.Name == scheduleName {
+			return fmt.Errorf("schedule %q already exists", scheduleName)
+		}
+	}
+	cron, err := schedule.Parse(scheduleCron)
+	if err != nil {
+		return err
+	}
+	if scheduleInstances < 0 || (cfg.Instances.Max > 0 && scheduleInstances > cfg.Instances.Max) {
+		return fmt.Errorf("instances must be between 1 and %d", cfg.Instances.Max)
+	}
+	if _, err := scheduler.SelectAgent(cfg.Agents, scheduleTask, scheduleAgent); err != nil {
+		return err
+	}
+
+	s := config.Schedule{
+		Name:      scheduleName,
+		Cron:      cron.String(),
+		Task:      scheduleTask,
+		Instances: scheduleInstances,
+		Agent:     scheduleAgent,
+	}
+	if scheduleWorkflow {
+		if cfg.Coordination.Dispatch() {
+			return fmt.Errorf("--workflow schedules create coordination issues and are not available in dispatch coordination mode; use serve")
+		}
+		s.Trigger = config.ScheduleWorkflow
+	}
+	cfg.Schedules = append(cfg.Schedules, s)
+
+	if err := cfg.Save(config.ConfigPath()); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	fmt.Printf("%s Added schedule %s (%s UTC, next %s)\n", green(iconOK), s.Name, s.Cron, formatTime(cron.Next(time.Now()), cfg))
+
+	if s.ByWorkflow() {
+		return writeScheduleWorkflow(cfg)
+	}
+	fmt.Println("  Dispatched by: autonomous-dev serve")
+	return nil
+}
+
+func scheduleListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List recurring tasks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bold := color.New(color.Bold).SprintFunc()
+			cyan := color.New(color.FgCyan).SprintFunc()
+
+			cfg, err := config.Load(config.ConfigPath())
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if len(cfg.Schedules) == 0 {
+				fmt.Println("No schedules")
+				return nil
+			}
+
+			state, err := schedule.LoadState(schedule.DefaultPath())
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(bold("Schedules:"))
+			for _, s := range cfg.Schedules {
+				trigger := config.ScheduleServe
+				if s.ByWorkflow() {
+					trigger = config.ScheduleWorkflow
+				}
+				fmt.Printf("  %s  %s  (%s)  %s\n", cyan(s.Name), s.Cron, trigger, s.Task)
+				if cron, err := schedule.Parse(s.Cron); err != nil {
+					fmt.Printf("    error: %v\n", err)
+				} else {
+					fmt.Printf("    next: %s\n", formatTime(cron.Next(time.Now()), cfg))
+				}
+				if last, ok := state[s.Name]; ok && !s.ByWorkflow() {
+					fmt.Printf("    last checked: %s\n", formatTime(last, cfg))
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func scheduleRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a recurring task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			green := color.New(color.FgGreen).SprintFunc()
+
+			cfg, err := config.LoadFile(config.ConfigPath())
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			var kept []config.Schedule
+			found, byWorkflow := false, false
+			for _, s := range cfg.Schedules {
+				if s.Name == args[0] {
+					found, byWorkflow = true, s.ByWorkflow()
+					continue
+				}
+				kept = append(kept, s)
+			}
+			if !found {
+				return fmt.Errorf("schedule %q not found", args[0])
+			}
+			cfg.Schedules = kept
+
+			if err := cfg.Save(config.ConfigPath()); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			fmt.Printf("%s Removed %s\n", green(iconOK), args[0])
+
+			if byWorkflow {
+				return writeScheduleWorkflow(cfg)
+			}
+			return nil
+		},
+	}
+}
+
+// scheduleWorkflowPath returns the trigger workflow file, next to the main workflow
+func scheduleWorkflowPath(cfg *config.Config) string {
+	return filepath.Join(filepath.Dir(filepath.FromSlash(cfg.Workflow.File)), "autonomous-dev-schedule.yml")
+}
+
+// writeScheduleWorkflow regenerates the trigger workflow of --workflow
+// schedules, or removes it when none are left
+func writeScheduleWorkflow(cfg *config.Config) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	path := scheduleWorkflowPath(cfg)
+
+	var tasks []template.ScheduledTask
+	for _, s := range cfg.Schedules {
+		if !s.ByWorkflow() {
+			continue
+		}
+		agent, err := scheduler.SelectAgent(cfg.Agents, s.Task, s.Agent)
+		if err != nil {
+			return fmt.Errorf("schedule %s: %w", s.Name, err)
+		}
+		instances := s.Instances
+		if instances == 0 {
+			instances = cfg.Instances.Default
+		}
+		body, err := template.IssueBody(template.Data{Config: cfg, Task: s.Task, Instances: instances, Agent: agent})
+		if err != nil {
+			return fmt.Errorf("failed to render issue body: %w", err)
+		}
+		tasks = append(tasks, template.ScheduledTask{
+			Schedule:  s,
+			Instances: instances,
+			Inputs:    scheduler.MergeInputs(agent, nil),
+			Body:      body,
+		})
+	}
+
+	if len(tasks) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove schedule workflow: %w", err)
+		}
+		fmt.Printf("%s Removed %s (no workflow schedules left)\n", green(iconOK), path)
+		return nil
+	}
+
+	content, err := template.ScheduleWorkflow(cfg, tasks)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create workflow directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write schedule workflow: %w", err)
+	}
+	fmt.Printf("%s Wrote %s; commit and push it to activate the schedule\n", green(iconOK), path)
+	return nil
+}
+
+// queueDueSchedules queues serve schedules that fired since they were last
+// checked. Schedules seen for the first time start counting from now.
+func queueDueSchedules(cfg *config.Config) error {
+	if len(cfg.Schedules) == 0 {
+		return nil
+	}
+
+	state, err := schedule.LoadState(schedule.DefaultPath())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	store := queue.Open(queue.DefaultDir())
+	for _, s := range cfg.Schedules {
+		if s.ByWorkflow() {
+			continue
+		}
+		cron, err := schedule.Parse(s.Cron)
+		if err != nil {
+			return fmt.Errorf("schedule %s: %w", s.Name, err)
+		}
+
+		last, ok := state[s.Name]
+		state[s.Name] = now
+		if !ok || !schedule.Due(cron, last, now) {
+			continue
+		}
+
+		instances := s.Instances
+		if instances == 0 {
+			instances = cfg.Instances.Default
+		}
+		entry := requestEntry(runRequest{Task: s.Task, Instances: instances, Agent: s.Agent}, "schedule "+s.Name)
+		if err := store.Add(entry); err != nil {
+			return err
+		}
+		fmt.Printf("Queued scheduled task %s (%s)\n", s.Name, entry.ID)
+	}
+
+	return state.Save(schedule.DefaultPath())
+}
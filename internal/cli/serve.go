@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/events"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/permissions"
+	"github.com/autonomous-dev/cli/internal/poll"
+	"github.com/autonomous-dev/cli/internal/queue"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveInterval   time.Duration
+	serveGCInterval time.Duration
+	serveBadgeAddr  string
+	serveCommands   bool
+)
+
+func ServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run the background dispatcher",
+		Long: `Run a long-lived process that periodically performs background work:
+- Dispatch starts deferred in the outbox once GitHub is reachable
+- Dispatch the remaining instances of runs whose canary passed
+- Queue recurring tasks from the schedules config when they are due
+- Dispatch queued runs once quotas and scheduling windows allow
+- Watch the latest run and react to failures and completion
+  (history, notifications, commit status, local dashboard)
+- Every --gc-interval, settle runs history still shows as started: record
+  runs whose issue was closed by hand, whose workflow run was deleted or
+  whose instances stopped without reporting, and suggest cleanups; then
+  compact the history journal
+- With --badge-addr, serve the endpoint files of 'badge' over HTTP,
+  refreshed every interval
+- With --commands, answer /auto-fix, /close, /pause and /resume comments
+  on issues, for the users and teams granted them under permissions:
+  (see 'autonomous-dev permissions'); each is recorded in the audit trail
+
+Work runs every --interval while the latest run is active and changing,
+less often while it is quiet, and every network.polling.idle while nothing
+runs. Failed rounds back off up to the same limit, and each wait is
+jittered so many serve processes do not poll GitHub in lockstep.
+
+Stop with Ctrl+C.`,
+		RunE: runServe,
+	}
+
+	cmd.Flags().DurationVar(&serveInterval, "interval", time.Minute, "Polling interval while a run is active and changing (see network.polling)")
+	cmd.Flags().DurationVar(&serveGCInterval, "gc-interval", 15*time.Minute, "How often stale runs are collected (0 disables)")
+	cmd.Flags().StringVar(&serveBadgeAddr, "badge-addr", "", "Address to serve badge endpoints on, e.g. :8080 (/badges/status.json)")
+	cmd.Flags().BoolVar(&serveCommands, "commands", false, "Answer comment commands such as /auto-fix and /close")
+
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	// Load config
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	client := newClient(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Println(bold("Serving"), fmt.Sprintf("(every %s while runs change, up to %s when idle; Ctrl+C to stop)", serveInterval, cfg.Network.Polling.IdleInterval()))
+
+	poller := newPoller(cfg, serveInterval)
+	var last string
+
+	bus := newEventBus(client, cfg)
+
+	var badges *badgeServer
+	if serveBadgeAddr != "" {
+		badges = &badgeServer{}
+		srv := &http.Server{Addr: serveBadgeAddr, Handler: badges}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				fmt.Printf("%s Warning: badge server stopped: %v\n", yellow(iconWarn), err)
+			}
+		}()
+		defer srv.Close()
+		fmt.Printf("Serving badges on %s\n", serveBadgeAddr)
+	}
+
+	// Comment commands are answered from the time serve starts
+	commandsSince := time.Now()
+	if serveCommands {
+		if err := permissions.Policy(cfg.Permissions).Validate(); err != nil {
+			return err
+		}
+		fmt.Println("Answering comment commands")
+	}
+
+	var lastGC time.Time
+	for {
+		if serveCommands {
+			if commandsSince, err = answerCommands(client, cfg, commandsSince); err != nil {
+				fmt.Printf("%s Warning: failed to answer comment commands: %v\n", yellow(iconWarn), err)
+			}
+		}
+		fingerprint, active, err := serveTick(client, cfg, bus)
+		obs := poll.Observation{Active: active, Changed: fingerprint != last}
+		last = fingerprint
+		if err != nil {
+			fmt.Printf("%s %v\n", yellow(iconWarn), err)
+			obs = failedPoll(err)
+		}
+		if badges != nil {
+			if err := badges.update(client); err != nil {
+				fmt.Printf("%s Warning: failed to update badges: %v\n", yellow(iconWarn), err)
+			}
+		}
+		if serveGCInterval > 0 && time.Since(lastGC) >= serveGCInterval {
+			lastGC = time.Now()
+			if err := collectStaleRuns(client, cfg, bus); err != nil {
+				fmt.Printf("%s Warning: failed to collect stale runs: %v\n", yellow(iconWarn), err)
+			}
+			if err := compactHistory(); err != nil {
+				fmt.Printf("%s Warning: %v\n", yellow(iconWarn), err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("Stopped")
+			return nil
+		case <-time.After(poller.Next(obs)):
+		}
+	}
+}
+
+// serveTick performs one round of background work. It returns the latest
+// run's fingerprint and whether work is pending: the run is in progress or
+// runs wait in the queue.
+func serveTick(client *github.Client, cfg *config.Config, bus *events.Bus) (string, bool, error) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	// Failed starts stay in the outbox; the rest of the tick goes on
+	if _, err := flushOutbox(client, cfg, bus); err != nil {
+		fmt.Printf("%s Warning: %v\n", yellow(iconWarn), err)
+	}
+	run, jobs, err := observeLatestRun(client, bus)
+	if err != nil {
+		return "", false, err
+	}
+	if err := promoteCanaries(client, cfg); err != nil {
+		return "", false, err
+	}
+	if err := queueDueSchedules(cfg); err != nil {
+		return "", false, err
+	}
+	if err := dispatchQueued(client, cfg, bus); err != nil {
+		fmt.Printf("%s Warning: %v\n", yellow(iconWarn), err)
+	}
+	queued, err := queue.Open(queue.DefaultDir()).List()
+	if err != nil {
+		return "", false, err
+	}
+	waiting := 0
+	for _, entry := range queued {
+		if !entry.Parked() {
+			waiting++
+		}
+	}
+	active := (run != nil && run.Status != "completed") || waiting > 0
+	return runFingerprint(run, jobs, nil), active, nil
+}
+
+// observeLatestRun publishes lifecycle events for the latest run so
+// subscribers react to failures and completion without anyone polling
+// status. It returns the run and its jobs, or nil if there is no run.
+func observeLatestRun(client *github.Client, bus *events.Bus) (*github.WorkflowRun, []github.Job, error) {
+	run, err := client.GetLatestWorkflowRun()
+	if err != nil || run == nil {
+		return nil, nil, err
+	}
+	jobs, err := client.GetWorkflowJobs(run.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get workflow jobs: %w", err)
+	}
+	publishRunEvents(bus, run, jobs, runIssue(client, run))
+	return run, jobs, nil
+}
+
+// dispatchQueued dispatches queued runs in order while quotas allow. A run
+// that fails is kept with its error and what it created, and the next one is
+// dispatched; after queue.MaxAttempts failures it is parked.
+func dispatchQueued(client *github.Client, cfg *config.Config, bus *events.Bus) error {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	store := queue.Open(queue.DefaultDir())
+	entries, err := store.List()
+	if err != nil {
+		return err
+	}
+	var pending []*queue.Entry
+	for _, entry := range entries {
+		if !entry.Parked() {
+			pending = append(pending, entry)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := checkDispatch(client, cfg); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, entry := range pending {
+		if cfg.Quotas.Enabled() {
+			decision, err := checkQuota(client, cfg)
+			if err != nil {
+				return fmt.Errorf("failed to check quotas: %w", err)
+			}
+			if !decision.Allowed {
+				// Remaining entries wait for the next tick
+				break
+			}
+		}
+
+		fmt.Printf("Dispatching queued run %s\n", entry.ID)
+		if _, _, err := dispatchRun(client, cfg, bus, entryRequest(entry)); err != nil {
+			keepCreated(entry, err)
+			entry.Attempts++
+			entry.LastError = err.Error()
+			if err := store.Update(entry); err != nil {
+				return err
+			}
+			if entry.Parked() {
+				fmt.Printf("%s Queued run %s parked after %d failed attempts: %v\n", yellow(iconWarn), entry.ID, entry.Attempts, err)
+			} else {
+				fmt.Printf("%s Queued run %s failed (attempt %d): %v\n", yellow(iconWarn), entry.ID, entry.Attempts, err)
+			}
+			failed++
+			continue
+		}
+		if err := store.Remove(entry.ID); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d queued run(s) failed; see 'autonomous-dev queue list'", failed)
+	}
+	return nil
+}
@@ -1,17 +1,51 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/autonomous-dev/cli/internal/bundle"
+	"github.com/autonomous-dev/cli/internal/canary"
+	"github.com/autonomous-dev/cli/internal/chunk"
 	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/coordination"
+	"github.com/autonomous-dev/cli/internal/envlock"
+	"github.com/autonomous-dev/cli/internal/events"
 	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/hooks"
+	"github.com/autonomous-dev/cli/internal/logstream"
+	"github.com/autonomous-dev/cli/internal/planner"
+	"github.com/autonomous-dev/cli/internal/queue"
+	"github.com/autonomous-dev/cli/internal/quota"
+	"github.com/autonomous-dev/cli/internal/scheduler"
+	"github.com/autonomous-dev/cli/internal/template"
+	"github.com/autonomous-dev/cli/internal/tracker"
+	"github.com/autonomous-dev/cli/pkg/protocol"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
 var (
-	instances int
-	task      string
+	instances       int
+	task            string
+	startUntrusted  bool
+	startAgent      string
+	startFrom       string
+	startAutoSize   bool
+	startSHA        string
+	startEnv        string
+	startEnvLock    string
+	startPriority   string
+	startCanary     bool
+	startLogLevel   string
+	startRunEnv     []string
+	startFromFile   string
+	startFromIssues []int
 )
 
 func StartCmd() *cobra.Command {
@@ -26,22 +60,112 @@ This command will:
 2. Trigger the GitHub Actions workflow
 3. Pass the number of instances as parameter
 
-The instances will coordinate through P2P messaging in the issue comments.`,
+The instances will coordinate through P2P messaging in the issue comments.
+With coordination.mode set to dispatch, no issue is created: the run is
+recorded on the state branch and triggered with repository_dispatch.
+
+With --from, the task is imported from a Jira or Linear ticket: its summary
+becomes the task, its description and a backlink go into the issue body, and
+the run's conclusion is commented back on the ticket when status or serve
+observes that the run completed.
+
+With --auto-size and no --instances, the instance count comes from the
+task's complexity estimate (see 'autonomous-dev estimate').
+
+Every instance checks out the same base commit: --sha, or the head of main
+when the run is dispatched, so commits landing mid-dispatch do not split
+instances across bases. The base is shown by status and report.
+
+Runs that affect an environment (--environment, or a task mentioning one of
+deployments.keywords with deployments.environment set) create a GitHub
+Deployment of the base commit. Instances wait for the environment's
+protection rules, and the deployment status follows the run.
+
+With --env-lock, instances run in the environment recorded from a past run
+by 'autonomous-dev report --env-lock': the same base commit (unless --sha is
+given), runner image family, Go, Node.js and Python versions and model.
+Hosted runner image versions cannot be pinned; instances warn about anything
+that differs from the lock.
+
+Runs that exceed quotas wait in the queue in priority order: with --priority
+high a run is dispatched before every queued normal and low priority run,
+whenever it was queued.
+
+With --canary, a single instance first attempts a representative subtask.
+serve (or status) dispatches the remaining instances once the canary
+completes and meets the quality gates: a completed outcome, no failing tests
+and canary.min_coverage if set. A failed canary stops the run before the
+other instances spend anything.
+
+--env KEY=VALUE (repeatable) sets an environment variable for every
+instance of this run, e.g. a feature flag or target, without editing the
+config or workflow. Values are passed as a workflow input and are visible
+in the run; use Actions secrets for credentials.
+
+--from-file starts a run for every row of a CSV file. The header names the
+columns: task, instances, agent, priority, environment, sha, issue and
+from (a jira:KEY or linear:KEY ticket). Each row needs a task, issue or
+from; empty cells take the other start flags. --from-issues starts a run
+for each of the given open issues, titled after them. A row with an issue
+attaches its run to that issue instead of creating one: the run section is
+appended to the issue body and the issue is labeled autonomous-dev. In
+dispatch mode the issue body becomes the run's details instead.
+
+Batch runs are added to the queue and dispatched in priority order, after
+queued runs of higher priority, while quotas allow; the rest wait for
+serve. Every row is checked before any run is queued.`,
+		Example: `  autonomous-dev start --task "Add OAuth login"
+  autonomous-dev start --from jira:PROJ-123
+  autonomous-dev start --from linear:ABC-45
+  autonomous-dev start --task "Migrate auth to OAuth" --auto-size
+  autonomous-dev start --task "Fix flaky tests" --sha 1a2b3c4
+  autonomous-dev start --task "Rotate staging certificates" --environment staging
+  autonomous-dev start --task "Fix flaky tests" --env-lock env-lock.json
+  autonomous-dev start --task "Fix the production outage" --priority high
+  autonomous-dev start --task "Migrate auth to OAuth" -n 5 --canary
+  autonomous-dev start --task "Try the new checkout" --env FEATURE_CHECKOUT_V2=1 --env TARGET=staging
+  autonomous-dev start --from-file tasks.csv
+  autonomous-dev start --from-issues 12,15,18 --priority low`,
 		RunE: runStart,
 	}
 
 	cmd.Flags().IntVarP(&instances, "instances", "n", 0, "Number of parallel instances (default from config)")
-	cmd.Flags().StringVarP(&task, "task", "t", "", "Task description (required)")
-	cmd.MarkFlagRequired("task")
+	cmd.Flags().StringVarP(&task, "task", "t", "", "Task description (required unless --from, --from-file or --from-issues is given)")
+	cmd.Flags().BoolVar(&startUntrusted, "untrusted", false, "Run an externally sourced task on a fork with restricted credentials")
+	cmd.Flags().StringVar(&startAgent, "agent", "", "Agent to frame the task with (default: best skill match)")
+	cmd.Flags().StringVar(&startFrom, "from", "", "Import the task from a ticket: jira:KEY or linear:KEY")
+	cmd.Flags().BoolVar(&startAutoSize, "auto-size", false, "Size the run by the task's complexity estimate unless --instances is given")
+	cmd.Flags().StringVar(&startSHA, "sha", "", "Commit every instance starts from (default: the head of main at dispatch)")
+	cmd.Flags().StringVar(&startPriority, "priority", queue.PriorityNormal, "Queue priority when quotas are saturated: low, normal or high")
+	cmd.Flags().StringVar(&startEnvLock, "env-lock", "", "Reproduce the environment recorded in a lockfile (see report --env-lock)")
+	cmd.Flags().StringArrayVar(&startRunEnv, "env", nil, "Environment variable KEY=VALUE for the run's instances (repeatable)")
+	cmd.Flags().StringVar(&startLogLevel, "log-level", "", "Instance log level for this run: debug, info, warn or error (default logs.level)")
+	cmd.Flags().BoolVar(&startCanary, "canary", false, "Run one instance on a representative subtask before dispatching the rest")
+	cmd.Flags().StringVar(&startEnv, "environment", "", "Deployment environment the run affects (default: deployments.environment for deployable tasks)")
+	cmd.Flags().StringVar(&startFromFile, "from-file", "", "Start a run for every row of a CSV file (see above for columns)")
+	cmd.Flags().IntSliceVar(&startFromIssues, "from-issues", nil, "Start a run attached to each of these open issues, e.g. 12,15,18")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "task")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "from")
+	cmd.MarkFlagsMutuallyExclusive("from-issues", "task")
+	cmd.MarkFlagsMutuallyExclusive("from-issues", "from")
+	cmd.MarkFlagsMutuallyExclusive("from-file", "from-issues")
 
 	return cmd
 }
 
 func runStart(cmd *cobra.Command, args []string) error {
 	green := color.New(color.FgGreen).SprintFunc()
-	cyan := color.New(color.FgCyan).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
 	bold := color.New(color.Bold).SprintFunc()
 
+	batch := startFromFile != "" || len(startFromIssues) > 0
+	if task == "" && startFrom == "" && !batch {
+		return fmt.Errorf("required flag \"task\" not set (or use --from, --from-file or --from-issues)")
+	}
+	if batch && (startAutoSize || startCanary) {
+		return fmt.Errorf("--auto-size and --canary cannot be combined with --from-file or --from-issues")
+	}
+
 	// Load config
 	cfg, err := config.Load(config.ConfigPath())
 	if err != nil {
@@ -58,43 +182,133 @@ func runStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("instances (%d) exceeds maximum (%d)", instances, cfg.Instances.Max)
 	}
 
-	// Create GitHub client
-	client := github.NewClient(cfg.GitHub.Token, cfg.GitHub.Owner, cfg.GitHub.Repo)
+	if startSHA != "" && !shaPattern.MatchString(startSHA) {
+		return fmt.Errorf("invalid --sha %q: expected a commit SHA of 7 to 40 hex digits", startSHA)
+	}
 
-	fmt.Println(bold("Starting autonomous development..."))
-	fmt.Println()
+	priority, err := queue.ParsePriority(startPriority)
+	if err != nil {
+		return err
+	}
+	if startLogLevel != "" {
+		if _, err := logstream.ParseLevel(startLogLevel); err != nil {
+			return err
+		}
+	}
+	runEnv, err := parseRunEnv(cfg, startRunEnv)
+	if err != nil {
+		return err
+	}
 
-	// Create GitHub Issue
-	fmt.Printf("Creating issue with task: %s\n", cyan(task))
-	issue, err := client.CreateIssue(task, fmt.Sprintf(`# Autonomous Development Task
+	req := runRequest{Task: task, Instances: instances, Untrusted: startUntrusted, Agent: startAgent, SHA: strings.ToLower(startSHA), Environment: startEnv, Priority: priority, Canary: startCanary, LogLevel: startLogLevel, Env: runEnv}
 
-%s
+	// Reproduce a past run's environment
+	if startEnvLock != "" {
+		lock, err := envlock.Load(startEnvLock)
+		if err != nil {
+			return err
+		}
+		if req.EnvLock, err = lock.Input(); err != nil {
+			return err
+		}
+		if req.SHA == "" {
+			req.SHA = lock.BaseSHA
+		}
+		fmt.Printf("%s Reproducing environment of %s: %s\n", green(iconOK), lock.Run, lock.Summary())
+		if len(cfg.Runners.Labels) > 0 && lock.RunnerLabel != "" {
+			fmt.Printf("%s Self-hosted runners ignore the locked runner image %s\n", yellow(iconWarn), lock.Image)
+		}
+	}
 
-## Configuration
-- Instances: %d
-- Repository: %s/%s
+	if batch {
+		return runBatch(cfg, req)
+	}
 
-This issue will be used for P2P coordination between Claude Code instances.
-`, task, instances, cfg.GitHub.Owner, cfg.GitHub.Repo))
-	if err != nil {
-		return fmt.Errorf("failed to create issue: %w", err)
+	// Import the task from an external tracker
+	if startFrom != "" {
+		ticket, err := tracker.Fetch(cfg.Trackers, startFrom)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s Imported %s: %s\n", green(iconOK), ticket.Key, ticket.Title)
+		if req.Task == "" {
+			req.Task = ticket.Title
+		}
+		req.Details = tracker.IssueDetails(ticket)
+		req.Source = ticket.Ref
 	}
-	fmt.Printf("%s Created issue #%d\n", green("✓"), issue.Number)
 
-	// Trigger workflow
-	fmt.Printf("Triggering workflow with %d instances...\n", instances)
-	run, err := client.TriggerWorkflow(issue.Number, instances)
+	// Size the run by estimated complexity
+	if startAutoSize && !cmd.Flags().Changed("instances") {
+		analyzer, err := planner.New(cfg.Planner, "", ".", cfg.Instances.Max, responseCache(cfg))
+		if err != nil {
+			return err
+		}
+		est, err := analyzer.Analyze(req.Task)
+		if err != nil {
+			return fmt.Errorf("failed to estimate task: %w", err)
+		}
+		req.Instances = est.Instances
+		fmt.Printf("%s Auto-sized to %d instances (complexity %d/10, %s analyzer)\n", green(iconOK), est.Instances, est.Score, analyzer.Name())
+	}
+
+	// Create GitHub client
+	client := newClient(cfg)
+
+	// Without a token or connectivity, keep the request for 'outbox flush'
+	if cfg.GitHub.Token == "" {
+		return deferRun(req, "GitHub token is not set")
+	}
+	if err := client.Ping(); errors.Is(err, github.ErrUnreachable) || errors.Is(err, github.ErrUnauthorized) {
+		return deferRun(req, err.Error())
+	}
+
+	// Fail before creating the issue if the run cannot be dispatched
+	if err := checkDispatch(client, cfg); err != nil {
+		return err
+	}
+
+	// Enforce quotas and scheduling windows
+	if cfg.Quotas.Enabled() {
+		decision, err := checkQuota(client, cfg)
+		if err != nil {
+			return fmt.Errorf("failed to check quotas: %w", err)
+		}
+		if !decision.Allowed {
+			return queueRun(cfg, req, decision.Reason)
+		}
+	}
+
+	fmt.Println(bold("Starting autonomous development..."))
+	if cfg.Package != "" {
+		fmt.Printf("Package: %s (scope: %s)\n", cfg.Package, strings.Join(cfg.Paths, ", "))
+	}
+	fmt.Println()
+
+	issue, run, err := dispatchRun(client, cfg, newEventBus(client, cfg), req)
 	if err != nil {
-		return fmt.Errorf("failed to trigger workflow: %w", err)
+		return err
+	}
+	summarize("status", "dispatched")
+	summarize("instances", req.Instances)
+	if issue.Number > 0 {
+		summarize("issue", issue.Number)
+	}
+	if run.ID > 0 {
+		summarize("run_id", run.ID)
 	}
-	fmt.Printf("%s Triggered workflow run #%d\n", green("✓"), run.ID)
+	summarize("url", issue.URL)
 
 	// Print success
 	fmt.Println()
-	fmt.Println(green("✓"), bold("Autonomous development started!"))
+	fmt.Println(green(iconOK), bold("Autonomous development started!"))
 	fmt.Println()
 	fmt.Println("Monitor progress:")
-	fmt.Printf("  Issue: %s\n", issue.URL)
+	if issue.Number > 0 {
+		fmt.Printf("  Issue: %s\n", issue.URL)
+	} else {
+		fmt.Printf("  State: %s\n", issue.URL)
+	}
 	fmt.Printf("  Workflow: %s\n", run.URL)
 	fmt.Printf("  Dashboard: autonomous-dev dashboard\n")
 	fmt.Println()
@@ -103,3 +317,575 @@ This issue will be used for P2P coordination between Claude Code instances.
 
 	return nil
 }
+
+// runRequest describes a run to dispatch
+type runRequest struct {
+	Task      string
+	Instances int
+	// Untrusted runs work on a fork and return changes as reviewed fork PRs
+	Untrusted bool
+	// Agent names the agent to use; empty selects one by skills
+	Agent string
+	// Details are added below the task in the issue body
+	Details string
+	// Source references the external ticket the task came from, e.g. "jira:PROJ-123"
+	Source string
+	// SHA pins the commit instances check out; empty pins the head of main
+	SHA string
+	// Environment is the deployment environment the run targets; empty
+	// targets deployments.environment if the task mentions a deployment keyword
+	Environment string
+	// EnvLock is the env_lock workflow input reproducing a past run's environment
+	EnvLock string
+	// Priority orders the run in the queue and outbox
+	Priority string
+	// Canary dispatches a single trial instance first; the rest follow
+	// once it passes its quality gates
+	Canary bool
+	// LogLevel overrides logs.level for the run's instances
+	LogLevel string
+	// Trace is the run's trace ID, generated when it is dispatched
+	Trace string
+	// Env holds environment variables set for every instance
+	Env map[string]string
+	// Issue is an existing issue to attach the run to instead of creating
+	// one, in issue coordination mode
+	Issue int
+	// CreatedIssue and CreatedRun are the issue or state branch run an
+	// earlier attempt created before its workflow failed to trigger
+	CreatedIssue int
+	CreatedRun   string
+}
+
+// triggerError is returned by dispatchRun when the run's issue or state
+// branch run was created but its workflow could not be triggered
+type triggerError struct {
+	Issue    int
+	StateRun string
+	Trace    string
+	BaseSHA  string
+	err      error
+}
+
+func (e *triggerError) Error() string {
+	return e.err.Error()
+}
+
+func (e *triggerError) Unwrap() error {
+	return e.err
+}
+
+// keepCreated records on a queue or outbox entry what its failed dispatch
+// created, so the retry reuses it; it reports whether there was anything
+func keepCreated(entry *queue.Entry, err error) bool {
+	var terr *triggerError
+	if !errors.As(err, &terr) {
+		return false
+	}
+	entry.CreatedIssue = terr.Issue
+	entry.CreatedRun = terr.StateRun
+	entry.Trace = terr.Trace
+	// The created issue or run names its base; the retry must not move it
+	if entry.SHA == "" {
+		entry.SHA = terr.BaseSHA
+	}
+	return true
+}
+
+// dispatchCount is the number of instances the first workflow run starts
+func (r runRequest) dispatchCount() int {
+	if r.canary() {
+		return canary.Instance
+	}
+	return r.Instances
+}
+
+// canary reports whether the run starts with a canary; a single-instance
+// run has nothing to fan out
+func (r runRequest) canary() bool {
+	return r.Canary && r.Instances > canary.Instance
+}
+
+// requestEntry converts a run request to a queue or outbox entry
+func requestEntry(req runRequest, reason string) *queue.Entry {
+	return &queue.Entry{
+		Task:         req.Task,
+		Instances:    req.Instances,
+		Untrusted:    req.Untrusted,
+		Agent:        req.Agent,
+		Details:      req.Details,
+		Source:       req.Source,
+		SHA:          req.SHA,
+		Environment:  req.Environment,
+		EnvLock:      req.EnvLock,
+		Priority:     req.Priority,
+		Canary:       req.Canary,
+		LogLevel:     req.LogLevel,
+		Env:          req.Env,
+		Issue:        req.Issue,
+		CreatedIssue: req.CreatedIssue,
+		CreatedRun:   req.CreatedRun,
+		Trace:        req.Trace,
+		Reason:       reason,
+	}
+}
+
+// entryRequest converts a queue or outbox entry back to a run request
+func entryRequest(entry *queue.Entry) runRequest {
+	return runRequest{
+		Task:         entry.Task,
+		Instances:    entry.Instances,
+		Untrusted:    entry.Untrusted,
+		Agent:        entry.Agent,
+		Details:      entry.Details,
+		Source:       entry.Source,
+		SHA:          entry.SHA,
+		Environment:  entry.Environment,
+		EnvLock:      entry.EnvLock,
+		Priority:     entry.Priority,
+		Canary:       entry.Canary,
+		LogLevel:     entry.LogLevel,
+		Env:          entry.Env,
+		Issue:        entry.Issue,
+		Trace:        entry.Trace,
+		CreatedIssue: entry.CreatedIssue,
+		CreatedRun:   entry.CreatedRun,
+	}
+}
+
+// checkDispatch fails when no run can be dispatched: the token cannot
+// dispatch workflows or mandatory requirements are missing. Every path to
+// dispatchRun calls it first, deferred and queued starts included.
+func checkDispatch(client *github.Client, cfg *config.Config) error {
+	if err := preflightToken(client); err != nil {
+		return err
+	}
+	return requireMandatory(client, cfg)
+}
+
+// dispatchRun creates the coordination issue (or state branch run in dispatch
+// mode), triggers the workflow and publishes RunStarted
+func dispatchRun(client *github.Client, cfg *config.Config, bus *events.Bus, req runRequest) (*github.Issue, *github.WorkflowRun, error) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	// The trace ID ties the issue, workflow run, logs, notifications and
+	// history of the run together
+	if req.Trace == "" {
+		req.Trace = protocol.NewTraceID()
+	}
+	inputs := map[string]string{"trace_id": req.Trace}
+	var labels []string
+
+	// Untrusted tasks never receive repo-write credentials: instances check
+	// out and push to a fork using a token scoped to that fork only
+	if req.Untrusted {
+		if cfg.Untrusted.ForkOwner == "" {
+			return nil, nil, fmt.Errorf("untrusted mode requires untrusted.fork_owner in config")
+		}
+		forkRepo, err := client.EnsureFork(cfg.Untrusted.ForkOwner, cfg.Untrusted.ForkOwnerIsOrg)
+		if err != nil {
+			return nil, nil, err
+		}
+		fmt.Printf("%s Using fork %s\n", green(iconOK), forkRepo)
+
+		inputs["untrusted"] = "true"
+		inputs["fork_repo"] = forkRepo
+		labels = append(labels, "untrusted")
+	}
+
+	// Agent defaults frame the task consistently per specialty
+	agent, err := scheduler.SelectAgent(cfg.Agents, req.Task, req.Agent)
+	if err != nil {
+		return nil, nil, err
+	}
+	if agent != nil {
+		fmt.Printf("%s Using agent %s\n", green(iconOK), agent.Name)
+		inputs = scheduler.MergeInputs(agent, inputs)
+	}
+
+	// Pin the base before rendering so the issue and every instance agree on it
+	baseSHA, err := resolveBaseSHA(client, req.SHA)
+	if err != nil {
+		return nil, nil, err
+	}
+	if baseSHA != "" {
+		inputs["base_sha"] = baseSHA
+		fmt.Printf("%s Pinned base commit %s\n", green(iconOK), shortSHA(baseSHA))
+	}
+
+	if req.EnvLock != "" {
+		inputs["env_lock"] = req.EnvLock
+	}
+	if req.LogLevel != "" {
+		inputs["log_level"] = req.LogLevel
+	}
+	if len(req.Env) > 0 {
+		data, err := json.Marshal(req.Env)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to encode run environment: %w", err)
+		}
+		inputs["run_env"] = string(data)
+		names := make([]string, 0, len(req.Env))
+		for name := range req.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Printf("%s Setting %s for every instance\n", green(iconOK), strings.Join(names, ", "))
+	}
+
+	// Deployable runs wait for the environment's protection rules
+	environment := req.Environment
+	if environment == "" && cfg.Deployments.Environment != "" && scheduler.IsDeployable(req.Task, cfg.Deployments) {
+		environment = cfg.Deployments.Environment
+	}
+	if environment != "" {
+		inputs["environment"] = environment
+		rules, err := client.EnvironmentRules(environment)
+		switch {
+		case errors.Is(err, github.ErrNoEnvironment):
+			fmt.Printf("%s Environment %s does not exist; GitHub creates it without protection rules\n", yellow(iconWarn), environment)
+		case err != nil:
+			fmt.Printf("%s Warning: %v\n", yellow(iconWarn), err)
+		case len(rules) > 0:
+			fmt.Printf("%s Instances wait for %s protection rules: %s\n", cyan(iconWait), environment, strings.Join(rules, ", "))
+		default:
+			fmt.Printf("%s Deploying to %s\n", green(iconOK), environment)
+		}
+	}
+
+	// A failing pre_start hook stops the run before anything is created
+	if cfg.Hooks.PreStart != "" {
+		agentName := ""
+		if agent != nil {
+			agentName = agent.Name
+		}
+		err := hooks.Run(hooks.PreStart, cfg.Hooks.PreStart, hooks.Start{
+			Hook:        hooks.PreStart,
+			Repository:  cfg.GitHub.Owner + "/" + cfg.GitHub.Repo,
+			Task:        req.Task,
+			Instances:   req.Instances,
+			Agent:       agentName,
+			Source:      req.Source,
+			BaseSHA:     baseSHA,
+			Environment: environment,
+			Canary:      req.canary(),
+			Trace:       req.Trace,
+			Env:         req.Env,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		fmt.Printf("%s Ran pre_start hook\n", green(iconOK))
+	}
+
+	// Point instances at context files changed since the previous run
+	data := template.Data{Config: cfg, Task: req.Task, Instances: req.Instances, Agent: agent, BaseSHA: baseSHA, Environment: environment}
+	if req.Details != "" {
+		data.Task += "\n\n" + req.Details
+	}
+	if len(cfg.Context.Sources) > 0 {
+		result, err := bundle.Build(bundle.DefaultDir(), cfg.Context.Sources, bundle.Options{MaxFileBytes: cfg.Context.MaxFileBytes})
+		if err != nil {
+			fmt.Printf("%s Warning: failed to refresh context bundle: %v\n", yellow(iconWarn), err)
+		} else {
+			fmt.Printf("%s Refreshed context bundle (%d of %d sections regenerated)\n", green(iconOK), result.Regenerated, result.Files)
+			data.Changes = result.Delta.Markdown()
+		}
+	}
+
+	body, err := template.IssueBody(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to render issue body: %w", err)
+	}
+	body += fmt.Sprintf("\nTrace ID: `%s`\n", req.Trace)
+	body += protocol.Link{Kind: protocol.LinkCoordination, Trace: req.Trace}.Marker() + "\n"
+
+	// The fan-out reuses the inputs; only the first run is the canary
+	fanout := make(map[string]string, len(inputs))
+	for name, value := range inputs {
+		fanout[name] = value
+	}
+	if req.canary() {
+		inputs["canary"] = "true"
+	}
+
+	if cfg.Coordination.Dispatch() {
+		return dispatchStateRun(client, cfg, bus, req, body, inputs, fanout, baseSHA, environment)
+	}
+
+	// Create GitHub Issue, or attach the run to an existing one
+	var issue *github.Issue
+	switch {
+	case req.CreatedIssue > 0:
+		if issue, err = client.GetIssue(req.CreatedIssue); err != nil {
+			return nil, nil, err
+		}
+		fmt.Printf("%s Reusing issue #%d created by an earlier attempt\n", green(iconOK), issue.Number)
+	case req.Issue > 0:
+		if issue, err = adoptIssue(client, req.Issue, body, labels); err != nil {
+			return nil, nil, err
+		}
+	default:
+		fmt.Printf("Creating issue with task: %s\n", cyan(req.Task))
+		// Oversized bodies continue in comments, which get_task joins back
+		parts := chunk.Split(body, chunk.MaxBody)
+		issue, err = client.CreateIssue(req.Task, parts[0], labels...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create issue: %w", err)
+		}
+		for _, part := range parts[1:] {
+			if err := client.CreateComment(issue.Number, part); err != nil {
+				return nil, nil, fmt.Errorf("failed to continue issue body: %w", err)
+			}
+		}
+		fmt.Printf("%s Created issue #%d\n", green(iconOK), issue.Number)
+	}
+
+	// Trigger workflow
+	if req.canary() {
+		fmt.Printf("Triggering workflow with a canary instance (%d after it passes)...\n", req.Instances-canary.Instance)
+	} else {
+		fmt.Printf("Triggering workflow with %d instances...\n", req.Instances)
+	}
+	run, err := client.TriggerWorkflow(issue.Number, req.dispatchCount(), inputs)
+	if err != nil {
+		return nil, nil, &triggerError{Issue: issue.Number, Trace: req.Trace, BaseSHA: baseSHA, err: fmt.Errorf("failed to trigger workflow: %w", err)}
+	}
+	fmt.Printf("%s Triggered workflow run #%d (trace %s)\n", green(iconOK), run.ID, req.Trace)
+	summarize("trace", req.Trace)
+	if req.canary() {
+		if err := recordCanary(canary.Trial{Issue: issue.Number, Task: req.Task, Instances: req.Instances, Inputs: fanout, CreatedAt: time.Now()}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	bus.Publish(events.RunStarted{
+		Issue:       issue.Number,
+		IssueURL:    issue.URL,
+		RunURL:      run.URL,
+		Task:        req.Task,
+		Source:      req.Source,
+		Instances:   req.Instances,
+		BaseSHA:     baseSHA,
+		Environment: environment,
+		Trace:       req.Trace,
+		At:          time.Now(),
+	})
+
+	return issue, run, nil
+}
+
+// attachedMarker separates an attached issue's own body from the run's
+// section, which is replaced when the issue is attached again
+const attachedMarker = "<!-- autonomous-dev:attached -->"
+
+// adoptIssue makes an existing open issue the coordination issue of a run:
+// the rendered run section is appended to its body, where instances read
+// the task, and it is labeled like created issues
+func adoptIssue(client *github.Client, number int, section string, labels []string) (*github.Issue, error) {
+	green := color.New(color.FgGreen).SprintFunc()
+
+	issue, err := client.GetIssue(number)
+	if err != nil {
+		return nil, err
+	}
+	if issue.PullRequest {
+		return nil, fmt.Errorf("#%d is a pull request, not an issue", number)
+	}
+	if issue.State == "closed" {
+		return nil, fmt.Errorf("issue #%d is closed", number)
+	}
+
+	own, _, _ := strings.Cut(issue.Body, attachedMarker)
+	parts := chunk.Split(strings.TrimRight(own, "\n")+"\n\n"+attachedMarker+"\n"+section, chunk.MaxBody)
+	if err := client.UpdateIssueBody(number, parts[0]); err != nil {
+		return nil, err
+	}
+	for _, part := range parts[1:] {
+		if err := client.CreateComment(number, part); err != nil {
+			return nil, fmt.Errorf("failed to continue issue body: %w", err)
+		}
+	}
+	if err := client.AddLabels(number, append([]string{"autonomous-dev"}, labels...)...); err != nil {
+		return nil, err
+	}
+	fmt.Printf("%s Attached run to issue #%d\n", green(iconOK), number)
+	return issue, nil
+}
+
+// dispatchStateRun records the run on the state branch instead of creating an
+// issue and triggers the workflow with repository_dispatch. The returned issue
+// has no number; its URL points at the run's state directory.
+func dispatchStateRun(client *github.Client, cfg *config.Config, bus *events.Bus, req runRequest, body string, inputs, fanout map[string]string, baseSHA, environment string) (*github.Issue, *github.WorkflowRun, error) {
+	green := color.New(color.FgGreen).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	now := time.Now()
+	branch := cfg.Coordination.Branch()
+	record := coordination.Run{ID: coordination.NewRunID(now), Task: req.Task, Instances: req.Instances, CreatedAt: now, BaseSHA: baseSHA, Trace: req.Trace}
+
+	if req.CreatedRun != "" {
+		record.ID = req.CreatedRun
+		fmt.Printf("%s Reusing run %s recorded by an earlier attempt\n", green(iconOK), record.ID)
+	} else {
+		fmt.Printf("Recording run with task: %s\n", cyan(req.Task))
+		if err := coordination.StartRun(client, branch, record, body); err != nil {
+			return nil, nil, err
+		}
+		fmt.Printf("%s Recorded run %s on %s\n", green(iconOK), record.ID, branch)
+	}
+	store := coordination.NewBranchStore(client, cfg.GitHub.Owner, cfg.GitHub.Repo, branch, record.ID)
+
+	if req.canary() {
+		fmt.Printf("Dispatching workflow with a canary instance (%d after it passes)...\n", req.Instances-canary.Instance)
+	} else {
+		fmt.Printf("Dispatching workflow with %d instances...\n", req.Instances)
+	}
+	run, err := client.DispatchRun(record.ID, req.dispatchCount(), inputs)
+	if err != nil {
+		return nil, nil, &triggerError{StateRun: record.ID, Trace: req.Trace, BaseSHA: baseSHA, err: fmt.Errorf("failed to trigger workflow: %w", err)}
+	}
+	fmt.Printf("%s Sent repository_dispatch event (trace %s)\n", green(iconOK), req.Trace)
+	summarize("trace", req.Trace)
+	if req.canary() {
+		if err := recordCanary(canary.Trial{RunID: record.ID, Task: req.Task, Instances: req.Instances, Inputs: fanout, CreatedAt: now}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	bus.Publish(events.RunStarted{
+		StateRun:    record.ID,
+		IssueURL:    store.URL(),
+		RunURL:      run.URL,
+		Task:        req.Task,
+		Source:      req.Source,
+		Instances:   req.Instances,
+		BaseSHA:     baseSHA,
+		Environment: environment,
+		Trace:       req.Trace,
+		At:          now,
+	})
+
+	return &github.Issue{Title: req.Task, URL: store.URL()}, run, nil
+}
+
+// envKeyPattern matches environment variable names
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseRunEnv parses start --env KEY=VALUE pairs. Names the workflow or
+// GitHub Actions set for instances are refused, since they would be ignored
+// or break coordination; the workflow's are read from its env blocks.
+func parseRunEnv(cfg *config.Config, pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	names, err := template.WorkflowEnv(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the workflow's variables: %w", err)
+	}
+	reserved := make(map[string]bool, len(names))
+	for _, name := range names {
+		reserved[strings.ToUpper(name)] = true
+	}
+	env := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || !envKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid --env %q: expected KEY=VALUE with a variable name as KEY", pair)
+		}
+		upper := strings.ToUpper(key)
+		for _, prefix := range []string{"GITHUB_", "RUNNER_", "ACTIONS_"} {
+			if strings.HasPrefix(upper, prefix) {
+				return nil, fmt.Errorf("invalid --env %s: %s* variables are set by GitHub Actions", key, prefix)
+			}
+		}
+		if reserved[upper] {
+			return nil, fmt.Errorf("invalid --env %s: set by the workflow for every instance", key)
+		}
+		env[key] = value
+	}
+	return env, nil
+}
+
+// shaPattern matches full and abbreviated commit SHAs
+var shaPattern = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// resolveBaseSHA returns the full SHA of a pinned commit, or the head of
+// main when none is given. An unreadable head leaves the run unpinned.
+func resolveBaseSHA(client *github.Client, sha string) (string, error) {
+	if sha != "" {
+		return client.ResolveCommit(sha)
+	}
+	head, err := client.GetBranchSHA("main")
+	if err != nil {
+		return "", nil
+	}
+	return head, nil
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// checkQuota decides whether a run may be dispatched now
+func checkQuota(client *github.Client, cfg *config.Config) (quota.Decision, error) {
+	// Windows are expressed in the configured time zone, independent of --utc
+	loc, err := cfg.Display.Location()
+	if err != nil {
+		return quota.Decision{}, err
+	}
+	now := time.Now().In(loc)
+
+	usage, err := quota.MeasureUsage(client, now)
+	if err != nil {
+		return quota.Decision{}, err
+	}
+
+	return quota.Check(cfg.Quotas, usage, now)
+}
+
+// queueRun stores a run for later dispatch, or refuses it per quotas.on_limit
+func queueRun(cfg *config.Config, req runRequest, reason string) error {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	if cfg.Quotas.OnLimit == "refuse" {
+		return fmt.Errorf("run refused: %s", reason)
+	}
+
+	store := queue.Open(queue.DefaultDir())
+	queued, err := store.List()
+	if err != nil {
+		return err
+	}
+	entry := requestEntry(req, reason)
+	if err := store.Add(entry); err != nil {
+		return err
+	}
+	// The new entry goes after every entry of its priority or higher
+	preempted := 0
+	for _, other := range queued {
+		if entry.Preempts(other) {
+			preempted++
+		}
+	}
+	ahead := len(queued) - preempted
+
+	summarize("status", "queued")
+	summarize("queue_id", entry.ID)
+	fmt.Printf("%s Run queued: %s\n", yellow(iconPaused), reason)
+	fmt.Printf("  Queue entry: %s (%s priority, position %d)\n", entry.ID, entry.PriorityOf(), ahead+1)
+	if preempted > 0 {
+		fmt.Printf("  Dispatched before %d queued lower-priority runs\n", preempted)
+	}
+	fmt.Println()
+	fmt.Println("Queued runs are dispatched by:")
+	fmt.Println("  autonomous-dev serve")
+
+	return nil
+}
@@ -1,16 +1,37 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/coordination"
 	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/health"
+	"github.com/autonomous-dev/cli/internal/offline"
+	"github.com/autonomous-dev/cli/internal/poll"
+	"github.com/autonomous-dev/cli/internal/progress"
+	"github.com/autonomous-dev/cli/pkg/protocol"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var (
+	statusIssue     int
+	statusAttempt   int
+	statusDecisions bool
+	statusRun       string
+	statusWait      bool
+	statusInterval  time.Duration
+	statusRebalance bool
+	statusPreview   bool
+	statusOffline   bool
+)
+
 func StatusCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Check status of running instances",
 		Long: `Check the status of autonomous development by querying:
@@ -18,59 +39,353 @@ func StatusCmd() *cobra.Command {
 - P2P messages in issues
 - Overall progress
 
-Shows a summary of all running instances and their current tasks.`,
+Shows a summary of all running instances and their current tasks.
+With --issue, structured coordination messages posted to the issue are
+shown as well. In dispatch coordination mode the reports of the latest run
+on the state branch are shown without --issue; use --run to pick another.
+
+Re-run workflows show their latest attempt; use --attempt to inspect an
+earlier one. With --decisions, votes on decisions posted to the issue are
+tallied against their quorum.
+
+The console preview of failed instances is shown under their report; use
+--preview to show it for every instance. What previews carry is set under
+logs.status in the config.
+
+With --wait, status refreshes until the run finishes, then posts a final
+summary (instance outcomes, pull requests, duration and reported cost) to the
+coordination issue or state branch and closes or labels the issue as set
+under completion in the config. Refreshes come every --interval while the
+run changes and slow down while it is quiet, up to network.polling.idle;
+failed refreshes are retried with backoff.
+
+Every successful refresh is saved under .autonomous-dev/offline/. When
+GitHub cannot be reached or fails, status shows that snapshot instead,
+marked stale with its age; --offline shows it without calling GitHub.`,
 		RunE: runStatus,
 	}
+
+	cmd.Flags().IntVar(&statusIssue, "issue", 0, "Coordination issue number to read instance reports from (default: the run's issue)")
+	cmd.Flags().IntVar(&statusAttempt, "attempt", 0, "Run attempt to show (default latest)")
+	cmd.Flags().BoolVar(&statusDecisions, "decisions", false, "Show decision votes (requires an issue or dispatch mode)")
+	cmd.Flags().BoolVar(&statusRebalance, "rebalance", false, "Reassign heavy pending tasks away from constrained runners")
+	cmd.Flags().StringVar(&statusRun, "run", "", "State branch run ID to read in dispatch mode (default latest)")
+	cmd.Flags().BoolVar(&statusWait, "wait", false, "Refresh until the run finishes, then post a final summary")
+	cmd.Flags().DurationVar(&statusInterval, "interval", 30*time.Second, "Refresh interval with --wait while the run changes (see network.polling)")
+	cmd.Flags().BoolVar(&statusPreview, "preview", false, "Show the console preview of every instance, not only failed ones")
+	cmd.Flags().BoolVar(&statusOffline, "offline", false, "Show the status saved by the last successful refresh without calling GitHub")
+	cmd.MarkFlagsMutuallyExclusive("offline", "wait")
+	cmd.MarkFlagsMutuallyExclusive("offline", "rebalance")
+	cmd.MarkFlagsMutuallyExclusive("offline", "decisions")
+
+	return cmd
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
 	yellow := color.New(color.FgYellow).SprintFunc()
-	cyan := color.New(color.FgCyan).SprintFunc()
-	bold := color.New(color.Bold).SprintFunc()
 
 	// Load config
 	cfg, err := config.Load(config.ConfigPath())
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
+	if statusOffline {
+		cached, err := offline.Open(offline.DefaultDir()).Load(statusKey())
+		if err != nil {
+			return err
+		}
+		if cached == nil {
+			return fmt.Errorf("no status saved yet; run autonomous-dev status while GitHub is reachable")
+		}
+		showCachedStatus(cfg, cached)
+		return nil
+	}
 
 	// Create GitHub client
-	client := github.NewClient(cfg.GitHub.Token, cfg.GitHub.Owner, cfg.GitHub.Repo)
+	client := newClient(cfg)
+
+	// Warn about expiring tokens
+	if info, err := client.GetTokenInfo(); err == nil {
+		if warning := tokenExpiryWarning(info, cfg); warning != "" {
+			fmt.Printf("%s %s\n\n", yellow(iconWarn), warning)
+		}
+	}
+
+	// Refresh quickly while the run changes, slower while it is quiet
+	poller := newPoller(cfg, statusInterval)
+	var last string
+	for {
+		view, err := showStatus(client, cfg)
+		if err != nil {
+			if !transientError(err) {
+				return err
+			}
+			if !statusWait {
+				// Fall back to the last snapshot while GitHub is unavailable,
+				// unless part of the live status is already shown
+				var printed printedError
+				if errors.As(err, &printed) {
+					return err
+				}
+				cached, loadErr := offline.Open(offline.DefaultDir()).Load(statusKey())
+				if loadErr != nil || cached == nil {
+					return err
+				}
+				fmt.Printf("%s %v\n\n", yellow(iconWarn), err)
+				showCachedStatus(cfg, cached)
+				return nil
+			}
+			wait := poller.Next(failedPoll(err))
+			fmt.Printf("%s %v; retrying in %s\n", yellow(iconWarn), err, wait.Round(time.Second))
+			time.Sleep(wait)
+			continue
+		}
+		if !statusWait {
+			return nil
+		}
+		if view != nil && view.run.Status == "completed" {
+			return finishRun(client, cfg, view)
+		}
+
+		obs := poll.Observation{Active: view != nil}
+		if view != nil {
+			fingerprint := runFingerprint(view.run, view.jobs, view.snap)
+			obs.Changed, last = fingerprint != last, fingerprint
+		}
+		wait := poller.Next(obs)
+		fmt.Println()
+		fmt.Printf("%s Waiting for the run to finish (refreshing in %s)...\n\n", yellow(iconWait), wait.Round(time.Second))
+		time.Sleep(wait)
+	}
+}
+
+// statusView is what one status refresh observed
+type statusView struct {
+	run   *github.WorkflowRun
+	jobs  []github.Job
+	store coordination.Store
+	snap  *protocol.Snapshot
+}
+
+// showStatus prints the status of the latest (or --attempt) run once. It
+// returns nil when there is no run yet.
+func showStatus(client *github.Client, cfg *config.Config) (*statusView, error) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
 
 	// Get latest workflow run
 	run, err := client.GetLatestWorkflowRun()
 	if err != nil {
-		return fmt.Errorf("failed to get workflow run: %w", err)
+		return nil, fmt.Errorf("failed to get workflow run: %w", err)
 	}
 
 	if run == nil {
 		fmt.Println(yellow("No workflow runs found"))
 		fmt.Println("Start development with: autonomous-dev start --task=\"...\"")
-		return nil
+		return nil, nil
 	}
 
-	// Print status
-	fmt.Println(bold("Workflow Run #"), run.ID)
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("Status: %s\n", statusColor(run.Status))
-	fmt.Printf("Started: %s\n", run.CreatedAt)
-	fmt.Printf("URL: %s\n", cyan(run.URL))
-	fmt.Println()
+	latestAttempt := run.Attempt
+	if latestAttempt < 1 {
+		latestAttempt = 1
+		run.Attempt = 1
+	}
+	if statusAttempt > latestAttempt {
+		return nil, fmt.Errorf("run #%d has %d attempt(s)", run.ID, latestAttempt)
+	}
 
-	// Get jobs
-	jobs, err := client.GetWorkflowJobs(run.ID)
+	// Jobs of the latest attempt only, unless an earlier attempt was requested
+	var jobs []github.Job
+	if statusAttempt > 0 && statusAttempt != latestAttempt {
+		run, err = client.GetWorkflowRunAttempt(run.ID, statusAttempt)
+		if err != nil {
+			return nil, err
+		}
+		jobs, err = client.GetWorkflowAttemptJobs(run.ID, statusAttempt)
+	} else {
+		jobs, err = client.GetWorkflowJobs(run.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow jobs: %w", err)
+	}
+
+	issue := statusIssue
+	if issue == 0 && !cfg.Coordination.Dispatch() {
+		issue = runIssue(client, run)
+	}
+	// Read coordination reports before rendering instances so health can use
+	// heartbeats, and before printing anything so a failed read can still
+	// fall back to the cached status
+	store, err := coordinationStore(client, cfg, issue, statusRun)
 	if err != nil {
-		return fmt.Errorf("failed to get workflow jobs: %w", err)
+		return nil, err
+	}
+	if statusDecisions && store == nil {
+		return nil, fmt.Errorf("--decisions requires --issue: run #%d is not attributable to an issue", run.ID)
+	}
+	var snap *protocol.Snapshot
+	var protocolErrs []error
+	var bodies []string
+	if store != nil {
+		if bodies, err = store.Bodies(); err != nil {
+			return nil, err
+		}
+		snap, protocolErrs = protocol.Collect(bodies)
+		if cfg.Completion.RequireResultSchema {
+			protocolErrs = append(protocolErrs, snap.RequireSchema()...)
+		}
+		if err := failRejected(store, snap); err != nil {
+			return nil, err
+		}
+	}
+	printRunHeader(run, latestAttempt, issue, cfg)
+
+	// Compare completed instances' branches so reviewers see what each produced
+	var branches []string
+	instanceBranches := map[int]string{}
+	for _, job := range jobs {
+		if job.Status == "completed" && job.InstanceNumber() > 0 {
+			branch := instanceBranch(cfg, snap, job.InstanceNumber())
+			branches = append(branches, branch)
+			instanceBranches[job.InstanceNumber()] = branch
+		}
 	}
+	diffs := branchDiffs(client, branches)
 
 	fmt.Println(bold("Instances:"))
+	now := time.Now()
 	for i, job := range jobs {
 		status := statusIcon(job.Status)
-		fmt.Printf("%s Instance %d (%s) %s\n", status, i+1, job.Name, statusColor(job.Status))
+
+		// Logs are unavailable until the job has started; health and
+		// progress only need their recent part
+		logs := ""
+		if job.Status != "queued" {
+			logs, _ = client.GetJobLogTail(job.ID, health.LogBytes)
+		}
+		score := health.Compute(health.FromJob(job, logs, snap), now)
+
+		fmt.Printf("%s Instance %d (%s) %s %s%s\n", status, i+1, job.Name, statusColor(job.Status), healthBadge(score), inferredProgress(job, logs, snap))
+		if score.Level != health.Green {
+			for _, reason := range score.Reasons {
+				fmt.Printf("    - %s\n", reason)
+			}
+		}
+		if job.Status == "completed" && job.InstanceNumber() > 0 {
+			if stats, ok := diffs[instanceBranch(cfg, snap, job.InstanceNumber())]; ok {
+				fmt.Printf("    Changes: %s\n", diffSummary(stats))
+			}
+		}
 	}
 	fmt.Println()
 
-	// Calculate progress
+	// Duplicate work must not be merged twice
+	overlaps := instanceOverlaps(instanceBranches, diffs)
+	for _, o := range overlaps {
+		fmt.Printf("%s Overlap: %s\n", yellow(iconWarn), o)
+	}
+	if len(overlaps) > 0 {
+		fmt.Println()
+	}
+	summarize("overlaps", len(overlaps))
+	if err := blockOverlaps(client, instanceBranches, diffs, overlaps, run.URL); err != nil {
+		fmt.Printf("%s Warning: %v\n\n", yellow(iconWarn), err)
+	}
+
+	printProgress(jobs, snap, cfg)
+
+	if snap != nil {
+		printCoordination(snap, protocolErrs, store.Location())
+		printLinkedPRs(store)
+		if err := printCapabilities(snap, cfg, store, statusRebalance); err != nil {
+			return nil, printedError{err}
+		}
+		if statusDecisions {
+			if err := printDecisions(client, store, snap, cfg.Coordination.Voters); err != nil {
+				return nil, printedError{err}
+			}
+		}
+	}
+
+	// Let subscribers react (commit status, history, notifications);
+	// earlier attempts are history already and must not overwrite the commit status
+	if run.Attempt == latestAttempt {
+		publishRunEvents(newEventBus(client, cfg), run, jobs, issue)
+	}
+	if err := promoteCanaries(client, cfg); err != nil {
+		fmt.Printf("%s %v\n", yellow(iconWarn), err)
+	}
+
+	if run.Status == "in_progress" && !statusWait {
+		fmt.Println()
+		fmt.Println("Watch in real-time:")
+		fmt.Println("  autonomous-dev dashboard")
+	}
+
+	// Keep what was fetched for status while GitHub is unavailable
+	cached := &offline.Status{FetchedAt: time.Now().UTC(), Key: statusKey(), Run: run, LatestAttempt: latestAttempt, Jobs: jobs, Issue: issue, Bodies: bodies}
+	if store != nil {
+		cached.Location = store.Location()
+	}
+	if err := offline.Open(offline.DefaultDir()).Save(cached); err != nil {
+		fmt.Printf("%s Warning: %v\n", yellow(iconWarn), err)
+	}
+
+	return &statusView{run: run, jobs: jobs, store: store, snap: snap}, nil
+}
+
+// printedError is an error of showStatus after it printed part of the live
+// status, which the cached status must not be mixed into
+type printedError struct{ error }
+
+func (e printedError) Unwrap() error { return e.error }
+
+// statusKey identifies the view status shows, for its offline snapshot
+func statusKey() string {
+	if statusIssue == 0 && statusRun == "" && statusAttempt == 0 {
+		return ""
+	}
+	return fmt.Sprintf("issue=%d run=%s attempt=%d", statusIssue, statusRun, statusAttempt)
+}
+
+// printRunHeader prints the run's status, attempt, start, issue and URL
+func printRunHeader(run *github.WorkflowRun, latestAttempt, issue int, cfg *config.Config) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	summarize("run_id", run.ID)
+	summarize("status", run.Status)
+	summarize("url", run.URL)
+	if run.Conclusion != "" {
+		summarize("conclusion", run.Conclusion)
+	}
+
+	fmt.Println(bold("Workflow Run #"), run.ID)
+	fmt.Println(rule())
+	fmt.Printf("Status: %s\n", statusColor(run.Status))
+	if latestAttempt > 1 {
+		fmt.Printf("Attempt: %d of %d\n", run.Attempt, latestAttempt)
+	}
+	fmt.Printf("Started: %s\n", formatTime(run.CreatedAt, cfg))
+	if issue > 0 {
+		fmt.Printf("Issue: #%d\n", issue)
+		summarize("issue", issue)
+	}
+	if base := run.BaseSHA(); base != "" {
+		fmt.Printf("Base: %s\n", shortSHA(base))
+	}
+	if cfg.Package != "" {
+		fmt.Printf("Package: %s\n", cfg.Package)
+	}
+	fmt.Printf("URL: %s\n", cyan(run.URL))
+	fmt.Println()
+}
+
+// printProgress prints how many instances completed, and whether the run
+// is paused
+func printProgress(jobs []github.Job, snap *protocol.Snapshot, cfg *config.Config) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
 	completed := 0
 	total := len(jobs)
 	for _, job := range jobs {
@@ -84,25 +399,297 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Overall Progress: %d/%d instances completed (%d%%)\n", completed, total, progress)
+	summarize("completed", completed)
+	summarize("total", total)
+	if cost, ok := runCost(snap); ok {
+		summarize("cost_usd", fmt.Sprintf("%.2f", cost))
+	}
+	if snap != nil && snap.Paused() {
+		pause := snap.Control
+		since := pause.IssuedAt
+		if at, err := time.Parse(time.RFC3339, since); err == nil {
+			since = formatTime(at, cfg)
+		}
+		fmt.Printf("%s Paused (%s) since %s", yellow(iconPaused), pause.Mode, since)
+		if pause.Reason != "" {
+			fmt.Printf(": %s", pause.Reason)
+		}
+		fmt.Println()
+	}
+}
+
+// showCachedStatus prints the status saved by the last successful refresh,
+// marked stale with its age. Nothing is fetched or posted.
+func showCachedStatus(cfg *config.Config, cached *offline.Status) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	age := cached.Age()
+	summarize("stale", true)
+	summarize("fetched_at", cached.FetchedAt.UTC().Format(time.RFC3339))
+	summarize("age_seconds", int(age.Seconds()))
+	fmt.Printf("%s Offline: showing status saved %s ago (%s); it may be out of date\n\n",
+		yellow(iconWarn), age.Truncate(time.Second), formatTime(cached.FetchedAt, cfg))
+
+	printRunHeader(cached.Run, cached.LatestAttempt, cached.Issue, cfg)
+
+	var snap *protocol.Snapshot
+	var protocolErrs []error
+	if cached.Location != "" {
+		snap, protocolErrs = protocol.Collect(cached.Bodies)
+		if cfg.Completion.RequireResultSchema {
+			protocolErrs = append(protocolErrs, snap.RequireSchema()...)
+		}
+	}
+
+	// Health and changes need job logs and branches, which are not saved
+	fmt.Println(bold("Instances:"))
+	for i, job := range cached.Jobs {
+		fmt.Printf("%s Instance %d (%s) %s%s\n", statusIcon(job.Status), i+1, job.Name, statusColor(job.Status), inferredProgress(job, "", snap))
+	}
+	fmt.Println()
+
+	printProgress(cached.Jobs, snap, cfg)
+	if snap != nil {
+		printCoordination(snap, protocolErrs, cached.Location)
+	}
+}
+
+// inferredProgress describes progress inferred from steps and logs for
+// running instances that have not reported progress themselves
+func inferredProgress(job github.Job, logs string, snap *protocol.Snapshot) string {
+	if job.Status != "in_progress" {
+		return ""
+	}
+	if snap != nil {
+		if _, reported := snap.Statuses[job.InstanceNumber()]; reported {
+			return ""
+		}
+	}
+
+	est := progress.Infer(job, logs)
+	detail := est.Milestone
+	if detail == "" {
+		detail = est.Step
+	}
+	if detail == "" {
+		return fmt.Sprintf(" ~%d%%", est.Percent)
+	}
+	return fmt.Sprintf(" ~%d%% (%s)", est.Percent, detail)
+}
+
+// runIssue returns the issue a run was dispatched for, or 0 if unknown
+func runIssue(client *github.Client, run *github.WorkflowRun) int {
+	if issue := run.Issue(); issue > 0 {
+		return issue
+	}
+	issue, err := client.GetRunIssue(run.ID)
+	if err != nil {
+		return 0
+	}
+	return issue
+}
+
+// countInstanceJobs counts completed and total matrix instance jobs
+func countInstanceJobs(jobs []github.Job) (completed, total int) {
+	for _, job := range jobs {
+		if job.InstanceNumber() == 0 {
+			continue
+		}
+		total++
+		if job.Status == "completed" {
+			completed++
+		}
+	}
+	return completed, total
+}
+
+// instanceStatusDescription is the commit status text for run progress
+func instanceStatusDescription(completed, total int) string {
+	return fmt.Sprintf("%d/%d instances complete", completed, total)
+}
+
+// coordinationStore returns where coordination messages are read from: the
+// given issue, or in dispatch mode the given (default latest) state branch
+// run. It returns nil when there is nothing to read.
+func coordinationStore(client *github.Client, cfg *config.Config, issueNumber int, runID string) (coordination.Store, error) {
+	if issueNumber > 0 {
+		return coordination.NewIssueStore(client, cfg.GitHub.Owner, cfg.GitHub.Repo, issueNumber), nil
+	}
+	if !cfg.Coordination.Dispatch() {
+		return nil, nil
+	}
+
+	if runID == "" {
+		latest, err := coordination.LatestRunID(client, cfg.Coordination.Branch())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read state branch: %w", err)
+		}
+		if latest == "" {
+			return nil, nil
+		}
+		runID = latest
+	}
+	return coordination.NewBranchStore(client, cfg.GitHub.Owner, cfg.GitHub.Repo, cfg.Coordination.Branch(), runID), nil
+}
+
+func readCoordination(store coordination.Store) (*protocol.Snapshot, []error, error) {
+	bodies, err := store.Bodies()
+	if err != nil {
+		return nil, nil, err
+	}
+	snap, errs := protocol.Collect(bodies)
+
+	return snap, errs, nil
+}
+
+func printCoordination(snap *protocol.Snapshot, errs []error, location string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+
+	fmt.Println()
+	fmt.Println(bold("Instance Reports (" + location + ")"))
+	if len(snap.Statuses) == 0 {
+		fmt.Println("  No status reports yet")
+	}
+	for _, id := range snap.InstanceIDs() {
+		st := snap.Statuses[id]
+		fmt.Printf("%s Instance %d (%s) %s %d%% - %s\n",
+			statusIcon(st.Status), id, st.Role, statusColor(st.Status), st.CurrentTask.Progress, st.CurrentTask.Description)
+		if statusPreview || st.Status == "failed" {
+			printPreview(st)
+		}
+	}
 
-	if run.Status == "in_progress" {
+	if len(snap.Assignments) > 0 {
 		fmt.Println()
-		fmt.Println("Watch in real-time:")
-		fmt.Println("  autonomous-dev dashboard")
+		fmt.Println(bold("Tasks:"))
+		followUps := 0
+		for _, taskID := range snap.TaskIDs() {
+			assignment := snap.Assignments[taskID]
+			outcome, label := "pending", "pending"
+			if result, ok := snap.Results[taskID]; ok {
+				outcome, label = result.Outcome, resultOutcome(result)
+				if result.NeedsFollowUp() {
+					followUps++
+				}
+			}
+			fmt.Printf("%s %s %s instance %d: %s %s\n",
+				statusIcon(outcome), taskID, iconArrow, assignment.InstanceID, assignment.Description, statusColor(label))
+		}
+		if followUps > 0 {
+			fmt.Printf("%s %d task(s) stopped early and need a follow-up run\n", yellow(iconWarn), followUps)
+		}
+		if snap.Replan != nil && len(snap.Cancelled) > 0 {
+			fmt.Printf("%s %d task(s) cancelled by replan\n", iconBullet, len(snap.Cancelled))
+		}
 	}
 
-	return nil
+	for _, err := range errs {
+		fmt.Printf("%s %v\n", yellow(iconWarn), err)
+	}
+}
+
+// printPreview prints the console preview of a status report indented under
+// it, with omitted lines and the line numbers of errors truncation marked
+func printPreview(st *protocol.Status) {
+	faint := color.New(color.Faint).SprintFunc()
+
+	for _, line := range st.Preview() {
+		switch {
+		case line.Omitted > 0:
+			fmt.Printf("    %s\n", faint(fmt.Sprintf("... %d lines omitted ...", line.Omitted)))
+		case line.Number > 0:
+			fmt.Printf("    %s %s\n", faint(fmt.Sprintf("%4d|", line.Number)), line.Text)
+		default:
+			fmt.Printf("    %s\n", line.Text)
+		}
+	}
+}
+
+// printLinkedPRs lists the pull requests created for the run with their
+// check state, from the issue graph read with the coordination messages
+func printLinkedPRs(store coordination.Store) {
+	bold := color.New(color.Bold).SprintFunc()
+
+	s, ok := store.(*coordination.IssueStore)
+	if !ok {
+		return
+	}
+	graph, err := s.Graph()
+	if err != nil {
+		return
+	}
+
+	var prs []github.Reference
+	for _, ref := range graph.References {
+		if link, ok := protocol.ParseLink(ref.Body); ok && ref.PullRequest && link.Of(s.Issue(), "") {
+			prs = append(prs, ref)
+		}
+	}
+	if len(prs) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(bold("Pull Requests:"))
+	drafts := 0
+	for _, pr := range prs {
+		state := pr.State
+		if pr.Draft {
+			state += ", draft"
+			if pr.State == "open" {
+				drafts++
+			}
+		}
+		if pr.Checks != "" {
+			state += ", checks " + pr.Checks
+		}
+		fmt.Printf("%s #%d %s (%s)\n", statusIcon(prStatus(pr)), pr.Number, pr.Title, state)
+	}
+	if drafts > 0 {
+		fmt.Printf("Mark drafts ready for review with: autonomous-dev prs ready --issue %d\n", s.Issue())
+	}
+}
+
+// prStatus maps a pull request's state and checks to a status icon name
+func prStatus(pr github.Reference) string {
+	switch {
+	case pr.State == "merged" || pr.Checks == "success":
+		return "completed"
+	case pr.Checks == "failure" || pr.Checks == "error":
+		return "failed"
+	case pr.State == "closed":
+		return "cancelled"
+	}
+	return "in_progress"
+}
+
+// healthBadge renders a colored health badge for an instance
+func healthBadge(score health.Score) string {
+	badge := fmt.Sprintf("[health %d]", score.Value)
+	switch score.Level {
+	case health.Green:
+		return color.GreenString(badge)
+	case health.Yellow:
+		return color.YellowString(badge)
+	default:
+		return color.RedString(badge)
+	}
 }
 
 func statusColor(status string) string {
 	switch status {
 	case "completed", "success":
 		return color.GreenString(status)
-	case "in_progress", "queued":
+	case "in_progress", "queued", "partial", "paused":
 		return color.YellowString(status)
 	case "failed", "failure":
 		return color.RedString(status)
 	default:
+		if strings.HasPrefix(status, protocol.OutcomeLimitReached) {
+			return color.YellowString(status)
+		}
 		return status
 	}
 }
@@ -110,14 +697,18 @@ func statusColor(status string) string {
 func statusIcon(status string) string {
 	switch status {
 	case "completed", "success":
-		return color.GreenString("✓")
+		return color.GreenString(iconOK)
 	case "in_progress":
-		return color.YellowString("⏳")
+		return color.YellowString(iconWait)
 	case "queued":
-		return color.CyanString("⏸")
+		return color.CyanString(iconPaused)
+	case "paused":
+		return color.YellowString(iconPaused)
+	case "partial", protocol.OutcomeLimitReached:
+		return color.YellowString(iconWarn)
 	case "failed", "failure":
-		return color.RedString("✗")
+		return color.RedString(iconFail)
 	default:
-		return "•"
+		return iconBullet
 	}
 }
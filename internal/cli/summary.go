@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/coordination"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+	"github.com/fatih/color"
+)
+
+// summaryMarker identifies the final summary of a run attempt so it is posted once
+func summaryMarker(run *github.WorkflowRun) string {
+	return fmt.Sprintf("<!-- RUN_SUMMARY:%d:%d -->", run.ID, run.Attempt)
+}
+
+// finishRun posts the final summary of a finished run to its coordination
+// store and closes or labels the issue per completion config
+func finishRun(client *github.Client, cfg *config.Config, view *statusView) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Println()
+	if view.store == nil {
+		fmt.Printf("%s Run finished; no coordination issue to post a summary to (use --issue)\n", yellow(iconWarn))
+		return nil
+	}
+
+	bodies, err := view.store.Bodies()
+	if err != nil {
+		return err
+	}
+	marker := summaryMarker(view.run)
+	for _, body := range bodies {
+		if strings.Contains(body, marker) {
+			fmt.Printf("%s Summary already posted to %s\n", green(iconOK), view.store.Location())
+			return nil
+		}
+	}
+
+	if err := view.store.Post(renderRunSummary(view.run, view.jobs, view.snap)); err != nil {
+		return fmt.Errorf("failed to post summary: %w", err)
+	}
+	fmt.Printf("%s Posted summary to %s\n", green(iconOK), view.store.Location())
+
+	issueStore, ok := view.store.(*coordination.IssueStore)
+	if !ok {
+		return nil
+	}
+	return completeIssue(client, cfg.Completion, issueStore.Issue(), view.run.Conclusion)
+}
+
+// completeIssue labels and closes a coordination issue by run conclusion
+func completeIssue(client *github.Client, cfg config.CompletionConfig, issue int, conclusion string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+
+	label := cfg.FailureLabel
+	if conclusion == "success" {
+		label = cfg.SuccessLabel
+	}
+	if label != "" {
+		if err := client.AddLabels(issue, label); err != nil {
+			return err
+		}
+		fmt.Printf("%s Labeled issue #%d %s\n", green(iconOK), issue, label)
+	}
+
+	if conclusion == "success" && cfg.CloseOnSuccess {
+		if err := client.CloseIssue(issue); err != nil {
+			return err
+		}
+		fmt.Printf("%s Closed issue #%d\n", green(iconOK), issue)
+	}
+	return nil
+}
+
+// renderRunSummary renders the final summary comment of a finished run
+func renderRunSummary(run *github.WorkflowRun, jobs []github.Job, snap *protocol.Snapshot) string {
+	var b strings.Builder
+
+	icon := "❌"
+	if run.Conclusion == "success" {
+		icon = "✅"
+	}
+	b.WriteString(summaryMarker(run))
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "## %s Run #%d%s finished: %s\n\n", icon, run.ID, attemptSuffix(run.Attempt), run.Conclusion)
+
+	completed, total := countInstanceJobs(jobs)
+	facts := []string{fmt.Sprintf("Instances: %d/%d completed", completed, total)}
+	if !run.CreatedAt.IsZero() && run.UpdatedAt.After(run.CreatedAt) {
+		facts = append([]string{"Duration: " + run.UpdatedAt.Sub(run.CreatedAt).Round(time.Second).String()}, facts...)
+	}
+	if cost, reported := runCost(snap); reported {
+		facts = append(facts, fmt.Sprintf("Cost: $%.2f", cost))
+	}
+	b.WriteString(strings.Join(facts, " · "))
+	fmt.Fprintf(&b, "\n\n[Workflow run](%s)\n\n", run.URL)
+
+	// Task results grouped by the instance that reported them
+	tasks := map[int][]string{}
+	prs := map[int][]string{}
+	if snap != nil {
+		for _, taskID := range reportTaskIDs(snap) {
+			r, ok := snap.Results[taskID]
+			if !ok {
+				continue
+			}
+			tasks[r.InstanceID] = append(tasks[r.InstanceID], fmt.Sprintf("%s: %s", taskID, resultOutcome(r)))
+			for _, pr := range r.PullRequests {
+				prs[r.InstanceID] = append(prs[r.InstanceID], fmt.Sprintf("#%d", pr))
+			}
+		}
+	}
+
+	var instances []github.Job
+	for _, job := range jobs {
+		if job.InstanceNumber() > 0 {
+			instances = append(instances, job)
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].InstanceNumber() < instances[j].InstanceNumber() })
+
+	if len(instances) > 0 {
+		b.WriteString("| Instance | Outcome | Tasks | Pull requests |\n")
+		b.WriteString("|---|---|---|---|\n")
+		for _, job := range instances {
+			n := job.InstanceNumber()
+			outcome := job.Conclusion
+			if outcome == "" {
+				outcome = job.Status
+			}
+			fmt.Fprintf(&b, "| %d | %s | %s | %s |\n", n, outcome, markdownCell(strings.Join(tasks[n], ", ")), strings.Join(prs[n], ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	if snap != nil {
+		var followUps []string
+		for _, taskID := range reportTaskIDs(snap) {
+			if r, ok := snap.Results[taskID]; ok && r.NeedsFollowUp() {
+				followUps = append(followUps, taskID)
+			}
+		}
+		if len(followUps) > 0 {
+			fmt.Fprintf(&b, "⚠️ Follow-up needed: %s\n", strings.Join(followUps, ", "))
+		}
+	}
+
+	return b.String()
+}
+
+// runCost sums the cost reported with task results
+func runCost(snap *protocol.Snapshot) (float64, bool) {
+	if snap == nil {
+		return 0, false
+	}
+	total, reported := 0.0, false
+	for _, r := range snap.Results {
+		if r.CostUSD > 0 {
+			total += r.CostUSD
+			reported = true
+		}
+	}
+	return total, reported
+}
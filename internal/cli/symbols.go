@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	globalNoColor bool
+	globalASCII   bool
+)
+
+// Icons and drawing characters of human output. --ascii replaces them with
+// plain text for logs, screen readers and terminals without Unicode fonts.
+var (
+	iconOK     = "✓"
+	iconWarn   = "⚠"
+	iconFail   = "✗"
+	iconWait   = "⏳"
+	iconPaused = "⏸"
+	iconBullet = "•"
+	iconArrow  = "→"
+	ruleChar   = "━"
+	treeBranch = "├─ "
+	treeLast   = "└─ "
+	treePipe   = "│  "
+)
+
+// useASCII switches icons and drawing characters to plain text
+func useASCII() {
+	iconOK = "[ok]"
+	iconWarn = "[warn]"
+	iconFail = "[fail]"
+	iconWait = "[wait]"
+	iconPaused = "[paused]"
+	iconBullet = "*"
+	iconArrow = "->"
+	ruleChar = "="
+	treeBranch = "|- "
+	treeLast = "`- "
+	treePipe = "|  "
+}
+
+// rule returns a horizontal line under a heading
+func rule() string {
+	return strings.Repeat(ruleChar, 40)
+}
+
+// setupOutput applies --no-color and --ascii before a command runs. Colors
+// are already off when NO_COLOR is set or stdout is not a terminal;
+// AUTONOMOUS_DEV_ASCII=1 turns on --ascii for every command.
+func setupOutput(cmd *cobra.Command, args []string) error {
+	if globalNoColor {
+		color.NoColor = true
+	}
+	if globalASCII || os.Getenv("AUTONOMOUS_DEV_ASCII") != "" {
+		useASCII()
+	}
+	return checkPorcelain(cmd, args)
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/telemetry"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func TelemetryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Inspect opt-in anonymous usage statistics",
+		Long: `Telemetry is off unless enabled with:
+  autonomous-dev config set telemetry.enabled true
+  autonomous-dev config set telemetry.endpoint https://...
+
+When enabled, each command sends its name, duration and error class to
+telemetry.endpoint. Arguments, flags, repository data and error messages are
+never sent.`,
+	}
+
+	cmd.AddCommand(telemetryShowCmd())
+
+	return cmd
+}
+
+func telemetryShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show telemetry settings and exactly what would be sent",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			started := time.Now()
+			green := color.New(color.FgGreen).SprintFunc()
+			yellow := color.New(color.FgYellow).SprintFunc()
+			bold := color.New(color.Bold).SprintFunc()
+
+			var settings config.TelemetryConfig
+			if cfg, err := config.Load(config.ConfigPath()); err == nil {
+				settings = cfg.Telemetry
+			}
+
+			switch {
+			case !settings.Enabled:
+				fmt.Printf("%s Telemetry is disabled; nothing is sent\n", green(iconOK))
+			case settings.Endpoint == "":
+				fmt.Printf("%s Telemetry is enabled but telemetry.endpoint is not set; nothing is sent\n", yellow(iconWarn))
+			default:
+				fmt.Printf("%s Telemetry is enabled; events are posted to %s\n", yellow(iconWarn), settings.Endpoint)
+			}
+			fmt.Println()
+
+			// Built exactly as RecordTelemetry builds events, from this invocation
+			payload, err := telemetry.Payload(telemetry.NewEvent(telemetryCommandName(cmd), time.Since(started), errorClass(nil)))
+			if err != nil {
+				return err
+			}
+			fmt.Println(bold("Payload for this command:"))
+			fmt.Printf("  %s\n", payload)
+			fmt.Println()
+			fmt.Printf("error_class is one of: %s\n", strings.Join([]string{
+				telemetry.ClassNone, telemetry.ClassUsage, telemetry.ClassConfig, telemetry.ClassPermission,
+				telemetry.ClassGitHub, telemetry.ClassNetwork, telemetry.ClassOther,
+			}, ", "))
+
+			return nil
+		},
+	}
+}
+
+// RecordTelemetry sends a usage event for a finished command when telemetry
+// is enabled. Failures are ignored so telemetry never affects the command.
+func RecordTelemetry(cmd *cobra.Command, duration time.Duration, cmdErr error) {
+	if cmd == nil {
+		return
+	}
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil || !cfg.Telemetry.Enabled || cfg.Telemetry.Endpoint == "" {
+		return
+	}
+
+	event := telemetry.NewEvent(telemetryCommandName(cmd), duration, errorClass(cmdErr))
+	_ = telemetry.NewSender(cfg.Telemetry.Endpoint).Send(event)
+}
+
+// telemetryCommandName is the command path without the binary name, e.g. "queue list"
+func telemetryCommandName(cmd *cobra.Command) string {
+	if !cmd.HasParent() {
+		return "root"
+	}
+	// Plugin names are org-specific, so they are not recorded
+	if _, ok := cmd.Annotations[pluginAnnotation]; ok {
+		return "plugin"
+	}
+	path := cmd.CommandPath()
+	return strings.TrimPrefix(path, cmd.Root().Name()+" ")
+}
+
+// errorClass maps an error to a coarse class without exposing its message
+func errorClass(err error) string {
+	if err == nil {
+		return telemetry.ClassNone
+	}
+
+	var netErr net.Error
+	msg := err.Error()
+	switch {
+	case errors.Is(err, github.ErrActionsWritePermission):
+		return telemetry.ClassPermission
+	case errors.As(err, &netErr):
+		return telemetry.ClassNetwork
+	case github.IsAPIError(err):
+		return telemetry.ClassGitHub
+	case strings.Contains(msg, "config"):
+		return telemetry.ClassConfig
+	case strings.HasPrefix(msg, "unknown command"), strings.HasPrefix(msg, "unknown flag"),
+		strings.HasPrefix(msg, "unknown shorthand flag"), strings.HasPrefix(msg, "required flag"),
+		strings.HasPrefix(msg, "invalid argument"), strings.Contains(msg, "arg(s)"):
+		return telemetry.ClassUsage
+	default:
+		return telemetry.ClassOther
+	}
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/template"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func TemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Inspect built-in templates and overrides",
+		Long: `Inspect the templates used to generate workflows and issues.
+
+Templates are resolved in layers: built-in → organization (templates.org_url)
+→ repository (.autonomous-dev/overrides/<name>.tmpl). Override files redefine
+blocks of the built-in template, for example:
+
+  [[define "runs-on"]]self-hosted[[end]]`,
+	}
+
+	cmd.AddCommand(templateResolveCmd())
+
+	return cmd
+}
+
+func templateResolveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "resolve <name>",
+		Short: "Show the final output of a template and its source layers",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bold := color.New(color.Bold).SprintFunc()
+			cyan := color.New(color.FgCyan).SprintFunc()
+
+			// Config is optional so built-ins can be inspected before init
+			cfg := config.DefaultConfig()
+			if config.Exists() {
+				loaded, err := config.Load(config.ConfigPath())
+				if err != nil {
+					return fmt.Errorf("failed to load config: %w", err)
+				}
+				cfg = loaded
+			}
+
+			resolved, err := template.Resolve(args[0], cfg, template.Data{
+				Config:    cfg,
+				Task:      "<task description>",
+				Instances: cfg.Instances.Default,
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(bold("Layers:"))
+			for i, layer := range resolved.Layers {
+				fmt.Printf("  %d. %s", i+1, cyan(layer.Source))
+				if len(layer.Blocks) > 0 {
+					fmt.Printf(" [%s]", strings.Join(layer.Blocks, ", "))
+				}
+				fmt.Println()
+			}
+			fmt.Println()
+			fmt.Println(bold("Output:"))
+			fmt.Print(resolved.Output)
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,347 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/coordination"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/history"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	traceIssue int
+	traceRun   string
+)
+
+// traceFindRunWindow is how many recent workflow runs trace find searches
+const traceFindRunWindow = 100
+
+func TraceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trace",
+		Short: "Show every object linked to a run",
+		Long: `Print the graph of objects created for a run: the coordination issue (or
+state branch run), the imported ticket, the workflow run and its deployment,
+each instance with its branch, and every issue and pull request that links
+back to the run.
+
+Artifacts created for a run carry a hidden marker,
+<!-- autonomous-dev:link kind=... issue=N instance=I -->, below a
+"Part of #N" breadcrumb. Instance pull requests are opened with it by the
+status reporter (open_pull_request); link_breadcrumb adds it to other
+issues and pull requests created for the run.
+
+Every run gets a trace ID (tr- and 12 hex digits) when it is started. It
+is shown in the issue body, the workflow run name, instance log lines,
+notifications and history; 'trace find' maps it back to the run.`,
+		Example: `  autonomous-dev trace --issue 42
+  autonomous-dev trace --run 20240601T120000.000Z-ab12
+  autonomous-dev trace find tr-3f9a0c12b7e4`,
+		RunE: runTrace,
+	}
+
+	cmd.Flags().IntVar(&traceIssue, "issue", 0, "Coordination issue number")
+	cmd.Flags().StringVar(&traceRun, "run", "", "State branch run ID in dispatch mode (default latest)")
+
+	cmd.AddCommand(traceFindCmd())
+
+	return cmd
+}
+
+func traceFindCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "find <trace-id>",
+		Short: "Show the run a trace ID belongs to",
+		Long: `Find the run of a trace ID and print its graph like 'trace'.
+
+The argument may be any text containing the ID, such as a log line, a
+notification or a workflow run name. The run is looked up in the local
+history, then in the names of recent workflow runs, then in issue and pull
+request bodies.`,
+		Example: `  autonomous-dev trace find tr-3f9a0c12b7e4
+  autonomous-dev trace find "Autonomous Development (issue #42) [tr-3f9a0c12b7e4]"`,
+		Args: cobra.ExactArgs(1),
+		RunE: runTraceFind,
+	}
+}
+
+func runTraceFind(cmd *cobra.Command, args []string) error {
+	green := color.New(color.FgGreen).SprintFunc()
+
+	id := protocol.FindTraceID(args[0])
+	if id == "" {
+		return fmt.Errorf("no trace ID in %q (expected tr- followed by 12 hex digits)", args[0])
+	}
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := newClient(cfg)
+
+	issue, runID, err := findTrace(client, id)
+	if err != nil {
+		return err
+	}
+	if issue == 0 && runID == "" {
+		return fmt.Errorf("trace %s not found in history, the last %d workflow runs or issue bodies", id, traceFindRunWindow)
+	}
+
+	summarize("trace", id)
+	if issue > 0 {
+		summarize("issue", issue)
+		fmt.Printf("%s Trace %s belongs to issue #%d\n\n", green(iconOK), id, issue)
+	} else {
+		summarize("run_id", runID)
+		fmt.Printf("%s Trace %s belongs to run %s\n\n", green(iconOK), id, runID)
+	}
+	traceIssue, traceRun = issue, runID
+	return runTrace(cmd, nil)
+}
+
+// findTrace returns the coordination issue, or state branch run, of a trace
+// ID. Local history is checked before the API.
+func findTrace(client *github.Client, id string) (int, string, error) {
+	records, err := history.Open(history.DefaultDir()).List()
+	if err != nil {
+		return 0, "", err
+	}
+	for _, r := range records {
+		if r.Trace != id {
+			continue
+		}
+		if r.Issue > 0 {
+			return r.Issue, "", nil
+		}
+		if runID, ok := strings.CutPrefix(r.Key, "run_started:"); ok {
+			return 0, runID, nil
+		}
+	}
+
+	// Workflow run names carry the trace ID
+	runs, err := client.ListWorkflowRuns(traceFindRunWindow)
+	if err != nil {
+		return 0, "", err
+	}
+	for i := range runs {
+		if runs[i].TraceID() != id {
+			continue
+		}
+		if issue := runIssue(client, &runs[i]); issue > 0 {
+			return issue, "", nil
+		}
+	}
+
+	// So do coordination issues and the artifacts linked to them
+	items, err := client.SearchIssues(id)
+	if err != nil {
+		return 0, "", err
+	}
+	for _, item := range items {
+		link, ok := protocol.ParseLink(item.Body)
+		if !ok || link.Trace != id {
+			continue
+		}
+		if link.Kind == protocol.LinkCoordination {
+			return item.Number, "", nil
+		}
+		if link.Issue > 0 || link.RunID != "" {
+			return link.Issue, link.RunID, nil
+		}
+	}
+	return 0, "", nil
+}
+
+// traceNode is one object in the printed run graph
+type traceNode struct {
+	label    string
+	children []*traceNode
+}
+
+func (n *traceNode) add(label string) *traceNode {
+	child := &traceNode{label: label}
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *traceNode) print(indent string) {
+	for i, child := range n.children {
+		branch, next := treeBranch, treePipe
+		if i == len(n.children)-1 {
+			branch, next = treeLast, "   "
+		}
+		fmt.Printf("%s%s%s\n", indent, branch, child.label)
+		child.print(indent + next)
+	}
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	bold := color.New(color.Bold).SprintFunc()
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	cfg, err := config.Load(config.ConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	client := newClient(cfg)
+
+	store, err := coordinationStore(client, cfg, traceIssue, traceRun)
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		if cfg.Coordination.Dispatch() {
+			return fmt.Errorf("no runs found on %s", cfg.Coordination.Branch())
+		}
+		return fmt.Errorf("--issue is required")
+	}
+
+	// Reading the messages also fetches the issue graph in issue mode
+	snap, _, err := readCoordination(store)
+	if err != nil {
+		return err
+	}
+
+	// The root is the coordination issue or state branch run; artifacts
+	// linking back to it cannot be older
+	var (
+		root     *traceNode
+		graph    *github.IssueGraph
+		issue    int
+		runID    string
+		since    time.Time
+		startKey string
+		trace    string
+	)
+	switch s := store.(type) {
+	case *coordination.IssueStore:
+		var coord *github.Issue
+		if graph, err = s.Graph(); err == nil {
+			coord = &graph.Issue
+		} else if coord, err = client.GetIssue(s.Issue()); err != nil {
+			return err
+		}
+		issue, since = coord.Number, coord.CreatedAt
+		startKey = fmt.Sprintf("run_started:%d", issue)
+		root = &traceNode{label: fmt.Sprintf("%s #%d: %s (%s)", bold("Issue"), coord.Number, coord.Title, coord.State)}
+		if link, ok := protocol.ParseLink(coord.Body); ok {
+			trace = link.Trace
+		}
+	case *coordination.BranchStore:
+		record, err := s.Record()
+		if err != nil {
+			return err
+		}
+		runID, since, trace = s.RunID(), record.CreatedAt, record.Trace
+		startKey = "run_started:" + runID
+		root = &traceNode{label: fmt.Sprintf("%s %s: %s", bold("Run"), runID, record.Task)}
+	}
+	root.add(cyan(store.URL()))
+	if trace != "" {
+		root.add("Trace " + trace)
+	}
+
+	if source := traceSource(startKey); source != "" {
+		root.add("Ticket " + source)
+	}
+
+	run, err := storeWorkflowRun(client, store)
+	if err != nil {
+		return err
+	}
+	if run != nil {
+		label := fmt.Sprintf("Workflow run #%d%s: %s", run.ID, attemptSuffix(run.Attempt), run.Status)
+		if run.Conclusion != "" {
+			label += ", " + run.Conclusion
+		}
+		if base := run.BaseSHA(); base != "" {
+			label += " @ " + shortSHA(base)
+		}
+		node := root.add(label)
+		node.add(cyan(run.URL))
+		if env := run.Environment(); env != "" && run.BaseSHA() != "" {
+			if d, err := client.FindDeployment(run.BaseSHA(), env); err == nil && d != nil {
+				node.add(fmt.Sprintf("Deployment %d to %s: %s", d.ID, env, orNone(string(d.State))))
+			}
+		}
+	}
+
+	instanceNodes := make(map[int]*traceNode)
+	for _, id := range snap.InstanceIDs() {
+		label := fmt.Sprintf("Instance %d", id)
+		if st, ok := snap.Statuses[id]; ok {
+			label += " (" + st.Status + ")"
+		}
+		instanceNodes[id] = root.add(label + ": " + instanceBranch(cfg, snap, id))
+	}
+
+	// Artifacts' breadcrumbs cross-reference the coordination issue, so the
+	// issue graph holds them all; otherwise scan items created since the run
+	var items []github.Issue
+	checks := map[int]string{}
+	if graph != nil {
+		for _, ref := range graph.References {
+			items = append(items, ref.Issue)
+			checks[ref.Number] = ref.Checks
+		}
+	} else if items, err = client.ListCreatedSince(since); err != nil {
+		return err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Number < items[j].Number })
+	linked := 0
+	for _, item := range items {
+		link, ok := protocol.ParseLink(item.Body)
+		if !ok || !link.Of(issue, runID) || item.Number == issue {
+			continue
+		}
+		kind := "Issue"
+		if item.PullRequest {
+			kind = "PR"
+		}
+		parent := root
+		if node, ok := instanceNodes[link.Instance]; ok {
+			parent = node
+		}
+		state := item.State
+		if c := checks[item.Number]; c != "" {
+			state += ", checks " + c
+		}
+		parent.add(fmt.Sprintf("%s #%d [%s]: %s (%s)", kind, item.Number, link.Kind, item.Title, state)).add(cyan(item.URL))
+		linked++
+	}
+	summarize("linked", linked)
+
+	fmt.Println(root.label)
+	root.print("")
+	return nil
+}
+
+// traceSource returns the ticket a run was imported from, as recorded in the
+// local history when it was started
+func traceSource(startKey string) string {
+	records, err := history.Open(history.DefaultDir()).List()
+	if err != nil {
+		return ""
+	}
+	for _, r := range records {
+		if r.Key == startKey {
+			return r.Source
+		}
+	}
+	return ""
+}
+
+// orNone labels an empty state
+func orNone(state string) string {
+	if state == "" {
+		return "no status"
+	}
+	return state
+}
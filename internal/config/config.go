@@ -4,16 +4,54 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the autonomous-dev configuration
 type Config struct {
-	GitHub    GitHubConfig    `yaml:"github"`
-	Instances InstancesConfig `yaml:"instances"`
-	Agents    []Agent         `yaml:"agents"`
-	Workflow  WorkflowConfig  `yaml:"workflow"`
+	GitHub        GitHubConfig        `yaml:"github"`
+	Instances     InstancesConfig     `yaml:"instances"`
+	Agents        []Agent             `yaml:"agents"`
+	Workflow      WorkflowConfig      `yaml:"workflow"`
+	Display       DisplayConfig       `yaml:"display"`
+	Templates     TemplatesConfig     `yaml:"templates"`
+	Quotas        QuotasConfig        `yaml:"quotas"`
+	Untrusted     UntrustedConfig     `yaml:"untrusted"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Runners       RunnersConfig       `yaml:"runners"`
+	Coordination  CoordinationConfig  `yaml:"coordination"`
+	Telemetry     TelemetryConfig     `yaml:"telemetry"`
+	Context       ContextConfig       `yaml:"context"`
+	Trackers      TrackersConfig      `yaml:"trackers"`
+	Provider      ProviderConfig      `yaml:"provider"`
+	Branches      BranchesConfig      `yaml:"branches"`
+	PRs           PRsConfig           `yaml:"prs"`
+	Completion    CompletionConfig    `yaml:"completion"`
+	Capabilities  CapabilitiesConfig  `yaml:"capabilities"`
+	Planner       PlannerConfig       `yaml:"planner"`
+	Deployments   DeploymentsConfig   `yaml:"deployments"`
+	Canary        CanaryConfig        `yaml:"canary"`
+	Logs          LogsConfig          `yaml:"logs"`
+	Hooks         HooksConfig         `yaml:"hooks"`
+	Network       NetworkConfig       `yaml:"network"`
+	Audit         AuditConfig         `yaml:"audit"`
+	// Requirements are the secrets and variables the workflow needs
+	Requirements []Requirement `yaml:"requirements,omitempty"`
+	// Schedules are recurring tasks started on a cron schedule
+	Schedules []Schedule `yaml:"schedules,omitempty"`
+	// Permissions maps users and "@org/team" teams to the comment commands
+	// they may run when serve answers them; empty allows collaborators with
+	// write access to run every command
+	Permissions map[string][]string `yaml:"permissions,omitempty"`
+	// Paths scope instance work to repository-relative paths. Package
+	// configs default to their package directory.
+	Paths []string `yaml:"paths,omitempty"`
+	// Package is the repository-relative directory of a package config that
+	// inherits from a config above it; empty for the root config
+	Package string `yaml:"-"`
 }
 
 // GitHubConfig represents GitHub-related settings
@@ -21,6 +59,10 @@ type GitHubConfig struct {
 	Owner string `yaml:"owner"`
 	Repo  string `yaml:"repo"`
 	Token string `yaml:"token"`
+	// TokenExpiryWarnDays warns when the token expires within this many days
+	TokenExpiryWarnDays int `yaml:"token_expiry_warn_days"`
+	// CommitStatus posts run progress as a commit status on the base commit
+	CommitStatus bool `yaml:"commit_status"`
 }
 
 // InstancesConfig represents instance settings
@@ -33,6 +75,17 @@ type InstancesConfig struct {
 type Agent struct {
 	Name   string   `yaml:"name"`
 	Skills []string `yaml:"skills"`
+	// Inputs are default workflow_dispatch inputs sent when the agent is selected.
+	// Each must be declared by the workflow (see the "inputs" template block).
+	Inputs map[string]string `yaml:"inputs,omitempty"`
+	// Sections are appended to the issue body when the agent is selected
+	Sections []IssueSection `yaml:"sections,omitempty"`
+}
+
+// IssueSection is an extra section of the coordination issue body
+type IssueSection struct {
+	Title string `yaml:"title"`
+	Body  string `yaml:"body"`
 }
 
 // WorkflowConfig represents workflow settings
@@ -41,13 +94,620 @@ type WorkflowConfig struct {
 	Concurrency int    `yaml:"concurrency"`
 }
 
+// QuotasConfig represents run limits and scheduling windows
+type QuotasConfig struct {
+	// MaxRunsPerDay limits runs started per calendar day (0 = unlimited)
+	MaxRunsPerDay int `yaml:"max_runs_per_day"`
+	// MaxConcurrentRuns limits runs queued or in progress (0 = unlimited)
+	MaxConcurrentRuns int `yaml:"max_concurrent_runs"`
+	// Windows are allowed daily time ranges such as "22:00-06:00"
+	Windows []string `yaml:"windows"`
+	// OnLimit is "queue" (default) or "refuse"
+	OnLimit string `yaml:"on_limit"`
+}
+
+// Enabled reports whether any quota is configured
+func (q *QuotasConfig) Enabled() bool {
+	return q.MaxRunsPerDay > 0 || q.MaxConcurrentRuns > 0 || len(q.Windows) > 0
+}
+
+// Requirement kinds
+const (
+	RequirementSecret   = "secret"
+	RequirementVariable = "variable"
+)
+
+// Requirement is an Actions secret or variable the workflow passes to
+// instances as an environment variable of the same name
+type Requirement struct {
+	Name string `yaml:"name"`
+	// Kind is "secret" (default) or "variable"
+	Kind        string `yaml:"kind,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	// Optional requirements are reported but do not stop start
+	Optional bool `yaml:"optional,omitempty"`
+}
+
+// IsVariable reports whether the requirement is an Actions variable
+func (r *Requirement) IsVariable() bool {
+	return r.Kind == RequirementVariable
+}
+
+// Expression returns the workflow expression that reads the requirement
+func (r *Requirement) Expression() string {
+	if r.IsVariable() {
+		return "${{ vars." + r.Name + " }}"
+	}
+	return "${{ secrets." + r.Name + " }}"
+}
+
+// Schedule triggers
+const (
+	ScheduleServe    = "serve"
+	ScheduleWorkflow = "workflow"
+)
+
+// Schedule is a recurring task, such as a nightly dependency audit
+type Schedule struct {
+	Name string `yaml:"name"`
+	// Cron is a five-field cron expression evaluated in UTC
+	Cron      string `yaml:"cron"`
+	Task      string `yaml:"task"`
+	Instances int    `yaml:"instances,omitempty"`
+	Agent     string `yaml:"agent,omitempty"`
+	// Trigger is "serve" (default), dispatched by autonomous-dev serve, or
+	// "workflow", started by the scheduled trigger workflow
+	Trigger string `yaml:"trigger,omitempty"`
+}
+
+// ByWorkflow reports whether the schedule is started by the trigger workflow
+func (s *Schedule) ByWorkflow() bool {
+	return s.Trigger == ScheduleWorkflow
+}
+
+// UntrustedConfig represents fork-based mode for externally sourced tasks
+type UntrustedConfig struct {
+	// ForkOwner is the user or organization that owns the working fork
+	ForkOwner string `yaml:"fork_owner"`
+	// ForkOwnerIsOrg creates the fork in an organization instead of the token user
+	ForkOwnerIsOrg bool `yaml:"fork_owner_is_org"`
+	// TokenSecret names the Actions secret holding a token scoped to the fork only
+	TokenSecret string `yaml:"token_secret"`
+	// ReviewLabel is applied to fork PRs so maintainers review them
+	ReviewLabel string `yaml:"review_label"`
+}
+
+// Coordination modes
+const (
+	CoordinationIssues   = "issues"
+	CoordinationDispatch = "dispatch"
+)
+
+// CoordinationConfig represents how runs are triggered and coordinated
+type CoordinationConfig struct {
+	// Mode is "issues" (default) or "dispatch", which triggers runs with
+	// repository_dispatch and keeps coordination state on a branch
+	Mode string `yaml:"mode"`
+	// StateBranch holds run state in dispatch mode
+	StateBranch string `yaml:"state_branch"`
+	// Voters are the users whose reactions to decision comments count as
+	// votes; instances vote with DECISION_VOTE blocks. Empty counts no
+	// reactions.
+	Voters []string `yaml:"voters,omitempty"`
+}
+
+// Dispatch reports whether runs coordinate through the state branch instead of issues
+func (c *CoordinationConfig) Dispatch() bool {
+	return c.Mode == CoordinationDispatch
+}
+
+// Branch returns the state branch name
+func (c *CoordinationConfig) Branch() string {
+	if c.StateBranch == "" {
+		return "autonomous-dev-state"
+	}
+	return c.StateBranch
+}
+
+// CompletionConfig represents what happens to a coordination issue once
+// status --wait observes its run finish
+type CompletionConfig struct {
+	// CloseOnSuccess closes the issue when the run succeeds
+	CloseOnSuccess bool `yaml:"close_on_success"`
+	// SuccessLabel and FailureLabel are added to the issue by conclusion
+	SuccessLabel string `yaml:"success_label"`
+	FailureLabel string `yaml:"failure_label"`
+	// RequireResultSchema rejects results that do not declare a result schema
+	RequireResultSchema bool `yaml:"require_result_schema"`
+}
+
+// CapabilitiesConfig represents the thresholds below which an instance's
+// capability probe marks its runner as constrained
+type CapabilitiesConfig struct {
+	// MinMemoryMB and MinCPUs are the resources heavy tasks need
+	// (default 4096 and 2)
+	MinMemoryMB int `yaml:"min_memory_mb"`
+	MinCPUs     int `yaml:"min_cpus"`
+	// HeavyKeywords mark a task as heavy when its description mentions one
+	HeavyKeywords []string `yaml:"heavy_keywords"`
+}
+
+// MinMemory returns the memory heavy tasks need in MB
+func (c *CapabilitiesConfig) MinMemory() int {
+	if c.MinMemoryMB > 0 {
+		return c.MinMemoryMB
+	}
+	return 4096
+}
+
+// MinCPU returns the CPU count heavy tasks need
+func (c *CapabilitiesConfig) MinCPU() int {
+	if c.MinCPUs > 0 {
+		return c.MinCPUs
+	}
+	return 2
+}
+
+// Keywords returns the words that mark a task as heavy
+func (c *CapabilitiesConfig) Keywords() []string {
+	if len(c.HeavyKeywords) > 0 {
+		return c.HeavyKeywords
+	}
+	return []string{"build", "compile", "e2e", "integration", "docker", "benchmark", "migration"}
+}
+
+// Task complexity analyzers
+const (
+	AnalyzerHeuristic = "heuristic"
+	AnalyzerLLM       = "llm"
+)
+
+// DefaultPlannerModel is the model the llm analyzer uses unless planner.model is set
+const DefaultPlannerModel = "claude-3-5-haiku-latest"
+
+// PlannerConfig represents how task complexity is estimated for estimate,
+// start --auto-size and subtask weighting
+type PlannerConfig struct {
+	// Analyzer is heuristic (default) or llm
+	Analyzer string `yaml:"analyzer"`
+	// Model is the model of the llm analyzer
+	Model string `yaml:"model"`
+	// APIKey authenticates the llm analyzer; ${VAR} references are expanded
+	APIKey string `yaml:"api_key"`
+	// CacheTTLHours is how long llm replies are reused for identical
+	// requests (default 24, negative disables the cache)
+	CacheTTLHours int `yaml:"cache_ttl_hours"`
+}
+
+// CacheTTL returns how long llm replies are cached, or 0 if caching is disabled
+func (p *PlannerConfig) CacheTTL() time.Duration {
+	switch {
+	case p.CacheTTLHours < 0:
+		return 0
+	case p.CacheTTLHours == 0:
+		return 24 * time.Hour
+	}
+	return time.Duration(p.CacheTTLHours) * time.Hour
+}
+
+// LLMModel returns the model of the llm analyzer
+func (p *PlannerConfig) LLMModel() string {
+	if p.Model != "" {
+		return p.Model
+	}
+	return DefaultPlannerModel
+}
+
+// DefaultBranchPrefix prefixes instance branches unless branches.prefix is set
+const DefaultBranchPrefix = "autonomous/"
+
+// BranchesConfig represents instance branch naming and merging
+type BranchesConfig struct {
+	// Prefix is prepended to instance branch names, e.g. "autonomous/"
+	Prefix string `yaml:"prefix"`
+	// AutoMerge lets instance pull requests be auto-merged by GitHub Actions
+	AutoMerge bool `yaml:"auto_merge"`
+}
+
+// PRsConfig represents how instances open pull requests
+type PRsConfig struct {
+	// Draft opens instance pull requests as drafts, marked ready for review
+	// with 'autonomous-dev prs ready' once the run's gates pass
+	Draft bool `yaml:"draft"`
+}
+
+// InstancePrefix returns the instance branch prefix
+func (b *BranchesConfig) InstancePrefix() string {
+	if b.Prefix == "" {
+		return DefaultBranchPrefix
+	}
+	return b.Prefix
+}
+
+// InstanceBranch returns the branch instance n pushes its work to
+func (b *BranchesConfig) InstanceBranch(n int) string {
+	return fmt.Sprintf("%sinstance-%d", b.InstancePrefix(), n)
+}
+
+// RefPattern returns the ruleset ref pattern matching all instance branches
+func (b *BranchesConfig) RefPattern() string {
+	return "refs/heads/" + b.InstancePrefix() + "**"
+}
+
+// DeploymentsConfig represents GitHub Deployments created for runs that
+// affect an environment
+type DeploymentsConfig struct {
+	// Environment is the environment deployable runs target; empty disables
+	// deployments unless start --environment is given
+	Environment string `yaml:"environment"`
+	// Keywords mark a task as deployable when its description mentions one
+	Keywords []string `yaml:"keywords"`
+}
+
+// CanaryConfig represents the quality gates a start --canary trial must
+// meet before the remaining instances are dispatched
+type CanaryConfig struct {
+	// MinCoverage is the coverage in percent the canary's results must
+	// report; 0 does not require coverage
+	MinCoverage float64 `yaml:"min_coverage"`
+}
+
+// Instance log formats
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+// LogsConfig represents the verbosity and format of instance logs
+type LogsConfig struct {
+	// Level is the least severe level instances log: debug, info (default),
+	// warn or error
+	Level string `yaml:"level"`
+	// Format is json (default), one object per line, or text
+	Format string `yaml:"format"`
+	// Status configures the console preview in status reports
+	Status StatusLogsConfig `yaml:"status"`
+}
+
+// Console preview truncation strategies
+const (
+	PreviewTail     = "tail"
+	PreviewHeadTail = "head_tail"
+	PreviewErrors   = "errors"
+)
+
+// Console preview inclusion policies
+const (
+	PreviewAlways   = "always"
+	PreviewFailures = "failures"
+	PreviewNever    = "never"
+)
+
+// StatusLogsConfig represents how much instance output status reports carry.
+// Zero values use the defaults.
+type StatusLogsConfig struct {
+	// Tail is how many log lines each status report is given (default 10)
+	Tail int `yaml:"tail,omitempty"`
+	// Lines is how many of them the preview keeps (default 3)
+	Lines int `yaml:"lines,omitempty"`
+	// MaxBytes caps the size of the preview (default 2048)
+	MaxBytes int `yaml:"max_bytes,omitempty"`
+	// Include is when reports carry a preview: always (default), failures
+	// (only failed instances) or never
+	Include string `yaml:"include,omitempty"`
+	// Truncate is how the output is cut down to Lines: tail (default, the
+	// last lines), head_tail (first and last lines) or errors (lines around
+	// errors, numbered, or the tail without errors)
+	Truncate string `yaml:"truncate,omitempty"`
+}
+
+// TailLines returns how many log lines each status report is given
+func (s *StatusLogsConfig) TailLines() int {
+	if s.Tail <= 0 {
+		return 10
+	}
+	return s.Tail
+}
+
+// PreviewLines returns how many lines the preview keeps
+func (s *StatusLogsConfig) PreviewLines() int {
+	if s.Lines <= 0 {
+		return 3
+	}
+	return s.Lines
+}
+
+// PreviewMaxBytes returns the size cap of the preview
+func (s *StatusLogsConfig) PreviewMaxBytes() int {
+	if s.MaxBytes <= 0 {
+		return 2048
+	}
+	return s.MaxBytes
+}
+
+// IncludePolicy returns when reports carry a preview
+func (s *StatusLogsConfig) IncludePolicy() string {
+	if s.Include == "" {
+		return PreviewAlways
+	}
+	return s.Include
+}
+
+// Truncation returns how the output is cut down to the preview
+func (s *StatusLogsConfig) Truncation() string {
+	if s.Truncate == "" {
+		return PreviewTail
+	}
+	return s.Truncate
+}
+
+// InstanceLevel returns the level instances log at
+func (l *LogsConfig) InstanceLevel() string {
+	if l.Level == "" {
+		return "info"
+	}
+	return l.Level
+}
+
+// InstanceFormat returns the format of instance log lines
+func (l *LogsConfig) InstanceFormat() string {
+	if l.Format == "" {
+		return LogFormatJSON
+	}
+	return l.Format
+}
+
+// HooksConfig represents local commands run around the run lifecycle. Each
+// is run through the shell with the run's metadata as JSON on stdin and as
+// AUTONOMOUS_DEV_* environment variables.
+type HooksConfig struct {
+	// PreStart runs before a run is dispatched; a failing hook stops the start
+	PreStart string `yaml:"pre_start,omitempty"`
+	// PostComplete runs once when a workflow run is observed completed
+	PostComplete string `yaml:"post_complete,omitempty"`
+}
+
+// Secret audit modes
+const (
+	AuditBlock = "block"
+	AuditWarn  = "warn"
+	AuditOff   = "off"
+)
+
+// AuditConfig represents the checks run on each instance after its work
+type AuditConfig struct {
+	// Secrets scans the instance log and changes for leaked secrets and
+	// redacts them from the archived log: block (default) fails the instance
+	// on findings, warn only reports them and off skips the scan
+	Secrets string `yaml:"secrets,omitempty"`
+}
+
+// SecretsMode returns the mode of the secret audit
+func (a *AuditConfig) SecretsMode() string {
+	if a.Secrets == "" {
+		return AuditBlock
+	}
+	return a.Secrets
+}
+
+// NetworkConfig represents how the CLI talks to the GitHub API
+type NetworkConfig struct {
+	Timeouts TimeoutsConfig `yaml:"timeouts"`
+	Polling  PollingConfig  `yaml:"polling"`
+}
+
+// PollingConfig represents how status --wait, logs --follow and serve adapt
+// their --interval: it is used while a run is active and changing, grows
+// while it is quiet and reaches Idle while nothing runs
+type PollingConfig struct {
+	// Idle is the interval while no run is active, and the cap of backoff
+	// after errors, as a duration such as "5m"
+	Idle string `yaml:"idle,omitempty"`
+	// Jitter is the share of each interval randomized either way, 0 to 1
+	Jitter *float64 `yaml:"jitter,omitempty"`
+	// Fixed polls at exactly --interval
+	Fixed bool `yaml:"fixed,omitempty"`
+}
+
+// Default polling settings
+const (
+	DefaultPollIdle   = 5 * time.Minute
+	DefaultPollJitter = 0.2
+)
+
+// IdleInterval returns the polling interval while no run is active
+func (p *PollingConfig) IdleInterval() time.Duration {
+	return timeoutOr(p.Idle, DefaultPollIdle)
+}
+
+// JitterShare returns the share of each polling interval randomized
+func (p *PollingConfig) JitterShare() float64 {
+	if p.Jitter == nil {
+		return DefaultPollJitter
+	}
+	return min(max(*p.Jitter, 0), 1)
+}
+
+// TimeoutsConfig represents deadlines of GitHub API calls by kind, as
+// durations such as "30s" or "2m". Empty uses the default.
+type TimeoutsConfig struct {
+	// Read bounds fetching one object, e.g. an issue or a workflow run
+	Read string `yaml:"read,omitempty"`
+	// List bounds listing and searching, across all pages
+	List string `yaml:"list,omitempty"`
+	// Dispatch bounds creating and changing objects, e.g. starting a run
+	Dispatch string `yaml:"dispatch,omitempty"`
+	// Logs bounds downloading workflow and job logs
+	Logs string `yaml:"logs,omitempty"`
+}
+
+// Default GitHub API timeouts
+const (
+	DefaultReadTimeout     = 30 * time.Second
+	DefaultListTimeout     = time.Minute
+	DefaultDispatchTimeout = 30 * time.Second
+	DefaultLogsTimeout     = 5 * time.Minute
+)
+
+// ParseTimeout parses a timeout value; it must be a positive duration
+func ParseTimeout(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be a positive duration such as 30s or 2m", value)
+	}
+	return d, nil
+}
+
+// ReadTimeout returns the timeout of reading one object
+func (t *TimeoutsConfig) ReadTimeout() time.Duration {
+	return timeoutOr(t.Read, DefaultReadTimeout)
+}
+
+// ListTimeout returns the timeout of listing and searching
+func (t *TimeoutsConfig) ListTimeout() time.Duration {
+	return timeoutOr(t.List, DefaultListTimeout)
+}
+
+// DispatchTimeout returns the timeout of creating and changing objects
+func (t *TimeoutsConfig) DispatchTimeout() time.Duration {
+	return timeoutOr(t.Dispatch, DefaultDispatchTimeout)
+}
+
+// LogsTimeout returns the timeout of downloading logs
+func (t *TimeoutsConfig) LogsTimeout() time.Duration {
+	return timeoutOr(t.Logs, DefaultLogsTimeout)
+}
+
+// timeoutOr parses value, falling back to def when it is empty or invalid
+func timeoutOr(value string, def time.Duration) time.Duration {
+	if value == "" {
+		return def
+	}
+	d, err := ParseTimeout(value)
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+// ProviderConfig represents limits passed to the AI provider of each instance.
+// Zero leaves a limit to the provider's default.
+type ProviderConfig struct {
+	// MaxTurns caps the agent turns an instance may take
+	MaxTurns int `yaml:"max_turns"`
+	// MaxTokensPerInstance caps the tokens an instance may consume
+	MaxTokensPerInstance int `yaml:"max_tokens_per_instance"`
+}
+
+// TelemetryConfig represents opt-in anonymous usage statistics
+type TelemetryConfig struct {
+	// Enabled sends command name, duration and error class after each command
+	Enabled bool `yaml:"enabled"`
+	// Endpoint receives usage events as JSON POST requests
+	Endpoint string `yaml:"endpoint"`
+}
+
+// RunnersConfig represents self-hosted runner settings
+type RunnersConfig struct {
+	// Labels are added to self-hosted runners on setup and targeted by the
+	// workflow (runs-on: [self-hosted, ...labels]). Empty uses ubuntu-latest.
+	Labels []string `yaml:"labels"`
+}
+
+// NotificationsConfig represents where run lifecycle notifications are sent
+type NotificationsConfig struct {
+	// WebhookURL receives Slack-compatible messages when instances or runs fail
+	WebhookURL string `yaml:"webhook_url"`
+	// Statuspage mirrors pipeline health on a status page component
+	Statuspage StatuspageConfig `yaml:"statuspage"`
+}
+
+// StatuspageConfig represents a status page component updated from recent
+// run success rates
+type StatuspageConfig struct {
+	// PageID and ComponentID identify a statuspage.io component
+	PageID      string `yaml:"page_id"`
+	ComponentID string `yaml:"component_id"`
+	// APIKey authenticates with statuspage.io; ${VAR} references are expanded
+	APIKey string `yaml:"api_key"`
+	// URL receives {"status": ..., "success_rate": ...} for a custom status page
+	URL string `yaml:"url"`
+	// Window is the number of recent runs the success rate covers (default 10)
+	Window int `yaml:"window"`
+	// DegradedBelow and OutageBelow are success rate thresholds in percent
+	// (default 80 and 50)
+	DegradedBelow int `yaml:"degraded_below"`
+	OutageBelow   int `yaml:"outage_below"`
+}
+
+// Enabled reports whether a status page is configured
+func (s *StatuspageConfig) Enabled() bool {
+	return s.URL != "" || (s.PageID != "" && s.ComponentID != "")
+}
+
+// ContextConfig represents the context bundle built from repository files
+type ContextConfig struct {
+	// Sources are files, directories or glob patterns included in the bundle;
+	// empty disables the bundle
+	Sources []string `yaml:"sources"`
+	// MaxFileBytes truncates large files in the bundle (default 32768)
+	MaxFileBytes int `yaml:"max_file_bytes"`
+}
+
+// TrackersConfig represents external issue trackers tasks are imported from
+// with start --from. Secret values may be ${VAR} references.
+type TrackersConfig struct {
+	Jira   JiraConfig   `yaml:"jira"`
+	Linear LinearConfig `yaml:"linear"`
+}
+
+// JiraConfig represents a Jira Cloud or Server site
+type JiraConfig struct {
+	// URL is the site base URL, e.g. https://example.atlassian.net
+	URL   string `yaml:"url"`
+	Email string `yaml:"email"`
+	// Token is an API token used with Email for basic authentication
+	Token string `yaml:"token"`
+}
+
+// LinearConfig represents a Linear workspace
+type LinearConfig struct {
+	APIKey string `yaml:"api_key"`
+}
+
+// TemplatesConfig represents template override settings
+type TemplatesConfig struct {
+	// OrgURL is a base URL serving organization-wide overrides as <name>.tmpl
+	OrgURL string `yaml:"org_url"`
+}
+
+// DisplayConfig represents output settings
+type DisplayConfig struct {
+	// Timezone is an IANA name such as "Asia/Tokyo"; empty uses the local zone
+	Timezone string `yaml:"timezone"`
+}
+
+// Location returns the configured display time zone
+func (d *DisplayConfig) Location() (*time.Location, error) {
+	if d.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(d.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid display.timezone %q: %w", d.Timezone, err)
+	}
+	return loc, nil
+}
+
 // DefaultConfig returns a default configuration
 func DefaultConfig() *Config {
 	return &Config{
 		GitHub: GitHubConfig{
-			Owner: "",
-			Repo:  "",
-			Token: "${GITHUB_TOKEN}",
+			Owner:               "",
+			Repo:                "",
+			Token:               "${GITHUB_TOKEN}",
+			TokenExpiryWarnDays: 7,
+			CommitStatus:        true,
 		},
 		Instances: InstancesConfig{
 			Default: 5,
@@ -65,25 +725,38 @@ func DefaultConfig() *Config {
 			{
 				Name:   "test-specialist",
 				Skills: []string{"testing", "e2e", "unit-test"},
+				Sections: []IssueSection{
+					{
+						Title: "Testing requirements",
+						Body:  "- Cover new behavior with tests\n- Run the full test suite before reporting completion",
+					},
+				},
 			},
 		},
 		Workflow: WorkflowConfig{
 			File:        ".github/workflows/autonomous-dev.yml",
 			Concurrency: 5,
 		},
+		PRs: PRsConfig{
+			Draft: true,
+		},
+		Untrusted: UntrustedConfig{
+			TokenSecret: "FORK_TOKEN",
+			ReviewLabel: "needs-maintainer-review",
+		},
+		Requirements: []Requirement{
+			{Name: "ANTHROPIC_API_KEY", Description: "API key of the AI provider"},
+		},
 	}
 }
 
-// Load loads configuration from file
+// Load loads configuration from file.
+// Package configs (e.g. apps/web/.autonomous-dev/config.yaml) inherit every
+// setting they do not override from the nearest config in a parent directory.
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	cfg, err := LoadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
-	}
-
-	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+		return nil, err
 	}
 
 	// Expand environment variables in token
@@ -92,16 +765,64 @@ func Load(path string) (*Config, error) {
 		cfg.GitHub.Token = os.Getenv(envVar)
 	}
 
+	return cfg, nil
+}
+
+// LoadFile loads configuration like Load but without expanding secrets, for
+// editing and saving it back
+func LoadFile(path string) (*Config, error) {
+	var cfg Config
+
+	if parent := parentConfig(path); parent != "" {
+		base, err := LoadFile(parent)
+		if err != nil {
+			return nil, err
+		}
+		cfg = *base
+		cfg.Paths = nil
+		cfg.Package = packageDir(path, parent)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	warning, err := decodeStrict(data, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if warning != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", path, warning)
+	}
+	if cfg.Package != "" && len(cfg.Paths) == 0 {
+		cfg.Paths = []string{cfg.Package}
+	}
+
 	return &cfg, nil
 }
 
-// Save saves configuration to file
+// Save saves configuration to file. Package configs only keep the settings
+// that differ from the config they inherit from.
 func (c *Config) Save(path string) error {
 	data, err := yaml.Marshal(c)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
+	if parent := parentConfig(path); parent != "" {
+		base, err := LoadFile(parent)
+		if err != nil {
+			return err
+		}
+		own := *c
+		if len(own.Paths) == 1 && own.Paths[0] == own.Package {
+			own.Paths = nil
+		}
+		if data, err = overrides(&own, base); err != nil {
+			return err
+		}
+	}
+
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -115,13 +836,148 @@ func (c *Config) Save(path string) error {
 	return nil
 }
 
-// ConfigPath returns the path to the config file
-func ConfigPath() string {
+// overrides marshals the settings of c that differ from base
+func overrides(c, base *Config) ([]byte, error) {
+	toMap := func(cfg *Config) (map[string]interface{}, error) {
+		data, err := yaml.Marshal(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		m := map[string]interface{}{}
+		return m, yaml.Unmarshal(data, &m)
+	}
+	own, err := toMap(c)
+	if err != nil {
+		return nil, err
+	}
+	inherited, err := toMap(base)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffMaps(own, inherited)
+	if len(diff) == 0 {
+		return []byte("# All settings are inherited\n"), nil
+	}
+	return yaml.Marshal(diff)
+}
+
+// diffMaps returns the entries of m that differ from base, recursing into maps
+func diffMaps(m, base map[string]interface{}) map[string]interface{} {
+	diff := map[string]interface{}{}
+	for key, value := range m {
+		if sub, ok := value.(map[string]interface{}); ok {
+			if baseSub, ok := base[key].(map[string]interface{}); ok {
+				if d := diffMaps(sub, baseSub); len(d) > 0 {
+					diff[key] = d
+				}
+				continue
+			}
+		}
+		if !reflect.DeepEqual(value, base[key]) {
+			diff[key] = value
+		}
+	}
+	return diff
+}
+
+// packageDir returns the repository-relative directory of a package config
+func packageDir(path, parent string) string {
+	root := repoRoot(filepath.Dir(parent))
+	abs, err := filepath.Abs(filepath.Dir(filepath.Dir(path)))
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// LocalPath returns the config path in the current directory, where init creates it
+func LocalPath() string {
 	return filepath.Join(".autonomous-dev", "config.yaml")
 }
 
-// Exists checks if config file exists
+// ConfigPath returns the path to the nearest config file: in the current
+// directory or the closest parent up to the repository root. It returns
+// LocalPath when there is none.
+func ConfigPath() string {
+	if _, err := os.Stat(LocalPath()); err == nil {
+		return LocalPath()
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return LocalPath()
+	}
+	root := repoRoot(cwd)
+	if cwd == root {
+		return LocalPath()
+	}
+	if path := findConfig(filepath.Dir(cwd), root); path != "" {
+		return path
+	}
+	return LocalPath()
+}
+
+// Exists checks if a config file applies to the current directory
 func Exists() bool {
 	_, err := os.Stat(ConfigPath())
 	return err == nil
 }
+
+// parentConfig returns the nearest config above the one at path, up to the
+// repository root, or "" if path is the root config
+func parentConfig(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+	// path is <dir>/.autonomous-dev/config.yaml
+	dir := filepath.Dir(filepath.Dir(abs))
+	root := repoRoot(dir)
+	if dir == root {
+		return ""
+	}
+	return findConfig(filepath.Dir(dir), root)
+}
+
+// findConfig walks up from dir to stop (inclusive) and returns the first config found
+func findConfig(dir, stop string) string {
+	for {
+		path := filepath.Join(dir, ".autonomous-dev", "config.yaml")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if dir == stop || parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// repoRoot returns the closest directory at or above dir containing .git,
+// or the filesystem root if there is none
+func repoRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+// TokenExpiryWarning returns how long before token expiry warnings are shown
+func (g *GitHubConfig) TokenExpiryWarning() time.Duration {
+	days := g.TokenExpiryWarnDays
+	if days <= 0 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lenient ignores unknown config keys instead of failing, for configs
+// written for a newer version. Set by the global --lenient flag.
+var Lenient bool
+
+// UnknownKey is a config key that matches no setting
+type UnknownKey struct {
+	// Path is the dotted key, e.g. "instances.defualt"
+	Path string
+	Line int
+	// Suggestion is the closest known key at the same level, if any
+	Suggestion string
+}
+
+// UnknownKeysError lists the unknown keys of a config file
+type UnknownKeysError struct {
+	Keys []UnknownKey
+}
+
+func (e *UnknownKeysError) Error() string {
+	var b strings.Builder
+	b.WriteString("unknown keys:")
+	for _, k := range e.Keys {
+		fmt.Fprintf(&b, "\n  line %d: %s", k.Line, k.Path)
+		if k.Suggestion != "" {
+			fmt.Fprintf(&b, " (did you mean %s?)", k.Suggestion)
+		}
+	}
+	return b.String()
+}
+
+// decodeStrict decodes config YAML into cfg, rejecting unknown keys unless
+// Lenient is set, in which case they are reported as a warning
+func decodeStrict(data []byte, cfg *Config) (warning error, err error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) > 0 {
+		if keys := unknownKeys(root.Content[0], reflect.TypeOf(cfg).Elem(), ""); len(keys) > 0 {
+			unknown := &UnknownKeysError{Keys: keys}
+			if !Lenient {
+				return nil, fmt.Errorf("%w\nFix the keys, or use --lenient to ignore them", unknown)
+			}
+			warning = unknown
+		}
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(!Lenient)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return warning, nil
+}
+
+// unknownKeys walks a YAML node alongside the type it decodes into and
+// returns the mapping keys that match no field
+func unknownKeys(node *yaml.Node, t reflect.Type, prefix string) []UnknownKey {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var keys []UnknownKey
+	switch {
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Struct:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			path := prefix + key.Value
+			field, ok := fields[key.Value]
+			if !ok {
+				keys = append(keys, UnknownKey{Path: path, Line: key.Line, Suggestion: suggestKey(key.Value, fields, prefix)})
+				continue
+			}
+			keys = append(keys, unknownKeys(value, field, path+".")...)
+		}
+	case node.Kind == yaml.MappingNode && t.Kind() == reflect.Map:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keys = append(keys, unknownKeys(node.Content[i+1], t.Elem(), prefix+node.Content[i].Value+".")...)
+		}
+	case node.Kind == yaml.SequenceNode && t.Kind() == reflect.Slice:
+		for i, item := range node.Content {
+			keys = append(keys, unknownKeys(item, t.Elem(), fmt.Sprintf("%s[%d].", strings.TrimSuffix(prefix, "."), i))...)
+		}
+	}
+	return keys
+}
+
+// yamlFields maps the YAML keys of a struct to their field types
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// suggestKey returns the known key closest to an unknown one, if any is
+// close enough to be a likely typo
+func suggestKey(key string, fields map[string]reflect.Type, prefix string) string {
+	best, bestDist := "", max(2, len(key)/3)+1
+	for name := range fields {
+		d := editDistance(key, name)
+		if d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return prefix + best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
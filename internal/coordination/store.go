@@ -0,0 +1,239 @@
+package coordination
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/chunk"
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+)
+
+// Store holds the coordination messages of a run
+type Store interface {
+	// Location is a human-readable reference such as "issue #42"
+	Location() string
+	// URL links to the coordination messages in the browser
+	URL() string
+	// Bodies returns message bodies in chronological order, with chunked
+	// messages reassembled
+	Bodies() ([]string, error)
+	// Post appends a message body, splitting it if the store limits size
+	Post(body string) error
+}
+
+// IssueStore keeps messages as comments on a coordination issue
+type IssueStore struct {
+	client *github.Client
+	issue  int
+	url    string
+	// graph is the issue as last read with GraphQL
+	graph *github.IssueGraph
+}
+
+// NewIssueStore returns a store backed by issue comments
+func NewIssueStore(client *github.Client, owner, repo string, issue int) *IssueStore {
+	return &IssueStore{
+		client: client,
+		issue:  issue,
+		url:    fmt.Sprintf("https://github.com/%s/%s/issues/%d", owner, repo, issue),
+	}
+}
+
+// Issue returns the coordination issue number
+func (s *IssueStore) Issue() int { return s.issue }
+
+// Location implements Store
+func (s *IssueStore) Location() string { return fmt.Sprintf("issue #%d", s.issue) }
+
+// URL implements Store
+func (s *IssueStore) URL() string { return s.url }
+
+// Graph returns the issue with its comments and the issues and pull
+// requests referencing it, as read by the last Bodies call or with a new
+// GraphQL query
+func (s *IssueStore) Graph() (*github.IssueGraph, error) {
+	if s.graph == nil {
+		graph, err := s.client.GetIssueGraph(s.issue)
+		if err != nil {
+			return nil, err
+		}
+		s.graph = graph
+	}
+	return s.graph, nil
+}
+
+// Bodies implements Store. Comments are read with GraphQL, falling back to
+// REST when GraphQL is unavailable (e.g. without a token). Only the token's
+// user and the run's instances may steer the run; the assignments, replans
+// and pause or resume messages of other commenters are dropped.
+func (s *IssueStore) Bodies() ([]string, error) {
+	var comments []github.Comment
+	graph, err := s.client.GetIssueGraph(s.issue)
+	if err == nil {
+		s.graph = graph
+		comments = graph.Comments
+	} else if comments, err = s.client.ListIssueComments(s.issue); err != nil {
+		return nil, fmt.Errorf("failed to read coordination issue: %w", err)
+	}
+
+	// Without a user to trust, e.g. with no token, only instances steer
+	login, _ := s.client.Login()
+	authored := make([]protocol.Comment, 0, len(comments))
+	for _, comment := range comments {
+		authored = append(authored, protocol.Comment{Author: comment.Author, Body: comment.Body})
+	}
+	return protocol.TrustedBodies(authored, login), nil
+}
+
+// Post implements Store. Bodies over the comment size limit are posted as
+// several marked comments.
+func (s *IssueStore) Post(body string) error {
+	for _, part := range chunk.Split(body, chunk.MaxBody) {
+		if err := s.client.CreateComment(s.issue, part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run is the record of a run stored on the state branch
+type Run struct {
+	ID        string    `json:"id"`
+	Task      string    `json:"task"`
+	Instances int       `json:"instances"`
+	CreatedAt time.Time `json:"created_at"`
+	// BaseSHA is the commit every instance checks out
+	BaseSHA string `json:"base_sha,omitempty"`
+	// Trace is the run's trace ID
+	Trace string `json:"trace,omitempty"`
+}
+
+// BranchStore keeps messages as files under runs/<id>/messages/ on a state
+// branch, for repositories where bot-created issues are not allowed
+type BranchStore struct {
+	client *github.Client
+	branch string
+	runID  string
+	url    string
+}
+
+// NewBranchStore returns a store for a run on the state branch
+func NewBranchStore(client *github.Client, owner, repo, branch, runID string) *BranchStore {
+	return &BranchStore{
+		client: client,
+		branch: branch,
+		runID:  runID,
+		url:    fmt.Sprintf("https://github.com/%s/%s/tree/%s/%s", owner, repo, branch, RunDir(runID)),
+	}
+}
+
+// RunID returns the state branch run ID
+func (s *BranchStore) RunID() string { return s.runID }
+
+// Record reads the run record written by StartRun
+func (s *BranchStore) Record() (*Run, error) {
+	data, err := s.client.GetFile(s.branch, path.Join(RunDir(s.runID), "run.json"))
+	if err != nil {
+		return nil, err
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse run record %s: %w", s.runID, err)
+	}
+	return &run, nil
+}
+
+// Branch returns the state branch
+func (s *BranchStore) Branch() string { return s.branch }
+
+// Location implements Store
+func (s *BranchStore) Location() string { return fmt.Sprintf("run %s on %s", s.runID, s.branch) }
+
+// URL implements Store
+func (s *BranchStore) URL() string { return s.url }
+
+// Bodies implements Store. Message file names start with a UTC timestamp,
+// so name order is chronological.
+func (s *BranchStore) Bodies() ([]string, error) {
+	dir := path.Join(RunDir(s.runID), "messages")
+	names, err := s.client.ListDirectory(s.branch, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	bodies := make([]string, 0, len(names))
+	for _, name := range names {
+		content, err := s.client.GetFile(s.branch, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, string(content))
+	}
+	return chunk.Join(bodies), nil
+}
+
+// Post implements Store
+func (s *BranchStore) Post(body string) error {
+	name := MessageName(time.Now(), "cli")
+	file := path.Join(RunDir(s.runID), "messages", name)
+	_, err := s.client.CommitFiles(s.branch, "Post message to run "+s.runID, map[string][]byte{file: []byte(body)})
+	return err
+}
+
+// RunDir is the state branch directory of a run
+func RunDir(runID string) string {
+	return path.Join("runs", runID)
+}
+
+// MessageName is the file name of a message posted at t by author
+func MessageName(t time.Time, author string) string {
+	return t.UTC().Format("20060102T150405.000000000Z") + "-" + author + ".md"
+}
+
+// NewRunID returns an identifier for a new run, e.g.
+// "20240102T150405.123Z-3f9a"; IDs sort chronologically, and the random
+// suffix keeps runs started in the same millisecond apart
+func NewRunID(t time.Time) string {
+	b := make([]byte, 2)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return t.UTC().Format("20060102T150405.000Z") + "-" + hex.EncodeToString(b)
+}
+
+// StartRun records a run on the state branch, creating the branch if needed
+func StartRun(client *github.Client, branch string, run Run, taskBody string) error {
+	record, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal run record: %w", err)
+	}
+
+	files := map[string][]byte{
+		path.Join(RunDir(run.ID), "run.json"): record,
+		path.Join(RunDir(run.ID), "task.md"):  []byte(taskBody),
+	}
+	if _, err := client.CommitFiles(branch, "Start run "+run.ID, files); err != nil {
+		return fmt.Errorf("failed to record run on %s: %w", branch, err)
+	}
+	return nil
+}
+
+// LatestRunID returns the most recent run on the state branch, or "" if none
+func LatestRunID(client *github.Client, branch string) (string, error) {
+	names, err := client.ListDirectory(branch, "runs")
+	if err != nil {
+		return "", err
+	}
+	for i := len(names) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(names[i], ".") {
+			return names[i], nil
+		}
+	}
+	return "", nil
+}
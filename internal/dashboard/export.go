@@ -0,0 +1,181 @@
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/health"
+	"github.com/autonomous-dev/cli/internal/queue"
+)
+
+//go:embed site
+var site embed.FS
+
+// DataFile is the path of the exported run data within the site
+const DataFile = "data/runs.json"
+
+// Export is the run data published alongside the static dashboard.
+// Timestamps are always RFC3339 in UTC; the page renders them locally.
+type Export struct {
+	Repository  string      `json:"repository"`
+	GeneratedAt string      `json:"generated_at"`
+	Runs        []RunExport `json:"runs"`
+	// Queue lists runs waiting for quotas, in dispatch order
+	Queue []QueueExport `json:"queue,omitempty"`
+}
+
+// QueueExport is a run waiting in the local queue
+type QueueExport struct {
+	ID        string `json:"id"`
+	Task      string `json:"task"`
+	Instances int    `json:"instances"`
+	Priority  string `json:"priority"`
+	Reason    string `json:"reason,omitempty"`
+	QueuedAt  string `json:"queued_at"`
+}
+
+// ExportQueue converts queue entries, already in dispatch order
+func ExportQueue(entries []*queue.Entry) []QueueExport {
+	exports := make([]QueueExport, 0, len(entries))
+	for _, e := range entries {
+		exports = append(exports, QueueExport{
+			ID:        e.ID,
+			Task:      e.Task,
+			Instances: e.Instances,
+			Priority:  e.PriorityOf(),
+			Reason:    e.Reason,
+			QueuedAt:  e.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return exports
+}
+
+// RunExport is a single workflow run in the export
+type RunExport struct {
+	ID         int64       `json:"id"`
+	Status     string      `json:"status"`
+	Conclusion string      `json:"conclusion"`
+	URL        string      `json:"url"`
+	Attempt    int         `json:"attempt"`
+	Issue      int         `json:"issue,omitempty"`
+	CreatedAt  string      `json:"created_at"`
+	Jobs       []JobExport `json:"jobs"`
+}
+
+// JobExport is a single instance job in the export
+type JobExport struct {
+	Name       string        `json:"name"`
+	Status     string        `json:"status"`
+	Conclusion string        `json:"conclusion"`
+	URL        string        `json:"url"`
+	Health     *HealthExport `json:"health,omitempty"`
+}
+
+// HealthExport is the health score of an instance job
+type HealthExport struct {
+	Score   int      `json:"score"`
+	Level   string   `json:"level"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Build collects the most recent runs and their jobs from GitHub
+func Build(client *github.Client, repository string, limit int) (*Export, error) {
+	runs, err := client.ListWorkflowRuns(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &Export{
+		Repository:  repository,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Runs:        make([]RunExport, 0, len(runs)),
+	}
+
+	now := time.Now()
+	for i, run := range runs {
+		jobs, err := client.GetWorkflowJobs(run.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		issue := run.Issue()
+		if issue == 0 {
+			issue, _ = client.GetRunIssue(run.ID)
+		}
+
+		runExport := RunExport{
+			ID:         run.ID,
+			Status:     run.Status,
+			Conclusion: run.Conclusion,
+			URL:        run.URL,
+			Attempt:    run.Attempt,
+			Issue:      issue,
+			CreatedAt:  run.CreatedAt.UTC().Format(time.RFC3339),
+			Jobs:       make([]JobExport, 0, len(jobs)),
+		}
+		for _, job := range jobs {
+			jobExport := JobExport{
+				Name:       job.Name,
+				Status:     job.Status,
+				Conclusion: job.Conclusion,
+				URL:        job.URL,
+			}
+
+			// Score only the latest run, from the end of each log, to keep
+			// log downloads bounded
+			if i == 0 && job.Status != "queued" {
+				logs, _ := client.GetJobLogTail(job.ID, health.LogBytes)
+				score := health.Compute(health.FromJob(job, logs, nil), now)
+				jobExport.Health = &HealthExport{
+					Score:   score.Value,
+					Level:   string(score.Level),
+					Reasons: score.Reasons,
+				}
+			}
+
+			runExport.Jobs = append(runExport.Jobs, jobExport)
+		}
+		export.Runs = append(export.Runs, runExport)
+	}
+
+	return export, nil
+}
+
+// Files renders the static site for an export, keyed by relative path
+func Files(export *Export) (map[string][]byte, error) {
+	index, err := site.ReadFile("site/index.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dashboard template: %w", err)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal run data: %w", err)
+	}
+
+	return map[string][]byte{
+		"index.html": index,
+		DataFile:     data,
+		// Disable Jekyll processing on GitHub Pages
+		".nojekyll": {},
+	}, nil
+}
+
+// WriteDir writes site files into a local directory
+func WriteDir(dir string, files map[string][]byte) error {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
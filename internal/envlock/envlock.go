@@ -0,0 +1,195 @@
+package envlock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/pkg/protocol"
+)
+
+// Version is the lockfile format version
+const Version = 1
+
+// Lock records the environment instances of a run worked in, so the run
+// can be repeated in the same environment with start --env-lock
+type Lock struct {
+	Version int `json:"version"`
+	// Run is the run the lock was recorded from, e.g. "issue #42"
+	Run     string `json:"run"`
+	BaseSHA string `json:"base_sha,omitempty"`
+	// Image is the hosted runner image as ImageOS/ImageVersion, e.g. "ubuntu22/20240101.1"
+	Image string `json:"image,omitempty"`
+	// RunnerLabel is the runs-on label of the image, e.g. "ubuntu-22.04"
+	RunnerLabel string            `json:"runner_label,omitempty"`
+	OS          string            `json:"os,omitempty"`
+	Arch        string            `json:"arch,omitempty"`
+	Toolchains  map[string]string `json:"toolchains,omitempty"`
+	Model       string            `json:"model,omitempty"`
+	ProviderCLI string            `json:"provider_cli,omitempty"`
+	RecordedAt  time.Time         `json:"recorded_at"`
+	// Differences lists what instances disagreed on; the lock keeps the
+	// values of the lowest instance
+	Differences []string `json:"differences,omitempty"`
+}
+
+// imageLabels maps ImageOS values to the runs-on labels of hosted images
+var imageLabels = []struct {
+	pattern *regexp.Regexp
+	label   string
+}{
+	{regexp.MustCompile(`^ubuntu(\d\d)$`), "ubuntu-$1.04"},
+	{regexp.MustCompile(`^win(\d\d)$`), "windows-20$1"},
+	{regexp.MustCompile(`^macos(\d+)$`), "macos-$1"},
+}
+
+// RunnerLabel returns the runs-on label of a hosted runner image such as
+// "ubuntu22/20240101.1", or "" for self-hosted and unknown images
+func RunnerLabel(image string) string {
+	imageOS, _, _ := strings.Cut(image, "/")
+	for _, l := range imageLabels {
+		if l.pattern.MatchString(imageOS) {
+			return l.pattern.ReplaceAllString(imageOS, l.label)
+		}
+	}
+	return ""
+}
+
+// FromCapabilities builds a lock from the capability probes of a run's
+// instances, or returns nil if no instance reported one
+func FromCapabilities(run, baseSHA string, caps map[int]*protocol.Capabilities) *Lock {
+	ids := make([]int, 0, len(caps))
+	for id := range caps {
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Ints(ids)
+
+	first := caps[ids[0]]
+	lock := &Lock{
+		Version:     Version,
+		Run:         run,
+		BaseSHA:     baseSHA,
+		Image:       first.Image,
+		RunnerLabel: RunnerLabel(first.Image),
+		OS:          first.OS,
+		Arch:        first.Arch,
+		Toolchains:  first.Toolchains,
+		Model:       first.Model,
+		ProviderCLI: first.ProviderCLI,
+		RecordedAt:  time.Now().UTC(),
+	}
+
+	for _, id := range ids[1:] {
+		c := caps[id]
+		differ := func(field, a, b string) {
+			if a != b {
+				lock.Differences = append(lock.Differences, fmt.Sprintf("instance %d %s: %s (instance %d: %s)", id, field, orUnknown(b), ids[0], orUnknown(a)))
+			}
+		}
+		differ("image", first.Image, c.Image)
+		differ("model", first.Model, c.Model)
+		differ("provider CLI", first.ProviderCLI, c.ProviderCLI)
+		for _, tool := range toolNames(first.Toolchains, c.Toolchains) {
+			differ(tool, first.Toolchains[tool], c.Toolchains[tool])
+		}
+	}
+	return lock
+}
+
+// toolNames returns the sorted tool names of two toolchain sets
+func toolNames(a, b map[string]string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, m := range []map[string]string{a, b} {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+// Load reads a lockfile
+func Load(path string) (*Lock, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env lock: %w", err)
+	}
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse env lock %s: %w", path, err)
+	}
+	if lock.Version < 1 || lock.Version > Version {
+		return nil, fmt.Errorf("unsupported env lock version %d in %s", lock.Version, path)
+	}
+	return &lock, nil
+}
+
+// Save writes the lock to path
+func (l *Lock) Save(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal env lock: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create env lock directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write env lock: %w", err)
+	}
+	return nil
+}
+
+// Input returns the env_lock workflow input: the parts of the lock the
+// workflow and instances apply
+func (l *Lock) Input() (string, error) {
+	data, err := json.Marshal(struct {
+		RunnerLabel string            `json:"runner_label,omitempty"`
+		Image       string            `json:"image,omitempty"`
+		Toolchains  map[string]string `json:"toolchains,omitempty"`
+		Model       string            `json:"model,omitempty"`
+		ProviderCLI string            `json:"provider_cli,omitempty"`
+	}{l.RunnerLabel, l.Image, l.Toolchains, l.Model, l.ProviderCLI})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal env lock input: %w", err)
+	}
+	return string(data), nil
+}
+
+// Summary describes the pinned environment in one line
+func (l *Lock) Summary() string {
+	var parts []string
+	if l.Image != "" {
+		parts = append(parts, "image "+l.Image)
+	}
+	for _, tool := range toolNames(l.Toolchains, nil) {
+		parts = append(parts, tool+" "+l.Toolchains[tool])
+	}
+	if l.Model != "" {
+		parts = append(parts, "model "+l.Model)
+	}
+	if len(parts) == 0 {
+		return "nothing recorded"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,51 @@
+package events
+
+import (
+	"sync"
+)
+
+// Handler reacts to an event. Errors are reported to the bus error handler
+// and do not stop other subscribers.
+type Handler func(Event) error
+
+// Bus is a synchronous in-process publish/subscribe event bus
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+	onError  func(Event, error)
+}
+
+// NewBus creates a bus. onError is called when a handler fails and may be nil.
+func NewBus(onError func(Event, error)) *Bus {
+	return &Bus{onError: onError}
+}
+
+// Subscribe registers a handler for all events
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// On registers a handler for a single event type
+func On[T Event](b *Bus, fn func(T) error) {
+	b.Subscribe(func(e Event) error {
+		if typed, ok := e.(T); ok {
+			return fn(typed)
+		}
+		return nil
+	})
+}
+
+// Publish delivers an event to all subscribers in registration order
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	handlers := append([]Handler(nil), b.handlers...)
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(e); err != nil && b.onError != nil {
+			b.onError(e, err)
+		}
+	}
+}
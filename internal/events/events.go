@@ -0,0 +1,118 @@
+package events
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event is a typed occurrence in the run lifecycle
+type Event interface {
+	// Name identifies the event type, e.g. "run_started"
+	Name() string
+	// Key uniquely identifies this occurrence so repeated observations
+	// (such as polling status twice) can be deduplicated
+	Key() string
+}
+
+// RunStarted is published after a run has been dispatched
+type RunStarted struct {
+	Issue int
+	// StateRun is the state branch run ID in dispatch coordination mode,
+	// where no issue is created
+	StateRun string
+	// Source references the external ticket the task was imported from
+	Source    string
+	IssueURL  string
+	RunURL    string
+	Task      string
+	Instances int
+	BaseSHA   string
+	// Environment is the deployment environment the run targets, if any
+	Environment string
+	// Trace is the run's trace ID
+	Trace string
+	At    time.Time
+}
+
+// Name implements Event
+func (e RunStarted) Name() string { return "run_started" }
+
+// Key implements Event
+func (e RunStarted) Key() string {
+	if e.StateRun != "" {
+		return "run_started:" + e.StateRun
+	}
+	return fmt.Sprintf("run_started:%d", e.Issue)
+}
+
+// RunProgress is published whenever a run's progress is observed
+type RunProgress struct {
+	RunID      int64
+	Attempt    int
+	RunURL     string
+	Status     string
+	Conclusion string
+	HeadSHA    string
+	// BaseSHA and Environment are read from the run title and empty for
+	// runs that were not pinned or do not target an environment
+	BaseSHA     string
+	Environment string
+	Completed   int
+	Total       int
+	At          time.Time
+}
+
+// Name implements Event
+func (e RunProgress) Name() string { return "run_progress" }
+
+// Key implements Event
+func (e RunProgress) Key() string {
+	return fmt.Sprintf("run_progress:%d.%d:%s:%d/%d", e.RunID, e.Attempt, e.Status, e.Completed, e.Total)
+}
+
+// InstanceFailed is published when an instance job fails
+type InstanceFailed struct {
+	RunID   int64
+	Attempt int
+	// Issue is the run's coordination issue, or 0 if unknown
+	Issue    int
+	Instance int
+	JobName  string
+	JobURL   string
+	// Trace is the run's trace ID, or "" for runs started before trace IDs
+	Trace string
+	At    time.Time
+}
+
+// Name implements Event
+func (e InstanceFailed) Name() string { return "instance_failed" }
+
+// Key implements Event
+func (e InstanceFailed) Key() string {
+	return fmt.Sprintf("instance_failed:%d:%d", e.RunID, e.Instance)
+}
+
+// RunCompleted is published when a run attempt reaches a terminal state.
+// Re-running a workflow publishes it again for the new attempt.
+type RunCompleted struct {
+	RunID   int64
+	Attempt int
+	// Issue is the run's coordination issue, or 0 if unknown
+	Issue int
+	// StartedAt is when the run was created
+	StartedAt  time.Time
+	RunURL     string
+	Conclusion string
+	HeadSHA    string
+	Completed  int
+	Total      int
+	// Trace is the run's trace ID, or "" for runs started before trace IDs
+	Trace string
+	At    time.Time
+}
+
+// Name implements Event
+func (e RunCompleted) Name() string { return "run_completed" }
+
+// Key implements Event
+func (e RunCompleted) Key() string { return fmt.Sprintf("run_completed:%d.%d", e.RunID, e.Attempt) }
@@ -0,0 +1,229 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// ActionsAppID is the app ID of GitHub Actions, the identity that pushes and
+// merges with GITHUB_TOKEN
+const ActionsAppID = 15368
+
+// Ruleset represents a branch ruleset that applies to the repository
+type Ruleset struct {
+	ID          int64
+	Name        string
+	Enforcement string
+	// Inherited rulesets belong to the organization and cannot be edited here
+	Inherited bool
+	Include   []string
+	Exclude   []string
+	// RequiredReviews is the approving review count of a pull_request rule
+	RequiredReviews int
+	// ActionsBypass reports whether GitHub Actions may bypass the ruleset
+	ActionsBypass bool
+}
+
+// Covers reports whether the ruleset's ref conditions include a branch
+func (r *Ruleset) Covers(branch, defaultBranch string) bool {
+	ref := "refs/heads/" + branch
+	matches := func(patterns []string) bool {
+		for _, p := range patterns {
+			if p == "~ALL" || p == ref || (p == "~DEFAULT_BRANCH" && branch == defaultBranch) {
+				return true
+			}
+		}
+		return false
+	}
+	return matches(r.Include) && !matches(r.Exclude)
+}
+
+// ListBranchRulesets returns the branch rulesets that apply to the repository,
+// including those inherited from the organization
+func (c *Client) ListBranchRulesets() ([]Ruleset, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	summaries, _, err := c.client.Repositories.GetAllRulesets(ctx, c.owner, c.repo, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rulesets: %w", err)
+	}
+
+	var result []Ruleset
+	for _, summary := range summaries {
+		if summary.GetTarget() != "branch" {
+			continue
+		}
+		rs, _, err := c.client.Repositories.GetRuleset(ctx, c.owner, c.repo, summary.GetID(), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ruleset %s: %w", summary.Name, err)
+		}
+		result = append(result, toRuleset(rs))
+	}
+	return result, nil
+}
+
+func toRuleset(rs *github.Ruleset) Ruleset {
+	r := Ruleset{
+		ID:          rs.GetID(),
+		Name:        rs.Name,
+		Enforcement: rs.Enforcement,
+		Inherited:   rs.GetSourceType() == "Organization",
+	}
+	if cond := rs.Conditions; cond != nil && cond.RefName != nil {
+		r.Include = cond.RefName.Include
+		r.Exclude = cond.RefName.Exclude
+	}
+	for _, rule := range rs.Rules {
+		if rule.Type != "pull_request" || rule.Parameters == nil {
+			continue
+		}
+		var params github.PullRequestRuleParameters
+		if err := json.Unmarshal(*rule.Parameters, &params); err == nil {
+			r.RequiredReviews = params.RequiredApprovingReviewCount
+		}
+	}
+	for _, actor := range rs.BypassActors {
+		if actor.GetActorType() == "Integration" && actor.GetActorID() == ActionsAppID {
+			r.ActionsBypass = true
+		}
+	}
+	return r
+}
+
+// ExcludeFromRuleset adds ref patterns to a repository ruleset's exclusions
+func (c *Client) ExcludeFromRuleset(id int64, patterns ...string) error {
+	return c.updateRuleset(id, func(rs *github.Ruleset) {
+		if rs.Conditions == nil {
+			rs.Conditions = &github.RulesetConditions{}
+		}
+		if rs.Conditions.RefName == nil {
+			rs.Conditions.RefName = &github.RulesetRefConditionParameters{Include: []string{}}
+		}
+		for _, p := range patterns {
+			if !contains(rs.Conditions.RefName.Exclude, p) {
+				rs.Conditions.RefName.Exclude = append(rs.Conditions.RefName.Exclude, p)
+			}
+		}
+	})
+}
+
+// AllowActionsBypass lets GitHub Actions bypass a repository ruleset through
+// pull requests, so PRs opened by workflows can be auto-merged
+func (c *Client) AllowActionsBypass(id int64) error {
+	return c.updateRuleset(id, func(rs *github.Ruleset) {
+		rs.BypassActors = append(rs.BypassActors, &github.BypassActor{
+			ActorID:    github.Int64(ActionsAppID),
+			ActorType:  github.String("Integration"),
+			BypassMode: github.String("pull_request"),
+		})
+	})
+}
+
+func (c *Client) updateRuleset(id int64, change func(*github.Ruleset)) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	rs, _, err := c.client.Repositories.GetRuleset(ctx, c.owner, c.repo, id, false)
+	if err != nil {
+		return fmt.Errorf("failed to get ruleset %d: %w", id, err)
+	}
+	change(rs)
+	if rs.Conditions != nil && rs.Conditions.RefName != nil && rs.Conditions.RefName.Exclude == nil {
+		rs.Conditions.RefName.Exclude = []string{}
+	}
+	if _, _, err := c.client.Repositories.UpdateRuleset(ctx, c.owner, c.repo, id, rs); err != nil {
+		return fmt.Errorf("failed to update ruleset %s: %w", rs.Name, err)
+	}
+	return nil
+}
+
+// GetBranchRules returns the types of ruleset rules that apply to a branch,
+// whether or not it exists
+func (c *Client) GetBranchRules(branch string) ([]string, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	rules, _, err := c.client.Repositories.GetRulesForBranch(ctx, c.owner, c.repo, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules for %s: %w", branch, err)
+	}
+
+	var types []string
+	for _, rule := range rules {
+		if !contains(types, rule.Type) {
+			types = append(types, rule.Type)
+		}
+	}
+	return types, nil
+}
+
+// ListProtectedBranches returns the names of branches with classic branch protection
+func (c *Client) ListProtectedBranches() ([]string, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	opts := &github.BranchListOptions{
+		Protected:   github.Bool(true),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var result []string
+	for {
+		branches, resp, err := c.client.Repositories.ListBranches(ctx, c.owner, c.repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list protected branches: %w", err)
+		}
+		for _, b := range branches {
+			result = append(result, b.GetName())
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+// GetRequiredReviews returns the approving reviews classic branch protection
+// requires on a branch, or 0 if the branch is not protected
+func (c *Client) GetRequiredReviews(branch string) (int, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	protection, resp, err := c.client.Repositories.GetBranchProtection(ctx, c.owner, c.repo, branch)
+	if errors.Is(err, github.ErrBranchNotProtected) || (resp != nil && resp.StatusCode == http.StatusNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get protection of %s: %w", branch, err)
+	}
+	if reviews := protection.GetRequiredPullRequestReviews(); reviews != nil {
+		return reviews.RequiredApprovingReviewCount, nil
+	}
+	return 0, nil
+}
+
+// EnableAutoMerge allows pull requests in the repository to be auto-merged
+func (c *Client) EnableAutoMerge() error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	if _, _, err := c.client.Repositories.Edit(ctx, c.owner, c.repo, &github.Repository{AllowAutoMerge: github.Bool(true)}); err != nil {
+		return fmt.Errorf("failed to enable auto-merge: %w", err)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
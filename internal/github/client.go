@@ -2,18 +2,48 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v56/github"
 	"golang.org/x/oauth2"
 )
 
+// defaultWorkflowFile is the workflow file name created by init
+const defaultWorkflowFile = "autonomous-dev.yml"
+
 // Client wraps GitHub API client
 type Client struct {
-	client *github.Client
-	owner  string
-	repo   string
-	ctx    context.Context
+	client       *github.Client
+	owner        string
+	repo         string
+	token        string
+	workflowFile string
+	ctx          context.Context
+	timeouts     Timeouts
+
+	// login caches the token's user for Login
+	loginMu sync.Mutex
+	login   string
+}
+
+// Timeouts bound GitHub API calls by kind. A call that runs past its
+// timeout fails with context.DeadlineExceeded; zero means no deadline.
+type Timeouts struct {
+	// Read bounds fetching one object, e.g. an issue or a workflow run
+	Read time.Duration
+	// List bounds listing and searching, across all pages
+	List time.Duration
+	// Dispatch bounds creating and changing objects, e.g. dispatching a
+	// workflow or posting a comment
+	Dispatch time.Duration
+	// Logs bounds downloading workflow and job logs
+	Logs time.Duration
 }
 
 // Issue represents a GitHub issue
@@ -21,20 +51,63 @@ type Issue struct {
 	Number int
 	Title  string
 	URL    string
+	// State, Labels and UpdatedAt are set by ListIssues
+	State     string
+	Labels    []string
+	UpdatedAt time.Time
+	// Body, CreatedAt and PullRequest are set by GetIssue and ListCreatedSince
+	Body        string
+	CreatedAt   time.Time
+	PullRequest bool
 }
 
 // WorkflowRun represents a workflow run
 type WorkflowRun struct {
-	ID        int64
-	Status    string
-	URL       string
-	CreatedAt string
+	ID         int64
+	Status     string
+	Conclusion string
+	URL        string
+	HeadSHA    string
+	// Title is the run's display title, set from the workflow's run-name
+	Title string
+	// Attempt is the run attempt number; re-runs increment it
+	Attempt   int
+	CreatedAt time.Time
+	// UpdatedAt is when the run last changed; the finish time of completed runs
+	UpdatedAt time.Time
 }
 
 // Job represents a workflow job
 type Job struct {
-	Name   string
-	Status string
+	ID         int64
+	Name       string
+	Status     string
+	Conclusion string
+	URL        string
+	Attempt    int
+	StartedAt  time.Time
+	Steps      []Step
+}
+
+// Step represents a step of a workflow job
+type Step struct {
+	Number     int
+	Name       string
+	Status     string
+	Conclusion string
+}
+
+// jobInstancePattern matches the matrix suffix of job names, e.g. "autonomous-dev (3)"
+var jobInstancePattern = regexp.MustCompile(`\((\d+)\)$`)
+
+// InstanceNumber returns the instance number of a matrix job, or 0 if unknown
+func (j *Job) InstanceNumber() int {
+	m := jobInstancePattern.FindStringSubmatch(j.Name)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
 }
 
 // NewClient creates a new GitHub client
@@ -46,22 +119,41 @@ func NewClient(token, owner, repo string) *Client {
 	tc := oauth2.NewClient(ctx, ts)
 
 	return &Client{
-		client: github.NewClient(tc),
-		owner:  owner,
-		repo:   repo,
-		ctx:    ctx,
+		client:       github.NewClient(tc),
+		owner:        owner,
+		repo:         repo,
+		token:        token,
+		workflowFile: defaultWorkflowFile,
+		ctx:          ctx,
+	}
+}
+
+// SetTimeouts bounds the client's API calls
+func (c *Client) SetTimeouts(t Timeouts) {
+	c.timeouts = t
+}
+
+// call returns the context of one API call with the given timeout
+func (c *Client) call(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(c.ctx)
 	}
+	return context.WithTimeout(c.ctx, timeout)
 }
 
-// CreateIssue creates a new GitHub issue
-func (c *Client) CreateIssue(title, body string) (*Issue, error) {
+// CreateIssue creates a new GitHub issue labeled autonomous-dev plus any extra labels
+func (c *Client) CreateIssue(title, body string, labels ...string) (*Issue, error) {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
 	issueReq := &github.IssueRequest{
 		Title:  &title,
 		Body:   &body,
 		Labels: &[]string{"autonomous-dev"},
 	}
+	*issueReq.Labels = append(*issueReq.Labels, labels...)
 
-	issue, _, err := c.client.Issues.Create(c.ctx, c.owner, c.repo, issueReq)
+	issue, _, err := c.client.Issues.Create(ctx, c.owner, c.repo, issueReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create issue: %w", err)
 	}
@@ -73,9 +165,11 @@ func (c *Client) CreateIssue(title, body string) (*Issue, error) {
 	}, nil
 }
 
-// TriggerWorkflow triggers the autonomous-dev workflow
-func (c *Client) TriggerWorkflow(issueNumber, instances int) (*WorkflowRun, error) {
-	workflowFile := "autonomous-dev.yml"
+// TriggerWorkflow triggers the autonomous-dev workflow.
+// extra holds additional workflow_dispatch inputs and may be nil.
+func (c *Client) TriggerWorkflow(issueNumber, instances int, extra map[string]string) (*WorkflowRun, error) {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
 
 	// Create workflow dispatch event
 	dispatchReq := github.CreateWorkflowDispatchEventRequest{
@@ -85,16 +179,19 @@ func (c *Client) TriggerWorkflow(issueNumber, instances int) (*WorkflowRun, erro
 			"instance_count": fmt.Sprint(instances),
 		},
 	}
+	for name, value := range extra {
+		dispatchReq.Inputs[name] = value
+	}
 
 	_, err := c.client.Actions.CreateWorkflowDispatchEventByFileName(
-		c.ctx,
+		ctx,
 		c.owner,
 		c.repo,
-		workflowFile,
+		c.workflowFile,
 		dispatchReq,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to trigger workflow: %w", err)
+		return nil, fmt.Errorf("failed to trigger workflow: %w", c.wrapPermissionError(err))
 	}
 
 	// Get the latest workflow run (just triggered)
@@ -110,46 +207,197 @@ func (c *Client) TriggerWorkflow(issueNumber, instances int) (*WorkflowRun, erro
 
 // GetLatestWorkflowRun gets the latest autonomous-dev workflow run
 func (c *Client) GetLatestWorkflowRun() (*WorkflowRun, error) {
+	runs, err := c.ListWorkflowRuns(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(runs) == 0 {
+		return nil, nil
+	}
+
+	return &runs[0], nil
+}
+
+// ListWorkflowRuns lists the most recent autonomous-dev workflow runs
+func (c *Client) ListWorkflowRuns(limit int) ([]WorkflowRun, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
 	opts := &github.ListWorkflowRunsOptions{
 		ListOptions: github.ListOptions{
-			PerPage: 1,
+			PerPage: limit,
 		},
 	}
 
 	runs, _, err := c.client.Actions.ListWorkflowRunsByFileName(
-		c.ctx,
+		ctx,
 		c.owner,
 		c.repo,
-		"autonomous-dev.yml",
+		c.workflowFile,
 		opts,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list workflow runs: %w", err)
 	}
 
-	if len(runs.WorkflowRuns) == 0 {
-		return nil, nil
+	result := make([]WorkflowRun, 0, len(runs.WorkflowRuns))
+	for _, run := range runs.WorkflowRuns {
+		result = append(result, toWorkflowRun(run))
 	}
 
-	run := runs.WorkflowRuns[0]
-	return &WorkflowRun{
-		ID:        *run.ID,
-		Status:    *run.Status,
-		URL:       *run.HTMLURL,
-		CreatedAt: run.CreatedAt.String(),
-	}, nil
+	return result, nil
+}
+
+// GetWorkflowRun gets a workflow run by ID
+func (c *Client) GetWorkflowRun(runID int64) (*WorkflowRun, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	run, _, err := c.client.Actions.GetWorkflowRunByID(ctx, c.owner, c.repo, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run %d: %w", runID, err)
+	}
+
+	result := toWorkflowRun(run)
+	return &result, nil
+}
+
+// CancelWorkflowRun cancels a queued or in-progress workflow run
+func (c *Client) CancelWorkflowRun(runID int64) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	// Cancellation is asynchronous; 202 Accepted means it was requested
+	_, err := c.client.Actions.CancelWorkflowRunByID(ctx, c.owner, c.repo, runID)
+	var accepted *github.AcceptedError
+	if err != nil && !errors.As(err, &accepted) {
+		return fmt.Errorf("failed to cancel run %d: %w", runID, c.wrapPermissionError(err))
+	}
+	return nil
+}
+
+// GetWorkflowRunAttempt gets a specific attempt of a workflow run
+func (c *Client) GetWorkflowRunAttempt(runID int64, attempt int) (*WorkflowRun, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	run, _, err := c.client.Actions.GetWorkflowRunAttempt(ctx, c.owner, c.repo, runID, attempt, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attempt %d of run %d: %w", attempt, runID, err)
+	}
+
+	result := toWorkflowRun(run)
+	return &result, nil
+}
+
+// RunIssueArtifactPrefix names the artifact the workflow uploads to record
+// a run's issue, e.g. "autonomous-dev-issue-42"
+const RunIssueArtifactPrefix = "autonomous-dev-issue-"
+
+// runTitleIssuePattern matches the issue in run titles set by run-name,
+// e.g. "Autonomous Development (issue #42)"
+var runTitleIssuePattern = regexp.MustCompile(`\(issue #(\d+)\)`)
+
+// runTitleBasePattern matches the pinned base commit in run titles,
+// e.g. "Autonomous Development (issue #42) @ 1a2b3c..."
+var runTitleBasePattern = regexp.MustCompile(`@ ([0-9a-f]{7,40})\b`)
+
+// runTitleTracePattern matches the trace ID in run titles, e.g.
+// "Autonomous Development (issue #42) [tr-3f9a0c12b7e4] @ 1a2b3c..."
+var runTitleTracePattern = regexp.MustCompile(`\[(tr-[0-9a-f]+)\]`)
+
+// runTitleEnvironmentPattern matches the deployment environment at the end of
+// run titles, e.g. "Autonomous Development (issue #42) @ 1a2b3c... → staging"
+var runTitleEnvironmentPattern = regexp.MustCompile(`→ (.+)$`)
+
+// Environment returns the deployment environment recorded in the run title,
+// or "" if the run does not target one
+func (r *WorkflowRun) Environment() string {
+	m := runTitleEnvironmentPattern.FindStringSubmatch(r.Title)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// BaseSHA returns the base commit recorded in the run title, or "" if the
+// run was not pinned
+func (r *WorkflowRun) BaseSHA() string {
+	m := runTitleBasePattern.FindStringSubmatch(r.Title)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// TraceID returns the trace ID recorded in the run title, or "" for runs
+// started before trace IDs
+func (r *WorkflowRun) TraceID() string {
+	m := runTitleTracePattern.FindStringSubmatch(r.Title)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// Issue returns the issue number recorded in the run title, or 0 if none
+func (r *WorkflowRun) Issue() int {
+	m := runTitleIssuePattern.FindStringSubmatch(r.Title)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
+
+// GetRunIssue returns the issue a workflow run was dispatched for, read from
+// the run title or, for workflows with a custom run-name, the issue artifact.
+// It returns 0 for runs that cannot be attributed, such as state branch runs.
+func (c *Client) GetRunIssue(runID int64) (int, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	run, _, err := c.client.Actions.GetWorkflowRunByID(ctx, c.owner, c.repo, runID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get run %d: %w", runID, err)
+	}
+	result := toWorkflowRun(run)
+	if issue := result.Issue(); issue > 0 {
+		return issue, nil
+	}
+
+	artifacts, _, err := c.client.Actions.ListWorkflowRunArtifacts(ctx, c.owner, c.repo, runID, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list artifacts of run %d: %w", runID, err)
+	}
+	for _, artifact := range artifacts.Artifacts {
+		name := artifact.GetName()
+		if !strings.HasPrefix(name, RunIssueArtifactPrefix) {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(name, RunIssueArtifactPrefix)); err == nil {
+			return n, nil
+		}
+	}
+	return 0, nil
 }
 
-// GetWorkflowJobs gets jobs for a workflow run
+// GetWorkflowJobs gets jobs for the latest attempt of a workflow run
 func (c *Client) GetWorkflowJobs(runID int64) ([]Job, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
 	opts := &github.ListWorkflowJobsOptions{
+		// Jobs from earlier attempts would mix old and new results
+		Filter: "latest",
 		ListOptions: github.ListOptions{
 			PerPage: 100,
 		},
 	}
 
 	jobs, _, err := c.client.Actions.ListWorkflowJobs(
-		c.ctx,
+		ctx,
 		c.owner,
 		c.repo,
 		runID,
@@ -159,13 +407,66 @@ func (c *Client) GetWorkflowJobs(runID int64) ([]Job, error) {
 		return nil, fmt.Errorf("failed to list workflow jobs: %w", err)
 	}
 
+	return toJobs(jobs), nil
+}
+
+// GetWorkflowAttemptJobs gets jobs for a specific attempt of a workflow run
+func (c *Client) GetWorkflowAttemptJobs(runID int64, attempt int) ([]Job, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	u := fmt.Sprintf("repos/%v/%v/actions/runs/%v/attempts/%v/jobs?per_page=100", c.owner, c.repo, runID, attempt)
+	req, err := c.client.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs for attempt %d: %w", attempt, err)
+	}
+
+	jobs := new(github.Jobs)
+	if _, err := c.client.Do(ctx, req, jobs); err != nil {
+		return nil, fmt.Errorf("failed to list jobs for attempt %d: %w", attempt, err)
+	}
+
+	return toJobs(jobs), nil
+}
+
+func toWorkflowRun(run *github.WorkflowRun) WorkflowRun {
+	return WorkflowRun{
+		ID:         run.GetID(),
+		Status:     run.GetStatus(),
+		Conclusion: run.GetConclusion(),
+		URL:        run.GetHTMLURL(),
+		HeadSHA:    run.GetHeadSHA(),
+		Title:      run.GetDisplayTitle(),
+		Attempt:    run.GetRunAttempt(),
+		CreatedAt:  run.GetCreatedAt().Time,
+		UpdatedAt:  run.GetUpdatedAt().Time,
+	}
+}
+
+func toJobs(jobs *github.Jobs) []Job {
 	result := make([]Job, 0, len(jobs.Jobs))
 	for _, job := range jobs.Jobs {
+		steps := make([]Step, 0, len(job.Steps))
+		for _, step := range job.Steps {
+			steps = append(steps, Step{
+				Number:     int(step.GetNumber()),
+				Name:       step.GetName(),
+				Status:     step.GetStatus(),
+				Conclusion: step.GetConclusion(),
+			})
+		}
+
 		result = append(result, Job{
-			Name:   *job.Name,
-			Status: *job.Status,
+			ID:         job.GetID(),
+			Name:       job.GetName(),
+			Status:     job.GetStatus(),
+			Conclusion: job.GetConclusion(),
+			URL:        job.GetHTMLURL(),
+			Attempt:    int(job.GetRunAttempt()),
+			StartedAt:  job.GetStartedAt().Time,
+			Steps:      steps,
 		})
 	}
 
-	return result, nil
+	return result
 }
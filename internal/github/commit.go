@@ -0,0 +1,144 @@
+package github
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// commitAttempts is how often CommitFiles commits on top of the branch head
+// when the branch moves while it commits, as the status reporter retries
+const commitAttempts = 3
+
+// CommitFiles commits files to a branch using the Git Data API, without a
+// local checkout. Existing files on the branch are kept unless overwritten.
+// The branch is created as an orphan if it does not exist yet. If another
+// commit lands on the branch first, the commit is rebuilt on the new head.
+// Returns the SHA of the new commit.
+func (c *Client) CommitFiles(branch, message string, files map[string][]byte) (string, error) {
+	entries, err := c.treeEntries(files)
+	if err != nil {
+		return "", err
+	}
+
+	for attempt := 1; ; attempt++ {
+		sha, moved, err := c.commitTree(branch, message, entries)
+		if !moved || attempt == commitAttempts {
+			return sha, err
+		}
+		time.Sleep(time.Duration(attempt) * time.Second)
+	}
+}
+
+// treeEntries returns the tree entries of files, uploading binary files as
+// blobs. Paths are sorted so the resulting tree is deterministic.
+func (c *Client) treeEntries(files map[string][]byte) ([]*github.TreeEntry, error) {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	entries := make([]*github.TreeEntry, 0, len(paths))
+	for _, path := range paths {
+		entry := &github.TreeEntry{
+			Path: github.String(path),
+			Mode: github.String("100644"),
+			Type: github.String("blob"),
+		}
+		// Tree content must be UTF-8; binary files are uploaded as blobs first
+		if utf8.Valid(files[path]) {
+			entry.Content = github.String(string(files[path]))
+		} else {
+			blob, _, err := c.client.Git.CreateBlob(ctx, c.owner, c.repo, &github.Blob{
+				Content:  github.String(base64.StdEncoding.EncodeToString(files[path])),
+				Encoding: github.String("base64"),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to upload %s: %w", path, err)
+			}
+			entry.SHA = blob.SHA
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// commitTree commits entries on top of the branch head and moves the branch
+// to the commit. moved reports that the branch changed in between, so the
+// ref could not be fast-forwarded or created.
+func (c *Client) commitTree(branch, message string, entries []*github.TreeEntry) (sha string, moved bool, err error) {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	refName := "refs/heads/" + branch
+
+	var parents []*github.Commit
+	baseTree := ""
+
+	ref, _, err := c.client.Git.GetRef(ctx, c.owner, c.repo, refName)
+	if err != nil && !isNotFound(err) {
+		return "", false, fmt.Errorf("failed to get branch %s: %w", branch, err)
+	}
+	if ref != nil {
+		parent, _, err := c.client.Git.GetCommit(ctx, c.owner, c.repo, ref.GetObject().GetSHA())
+		if err != nil {
+			return "", false, fmt.Errorf("failed to get head commit of %s: %w", branch, err)
+		}
+		parents = append(parents, parent)
+		baseTree = parent.GetTree().GetSHA()
+	}
+
+	tree, _, err := c.client.Git.CreateTree(ctx, c.owner, c.repo, baseTree, entries)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create tree: %w", err)
+	}
+
+	commit, _, err := c.client.Git.CreateCommit(ctx, c.owner, c.repo, &github.Commit{
+		Message: github.String(message),
+		Tree:    tree,
+		Parents: parents,
+	}, nil)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create commit: %w", err)
+	}
+
+	newRef := &github.Reference{
+		Ref:    github.String(refName),
+		Object: &github.GitObject{SHA: commit.SHA},
+	}
+	if ref == nil {
+		_, _, err = c.client.Git.CreateRef(ctx, c.owner, c.repo, newRef)
+	} else {
+		_, _, err = c.client.Git.UpdateRef(ctx, c.owner, c.repo, newRef, false)
+	}
+	if err != nil {
+		// Not a fast-forward, or the branch was created meanwhile
+		var errResp *github.ErrorResponse
+		moved := errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusUnprocessableEntity
+		return "", moved, fmt.Errorf("failed to update branch %s: %w", branch, err)
+	}
+
+	return commit.GetSHA(), false, nil
+}
+
+// isNotFound reports whether err is a 404 response from the API
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
+
+// IsAPIError reports whether err wraps an error response from the GitHub API
+func IsAPIError(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp)
+}
@@ -0,0 +1,56 @@
+package github
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// DiffStats summarizes what a branch changed compared to its base
+type DiffStats struct {
+	Files     int
+	Additions int
+	Deletions int
+	// Dirs are the top-level directories touched, sorted; files at the
+	// repository root are listed as "."
+	Dirs []string
+	// Patches are the unified diff patches by file; binary and very large
+	// files have none
+	Patches map[string]string
+}
+
+// CompareBranch returns the diff stats of head against base, or nil if
+// either branch does not exist. The API lists at most 300 changed files.
+func (c *Client) CompareBranch(base, head string) (*DiffStats, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	cmp, _, err := c.client.Repositories.CompareCommits(ctx, c.owner, c.repo, base, head, &github.ListOptions{PerPage: 100})
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare %s with %s: %w", head, base, err)
+	}
+
+	stats := &DiffStats{Files: len(cmp.Files), Patches: make(map[string]string)}
+	for _, f := range cmp.Files {
+		stats.Additions += f.GetAdditions()
+		stats.Deletions += f.GetDeletions()
+		if patch := f.GetPatch(); patch != "" {
+			stats.Patches[f.GetFilename()] = patch
+		}
+
+		dir := "."
+		if i := strings.Index(f.GetFilename(), "/"); i > 0 {
+			dir = f.GetFilename()[:i]
+		}
+		if !contains(stats.Dirs, dir) {
+			stats.Dirs = append(stats.Dirs, dir)
+		}
+	}
+	sort.Strings(stats.Dirs)
+	return stats, nil
+}
@@ -0,0 +1,103 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// DispatchEventType is the repository_dispatch event type the workflow listens for
+const DispatchEventType = "autonomous-dev"
+
+// RepositoryDispatch sends a repository_dispatch event with a JSON payload.
+// GitHub limits client payloads to 10 top-level properties.
+func (c *Client) RepositoryDispatch(eventType string, payload map[string]interface{}) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dispatch payload: %w", err)
+	}
+	raw := json.RawMessage(data)
+
+	_, _, err = c.client.Repositories.Dispatch(ctx, c.owner, c.repo, github.DispatchRequestOptions{
+		EventType:     eventType,
+		ClientPayload: &raw,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send repository dispatch: %w", c.wrapPermissionError(err))
+	}
+	return nil
+}
+
+// ListDirectory returns the sorted entry names of a directory on a branch.
+// A missing branch or directory yields no entries.
+func (c *Client) ListDirectory(branch, path string) ([]string, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	_, entries, _, err := c.client.Repositories.GetContents(ctx, c.owner, c.repo, path, &github.RepositoryContentGetOptions{Ref: branch})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list %s on %s: %w", path, branch, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.GetName())
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// GetFile returns the content of a file on a branch
+func (c *Client) GetFile(branch, path string) ([]byte, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	file, _, _, err := c.client.Repositories.GetContents(ctx, c.owner, c.repo, path, &github.RepositoryContentGetOptions{Ref: branch})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s on %s: %w", path, branch, err)
+	}
+	if file == nil {
+		return nil, fmt.Errorf("%s on %s is a directory", path, branch)
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return []byte(content), nil
+}
+
+// DispatchRun triggers the workflow with a repository_dispatch event for a
+// run recorded on the state branch. extra holds additional payload
+// properties and may be nil.
+func (c *Client) DispatchRun(runID string, instances int, extra map[string]string) (*WorkflowRun, error) {
+	payload := map[string]interface{}{
+		"run_id":         runID,
+		"instance_count": fmt.Sprint(instances),
+	}
+	for name, value := range extra {
+		payload[name] = value
+	}
+	if len(payload) > 10 {
+		return nil, fmt.Errorf("dispatch payload has %d properties; repository_dispatch allows at most 10", len(payload))
+	}
+
+	if err := c.RepositoryDispatch(DispatchEventType, payload); err != nil {
+		return nil, err
+	}
+
+	// The run appears shortly after the event; like TriggerWorkflow, return a placeholder
+	return &WorkflowRun{
+		Status: "queued",
+		URL:    fmt.Sprintf("https://github.com/%s/%s/actions", c.owner, c.repo),
+	}, nil
+}
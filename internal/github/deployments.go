@@ -0,0 +1,146 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// DeploymentTask is the task of deployments created for autonomous runs
+const DeploymentTask = "deploy:autonomous-dev"
+
+// DeploymentState is the state of a deployment status
+type DeploymentState string
+
+const (
+	DeploymentQueued     DeploymentState = "queued"
+	DeploymentInProgress DeploymentState = "in_progress"
+	DeploymentSuccess    DeploymentState = "success"
+	DeploymentFailure    DeploymentState = "failure"
+	DeploymentError      DeploymentState = "error"
+)
+
+// ErrNoEnvironment is returned for environments that do not exist yet
+var ErrNoEnvironment = errors.New("environment does not exist")
+
+// Deployment represents a GitHub deployment of an autonomous run
+type Deployment struct {
+	ID          int64
+	Environment string
+	SHA         string
+	// State is the state of the latest status, or "" if none was set
+	State DeploymentState
+}
+
+// CreateDeployment creates a deployment of sha to an environment. Commit
+// status checks are not required: the run itself is what is deployed.
+func (c *Client) CreateDeployment(sha, environment, description string, payload map[string]interface{}) (*Deployment, error) {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	d, _, err := c.client.Repositories.CreateDeployment(ctx, c.owner, c.repo, &github.DeploymentRequest{
+		Ref:              github.String(sha),
+		Task:             github.String(DeploymentTask),
+		AutoMerge:        github.Bool(false),
+		RequiredContexts: &[]string{},
+		Payload:          payload,
+		Environment:      github.String(environment),
+		Description:      github.String(description),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deployment: %w", c.wrapPermissionError(err))
+	}
+	return &Deployment{ID: d.GetID(), Environment: d.GetEnvironment(), SHA: d.GetSHA()}, nil
+}
+
+// FindDeployment returns the latest autonomous-dev deployment of sha to an
+// environment with the state of its latest status, or nil if there is none
+func (c *Client) FindDeployment(sha, environment string) (*Deployment, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	deployments, _, err := c.client.Repositories.ListDeployments(ctx, c.owner, c.repo, &github.DeploymentsListOptions{
+		SHA:         sha,
+		Task:        DeploymentTask,
+		Environment: environment,
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	if len(deployments) == 0 {
+		return nil, nil
+	}
+
+	d := deployments[0]
+	statuses, _, err := c.client.Repositories.ListDeploymentStatuses(ctx, c.owner, c.repo, d.GetID(), &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployment statuses: %w", err)
+	}
+
+	deployment := &Deployment{ID: d.GetID(), Environment: d.GetEnvironment(), SHA: d.GetSHA()}
+	if len(statuses) > 0 {
+		deployment.State = DeploymentState(statuses[0].GetState())
+	}
+	return deployment, nil
+}
+
+// SetDeploymentStatus adds a status to a deployment
+func (c *Client) SetDeploymentStatus(id int64, state DeploymentState, description, logURL string) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	status := &github.DeploymentStatusRequest{
+		State:       github.String(string(state)),
+		Description: github.String(description),
+	}
+	if logURL != "" {
+		status.LogURL = github.String(logURL)
+	}
+
+	if _, _, err := c.client.Repositories.CreateDeploymentStatus(ctx, c.owner, c.repo, id, status); err != nil {
+		return fmt.Errorf("failed to set deployment status: %w", err)
+	}
+	return nil
+}
+
+// EnvironmentRules returns the protection rule types of an environment,
+// e.g. "required_reviewers" or "wait_timer". A missing environment returns
+// ErrNoEnvironment; GitHub creates it without rules on first use.
+func (c *Client) EnvironmentRules(name string) ([]string, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	env, _, err := c.client.Repositories.GetEnvironment(ctx, c.owner, c.repo, name)
+	if isNotFound(err) {
+		return nil, ErrNoEnvironment
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment %s: %w", name, err)
+	}
+
+	var rules []string
+	for _, rule := range env.ProtectionRules {
+		rules = append(rules, rule.GetType())
+	}
+	return rules, nil
+}
+
+// RunDeploymentState maps a workflow run status and conclusion to a deployment state
+func RunDeploymentState(status, conclusion string) DeploymentState {
+	switch {
+	case status == "queued" || status == "waiting" || status == "pending":
+		return DeploymentQueued
+	case status != "completed":
+		return DeploymentInProgress
+	}
+	switch conclusion {
+	case "success":
+		return DeploymentSuccess
+	case "failure", "timed_out":
+		return DeploymentFailure
+	default:
+		return DeploymentError
+	}
+}
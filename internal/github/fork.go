@@ -0,0 +1,44 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// EnsureFork makes sure a fork of the repository exists under forkOwner and
+// returns its full name. Set org when forkOwner is an organization rather
+// than the authenticated user.
+func (c *Client) EnsureFork(forkOwner string, org bool) (string, error) {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	fork, _, err := c.client.Repositories.Get(ctx, forkOwner, c.repo)
+	if err == nil {
+		if !fork.GetFork() || fork.GetParent().GetFullName() != c.owner+"/"+c.repo {
+			return "", fmt.Errorf("%s/%s exists but is not a fork of %s/%s", forkOwner, c.repo, c.owner, c.repo)
+		}
+		return fork.GetFullName(), nil
+	}
+	if !isNotFound(err) {
+		return "", fmt.Errorf("failed to get fork: %w", err)
+	}
+
+	opts := &github.RepositoryCreateForkOptions{DefaultBranchOnly: true}
+	if org {
+		opts.Organization = forkOwner
+	}
+
+	fork, _, err = c.client.Repositories.CreateFork(ctx, c.owner, c.repo, opts)
+	if err != nil {
+		// Forking is asynchronous; 202 Accepted means it is being created
+		var accepted *github.AcceptedError
+		if !errors.As(err, &accepted) {
+			return "", fmt.Errorf("failed to create fork: %w", err)
+		}
+		return forkOwner + "/" + c.repo, nil
+	}
+
+	return fork.GetFullName(), nil
+}
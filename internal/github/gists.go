@@ -0,0 +1,34 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// CreateGist creates a secret gist of text files and returns its URL and the
+// raw URL of each file by name
+func (c *Client) CreateGist(description string, files map[string]string) (string, map[string]string, error) {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	gistFiles := make(map[github.GistFilename]github.GistFile, len(files))
+	for name, content := range files {
+		gistFiles[github.GistFilename(name)] = github.GistFile{Content: github.String(content)}
+	}
+
+	gist, _, err := c.client.Gists.Create(ctx, &github.Gist{
+		Description: github.String(description),
+		Public:      github.Bool(false),
+		Files:       gistFiles,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create gist: %w", c.wrapPermissionError(err))
+	}
+
+	raw := make(map[string]string, len(gist.Files))
+	for name, file := range gist.Files {
+		raw[string(name)] = file.GetRawURL()
+	}
+	return gist.GetHTMLURL(), raw, nil
+}
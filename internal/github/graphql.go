@@ -0,0 +1,249 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// graphQLError is an error reported in a GraphQL response
+type graphQLError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+// graphql runs a GraphQL query and decodes its data into out. One query
+// replaces the many REST calls needed to walk related objects.
+func (c *Client) graphql(ctx context.Context, query string, vars map[string]interface{}, out interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{"query": query, "variables": vars})
+	if err != nil {
+		return fmt.Errorf("failed to marshal GraphQL query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.client.BaseURL.String()+"graphql", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create GraphQL request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Client().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query GraphQL API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to query GraphQL API: %s", resp.Status)
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode GraphQL response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("GraphQL query failed: %s", strings.Join(messages, "; "))
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode GraphQL data: %w", err)
+	}
+	return nil
+}
+
+// IssueGraph is an issue with its comments and the issues and pull requests
+// that reference it
+type IssueGraph struct {
+	Issue    Issue
+	Comments []Comment
+	// References are the issues and pull requests that mention the issue,
+	// e.g. with a "Part of #N" breadcrumb, in the order they did so
+	References []Reference
+}
+
+// Reference is an issue or pull request that mentions another issue
+type Reference struct {
+	Issue
+	// Checks is the combined check state of a pull request's head commit,
+	// e.g. "success" or "failure"; empty for issues and unchecked commits
+	Checks string
+	// NodeID identifies a pull request in GraphQL mutations
+	NodeID string
+	// Draft reports whether a pull request is a draft
+	Draft bool
+}
+
+// issueGraphQuery fetches an issue, then pages through its comments and
+// cross-references; @include skips connections that are already complete
+const issueGraphQuery = `query($owner: String!, $repo: String!, $number: Int!,
+  $withComments: Boolean!, $comments: String, $withRefs: Boolean!, $refs: String) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {
+      number title url state body createdAt updatedAt
+      labels(first: 50) { nodes { name } }
+      comments(first: 100, after: $comments) @include(if: $withComments) {
+        pageInfo { hasNextPage endCursor }
+        nodes { databaseId author { login } body createdAt }
+      }
+      timelineItems(first: 100, after: $refs, itemTypes: [CROSS_REFERENCED_EVENT]) @include(if: $withRefs) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          ... on CrossReferencedEvent {
+            source {
+              __typename
+              ... on Issue { number title url state body createdAt updatedAt }
+              ... on PullRequest {
+                id number title url state body createdAt updatedAt isDraft
+                commits(last: 1) { nodes { commit { statusCheckRollup { state } } } }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type graphPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type graphIssue struct {
+	TypeName  string    `json:"__typename"`
+	ID        string    `json:"id"`
+	IsDraft   bool      `json:"isDraft"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	State     string    `json:"state"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Labels    struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State string `json:"state"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+func (g *graphIssue) toIssue() Issue {
+	issue := Issue{
+		Number:      g.Number,
+		Title:       g.Title,
+		URL:         g.URL,
+		State:       strings.ToLower(g.State),
+		Body:        g.Body,
+		CreatedAt:   g.CreatedAt,
+		UpdatedAt:   g.UpdatedAt,
+		PullRequest: g.TypeName == "PullRequest",
+	}
+	for _, label := range g.Labels.Nodes {
+		issue.Labels = append(issue.Labels, label.Name)
+	}
+	return issue
+}
+
+// GetIssueGraph fetches an issue with all its comments and references using
+// the GraphQL API: one query for up to 100 of each, instead of one REST
+// call per page plus one per referencing item
+func (c *Client) GetIssueGraph(number int) (*IssueGraph, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	vars := map[string]interface{}{
+		"owner":        c.owner,
+		"repo":         c.repo,
+		"number":       number,
+		"withComments": true,
+		"withRefs":     true,
+	}
+
+	graph := &IssueGraph{}
+	seen := map[string]bool{}
+	for first := true; ; first = false {
+		var data struct {
+			Repository struct {
+				Issue *struct {
+					graphIssue
+					Comments struct {
+						PageInfo graphPageInfo `json:"pageInfo"`
+						Nodes    []struct {
+							DatabaseID int64 `json:"databaseId"`
+							Author     *struct {
+								Login string `json:"login"`
+							} `json:"author"`
+							Body      string    `json:"body"`
+							CreatedAt time.Time `json:"createdAt"`
+						} `json:"nodes"`
+					} `json:"comments"`
+					TimelineItems struct {
+						PageInfo graphPageInfo `json:"pageInfo"`
+						Nodes    []struct {
+							Source *graphIssue `json:"source"`
+						} `json:"nodes"`
+					} `json:"timelineItems"`
+				} `json:"issue"`
+			} `json:"repository"`
+		}
+		if err := c.graphql(ctx, issueGraphQuery, vars, &data); err != nil {
+			return nil, fmt.Errorf("failed to get issue #%d: %w", number, err)
+		}
+		issue := data.Repository.Issue
+		if issue == nil {
+			return nil, fmt.Errorf("failed to get issue #%d: not found", number)
+		}
+
+		if first {
+			graph.Issue = issue.toIssue()
+		}
+		if vars["withComments"] == true {
+			for _, n := range issue.Comments.Nodes {
+				comment := Comment{ID: n.DatabaseID, Body: n.Body, CreatedAt: n.CreatedAt}
+				if n.Author != nil {
+					comment.Author = n.Author.Login
+				}
+				graph.Comments = append(graph.Comments, comment)
+			}
+			vars["withComments"] = issue.Comments.PageInfo.HasNextPage
+			vars["comments"] = issue.Comments.PageInfo.EndCursor
+		}
+		if vars["withRefs"] == true {
+			for _, n := range issue.TimelineItems.Nodes {
+				// An item referencing the issue twice is listed once
+				if n.Source == nil || n.Source.Number == 0 || seen[n.Source.URL] {
+					continue
+				}
+				seen[n.Source.URL] = true
+				ref := Reference{Issue: n.Source.toIssue(), NodeID: n.Source.ID, Draft: n.Source.IsDraft}
+				if commits := n.Source.Commits.Nodes; len(commits) > 0 && commits[0].Commit.StatusCheckRollup != nil {
+					ref.Checks = strings.ToLower(commits[0].Commit.StatusCheckRollup.State)
+				}
+				graph.References = append(graph.References, ref)
+			}
+			vars["withRefs"] = issue.TimelineItems.PageInfo.HasNextPage
+			vars["refs"] = issue.TimelineItems.PageInfo.EndCursor
+		}
+
+		if vars["withComments"] == false && vars["withRefs"] == false {
+			return graph, nil
+		}
+	}
+}
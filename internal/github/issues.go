@@ -0,0 +1,341 @@
+package github
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// Comment represents an issue comment
+type Comment struct {
+	ID        int64
+	Author    string
+	Body      string
+	CreatedAt time.Time
+	// Issue is the issue or pull request commented on, set by
+	// ListCommentsSince
+	Issue int
+}
+
+// ListIssueComments returns all comments on an issue in chronological order
+func (c *Client) ListIssueComments(issueNumber int) ([]Comment, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	var result []Comment
+	for {
+		comments, resp, err := c.client.Issues.ListComments(ctx, c.owner, c.repo, issueNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issue comments: %w", err)
+		}
+
+		for _, comment := range comments {
+			result = append(result, Comment{
+				ID:        comment.GetID(),
+				Author:    comment.GetUser().GetLogin(),
+				Body:      comment.GetBody(),
+				CreatedAt: comment.GetCreatedAt().Time,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// ListCommentsSince returns the comments on every issue and pull request of
+// the repository created or edited at or after since, oldest first
+func (c *Client) ListCommentsSince(since time.Time) ([]Comment, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	opts := &github.IssueListCommentsOptions{
+		Sort:        github.String("updated"),
+		Direction:   github.String("asc"),
+		Since:       &since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var result []Comment
+	for {
+		comments, resp, err := c.client.Issues.ListComments(ctx, c.owner, c.repo, 0, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments: %w", err)
+		}
+
+		for _, comment := range comments {
+			// The issue URL ends with the issue number
+			url := comment.GetIssueURL()
+			number, _ := strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+			result = append(result, Comment{
+				ID:        comment.GetID(),
+				Author:    comment.GetUser().GetLogin(),
+				Body:      comment.GetBody(),
+				CreatedAt: comment.GetCreatedAt().Time,
+				Issue:     number,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// CreateComment posts a comment on an issue
+func (c *Client) CreateComment(issueNumber int, body string) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	comment := &github.IssueComment{Body: &body}
+	if _, _, err := c.client.Issues.CreateComment(ctx, c.owner, c.repo, issueNumber, comment); err != nil {
+		return fmt.Errorf("failed to create comment: %w", err)
+	}
+	return nil
+}
+
+// Reaction represents a reaction on an issue comment
+type Reaction struct {
+	User    string
+	Content string
+}
+
+// ListCommentReactions returns all reactions on an issue comment
+func (c *Client) ListCommentReactions(commentID int64) ([]Reaction, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	var result []Reaction
+	for {
+		reactions, resp, err := c.client.Reactions.ListIssueCommentReactions(ctx, c.owner, c.repo, commentID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comment reactions: %w", err)
+		}
+
+		for _, reaction := range reactions {
+			result = append(result, Reaction{
+				User:    reaction.GetUser().GetLogin(),
+				Content: reaction.GetContent(),
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// ListIssues returns issues with a label in a state (open, closed or all),
+// most recently updated first. Pull requests are skipped.
+func (c *Client) ListIssues(label, state string) ([]Issue, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	opts := &github.IssueListByRepoOptions{
+		State:       state,
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	if label != "" {
+		opts.Labels = []string{label}
+	}
+
+	var result []Issue
+	for {
+		issues, resp, err := c.client.Issues.ListByRepo(ctx, c.owner, c.repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+
+		for _, issue := range issues {
+			if issue.IsPullRequest() {
+				continue
+			}
+			var labels []string
+			for _, l := range issue.Labels {
+				labels = append(labels, l.GetName())
+			}
+			result = append(result, Issue{
+				Number:    issue.GetNumber(),
+				Title:     issue.GetTitle(),
+				URL:       issue.GetHTMLURL(),
+				State:     issue.GetState(),
+				Labels:    labels,
+				UpdatedAt: issue.GetUpdatedAt().Time,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// GetIssue returns an issue or pull request by number
+func (c *Client) GetIssue(number int) (*Issue, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	issue, _, err := c.client.Issues.Get(ctx, c.owner, c.repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue #%d: %w", number, err)
+	}
+	result := toIssue(issue)
+	return &result, nil
+}
+
+// ListCreatedSince returns issues and pull requests created at or after since,
+// newest first
+func (c *Client) ListCreatedSince(since time.Time) ([]Issue, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	opts := &github.IssueListByRepoOptions{
+		State:       "all",
+		Sort:        "created",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var result []Issue
+	for {
+		issues, resp, err := c.client.Issues.ListByRepo(ctx, c.owner, c.repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+
+		for _, issue := range issues {
+			if issue.GetCreatedAt().Time.Before(since) {
+				return result, nil
+			}
+			result = append(result, toIssue(issue))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// SearchIssues returns the issues and pull requests of the repository whose
+// title or body contains text, newest first (at most 100)
+func (c *Client) SearchIssues(text string) ([]Issue, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	query := fmt.Sprintf("%q repo:%s/%s in:title,body", text, c.owner, c.repo)
+	opts := &github.SearchOptions{Sort: "created", Order: "desc", ListOptions: github.ListOptions{PerPage: 100}}
+	found, _, err := c.client.Search.Issues(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	result := make([]Issue, 0, len(found.Issues))
+	for _, issue := range found.Issues {
+		result = append(result, toIssue(issue))
+	}
+	return result, nil
+}
+
+func toIssue(issue *github.Issue) Issue {
+	var labels []string
+	for _, l := range issue.Labels {
+		labels = append(labels, l.GetName())
+	}
+	return Issue{
+		Number:      issue.GetNumber(),
+		Title:       issue.GetTitle(),
+		URL:         issue.GetHTMLURL(),
+		State:       issue.GetState(),
+		Labels:      labels,
+		UpdatedAt:   issue.GetUpdatedAt().Time,
+		Body:        issue.GetBody(),
+		CreatedAt:   issue.GetCreatedAt().Time,
+		PullRequest: issue.IsPullRequest(),
+	}
+}
+
+// CloseIssue closes an issue
+func (c *Client) CloseIssue(issueNumber int) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	state := "closed"
+	if _, _, err := c.client.Issues.Edit(ctx, c.owner, c.repo, issueNumber, &github.IssueRequest{State: &state}); err != nil {
+		return fmt.Errorf("failed to close issue #%d: %w", issueNumber, err)
+	}
+	return nil
+}
+
+// UpdateIssueBody replaces the body of an issue
+func (c *Client) UpdateIssueBody(issueNumber int, body string) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	if _, _, err := c.client.Issues.Edit(ctx, c.owner, c.repo, issueNumber, &github.IssueRequest{Body: &body}); err != nil {
+		return fmt.Errorf("failed to update issue #%d: %w", issueNumber, err)
+	}
+	return nil
+}
+
+// AddLabels adds labels to an issue
+func (c *Client) AddLabels(issueNumber int, labels ...string) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	if _, _, err := c.client.Issues.AddLabelsToIssue(ctx, c.owner, c.repo, issueNumber, labels); err != nil {
+		return fmt.Errorf("failed to label issue #%d: %w", issueNumber, err)
+	}
+	return nil
+}
+
+// RemoveLabel removes a label from an issue
+func (c *Client) RemoveLabel(issueNumber int, label string) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	if _, err := c.client.Issues.RemoveLabelForIssue(ctx, c.owner, c.repo, issueNumber, label); err != nil {
+		return fmt.Errorf("failed to remove label %s from issue #%d: %w", label, issueNumber, err)
+	}
+	return nil
+}
+
+// UpdateComment replaces the body of an issue comment
+func (c *Client) UpdateComment(commentID int64, body string) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	comment := &github.IssueComment{Body: &body}
+	if _, _, err := c.client.Issues.EditComment(ctx, c.owner, c.repo, commentID, comment); err != nil {
+		return fmt.Errorf("failed to update comment: %w", err)
+	}
+	return nil
+}
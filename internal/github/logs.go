@@ -2,27 +2,40 @@ package github
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
+
+	"github.com/google/go-github/v56/github"
 )
 
 // GetWorkflowLogs gets logs for a workflow run
 func (c *Client) GetWorkflowLogs(runID int64) (string, error) {
+	ctx, cancel := c.call(c.timeouts.Logs)
+	defer cancel()
+
 	// Get logs URL (followRedirects: 1 = true, 0 = false)
-	url, _, err := c.client.Actions.GetWorkflowRunLogs(c.ctx, c.owner, c.repo, runID, 1)
+	url, _, err := c.client.Actions.GetWorkflowRunLogs(ctx, c.owner, c.repo, runID, 1)
 	if err != nil {
 		return "", fmt.Errorf("failed to get logs URL: %w", err)
 	}
 
-	// Download logs
-	req, err := http.NewRequest("GET", url.String(), nil)
+	return downloadLogs(ctx, url.String())
+}
+
+// downloadLogs fetches log content from a signed logs URL
+func downloadLogs(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", err
 	}
 
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -53,12 +66,101 @@ func (c *Client) StreamWorkflowLogs(runID int64, callback func(string)) error {
 	return nil
 }
 
+// RateLimitWait returns how long to wait before retrying a request that
+// failed because of a primary or secondary rate limit
+func RateLimitWait(err error) (time.Duration, bool) {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return time.Until(rateErr.Rate.Reset.Time), true
+	}
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter, true
+		}
+		return time.Minute, true
+	}
+	return 0, false
+}
+
 // GetJobLogs gets logs for a specific job
 func (c *Client) GetJobLogs(jobID int64) (string, error) {
-	logs, _, err := c.client.Actions.GetWorkflowJobLogs(c.ctx, c.owner, c.repo, jobID, 1)
+	ctx, cancel := c.call(c.timeouts.Logs)
+	defer cancel()
+
+	url, _, err := c.client.Actions.GetWorkflowJobLogs(ctx, c.owner, c.repo, jobID, 1)
 	if err != nil {
 		return "", fmt.Errorf("failed to get job logs: %w", err)
 	}
 
-	return logs.String(), nil
+	return downloadLogs(ctx, url.String())
+}
+
+// GetJobLogTail gets at most the last maxBytes of a job's logs, starting at
+// a line. Only the tail is downloaded where the log storage honors range
+// requests, as GitHub's does.
+func (c *Client) GetJobLogTail(jobID int64, maxBytes int64) (string, error) {
+	ctx, cancel := c.call(c.timeouts.Logs)
+	defer cancel()
+
+	url, _, err := c.client.Actions.GetWorkflowJobLogs(ctx, c.owner, c.repo, jobID, 1)
+	if err != nil {
+		return "", fmt.Errorf("failed to get job logs: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=-%d", maxBytes))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var tail []byte
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		tail, err = io.ReadAll(io.LimitReader(resp.Body, maxBytes))
+	case http.StatusOK:
+		// The whole log came back; keep only its end
+		tail, err = readTail(resp.Body, maxBytes)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// An empty log has no bytes to range over
+		return "", nil
+	default:
+		return "", fmt.Errorf("failed to download job logs: %s", resp.Status)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// A tail shorter than the log starts mid-line
+	if int64(len(tail)) == maxBytes {
+		if i := bytes.IndexByte(tail, '\n'); i >= 0 {
+			tail = tail[i+1:]
+		}
+	}
+	return string(tail), nil
+}
+
+// readTail reads r to the end and returns its last max bytes
+func readTail(r io.Reader, max int64) ([]byte, error) {
+	buf := make([]byte, 0, 2*max)
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if int64(len(buf)) > max {
+			buf = append(buf[:0], buf[int64(len(buf))-max:]...)
+		}
+		if err == io.EOF {
+			return buf, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
 }
@@ -0,0 +1,35 @@
+package github
+
+import "fmt"
+
+// IsTeamMember reports whether user is an active member of the org's team
+// with the given slug. Reading memberships needs the read:org scope.
+func (c *Client) IsTeamMember(org, slug, user string) (bool, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	membership, _, err := c.client.Teams.GetTeamMembershipBySlug(ctx, org, slug, user)
+	if isNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check membership of %s in @%s/%s: %w", user, org, slug, err)
+	}
+	return membership.GetState() == "active", nil
+}
+
+// PermissionLevel returns user's permission on the repository: admin,
+// write, read or none
+func (c *Client) PermissionLevel(user string) (string, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	level, _, err := c.client.Repositories.GetPermissionLevel(ctx, c.owner, c.repo, user)
+	if isNotFound(err) {
+		return "none", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get permission of %s: %w", user, err)
+	}
+	return level.GetPermission(), nil
+}
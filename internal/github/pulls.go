@@ -0,0 +1,35 @@
+package github
+
+import "fmt"
+
+// MarkReadyForReview takes a draft pull request out of draft. The REST API
+// cannot change draft status, so this uses GraphQL with the PR's node ID.
+func (c *Client) MarkReadyForReview(nodeID string) error {
+	const mutation = `mutation($id: ID!) {
+  markPullRequestReadyForReview(input: {pullRequestId: $id}) { pullRequest { number } }
+}`
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	var data struct{}
+	if err := c.graphql(ctx, mutation, map[string]interface{}{"id": nodeID}, &data); err != nil {
+		return fmt.Errorf("failed to mark pull request ready for review: %w", err)
+	}
+	return nil
+}
+
+// EnablePullRequestAutoMerge squash-merges a pull request once its required
+// checks and reviews pass. GitHub refuses it for drafts.
+func (c *Client) EnablePullRequestAutoMerge(nodeID string) error {
+	const mutation = `mutation($id: ID!) {
+  enablePullRequestAutoMerge(input: {pullRequestId: $id, mergeMethod: SQUASH}) { pullRequest { number } }
+}`
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	var data struct{}
+	if err := c.graphql(ctx, mutation, map[string]interface{}{"id": nodeID}, &data); err != nil {
+		return fmt.Errorf("failed to enable auto-merge: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// ErrUnreachable is returned when GitHub cannot be reached over the network
+var ErrUnreachable = errors.New("GitHub is unreachable")
+
+// ErrUnauthorized is returned when GitHub rejects the token
+var ErrUnauthorized = errors.New("GitHub rejected the token")
+
+// Ping checks that GitHub is reachable and accepts the token. The rate limit
+// endpoint is used because it does not count against the rate limit.
+func (c *Client) Ping() error {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	_, _, err := c.client.RateLimits(ctx)
+	if err == nil {
+		return nil
+	}
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+	if IsUnreachable(err) {
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	}
+	return err
+}
+
+// IsUnreachable reports whether an error is a network failure rather than a
+// response from GitHub
+func IsUnreachable(err error) bool {
+	if errors.Is(err, ErrUnreachable) {
+		return true
+	}
+	var netErr net.Error
+	var urlErr *url.Error
+	return errors.As(err, &netErr) || errors.As(err, &urlErr)
+}
+
+// IsOutage reports whether an error means GitHub is unavailable: it cannot
+// be reached or answers with a server error
+func IsOutage(err error) bool {
+	if IsUnreachable(err) {
+		return true
+	}
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,165 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// Repository represents a GitHub repository
+type Repository struct {
+	Owner         string
+	Name          string
+	URL           string
+	CloneURL      string
+	DefaultBranch string
+	// AllowAutoMerge is set by GetRepository
+	AllowAutoMerge bool
+}
+
+// CreateRepository creates a repository for the authenticated user, or in
+// org when it is non-empty. The repository is initialized with a README so
+// files can be committed through the Git Data API right away.
+func (c *Client) CreateRepository(name, description string, private bool, org string) (*Repository, error) {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	repo, _, err := c.client.Repositories.Create(ctx, org, &github.Repository{
+		Name:        github.String(name),
+		Description: github.String(description),
+		Private:     github.Bool(private),
+		AutoInit:    github.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create repository %s: %w", name, err)
+	}
+
+	return &Repository{
+		Owner:         repo.GetOwner().GetLogin(),
+		Name:          repo.GetName(),
+		URL:           repo.GetHTMLURL(),
+		CloneURL:      repo.GetCloneURL(),
+		DefaultBranch: repo.GetDefaultBranch(),
+	}, nil
+}
+
+// GetRepository returns the client's repository
+func (c *Client) GetRepository() (*Repository, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	repo, _, err := c.client.Repositories.Get(ctx, c.owner, c.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	return &Repository{
+		Owner:          repo.GetOwner().GetLogin(),
+		Name:           repo.GetName(),
+		URL:            repo.GetHTMLURL(),
+		CloneURL:       repo.GetCloneURL(),
+		DefaultBranch:  repo.GetDefaultBranch(),
+		AllowAutoMerge: repo.GetAllowAutoMerge(),
+	}, nil
+}
+
+// GetTemplateRepository returns the template this repository was generated
+// from, or nil if it was not created from a template
+func (c *Client) GetTemplateRepository() (*Repository, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	repo, _, err := c.client.Repositories.Get(ctx, c.owner, c.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	tmpl := repo.GetTemplateRepository()
+	if tmpl == nil {
+		return nil, nil
+	}
+	return &Repository{
+		Owner:         tmpl.GetOwner().GetLogin(),
+		Name:          tmpl.GetName(),
+		URL:           tmpl.GetHTMLURL(),
+		CloneURL:      tmpl.GetCloneURL(),
+		DefaultBranch: tmpl.GetDefaultBranch(),
+	}, nil
+}
+
+// Variable is a repository Actions variable
+type Variable struct {
+	Name  string
+	Value string
+}
+
+// ListRepoVariables returns the Actions variables of a repository
+func (c *Client) ListRepoVariables(owner, repo string) ([]Variable, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	opts := &github.ListOptions{PerPage: 30}
+
+	var result []Variable
+	for {
+		vars, resp, err := c.client.Actions.ListRepoVariables(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list variables of %s/%s: %w", owner, repo, err)
+		}
+		for _, v := range vars.Variables {
+			result = append(result, Variable{Name: v.Name, Value: v.Value})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// SetRepoVariable creates or updates an Actions variable of this repository
+func (c *Client) SetRepoVariable(name, value string) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	variable := &github.ActionsVariable{Name: name, Value: value}
+
+	_, err := c.client.Actions.CreateRepoVariable(ctx, c.owner, c.repo, variable)
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusConflict {
+		_, err = c.client.Actions.UpdateRepoVariable(ctx, c.owner, c.repo, variable)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set variable %s: %w", name, err)
+	}
+	return nil
+}
+
+// ListRepoSecretNames returns the names of a repository's Actions secrets;
+// values cannot be read
+func (c *Client) ListRepoSecretNames(owner, repo string) ([]string, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	opts := &github.ListOptions{PerPage: 30}
+
+	var result []string
+	for {
+		secrets, resp, err := c.client.Actions.ListRepoSecrets(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets of %s/%s: %w", owner, repo, err)
+		}
+		for _, s := range secrets.Secrets {
+			result = append(result, s.Name)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
@@ -0,0 +1,127 @@
+package github
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// Runner represents a self-hosted runner registered to the repository
+type Runner struct {
+	ID     int64
+	Name   string
+	OS     string
+	Status string
+	Busy   bool
+	Labels []string
+}
+
+// HasLabels reports whether the runner carries every given label
+func (r *Runner) HasLabels(labels []string) bool {
+	have := make(map[string]bool, len(r.Labels))
+	for _, label := range r.Labels {
+		have[label] = true
+	}
+	for _, label := range labels {
+		if !have[label] {
+			return false
+		}
+	}
+	return true
+}
+
+// RunnerDownload is a downloadable runner application package
+type RunnerDownload struct {
+	OS           string
+	Architecture string
+	URL          string
+	Filename     string
+	SHA256       string
+}
+
+// RegistrationToken is a short-lived token used to register a runner
+type RegistrationToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// ListRunners lists the repository's self-hosted runners
+func (c *Client) ListRunners() ([]Runner, error) {
+	ctx, cancel := c.call(c.timeouts.List)
+	defer cancel()
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	var result []Runner
+	for {
+		runners, resp, err := c.client.Actions.ListRunners(ctx, c.owner, c.repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list runners: %w", err)
+		}
+
+		for _, runner := range runners.Runners {
+			labels := make([]string, 0, len(runner.Labels))
+			for _, label := range runner.Labels {
+				labels = append(labels, label.GetName())
+			}
+			result = append(result, Runner{
+				ID:     runner.GetID(),
+				Name:   runner.GetName(),
+				OS:     runner.GetOS(),
+				Status: runner.GetStatus(),
+				Busy:   runner.GetBusy(),
+				Labels: labels,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// CreateRunnerRegistrationToken creates a token for registering a runner
+func (c *Client) CreateRunnerRegistrationToken() (*RegistrationToken, error) {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	token, _, err := c.client.Actions.CreateRegistrationToken(ctx, c.owner, c.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runner registration token: %w", err)
+	}
+
+	return &RegistrationToken{
+		Token:     token.GetToken(),
+		ExpiresAt: token.GetExpiresAt().Time,
+	}, nil
+}
+
+// GetRunnerDownload finds the runner package for an OS ("linux", "osx",
+// "win") and architecture ("x64", "arm64", "arm")
+func (c *Client) GetRunnerDownload(os, arch string) (*RunnerDownload, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	downloads, _, err := c.client.Actions.ListRunnerApplicationDownloads(ctx, c.owner, c.repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runner downloads: %w", err)
+	}
+
+	for _, d := range downloads {
+		if d.GetOS() == os && d.GetArchitecture() == arch {
+			return &RunnerDownload{
+				OS:           d.GetOS(),
+				Architecture: d.GetArchitecture(),
+				URL:          d.GetDownloadURL(),
+				Filename:     d.GetFilename(),
+				SHA256:       d.GetSHA256Checksum(),
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no runner package for %s/%s", os, arch)
+}
@@ -0,0 +1,110 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// StatusContext is the commit status context used for autonomous runs
+const StatusContext = "autonomous-dev"
+
+// OverlapContext is the commit status context that blocks merging an
+// instance branch duplicating another instance's changes
+const OverlapContext = "autonomous-dev/overlap"
+
+// CommitState is the state of a commit status
+type CommitState string
+
+const (
+	StatePending CommitState = "pending"
+	StateSuccess CommitState = "success"
+	StateFailure CommitState = "failure"
+	StateError   CommitState = "error"
+)
+
+// GetBranchSHA returns the head commit SHA of a branch
+func (c *Client) GetBranchSHA(branch string) (string, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	b, _, err := c.client.Repositories.GetBranch(ctx, c.owner, c.repo, branch, 1)
+	if err != nil {
+		return "", fmt.Errorf("failed to get branch %s: %w", branch, err)
+	}
+	return b.GetCommit().GetSHA(), nil
+}
+
+// ResolveCommit expands a commit SHA, which may be abbreviated, to the full SHA
+func (c *Client) ResolveCommit(sha string) (string, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	full, _, err := c.client.Repositories.GetCommitSHA1(ctx, c.owner, c.repo, sha, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve commit %s: %w", sha, err)
+	}
+	return full, nil
+}
+
+// SetCommitStatus creates or updates the autonomous-dev status on a commit
+func (c *Client) SetCommitStatus(sha string, state CommitState, description, targetURL string) error {
+	return c.setStatus(sha, StatusContext, state, description, targetURL)
+}
+
+// GetOverlapStatus returns the state and description of the
+// autonomous-dev/overlap status on a commit; the state is empty if none
+func (c *Client) GetOverlapStatus(sha string) (CommitState, string, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	combined, _, err := c.client.Repositories.GetCombinedStatus(ctx, c.owner, c.repo, sha, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get commit status: %w", err)
+	}
+	for _, status := range combined.Statuses {
+		if status.GetContext() == OverlapContext {
+			return CommitState(status.GetState()), status.GetDescription(), nil
+		}
+	}
+	return "", "", nil
+}
+
+// SetOverlapStatus creates or updates the autonomous-dev/overlap status on a commit
+func (c *Client) SetOverlapStatus(sha string, state CommitState, description, targetURL string) error {
+	return c.setStatus(sha, OverlapContext, state, description, targetURL)
+}
+
+func (c *Client) setStatus(sha, context string, state CommitState, description, targetURL string) error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	status := &github.RepoStatus{
+		State:       github.String(string(state)),
+		Description: github.String(description),
+		Context:     github.String(context),
+	}
+	if targetURL != "" {
+		status.TargetURL = github.String(targetURL)
+	}
+
+	if _, _, err := c.client.Repositories.CreateStatus(ctx, c.owner, c.repo, sha, status); err != nil {
+		return fmt.Errorf("failed to set commit status: %w", err)
+	}
+	return nil
+}
+
+// RunCommitState maps a workflow run status and conclusion to a commit state
+func RunCommitState(status, conclusion string) CommitState {
+	if status != "completed" {
+		return StatePending
+	}
+	switch conclusion {
+	case "success":
+		return StateSuccess
+	case "failure", "timed_out":
+		return StateFailure
+	default:
+		return StateError
+	}
+}
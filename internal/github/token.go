@@ -0,0 +1,169 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v56/github"
+)
+
+// TokenKind identifies the type of GitHub token in use
+type TokenKind string
+
+const (
+	TokenClassic     TokenKind = "classic"
+	TokenFineGrained TokenKind = "fine-grained"
+	TokenOAuth       TokenKind = "oauth"
+	TokenApp         TokenKind = "app"
+	TokenUnknown     TokenKind = "unknown"
+)
+
+// expirationHeader is sent by GitHub for tokens that have an expiration date
+const expirationHeader = "GitHub-Authentication-Token-Expiration"
+
+// ErrActionsWritePermission is returned when the token cannot dispatch workflows
+var ErrActionsWritePermission = errors.New("token lacks Actions write permission")
+
+// TokenInfo describes the token used by the client
+type TokenInfo struct {
+	Kind      TokenKind
+	Login     string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// ExpiresWithin reports whether the token expires within the given duration
+func (t *TokenInfo) ExpiresWithin(d time.Duration) bool {
+	if t.ExpiresAt == nil {
+		return false
+	}
+	return time.Until(*t.ExpiresAt) < d
+}
+
+// DetectTokenKind returns the token kind based on its prefix
+func DetectTokenKind(token string) TokenKind {
+	switch {
+	case strings.HasPrefix(token, "github_pat_"):
+		return TokenFineGrained
+	case strings.HasPrefix(token, "ghp_"):
+		return TokenClassic
+	case strings.HasPrefix(token, "gho_"), strings.HasPrefix(token, "ghu_"):
+		return TokenOAuth
+	case strings.HasPrefix(token, "ghs_"):
+		return TokenApp
+	case len(token) == 40:
+		// Legacy classic tokens are 40 hex characters without a prefix
+		return TokenClassic
+	default:
+		return TokenUnknown
+	}
+}
+
+// GetTokenInfo queries GitHub for details about the configured token
+func (c *Client) GetTokenInfo() (*TokenInfo, error) {
+	ctx, cancel := c.call(c.timeouts.Read)
+	defer cancel()
+
+	user, resp, err := c.client.Users.Get(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+
+	info := &TokenInfo{
+		Kind:  DetectTokenKind(c.token),
+		Login: user.GetLogin(),
+	}
+
+	if scopes := resp.Header.Get("X-OAuth-Scopes"); scopes != "" {
+		for _, scope := range strings.Split(scopes, ",") {
+			info.Scopes = append(info.Scopes, strings.TrimSpace(scope))
+		}
+	}
+
+	if expiration := resp.Header.Get(expirationHeader); expiration != "" {
+		expiresAt, err := parseTokenExpiration(expiration)
+		if err != nil {
+			return nil, err
+		}
+		info.ExpiresAt = &expiresAt
+	}
+
+	return info, nil
+}
+
+// Login returns the login of the token's user, looked up once per client
+func (c *Client) Login() (string, error) {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	if c.login == "" {
+		info, err := c.GetTokenInfo()
+		if err != nil {
+			return "", err
+		}
+		c.login = info.Login
+	}
+	return c.login, nil
+}
+
+// probeRef cannot name a ref ('..' is not allowed in ref names), so a
+// dispatch to it never starts a run
+const probeRef = "autonomous-dev..preflight"
+
+// CheckActionsWrite verifies, without changing anything, that the token may
+// dispatch the workflow. It dispatches the workflow to a ref that cannot
+// exist: GitHub checks what the token was granted first and refuses with 403
+// without actions=write, and otherwise with 422 for the missing ref. Unlike
+// the user's push access, this holds for fine-grained and app tokens too.
+func (c *Client) CheckActionsWrite() error {
+	ctx, cancel := c.call(c.timeouts.Dispatch)
+	defer cancel()
+
+	_, err := c.client.Actions.CreateWorkflowDispatchEventByFileName(ctx, c.owner, c.repo, c.workflowFile,
+		github.CreateWorkflowDispatchEventRequest{Ref: probeRef})
+	var errResp *github.ErrorResponse
+	if err == nil || errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusUnprocessableEntity {
+		return nil
+	}
+	return c.wrapPermissionError(err)
+}
+
+// wrapPermissionError converts 403 responses into a descriptive error
+func (c *Client) wrapPermissionError(err error) error {
+	var errResp *github.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil || errResp.Response.StatusCode != http.StatusForbidden {
+		return err
+	}
+
+	required := errResp.Response.Header.Get("X-Accepted-GitHub-Permissions")
+	if required == "" {
+		required = "actions=write"
+	}
+	return c.permissionError(required)
+}
+
+// permissionError describes how to grant the token the required permission
+func (c *Client) permissionError(required string) error {
+	if DetectTokenKind(c.token) == TokenFineGrained {
+		return fmt.Errorf("%w: fine-grained token needs %q on %s/%s (edit the token at https://github.com/settings/tokens?type=beta)",
+			ErrActionsWritePermission, required, c.owner, c.repo)
+	}
+	return fmt.Errorf("%w: token needs %q (classic tokens require the 'repo' and 'workflow' scopes)", ErrActionsWritePermission, required)
+}
+
+func parseTokenExpiration(value string) (time.Time, error) {
+	layouts := []string{
+		"2006-01-02 15:04:05 MST",
+		"2006-01-02 15:04:05 -0700",
+		time.RFC3339,
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse token expiration %q", value)
+}
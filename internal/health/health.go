@@ -0,0 +1,152 @@
+package health
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/logstream"
+)
+
+// Level is the traffic-light classification of a score
+type Level string
+
+const (
+	Green  Level = "green"
+	Yellow Level = "yellow"
+	Red    Level = "red"
+)
+
+// Thresholds used to classify scores
+const (
+	greenThreshold  = 80
+	yellowThreshold = 50
+)
+
+// staleAfter is the heartbeat gap after which an instance is considered stale
+const staleAfter = 5 * time.Minute
+
+// errorPattern matches log lines that indicate an error
+var errorPattern = regexp.MustCompile(`(?i)(^|[^a-z])(error|fatal|panic|fail(ed|ure)?)([^a-z]|$)`)
+
+// Signals are the observations a health score is computed from
+type Signals struct {
+	// Progress is the last reported progress percentage, or an inferred one
+	Progress int
+	// Running is false once the instance has finished
+	Running bool
+	// StartedAt is when the instance started working
+	StartedAt time.Time
+	// ErrorCount is the number of error lines found in the instance logs
+	ErrorCount int
+	// RetryCount is the number of times the instance job was re-run
+	RetryCount int
+	// Heartbeats are the times of reported status updates
+	Heartbeats []time.Time
+	// CheckHeartbeats enables heartbeat evaluation when reports were read
+	CheckHeartbeats bool
+}
+
+// Score is the computed health of an instance
+type Score struct {
+	Value   int
+	Level   Level
+	Reasons []string
+}
+
+// CountErrors returns the number of log lines that look like errors:
+// structured lines logged at error level, and other lines mentioning an error
+func CountErrors(logs string) int {
+	count := 0
+	for _, line := range strings.Split(logs, "\n") {
+		if e, ok := logstream.ParseEntry(line); ok {
+			if e.AtLeast(logstream.LevelError) {
+				count++
+			}
+			continue
+		}
+		if errorPattern.MatchString(line) {
+			count++
+		}
+	}
+	return count
+}
+
+// Compute scores an instance from 0 (needs intervention) to 100 (healthy)
+func Compute(s Signals, now time.Time) Score {
+	score := Score{Value: 100}
+	penalize := func(points int, reason string) {
+		score.Value -= points
+		score.Reasons = append(score.Reasons, reason)
+	}
+
+	// Progress rate: expect some progress after the first 15 minutes
+	if s.Running && !s.StartedAt.IsZero() {
+		elapsed := now.Sub(s.StartedAt)
+		if elapsed > 15*time.Minute && s.Progress == 0 {
+			penalize(30, fmt.Sprintf("no progress after %s", elapsed.Round(time.Minute)))
+		} else if elapsed > 60*time.Minute && s.Progress < 50 {
+			penalize(15, fmt.Sprintf("slow progress (%d%% after %s)", s.Progress, elapsed.Round(time.Minute)))
+		}
+	}
+
+	if s.ErrorCount > 0 {
+		penalize(min(s.ErrorCount*5, 30), fmt.Sprintf("%d error(s) in logs", s.ErrorCount))
+	}
+
+	if s.RetryCount > 0 {
+		penalize(min(s.RetryCount*15, 30), fmt.Sprintf("re-run %d time(s)", s.RetryCount))
+	}
+
+	// Heartbeat regularity only matters while the instance is running
+	if s.Running && s.CheckHeartbeats {
+		if len(s.Heartbeats) == 0 {
+			penalize(20, "no heartbeat reported")
+		} else {
+			beats := append([]time.Time(nil), s.Heartbeats...)
+			sort.Slice(beats, func(i, j int) bool { return beats[i].Before(beats[j]) })
+
+			if gap := now.Sub(beats[len(beats)-1]); gap > staleAfter {
+				penalize(30, fmt.Sprintf("last heartbeat %s ago", gap.Round(time.Second)))
+			} else if irregularHeartbeats(beats) {
+				penalize(10, "irregular heartbeats")
+			}
+		}
+	}
+
+	if score.Value < 0 {
+		score.Value = 0
+	}
+	score.Level = classify(score.Value)
+
+	return score
+}
+
+// irregularHeartbeats reports whether the largest gap exceeds three times the median gap
+func irregularHeartbeats(beats []time.Time) bool {
+	if len(beats) < 3 {
+		return false
+	}
+
+	gaps := make([]time.Duration, 0, len(beats)-1)
+	for i := 1; i < len(beats); i++ {
+		gaps = append(gaps, beats[i].Sub(beats[i-1]))
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+
+	median := gaps[len(gaps)/2]
+	return median > 0 && gaps[len(gaps)-1] > 3*median
+}
+
+func classify(value int) Level {
+	switch {
+	case value >= greenThreshold:
+		return Green
+	case value >= yellowThreshold:
+		return Yellow
+	default:
+		return Red
+	}
+}
@@ -0,0 +1,38 @@
+package health
+
+import (
+	"github.com/autonomous-dev/cli/internal/github"
+	"github.com/autonomous-dev/cli/internal/progress"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+)
+
+// LogBytes is how much of the end of a job's log is enough for FromJob to
+// score health and infer progress
+const LogBytes = 64 << 10
+
+// FromJob gathers signals for an instance from its job, logs and the
+// coordination snapshot. snap may be nil when the issue was not read, in
+// which case heartbeat regularity is not evaluated. Without a progress
+// report, progress is inferred from steps and log milestones.
+func FromJob(job github.Job, logs string, snap *protocol.Snapshot) Signals {
+	signals := Signals{
+		Running:    job.Status != "completed",
+		StartedAt:  job.StartedAt,
+		ErrorCount: CountErrors(logs),
+		Progress:   progress.Infer(job, logs).Percent,
+	}
+	if job.Attempt > 1 {
+		signals.RetryCount = job.Attempt - 1
+	}
+
+	if snap != nil {
+		id := job.InstanceNumber()
+		signals.CheckHeartbeats = true
+		signals.Heartbeats = snap.Heartbeats[id]
+		if status, ok := snap.Statuses[id]; ok {
+			signals.Progress = status.CurrentTask.Progress
+		}
+	}
+
+	return signals
+}
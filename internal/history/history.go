@@ -0,0 +1,141 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/journal"
+)
+
+// Record is a single entry in the run history
+type Record struct {
+	Key        string `json:"key"`
+	Event      string `json:"event"`
+	RunID      int64  `json:"run_id,omitempty"`
+	Attempt    int    `json:"attempt,omitempty"`
+	Issue      int    `json:"issue,omitempty"`
+	Task       string `json:"task,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Instance   int    `json:"instance,omitempty"`
+	Conclusion string `json:"conclusion,omitempty"`
+	// Reason explains a run recorded as abandoned or failed
+	Reason string `json:"reason,omitempty"`
+	URL    string `json:"url,omitempty"`
+	// Trace is the trace ID of the record's run
+	Trace string    `json:"trace,omitempty"`
+	At    time.Time `json:"at"`
+}
+
+// Store keeps records in an append-only journal of JSON Lines segments
+// under .autonomous-dev/history/, safe for the CLI and serve to write at the
+// same time and for copies from several machines to be merged
+type Store struct {
+	journal *journal.Journal
+}
+
+// DefaultDir returns the directory of the local history journal
+func DefaultDir() string {
+	return filepath.Join(".autonomous-dev", "history")
+}
+
+// Open returns a store backed by the journal in dir. A history.jsonl file
+// next to it, written before the journal, is moved in on first use.
+func Open(dir string) *Store {
+	return &Store{journal: journal.Open(dir, dir+".jsonl")}
+}
+
+// Append adds a record to the history
+func (s *Store) Append(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history record: %w", err)
+	}
+	if err := s.journal.Append(data); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// List returns all records in the order they happened
+func (s *Store) List() ([]Record, error) {
+	lines, err := s.journal.Lines()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return parse(lines)
+}
+
+// Merge adds the records of other history journals or history.jsonl files,
+// e.g. copied from another machine, that this history does not hold yet.
+// It returns how many records were added.
+func (s *Store) Merge(paths ...string) (int, error) {
+	var lines [][]byte
+	for _, path := range paths {
+		found, err := journal.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+		// Validate before anything is written
+		if _, err := parse(found); err != nil {
+			return 0, fmt.Errorf("%s: %w", path, err)
+		}
+		lines = append(lines, found...)
+	}
+	added, err := s.journal.Import(lines)
+	if err != nil {
+		return 0, fmt.Errorf("failed to merge history: %w", err)
+	}
+	return added, nil
+}
+
+// Compact folds the history's segments into one, in time order, and returns
+// how many segments it replaced
+func (s *Store) Compact() (int, error) {
+	replaced, err := s.journal.Compact(func(a, b []byte) bool {
+		var ra, rb Record
+		json.Unmarshal(a, &ra)
+		json.Unmarshal(b, &rb)
+		return ra.At.Before(rb.At)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to compact history: %w", err)
+	}
+	return replaced, nil
+}
+
+// Segments returns how many segment files the history is kept in
+func (s *Store) Segments() (int, error) {
+	return s.journal.Segments()
+}
+
+// parse decodes records, ordered by time. Segments written on different
+// machines interleave.
+func parse(lines [][]byte) ([]Record, error) {
+	records := make([]Record, 0, len(lines))
+	for _, line := range lines {
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse history: %w", err)
+		}
+		records = append(records, r)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].At.Before(records[j].At) })
+	return records, nil
+}
+
+// Has reports whether a record with the given key exists
+func (s *Store) Has(key string) (bool, error) {
+	records, err := s.List()
+	if err != nil {
+		return false, err
+	}
+	for _, r := range records {
+		if r.Key == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,113 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Hook names, as configured under hooks:
+const (
+	PreStart     = "pre_start"
+	PostComplete = "post_complete"
+)
+
+// Timeout bounds how long a hook may run
+const Timeout = 5 * time.Minute
+
+// envPrefix prefixes the environment variables a hook receives
+const envPrefix = "AUTONOMOUS_DEV_"
+
+// Start is the metadata of a run about to be dispatched
+type Start struct {
+	Hook        string            `json:"hook"`
+	Repository  string            `json:"repository"`
+	Task        string            `json:"task"`
+	Instances   int               `json:"instances"`
+	Agent       string            `json:"agent,omitempty"`
+	Source      string            `json:"source,omitempty"`
+	BaseSHA     string            `json:"base_sha,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Canary      bool              `json:"canary,omitempty"`
+	Trace       string            `json:"trace"`
+	Env         map[string]string `json:"env,omitempty"`
+}
+
+// Complete is the metadata of a finished workflow run
+type Complete struct {
+	Hook       string `json:"hook"`
+	Repository string `json:"repository"`
+	RunID      int64  `json:"run_id"`
+	Attempt    int    `json:"attempt,omitempty"`
+	Issue      int    `json:"issue,omitempty"`
+	Conclusion string `json:"conclusion"`
+	Completed  int    `json:"completed"`
+	Total      int    `json:"total"`
+	RunURL     string `json:"run_url"`
+	HeadSHA    string `json:"head_sha,omitempty"`
+	Trace      string `json:"trace,omitempty"`
+}
+
+// Run executes a hook command through the shell. The metadata is written to
+// its stdin as JSON, and each top-level scalar field is also set as an
+// AUTONOMOUS_DEV_<FIELD> environment variable, e.g. AUTONOMOUS_DEV_TASK.
+// The hook's output goes to the CLI's; a non-zero exit is an error.
+func Run(name, command string, metadata interface{}) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s hook metadata: %w", name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	cmd := shell(ctx, command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), environ(data)...)
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%s hook timed out after %s", name, Timeout)
+		}
+		return fmt.Errorf("%s hook failed: %w", name, err)
+	}
+	return nil
+}
+
+// environ returns the environment variables for JSON-encoded metadata, sorted
+func environ(data []byte) []string {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+
+	var env []string
+	for key, value := range fields {
+		switch v := value.(type) {
+		case string, bool:
+			env = append(env, fmt.Sprintf("%s%s=%v", envPrefix, strings.ToUpper(key), v))
+		case float64:
+			// JSON numbers decode as float64; metadata numbers are integers
+			env = append(env, fmt.Sprintf("%s%s=%d", envPrefix, strings.ToUpper(key), int64(v)))
+		}
+	}
+	sort.Strings(env)
+	return env
+}
+
+// shell runs command with sh, or cmd on Windows
+func shell(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
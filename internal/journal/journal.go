@@ -0,0 +1,437 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	// segmentExt is the extension of segment files
+	segmentExt = ".jsonl"
+	// lockRetry is how often a writer retries a held lock
+	lockRetry = 20 * time.Millisecond
+	// staleLock is the age at which a lock whose holder cannot be checked,
+	// e.g. one on another machine, is broken
+	staleLock = time.Minute
+	// lockTouch is how often a holder refreshes its lock, so it never gets
+	// older than staleLock while held
+	lockTouch = staleLock / 4
+	// lockTimeout is how long a writer waits for the lock; longer than
+	// staleLock, so waiting outlasts any lock a crashed process left
+	lockTimeout = staleLock + 30*time.Second
+)
+
+// Journal is an append-only log of JSON lines kept as segment files in a
+// directory. Each machine appends to its own segment, so directories synced
+// between machines do not conflict, and a lock file serializes the processes
+// of one machine (e.g. the CLI and serve). Lines are returned once even if
+// several segments hold them.
+type Journal struct {
+	dir string
+	// legacy is a single-file log the journal replaces, imported on first use
+	legacy string
+}
+
+// Open returns the journal in dir. legacy names a single-file log written
+// before segments, or "" if there is none; it is moved into the journal the
+// first time the journal is used.
+func Open(dir, legacy string) *Journal {
+	return &Journal{dir: dir, legacy: legacy}
+}
+
+// Append adds a line to this machine's segment
+func (j *Journal) Append(line []byte) error {
+	return j.locked(func() error {
+		return appendLines(j.segment(), [][]byte{line})
+	})
+}
+
+// Lines returns every distinct line of all segments, segment by segment in
+// name order
+func (j *Journal) Lines() ([][]byte, error) {
+	var lines [][]byte
+	err := j.locked(func() error {
+		var err error
+		lines, err = j.read()
+		return err
+	})
+	return lines, err
+}
+
+// Import appends the given lines that the journal does not hold yet and
+// returns how many were added. Records synced from other machines are
+// merged this way.
+func (j *Journal) Import(lines [][]byte) (int, error) {
+	added := 0
+	err := j.locked(func() error {
+		existing, err := j.read()
+		if err != nil {
+			return err
+		}
+		seen := make(map[string]bool, len(existing))
+		for _, line := range existing {
+			seen[string(line)] = true
+		}
+
+		var fresh [][]byte
+		for _, line := range lines {
+			line = bytes.TrimSpace(line)
+			if len(line) == 0 || seen[string(line)] {
+				continue
+			}
+			seen[string(line)] = true
+			fresh = append(fresh, line)
+		}
+		added = len(fresh)
+		if added == 0 {
+			return nil
+		}
+		return appendLines(j.segment(), fresh)
+	})
+	return added, err
+}
+
+// Compact rewrites all segments as this machine's single segment, ordered
+// by less, and returns how many segments it replaced. Duplicate lines are
+// dropped.
+func (j *Journal) Compact(less func(a, b []byte) bool) (int, error) {
+	replaced := 0
+	err := j.locked(func() error {
+		segments, err := j.segments()
+		if err != nil || len(segments) == 0 {
+			return err
+		}
+		lines, err := j.read()
+		if err != nil {
+			return err
+		}
+		if less != nil {
+			sort.SliceStable(lines, func(a, b int) bool { return less(lines[a], lines[b]) })
+		}
+
+		// Replace our segment atomically before removing the others: a
+		// crash in between leaves duplicates, which reads skip, not gaps
+		tmp := filepath.Join(j.dir, ".compact.tmp")
+		os.Remove(tmp)
+		if err := appendLines(tmp, lines); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, j.segment()); err != nil {
+			return fmt.Errorf("failed to replace segment: %w", err)
+		}
+		for _, path := range segments {
+			if path == j.segment() {
+				continue
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove compacted segment: %w", err)
+			}
+		}
+		replaced = len(segments)
+		return nil
+	})
+	return replaced, err
+}
+
+// Segments returns the number of segment files
+func (j *Journal) Segments() (int, error) {
+	segments, err := j.segments()
+	return len(segments), err
+}
+
+// ReadFile returns the lines of a journal directory or single-file log, e.g.
+// one copied from another machine
+func ReadFile(path string) ([][]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return Open(path, "").read()
+	}
+	return readLines(path)
+}
+
+// segment is the file this machine appends to
+func (j *Journal) segment() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "local"
+	}
+	host = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == ' ' {
+			return '-'
+		}
+		return r
+	}, host)
+	return filepath.Join(j.dir, host+segmentExt)
+}
+
+// segments lists the segment files in name order
+func (j *Journal) segments() ([]string, error) {
+	entries, err := os.ReadDir(j.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", j.dir, err)
+	}
+	var paths []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), segmentExt) && !strings.HasPrefix(e.Name(), ".") {
+			paths = append(paths, filepath.Join(j.dir, e.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// read returns the distinct lines of all segments; the lock must be held
+func (j *Journal) read() ([][]byte, error) {
+	segments, err := j.segments()
+	if err != nil {
+		return nil, err
+	}
+	var lines [][]byte
+	seen := map[string]bool{}
+	for _, path := range segments {
+		segment, err := readLines(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range segment {
+			if !seen[string(line)] {
+				seen[string(line)] = true
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines, nil
+}
+
+// locked runs fn holding the journal's lock file. The lock is a file
+// created exclusively, which works the same on every platform. It names its
+// holder, so a lock left by a crashed process of this host is broken as soon
+// as the process is gone. Other locks are broken once older than staleLock;
+// the holder touches its lock while fn runs, so only a lock nobody holds
+// anymore gets that old.
+func (j *Journal) locked(fn func() error) error {
+	if err := os.MkdirAll(j.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", j.dir, err)
+	}
+
+	path := filepath.Join(j.dir, ".lock")
+	owner := lockOwner()
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = f.WriteString(owner)
+			f.Close()
+			if err != nil {
+				os.Remove(path)
+				return fmt.Errorf("failed to lock %s: %w", j.dir, err)
+			}
+			break
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("failed to lock %s: %w", j.dir, err)
+		}
+		if info, err := os.Stat(path); err == nil {
+			if held, err := os.ReadFile(path); err == nil && staleHolder(info, held) && breakLock(path, held) {
+				continue
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for %s (remove it if no autonomous-dev process is running)", path)
+		}
+		time.Sleep(lockRetry)
+	}
+	defer releaseLock(path, owner)
+	defer touchLock(path, owner)()
+
+	if err := j.migrate(); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// lockOwner returns the content of a lock taken by this process: its PID,
+// host and a token telling the locks it takes apart
+func lockOwner() string {
+	return fmt.Sprintf("%d %s %d\n", os.Getpid(), hostname(), time.Now().UnixNano())
+}
+
+// staleHolder reports whether a lock was left by a process that is gone. A
+// holder of this host is checked directly; a holder that cannot be checked,
+// on another host or named by an unreadable owner line, is gone once the lock
+// is older than staleLock.
+func staleHolder(info os.FileInfo, held []byte) bool {
+	var pid int
+	var host string
+	if n, _ := fmt.Sscan(string(held), &pid, &host); n == 2 && host == hostname() {
+		return !processAlive(pid)
+	}
+	return time.Since(info.ModTime()) > staleLock
+}
+
+// touchLock refreshes the lock's modification time every lockTouch while
+// this holder still owns it, until the returned function is called
+func touchLock(path, owner string) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lockTouch)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if held, err := os.ReadFile(path); err == nil && string(held) == owner {
+					os.Chtimes(path, now, now)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// breakLock removes a stale lock if it still holds held and reports whether
+// it did. Waiters break locks one at a time under a guard file, so a lock
+// another waiter took after breaking the same stale lock is never removed.
+func breakLock(path string, held []byte) bool {
+	guard := path + ".break"
+	f, err := os.OpenFile(guard, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		// A waiter that crashed while breaking leaves the guard behind
+		if info, err := os.Stat(guard); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(guard)
+		}
+		return false
+	}
+	f.Close()
+	defer os.Remove(guard)
+
+	current, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(current, held) {
+		return false
+	}
+	return os.Remove(path) == nil
+}
+
+// releaseLock removes the lock if this holder still owns it; a lock broken
+// while held may have been taken by another process since
+func releaseLock(path, owner string) {
+	if held, err := os.ReadFile(path); err == nil && string(held) == owner {
+		os.Remove(path)
+	}
+}
+
+// processAlive reports whether a process of this host runs. On Windows,
+// finding the process opens it, which fails once it exited.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		p.Release()
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+func hostname() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "localhost"
+	}
+	return strings.ReplaceAll(host, " ", "_")
+}
+
+// migrate moves the legacy single-file log into the journal; the lock must
+// be held
+func (j *Journal) migrate() error {
+	if j.legacy == "" {
+		return nil
+	}
+	if _, err := os.Stat(j.legacy); os.IsNotExist(err) {
+		return nil
+	}
+	target := filepath.Join(j.dir, "legacy"+segmentExt)
+	if err := os.Rename(j.legacy, target); err != nil {
+		return fmt.Errorf("failed to move %s into %s: %w", j.legacy, j.dir, err)
+	}
+	return nil
+}
+
+// appendLines appends lines to a file in one write, so a concurrent reader
+// never sees half of a batch
+func appendLines(path string, lines [][]byte) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// readLines returns the non-empty lines of a file. A last line that is not
+// complete JSON was cut short by a crash and is skipped.
+func readLines(path string) ([][]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) > 0 {
+			lines = append(lines, append([]byte(nil), line...))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if n := len(lines); n > 0 && !json.Valid(lines[n-1]) {
+		lines = lines[:n-1]
+	}
+	return lines, nil
+}
@@ -0,0 +1,112 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentAppends(t *testing.T) {
+	j := Open(t.TempDir(), "")
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := j.Append([]byte(fmt.Sprintf(`{"n":%d}`, i))); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines, err := j.Lines()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 20 {
+		t.Errorf("got %d lines, want 20", len(lines))
+	}
+}
+
+func TestLockOfExitedProcessIsBroken(t *testing.T) {
+	dir := t.TempDir()
+	// PIDs are far below this on every supported platform
+	dead := fmt.Sprintf("%d %s 1\n", 1<<30, hostname())
+	if err := os.WriteFile(filepath.Join(dir, ".lock"), []byte(dead), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := Open(dir, "").Append([]byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("waited %v for a lock of an exited process", waited)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".lock")); !os.IsNotExist(err) {
+		t.Errorf("lock left behind: %v", err)
+	}
+}
+
+func TestBreakLockKeepsNewerLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+	if err := os.WriteFile(path, []byte(lockOwner()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if breakLock(path, []byte("12345 gone 1\n")) {
+		t.Error("broke a lock other than the stale one")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("lock removed: %v", err)
+	}
+}
+
+func TestLiveHolderIsNotStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".lock")
+	owner := lockOwner()
+	if err := os.WriteFile(path, []byte(owner), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staleHolder(info, []byte(owner)) {
+		t.Error("lock of this process is stale")
+	}
+}
+
+func TestOldLockIsStaleOnlyIfUncheckable(t *testing.T) {
+	old := time.Now().Add(-2 * staleLock)
+	tests := []struct {
+		name  string
+		held  string
+		stale bool
+	}{
+		{"live holder of this host", lockOwner(), false},
+		{"holder of another host", fmt.Sprintf("%d elsewhere.invalid 1\n", os.Getpid()), true},
+		{"unreadable owner line", "garbage", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".lock")
+			if err := os.WriteFile(path, []byte(tt.held), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(path, old, old); err != nil {
+				t.Fatal(err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := staleHolder(info, []byte(tt.held)); got != tt.stale {
+				t.Errorf("staleHolder = %v, want %v", got, tt.stale)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package logstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Instance log levels, least severe first
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// ParseLevel validates a level name; empty means info
+func ParseLevel(s string) (string, error) {
+	switch s {
+	case "":
+		return LevelInfo, nil
+	case LevelDebug, LevelInfo, LevelWarn, LevelError:
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid log level %q: must be %s, %s, %s or %s", s, LevelDebug, LevelInfo, LevelWarn, LevelError)
+}
+
+// levelRank orders levels by severity; unknown levels rank as info
+func levelRank(level string) int {
+	switch level {
+	case LevelDebug:
+		return 0
+	case LevelWarn:
+		return 2
+	case LevelError:
+		return 3
+	}
+	return 1
+}
+
+// Entry is a structured line logged by log_event / Write-LogEvent
+type Entry struct {
+	Time     time.Time `json:"ts"`
+	Instance int       `json:"instance"`
+	Level    string    `json:"level"`
+	Step     string    `json:"step,omitempty"`
+	Message  string    `json:"msg"`
+	// Trace is the run's trace ID, if it has one
+	Trace string `json:"trace,omitempty"`
+}
+
+// actionsTimestamp is the timestamp GitHub prefixes to each job log line
+var actionsTimestamp = regexp.MustCompile(`^\d{4}-\d\d-\d\dT[\d:.]+Z `)
+
+// ParseEntry decodes a structured log line. Lines that are not log_event
+// output, including plain text written by tools, are not entries.
+func ParseEntry(line string) (*Entry, bool) {
+	line = strings.TrimSpace(actionsTimestamp.ReplaceAllString(line, ""))
+	if !strings.HasPrefix(line, "{") || !strings.Contains(line, `"level"`) {
+		return nil, false
+	}
+	var e Entry
+	if err := json.Unmarshal([]byte(line), &e); err != nil || e.Level == "" || e.Message == "" {
+		return nil, false
+	}
+	return &e, true
+}
+
+// AtLeast reports whether the entry is at least as severe as level
+func (e *Entry) AtLeast(level string) bool {
+	return levelRank(e.Level) >= levelRank(level)
+}
+
+// LineAtLeast reports whether a log line is at least as severe as level.
+// Unstructured lines count as info.
+func LineAtLeast(line, level string) bool {
+	if e, ok := ParseEntry(line); ok {
+		return e.AtLeast(level)
+	}
+	return levelRank(LevelInfo) >= levelRank(level)
+}
+
+// String renders the entry without its time, e.g. "ERROR build: go build failed"
+func (e *Entry) String() string {
+	s := strings.ToUpper(e.Level) + " "
+	if e.Step != "" {
+		s += e.Step + ": "
+	}
+	return s + e.Message
+}
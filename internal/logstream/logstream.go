@@ -0,0 +1,150 @@
+package logstream
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Ring keeps the most recent lines of a log in a fixed amount of memory
+type Ring struct {
+	lines []string
+	next  int
+	full  bool
+	// Dropped counts lines pushed out before they were read
+	Dropped int
+}
+
+// NewRing returns a ring holding up to size lines
+func NewRing(size int) *Ring {
+	if size < 1 {
+		size = 1
+	}
+	return &Ring{lines: make([]string, size)}
+}
+
+// Add appends a line, overwriting the oldest one when the ring is full
+func (r *Ring) Add(line string) {
+	if r.full {
+		r.Dropped++
+	}
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Drain returns the buffered lines, oldest first, and empties the ring
+func (r *Ring) Drain() []string {
+	var out []string
+	if r.full {
+		out = append(out, r.lines[r.next:]...)
+	}
+	out = append(out, r.lines[:r.next]...)
+	r.next, r.full, r.Dropped = 0, false, 0
+	return out
+}
+
+// Cursor records how much of a job's log has been printed
+type Cursor struct {
+	Lines int `json:"lines"`
+	// LastHash identifies the last printed line, so a log that was replaced
+	// (e.g. by a re-run) is not mistaken for a continuation
+	LastHash string `json:"last_hash,omitempty"`
+	// Done is set once the log of a completed job has been read in full
+	Done bool `json:"done,omitempty"`
+}
+
+// Advance feeds the lines of a full log that follow the cursor into ring and
+// moves the cursor to the end of the log. A log that does not continue the
+// cursor is fed from the start.
+func (c *Cursor) Advance(log string, ring *Ring) {
+	lines := strings.Split(strings.TrimSuffix(log, "\n"), "\n")
+	if log == "" {
+		lines = nil
+	}
+
+	start := c.Lines
+	if start > len(lines) || (start > 0 && hashLine(lines[start-1]) != c.LastHash) {
+		start = 0
+	}
+	for _, line := range lines[start:] {
+		ring.Add(line)
+	}
+
+	c.Lines = len(lines)
+	c.LastHash = ""
+	if len(lines) > 0 {
+		c.LastHash = hashLine(lines[len(lines)-1])
+	}
+}
+
+func hashLine(line string) string {
+	sum := sha256.Sum256([]byte(line))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Cursors are the log cursors of one run's jobs, persisted so a restarted
+// command resumes where the previous one stopped
+type Cursors struct {
+	path string
+	Jobs map[int64]*Cursor `json:"jobs"`
+}
+
+// DefaultDir returns the directory of persisted log cursors
+func DefaultDir() string {
+	return filepath.Join(".autonomous-dev", "logs")
+}
+
+// Load reads the cursors of a run from dir. Missing files yield empty cursors.
+func Load(dir string, runID int64) (*Cursors, error) {
+	c := &Cursors{
+		path: filepath.Join(dir, fmt.Sprintf("run-%d.json", runID)),
+		Jobs: make(map[int64]*Cursor),
+	}
+
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log cursors: %w", err)
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", c.path, err)
+	}
+	if c.Jobs == nil {
+		c.Jobs = make(map[int64]*Cursor)
+	}
+	return c, nil
+}
+
+// Job returns the cursor of a job, creating it if needed
+func (c *Cursors) Job(jobID int64) *Cursor {
+	cur, ok := c.Jobs[jobID]
+	if !ok {
+		cur = &Cursor{}
+		c.Jobs[jobID] = cur
+	}
+	return cur
+}
+
+// Save persists the cursors
+func (c *Cursors) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create log cursor directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal log cursors: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write log cursors: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Component statuses, named as in the statuspage.io API
+const (
+	StatusOperational   = "operational"
+	StatusDegraded      = "degraded_performance"
+	StatusPartialOutage = "partial_outage"
+)
+
+// statuspageAPI is the statuspage.io REST API base URL
+const statuspageAPI = "https://api.statuspage.io/v1"
+
+// Statuspage updates a status page component. With URL set, a custom status
+// page receives {"status": ..., "success_rate": ...} instead of statuspage.io.
+type Statuspage struct {
+	PageID      string
+	ComponentID string
+	APIKey      string
+	URL         string
+	client      *http.Client
+}
+
+// NewStatuspage creates a status page notifier
+func NewStatuspage(pageID, componentID, apiKey, url string) *Statuspage {
+	return &Statuspage{
+		PageID:      pageID,
+		ComponentID: componentID,
+		APIKey:      apiKey,
+		URL:         url,
+		client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// ComponentStatus maps a success rate in percent to a component status
+func ComponentStatus(successRate, degradedBelow, outageBelow int) string {
+	switch {
+	case successRate < outageBelow:
+		return StatusPartialOutage
+	case successRate < degradedBelow:
+		return StatusDegraded
+	default:
+		return StatusOperational
+	}
+}
+
+// Update sets the component status
+func (s *Statuspage) Update(status string, successRate int) error {
+	var (
+		method  = http.MethodPost
+		url     = s.URL
+		payload interface{}
+	)
+	if url == "" {
+		method = http.MethodPatch
+		url = fmt.Sprintf("%s/pages/%s/components/%s", statuspageAPI, s.PageID, s.ComponentID)
+		payload = map[string]interface{}{"component": map[string]string{"status": status}}
+	} else {
+		payload = map[string]interface{}{"status": status, "success_rate": successRate}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status update: %w", err)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create status update: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "OAuth "+s.APIKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to update status page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to update status page: %s", resp.Status)
+	}
+	return nil
+}
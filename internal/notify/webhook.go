@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Webhook posts Slack-compatible {"text": ...} messages to a URL
+type Webhook struct {
+	URL    string
+	client *http.Client
+}
+
+// NewWebhook creates a webhook notifier
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		URL:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send posts a message to the webhook
+func (w *Webhook) Send(text string) error {
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	resp, err := w.client.Post(w.URL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send notification: %s", resp.Status)
+	}
+	return nil
+}
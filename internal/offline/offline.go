@@ -0,0 +1,97 @@
+package offline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/github"
+)
+
+// Status is what a status refresh fetched from GitHub, kept so status can
+// still be shown while GitHub cannot be reached
+type Status struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	// Key identifies the view: the issue, state branch run and attempt
+	// status was asked for
+	Key           string              `json:"key"`
+	Run           *github.WorkflowRun `json:"run"`
+	LatestAttempt int                 `json:"latest_attempt"`
+	Jobs          []github.Job        `json:"jobs"`
+	Issue         int                 `json:"issue,omitempty"`
+	// Location and Bodies are the coordination store and the comment bodies
+	// read from it, if the run has one
+	Location string   `json:"location,omitempty"`
+	Bodies   []string `json:"bodies,omitempty"`
+}
+
+// Age returns how long ago the status was fetched
+func (s *Status) Age() time.Duration {
+	return time.Since(s.FetchedAt)
+}
+
+// Store keeps the last fetched status of each view as a JSON file
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the directory of the local status snapshots
+func DefaultDir() string {
+	return filepath.Join(".autonomous-dev", "offline")
+}
+
+// Open returns a store in dir
+func Open(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Save replaces the snapshot of the status's view
+func (s *Store) Save(st *Status) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create offline directory: %w", err)
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status snapshot: %w", err)
+	}
+
+	// Readers must never see a half-written snapshot
+	path := s.path(st.Key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write status snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write status snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load returns the snapshot of a view, or nil if none was saved
+func (s *Store) Load(key string) (*Status, error) {
+	data, err := os.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status snapshot: %w", err)
+	}
+	var st Status
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("failed to parse status snapshot: %w", err)
+	}
+	return &st, nil
+}
+
+// path returns the file of a view's snapshot; the default view is status.json
+func (s *Store) path(key string) string {
+	if key == "" {
+		return filepath.Join(s.dir, "status.json")
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, "status-"+hex.EncodeToString(sum[:6])+".json")
+}
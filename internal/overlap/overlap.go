@@ -0,0 +1,154 @@
+package overlap
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// shingleLines is how many consecutive added lines are hashed together;
+	// hunks with fewer lines are hashed whole
+	shingleLines = 3
+	// minShared is the fewest shared fingerprints that count as overlap
+	minShared = 2
+	// minSimilarity is the share of the smaller change that must be shared
+	minSimilarity = 0.3
+)
+
+// Change is what one instance changed: file paths to unified diff patches
+type Change struct {
+	Instance int
+	Patches  map[string]string
+}
+
+// Overlap is a pair of instances whose changes add the same code
+type Overlap struct {
+	// A and B are the instances, A < B
+	A, B int
+	// Dirs are the directories of the shared code, sorted
+	Dirs []string
+	// Shared is the number of shared fingerprints
+	Shared int
+	// Similarity is Shared as a share of the smaller change's fingerprints
+	Similarity float64
+}
+
+// String describes the overlap, e.g. "instances 2 and 4 produced
+// overlapping changes in pkg/auth (45% similar)"
+func (o Overlap) String() string {
+	return fmt.Sprintf("instances %d and %d produced overlapping changes in %s (%.0f%% similar)", o.A, o.B, strings.Join(o.Dirs, ", "), o.Similarity*100)
+}
+
+// Detect compares every pair of changes and returns the overlapping pairs,
+// ordered by instance. Added lines are compared after normalizing
+// whitespace, so reindented or reformatted copies still match; removed lines
+// and trivial lines such as lone braces are ignored.
+func Detect(changes []Change) []Overlap {
+	prints := make([]map[string]string, len(changes))
+	for i, c := range changes {
+		prints[i] = fingerprints(c.Patches)
+	}
+
+	var overlaps []Overlap
+	for i := range changes {
+		for j := i + 1; j < len(changes); j++ {
+			a, b := prints[i], prints[j]
+			if len(a) == 0 || len(b) == 0 {
+				continue
+			}
+			dirs := map[string]bool{}
+			shared := 0
+			for hash, dir := range a {
+				if _, ok := b[hash]; ok {
+					shared++
+					dirs[dir] = true
+				}
+			}
+			similarity := float64(shared) / float64(min(len(a), len(b)))
+			if shared < minShared || similarity < minSimilarity {
+				continue
+			}
+
+			o := Overlap{A: changes[i].Instance, B: changes[j].Instance, Shared: shared, Similarity: similarity}
+			if o.A > o.B {
+				o.A, o.B = o.B, o.A
+			}
+			for dir := range dirs {
+				o.Dirs = append(o.Dirs, dir)
+			}
+			sort.Strings(o.Dirs)
+			overlaps = append(overlaps, o)
+		}
+	}
+
+	sort.Slice(overlaps, func(i, j int) bool {
+		if overlaps[i].A != overlaps[j].A {
+			return overlaps[i].A < overlaps[j].A
+		}
+		return overlaps[i].B < overlaps[j].B
+	})
+	return overlaps
+}
+
+// fingerprints hashes the normalized added lines of each hunk, mapping each
+// hash to the directory of its file
+func fingerprints(patches map[string]string) map[string]string {
+	prints := map[string]string{}
+	for file, patch := range patches {
+		dir := path.Dir(file)
+		for _, hunk := range hunks(patch) {
+			if len(hunk) < shingleLines {
+				prints[hash(hunk)] = dir
+				continue
+			}
+			for i := 0; i+shingleLines <= len(hunk); i++ {
+				prints[hash(hunk[i:i+shingleLines])] = dir
+			}
+		}
+	}
+	return prints
+}
+
+// hunks returns the normalized, non-trivial added lines of each hunk in a
+// unified diff patch
+func hunks(patch string) [][]string {
+	var result [][]string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			flush()
+		case strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++"):
+			if normalized := normalize(line[1:]); normalized != "" {
+				current = append(current, normalized)
+			}
+		}
+	}
+	flush()
+	return result
+}
+
+// normalize collapses whitespace in a line and drops lines too short to
+// tell copies apart, e.g. "}" or ")"
+func normalize(line string) string {
+	normalized := strings.Join(strings.Fields(line), " ")
+	if len(normalized) < 4 {
+		return ""
+	}
+	return normalized
+}
+
+func hash(lines []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return string(sum[:])
+}
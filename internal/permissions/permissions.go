@@ -0,0 +1,194 @@
+package permissions
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/journal"
+)
+
+// Comment commands answered by serve --commands
+const (
+	// AutoFix starts a run attached to the issue
+	AutoFix = "auto-fix"
+	// Close closes the issue
+	Close = "close"
+	// Pause pauses the issue's run
+	Pause = "pause"
+	// Resume resumes the issue's paused run
+	Resume = "resume"
+)
+
+// Commands lists every comment command
+var Commands = []string{AutoFix, Close, Pause, Resume}
+
+// Any grants a principal every command
+const Any = "*"
+
+// Command is a slash command on the first line of an issue comment, e.g.
+// "/auto-fix 3"
+type Command struct {
+	Name string
+	Args []string
+}
+
+// String returns the command as it is written in a comment
+func (c Command) String() string {
+	return strings.Join(append([]string{"/" + c.Name}, c.Args...), " ")
+}
+
+// Parse returns the command a comment body starts with. Bodies starting
+// with anything but a known command have none.
+func Parse(body string) (Command, bool) {
+	line, _, _ := strings.Cut(strings.TrimSpace(body), "\n")
+	fields := strings.Fields(line)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return Command{}, false
+	}
+	name := strings.ToLower(strings.TrimPrefix(fields[0], "/"))
+	if !slices.Contains(Commands, name) {
+		return Command{}, false
+	}
+	return Command{Name: name, Args: fields[1:]}, true
+}
+
+// Principal is a GitHub user, or a team written "@org/team"
+type Principal struct {
+	User string
+	Org  string
+	Team string
+}
+
+// ParsePrincipal parses a user login or "@org/team"
+func ParsePrincipal(s string) (Principal, error) {
+	s = strings.TrimSpace(s)
+	if team, ok := strings.CutPrefix(s, "@"); ok {
+		org, slug, found := strings.Cut(team, "/")
+		if !found || org == "" || slug == "" || strings.Contains(slug, "/") {
+			return Principal{}, fmt.Errorf("invalid team %q: expected @org/team", s)
+		}
+		return Principal{Org: org, Team: slug}, nil
+	}
+	if s == "" || strings.ContainsAny(s, "/ ") {
+		return Principal{}, fmt.Errorf("invalid user %q", s)
+	}
+	return Principal{User: s}, nil
+}
+
+// String returns the principal as written in the config
+func (p Principal) String() string {
+	if p.Team != "" {
+		return "@" + p.Org + "/" + p.Team
+	}
+	return p.User
+}
+
+// Policy maps users and "@org/team" teams to the commands they may run, as
+// configured under permissions:. "*" grants every command.
+type Policy map[string][]string
+
+// Validate checks every principal and command of the policy
+func (p Policy) Validate() error {
+	for principal, commands := range p {
+		if _, err := ParsePrincipal(principal); err != nil {
+			return fmt.Errorf("permissions: %w", err)
+		}
+		for _, command := range commands {
+			command = strings.TrimPrefix(command, "/")
+			if command != Any && !slices.Contains(Commands, command) {
+				return fmt.Errorf("permissions: %s: unknown command %q (expected %s or %s)", principal, command, strings.Join(Commands, ", "), Any)
+			}
+		}
+	}
+	return nil
+}
+
+// Grants returns the principals allowed to run command: users first, since
+// they need no membership check, then teams, each sorted
+func (p Policy) Grants(command string) []Principal {
+	var grants []Principal
+	for name, commands := range p {
+		for _, c := range commands {
+			if c = strings.TrimPrefix(c, "/"); c == command || c == Any {
+				if principal, err := ParsePrincipal(name); err == nil {
+					grants = append(grants, principal)
+				}
+				break
+			}
+		}
+	}
+	sort.Slice(grants, func(i, j int) bool {
+		if (grants[i].Team == "") != (grants[j].Team == "") {
+			return grants[i].Team == ""
+		}
+		return grants[i].String() < grants[j].String()
+	})
+	return grants
+}
+
+// Record is one command in the audit trail
+type Record struct {
+	At      time.Time `json:"at"`
+	Issue   int       `json:"issue"`
+	Comment int64     `json:"comment"`
+	User    string    `json:"user"`
+	Command string    `json:"command"`
+	Allowed bool      `json:"allowed"`
+	// Grant is the principal that allowed the command
+	Grant string `json:"grant,omitempty"`
+	// Reason explains a denied command
+	Reason string `json:"reason,omitempty"`
+	// Error is set when an allowed command failed
+	Error string `json:"error,omitempty"`
+}
+
+// Log keeps the audit trail of comment commands in an append-only journal
+// under .autonomous-dev/audit/
+type Log struct {
+	journal *journal.Journal
+}
+
+// DefaultDir returns the directory of the local audit journal
+func DefaultDir() string {
+	return filepath.Join(".autonomous-dev", "audit")
+}
+
+// Open returns the audit log backed by the journal in dir
+func Open(dir string) *Log {
+	return &Log{journal: journal.Open(dir, "")}
+}
+
+// Append adds a record to the audit trail
+func (l *Log) Append(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	if err := l.journal.Append(data); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// List returns all records in the order they happened
+func (l *Log) List() ([]Record, error) {
+	lines, err := l.journal.Lines()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	records := make([]Record, 0, len(lines))
+	for _, line := range lines {
+		var r Record
+		if err := json.Unmarshal(line, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse audit log: %w", err)
+		}
+		records = append(records, r)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].At.Before(records[j].At) })
+	return records, nil
+}
@@ -0,0 +1,133 @@
+package planner
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxPredictedFiles bounds the files a heuristic estimate lists
+const maxPredictedFiles = 50
+
+// skippedDirs are never matched against task words
+var skippedDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true, ".autonomous-dev": true, "dist": true, "build": true}
+
+// scopeWords widen a task beyond the files it names
+var scopeWords = map[string]int{
+	"refactor": 2, "migrate": 3, "migration": 3, "rewrite": 3, "redesign": 3,
+	"architecture": 2, "across": 1, "all": 1, "every": 1, "entire": 2,
+	"fix": -1, "typo": -2, "rename": -1, "docs": -1, "readme": -1,
+}
+
+// stopWords are too common to predict files
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true,
+	"add": true, "new": true, "use": true, "make": true, "update": true, "support": true,
+	"that": true, "this": true, "when": true, "should": true, "test": true, "tests": true,
+}
+
+// Heuristic predicts the files a task touches by matching its words against
+// repository paths and scores complexity from the file count and scope words
+type Heuristic struct {
+	root         string
+	maxInstances int
+}
+
+// NewHeuristic returns a heuristic analyzer matching paths under root
+func NewHeuristic(root string, maxInstances int) *Heuristic {
+	return &Heuristic{root: root, maxInstances: maxInstances}
+}
+
+// Name implements Analyzer
+func (h *Heuristic) Name() string { return "heuristic" }
+
+// Analyze implements Analyzer
+func (h *Heuristic) Analyze(task string) (*Estimate, error) {
+	words := keywords(task)
+
+	var files []string
+	err := filepath.WalkDir(h.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != h.root && (skippedDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(h.root, path)
+		if err != nil {
+			return nil
+		}
+		if matchesPath(filepath.ToSlash(rel), words) {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan repository: %w", err)
+	}
+	sort.Strings(files)
+
+	score := 1 + len(files)/3
+	scope := 0
+	for word := range taskTokens(task) {
+		scope += scopeWords[word]
+	}
+	score = clampScore(score + scope)
+
+	reason := fmt.Sprintf("%d matching file(s)", len(files))
+	if scope != 0 {
+		reason += fmt.Sprintf(", scope %+d", scope)
+	}
+	if len(files) > maxPredictedFiles {
+		files = files[:maxPredictedFiles]
+	}
+
+	return &Estimate{
+		Score:     score,
+		Files:     files,
+		Instances: instancesFor(score, h.maxInstances),
+		Reason:    reason,
+	}, nil
+}
+
+// keywords returns the task words worth matching against paths
+func keywords(task string) []string {
+	var words []string
+	for word := range taskTokens(task) {
+		if len(word) >= 3 && !stopWords[word] && scopeWords[word] == 0 {
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words)
+	return words
+}
+
+// matchesPath reports whether a path segment (without extension) contains a keyword
+func matchesPath(path string, words []string) bool {
+	for _, segment := range strings.Split(strings.ToLower(path), "/") {
+		segment = strings.TrimSuffix(segment, filepath.Ext(segment))
+		for _, word := range words {
+			if strings.Contains(segment, word) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// taskTokens returns the lower-cased alphanumeric words of a task
+func taskTokens(task string) map[string]bool {
+	fields := strings.FieldsFunc(strings.ToLower(task), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	tokens := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		tokens[f] = true
+	}
+	return tokens
+}
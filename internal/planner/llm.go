@@ -0,0 +1,154 @@
+package planner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/cache"
+)
+
+// messagesAPI is the Anthropic Messages API endpoint
+const messagesAPI = "https://api.anthropic.com/v1/messages"
+
+// llmPrompt asks for an estimate as a single JSON object
+const llmPrompt = `Estimate the complexity of this software task in a repository.
+Reply with only a JSON object: {"score": 1-10, "files": ["paths likely touched"], "reason": "one sentence"}.
+
+Task:
+%s
+
+Paths matching the task's words (may be incomplete):
+%s`
+
+// LLM asks a model to estimate tasks. Path matches of the heuristic analyzer
+// are passed along as hints.
+type LLM struct {
+	apiKey string
+	model  string
+	hints  *Heuristic
+	http   *http.Client
+	// responses caches replies by request; nil calls the API every time
+	responses *cache.Cache
+}
+
+// NewLLM returns an analyzer calling the Anthropic Messages API
+func NewLLM(apiKey, model string, hints *Heuristic, responses *cache.Cache) *LLM {
+	return &LLM{apiKey: apiKey, model: model, hints: hints, http: &http.Client{Timeout: 60 * time.Second}, responses: responses}
+}
+
+// Name implements Analyzer
+func (l *LLM) Name() string { return "llm" }
+
+// Analyze implements Analyzer
+func (l *LLM) Analyze(task string) (*Estimate, error) {
+	paths := "(none)"
+	if hint, err := l.hints.Analyze(task); err == nil && len(hint.Files) > 0 {
+		paths = strings.Join(hint.Files, "\n")
+	}
+
+	// Identical prompts to the same model get the cached reply
+	prompt := fmt.Sprintf(llmPrompt, task, paths)
+	key := cache.Key(messagesAPI, l.model, prompt)
+	if text, ok := l.responses.Get(key); ok {
+		est, err := parseLLMEstimate(text, l.hints.maxInstances)
+		if err == nil {
+			est.Cached = true
+			return est, nil
+		}
+	}
+
+	text, err := l.complete(prompt)
+	if err != nil {
+		return nil, err
+	}
+	est, err := parseLLMEstimate(text, l.hints.maxInstances)
+	if err != nil {
+		return nil, err
+	}
+	// A cache that cannot be written only costs tokens next time
+	_ = l.responses.Put(key, text)
+	return est, nil
+}
+
+// complete sends a prompt to the Messages API and returns the reply text
+func (l *LLM) complete(prompt string) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":      l.model,
+		"max_tokens": 1024,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, messagesAPI, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", l.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := l.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call the LLM analyzer: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("LLM analyzer: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var reply struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return "", fmt.Errorf("failed to decode LLM analyzer reply: %w", err)
+	}
+
+	var text string
+	for _, c := range reply.Content {
+		if c.Type == "text" {
+			text += c.Text
+		}
+	}
+	return text, nil
+}
+
+// parseLLMEstimate extracts the JSON object of a model reply
+func parseLLMEstimate(text string, maxInstances int) (*Estimate, error) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("LLM analyzer reply has no JSON object")
+	}
+
+	var out struct {
+		Score  int      `json:"score"`
+		Files  []string `json:"files"`
+		Reason string   `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse LLM analyzer reply: %w", err)
+	}
+
+	score := clampScore(out.Score)
+	if len(out.Files) > maxPredictedFiles {
+		out.Files = out.Files[:maxPredictedFiles]
+	}
+	return &Estimate{
+		Score:     score,
+		Files:     out.Files,
+		Instances: instancesFor(score, maxInstances),
+		Reason:    out.Reason,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package planner
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// minSimilarity is the share of words a new subtask must have in common
+// with an assigned one to keep it
+const minSimilarity = 0.5
+
+// listItemPattern matches a top-level list item: "- ", "* ", "1. " or "1) "
+var listItemPattern = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.*)$`)
+
+// Subtasks splits a task description into subtasks: the items of its
+// top-level list, or the whole description if it has none. Indented lines
+// continue the item above them.
+func Subtasks(task string) []string {
+	var items []string
+	for _, line := range strings.Split(task, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if m := listItemPattern.FindStringSubmatch(line); m != nil {
+			items = append(items, strings.TrimSpace(m[1]))
+			continue
+		}
+		if len(items) > 0 && line != trimmed {
+			items[len(items)-1] += " " + trimmed
+		}
+	}
+	if len(items) == 0 {
+		if task = strings.TrimSpace(task); task != "" {
+			return []string{task}
+		}
+	}
+	return items
+}
+
+// PlanDiff is how a new plan changes the subtasks assigned in a run
+type PlanDiff struct {
+	// Kept maps the task IDs that stay in the plan to their new description
+	Kept map[string]string
+	// Cancelled are the task IDs no longer in the plan, sorted
+	Cancelled []string
+	// Added are the new subtasks no assigned task matches, in plan order
+	Added []string
+}
+
+// DiffPlan matches assigned subtasks (task ID to description) against the
+// subtasks of a new plan. The most similar pairs are matched first; a pair
+// matches when at least half of their words are shared. Matched tasks are
+// kept, unmatched assigned tasks cancelled and unmatched subtasks added.
+func DiffPlan(assigned map[string]string, subtasks []string) PlanDiff {
+	type pair struct {
+		taskID string
+		index  int
+		score  float64
+	}
+	var pairs []pair
+	for taskID, description := range assigned {
+		for i, subtask := range subtasks {
+			if score := similarity(description, subtask); score >= minSimilarity {
+				pairs = append(pairs, pair{taskID, i, score})
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].score != pairs[j].score {
+			return pairs[i].score > pairs[j].score
+		}
+		if pairs[i].taskID != pairs[j].taskID {
+			return pairs[i].taskID < pairs[j].taskID
+		}
+		return pairs[i].index < pairs[j].index
+	})
+
+	diff := PlanDiff{Kept: map[string]string{}}
+	matched := make([]bool, len(subtasks))
+	for _, p := range pairs {
+		if _, ok := diff.Kept[p.taskID]; ok || matched[p.index] {
+			continue
+		}
+		diff.Kept[p.taskID] = subtasks[p.index]
+		matched[p.index] = true
+	}
+	for taskID := range assigned {
+		if _, ok := diff.Kept[taskID]; !ok {
+			diff.Cancelled = append(diff.Cancelled, taskID)
+		}
+	}
+	sort.Strings(diff.Cancelled)
+	for i, subtask := range subtasks {
+		if !matched[i] {
+			diff.Added = append(diff.Added, subtask)
+		}
+	}
+	return diff
+}
+
+// similarity is the share of the words of two descriptions they have in
+// common (Jaccard index), ignoring stop words
+func similarity(a, b string) float64 {
+	wa, wb := planWords(a), planWords(b)
+	if len(wa) == 0 || len(wb) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range wa {
+		if wb[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wa)+len(wb)-shared)
+}
+
+// planWords returns the words of a description worth comparing
+func planWords(description string) map[string]bool {
+	words := taskTokens(description)
+	for word := range words {
+		if stopWords[word] {
+			delete(words, word)
+		}
+	}
+	return words
+}
@@ -0,0 +1,87 @@
+package planner
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/autonomous-dev/cli/internal/cache"
+	"github.com/autonomous-dev/cli/internal/config"
+)
+
+// Estimate is the predicted complexity of a task
+type Estimate struct {
+	// Score rates complexity from 1 (trivial) to 10 (sweeping)
+	Score int
+	// Files are the paths the task is predicted to touch
+	Files []string
+	// Instances is the suggested number of parallel instances
+	Instances int
+	Reason    string
+	// Cached is set when the estimate reused a cached provider reply
+	Cached bool
+}
+
+// Analyzer estimates how complex a task is
+type Analyzer interface {
+	// Name identifies the analyzer, e.g. "heuristic"
+	Name() string
+	// Analyze estimates a task description
+	Analyze(task string) (*Estimate, error)
+}
+
+// New returns the analyzer configured by planner.analyzer; name overrides it
+// when set. root is the repository the heuristic analyzer matches paths in.
+// The llm analyzer reuses replies from responses, which may be nil.
+func New(cfg config.PlannerConfig, name, root string, maxInstances int, responses *cache.Cache) (Analyzer, error) {
+	if name == "" {
+		name = cfg.Analyzer
+	}
+	switch name {
+	case "", config.AnalyzerHeuristic:
+		return NewHeuristic(root, maxInstances), nil
+	case config.AnalyzerLLM:
+		key := os.ExpandEnv(cfg.APIKey)
+		if key == "" {
+			return nil, fmt.Errorf("planner.api_key is not set")
+		}
+		return NewLLM(key, cfg.LLMModel(), NewHeuristic(root, maxInstances), responses), nil
+	default:
+		return nil, fmt.Errorf("unknown analyzer %q (use %s or %s)", name, config.AnalyzerHeuristic, config.AnalyzerLLM)
+	}
+}
+
+// Weight returns a task's scheduling weight: its complexity score, or 1 when
+// it cannot be estimated
+func Weight(a Analyzer, task string) int {
+	if a == nil {
+		return 1
+	}
+	est, err := a.Analyze(task)
+	if err != nil || est.Score < 1 {
+		return 1
+	}
+	return est.Score
+}
+
+// instancesFor suggests instances for a score, one per two points
+func instancesFor(score, maxInstances int) int {
+	n := (score + 1) / 2
+	if n < 1 {
+		n = 1
+	}
+	if maxInstances > 0 && n > maxInstances {
+		n = maxInstances
+	}
+	return n
+}
+
+// clampScore keeps a score within 1..10
+func clampScore(score int) int {
+	if score < 1 {
+		return 1
+	}
+	if score > 10 {
+		return 10
+	}
+	return score
+}
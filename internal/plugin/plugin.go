@@ -0,0 +1,145 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Prefix starts the file name of every plugin executable
+const Prefix = "autonomous-dev-"
+
+// Dir returns the in-repository plugin directory, searched before PATH
+func Dir() string {
+	return filepath.Join(".autonomous-dev", "plugins")
+}
+
+// SearchPath returns the directories plugins are discovered in: the
+// repository's plugin directory, then every PATH entry
+func SearchPath() []string {
+	return append([]string{Dir()}, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// Manifest optionally describes a plugin for help output. It is read from
+// a YAML file next to the executable, named like it without an extension
+// plus ".yaml", e.g. autonomous-dev-compliance-report.yaml.
+type Manifest struct {
+	// Short is the one-line description in the command list
+	Short string `yaml:"short"`
+	// Long is the description shown by help
+	Long string `yaml:"long,omitempty"`
+	// Usage follows the command name in help, e.g. "[--since DATE]"
+	Usage string `yaml:"usage,omitempty"`
+	// Example lists example invocations
+	Example string `yaml:"example,omitempty"`
+}
+
+// Plugin is an executable providing the subcommand Name
+type Plugin struct {
+	Name string
+	Path string
+	// Manifest is empty if the plugin has none
+	Manifest Manifest
+	// ManifestErr is set when the manifest exists but cannot be read; the
+	// plugin still runs
+	ManifestErr error
+	// Shadowed lists later executables of the same name that are not used
+	Shadowed []string
+}
+
+// Discover finds the plugins in dirs. The first executable of a name wins;
+// later ones are listed as shadowed. Plugins are sorted by name.
+func Discover(dirs []string) []Plugin {
+	found := map[string]*Plugin{}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			// PATH commonly names directories that do not exist
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := commandName(entry.Name())
+			if !ok {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !executable(path) {
+				continue
+			}
+			if p, ok := found[name]; ok {
+				if p.Path != path {
+					p.Shadowed = append(p.Shadowed, path)
+				}
+				continue
+			}
+			manifest, err := readManifest(path)
+			found[name] = &Plugin{Name: name, Path: path, Manifest: manifest, ManifestErr: err}
+		}
+	}
+
+	plugins := make([]Plugin, 0, len(found))
+	for _, p := range found {
+		plugins = append(plugins, *p)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// commandName returns the subcommand a file provides, e.g.
+// "compliance-report" for autonomous-dev-compliance-report
+func commandName(file string) (string, bool) {
+	name, ok := strings.CutPrefix(file, Prefix)
+	if !ok {
+		return "", false
+	}
+	ext := filepath.Ext(name)
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(ext) {
+		case ".exe", ".bat", ".cmd", ".ps1":
+			name = strings.TrimSuffix(name, ext)
+		default:
+			return "", false
+		}
+	} else if ext == ".yaml" || ext == ".yml" {
+		// Manifests sit next to the executables
+		return "", false
+	}
+	return name, name != "" && !strings.HasPrefix(name, "-")
+}
+
+// executable reports whether path is a regular file that can be run
+func executable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0111 != 0
+}
+
+// readManifest reads the manifest next to a plugin executable, if any
+func readManifest(path string) (Manifest, error) {
+	var m Manifest
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	if runtime.GOOS != "windows" {
+		base = path
+	}
+	data, err := os.ReadFile(base + ".yaml")
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return m, fmt.Errorf("failed to read plugin manifest: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("failed to parse plugin manifest %s.yaml: %w", base, err)
+	}
+	return m, nil
+}
@@ -0,0 +1,98 @@
+package poll
+
+import (
+	"math/rand"
+	"time"
+)
+
+// growth is how much the interval grows per poll while a run is active but
+// nothing changed
+const growth = 1.5
+
+// Policy bounds an adaptive polling interval
+type Policy struct {
+	// Min is the interval while a run is active and changing
+	Min time.Duration
+	// Max is the interval while nothing runs, and the cap of error backoff
+	Max time.Duration
+	// Jitter is the share of each interval randomized either way, e.g. 0.2
+	// waits between 80% and 120% of it, so many clients polling the same
+	// repository spread out
+	Jitter float64
+	// Fixed polls every Min without adapting or jitter
+	Fixed bool
+}
+
+// Observation is what one poll saw
+type Observation struct {
+	// Active is set while a run is in progress
+	Active bool
+	// Changed is set when the poll saw something the previous one did not
+	Changed bool
+	// Err is set when the poll failed
+	Err error
+	// RetryAfter is how long a rate limit asks to wait, if the poll hit one
+	RetryAfter time.Duration
+}
+
+// Poller picks the wait before each poll: Min while a run is active and
+// changing, growing towards Max while it is active but quiet, Max while
+// idle, and doubling up to Max after each consecutive error
+type Poller struct {
+	policy   Policy
+	interval time.Duration
+	failures int
+	random   func() float64
+}
+
+// New returns a poller starting at the policy's Min interval. Max is raised
+// to Min if it is lower.
+func New(p Policy) *Poller {
+	if p.Max < p.Min {
+		p.Max = p.Min
+	}
+	return &Poller{policy: p, interval: p.Min, random: rand.Float64}
+}
+
+// Next records a poll's observation and returns how long to wait before the
+// next poll
+func (p *Poller) Next(o Observation) time.Duration {
+	if p.policy.Fixed {
+		return max(p.policy.Min, o.RetryAfter)
+	}
+
+	switch {
+	case o.Err != nil:
+		p.failures++
+		p.interval = min(p.policy.Min<<min(p.failures, 16), p.policy.Max)
+	case !o.Active:
+		p.failures = 0
+		p.interval = p.policy.Max
+	case o.Changed:
+		p.failures = 0
+		p.interval = p.policy.Min
+	default:
+		p.failures = 0
+		p.interval = min(time.Duration(float64(p.interval)*growth), p.policy.Max)
+	}
+
+	// A rate limit's reset is a floor; jitter only ever adds to it
+	wait := p.jitter(p.interval)
+	if o.RetryAfter > wait {
+		wait = o.RetryAfter + time.Duration(p.random()*p.policy.Jitter*float64(p.policy.Min))
+	}
+	return wait
+}
+
+// Failures returns the number of consecutive failed polls
+func (p *Poller) Failures() int {
+	return p.failures
+}
+
+// jitter spreads d by up to the policy's Jitter share either way
+func (p *Poller) jitter(d time.Duration) time.Duration {
+	if p.policy.Jitter <= 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + p.policy.Jitter*(2*p.random()-1)))
+}
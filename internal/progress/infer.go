@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"regexp"
+
+	"github.com/autonomous-dev/cli/internal/github"
+)
+
+// Estimate is progress inferred for an instance that has not reported it
+type Estimate struct {
+	// Percent is the inferred progress (0-100)
+	Percent int
+	// Milestone is the latest recognized milestone, empty if none
+	Milestone string
+	// Step is the name of the step currently running, empty if none
+	Step string
+}
+
+// milestone is a recognizable log line and the progress it implies
+type milestone struct {
+	name    string
+	percent int
+	pattern *regexp.Regexp
+}
+
+// milestones are checked against logs; the highest match wins
+var milestones = []milestone{
+	{"instance started", 5, regexp.MustCompile(`Instance \d+ starting`)},
+	{"task assigned", 15, regexp.MustCompile(`(?i)TASK_ASSIGNMENT:START|task assigned`)},
+	{"tests started", 60, regexp.MustCompile(`(?im)^\s*(=== RUN|go test |npm (run )?test|pytest|cargo test|running tests)`)},
+	{"tests passed", 75, regexp.MustCompile(`(?im)^(ok\s+\S+|PASS$|\s*\d+ passed|tests? passed)`)},
+	{"changes pushed", 85, regexp.MustCompile(`(?im)^To (https://|git@)github\.com|git push`)},
+	{"pull request opened", 95, regexp.MustCompile(`https://github\.com/[^/\s]+/[^/\s]+/pull/\d+`)},
+	{"task completed", 100, regexp.MustCompile(`Instance \d+: Task completed`)},
+}
+
+// stepWeight caps the progress attributed to step transitions alone, so
+// milestones inside the long-running work step still move the estimate
+const stepWeight = 50
+
+// Infer estimates progress from a job's step transitions and log milestones
+func Infer(job github.Job, logs string) Estimate {
+	if job.Status == "completed" {
+		return Estimate{Percent: 100, Milestone: "job completed"}
+	}
+
+	var est Estimate
+
+	// Step transitions: completed steps out of all steps
+	if total := len(job.Steps); total > 0 {
+		done := 0
+		for _, step := range job.Steps {
+			switch step.Status {
+			case "completed":
+				done++
+			case "in_progress":
+				est.Step = step.Name
+			}
+		}
+		est.Percent = done * stepWeight / total
+	}
+
+	// Milestones recognized in logs
+	for _, m := range milestones {
+		if m.percent > est.Percent && m.pattern.MatchString(logs) {
+			est.Percent = m.percent
+			est.Milestone = m.name
+		}
+	}
+
+	// A running job is never done
+	if est.Percent >= 100 {
+		est.Percent = 99
+	}
+
+	return est
+}
@@ -0,0 +1,210 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Run priorities; entries without one are normal
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// ParsePriority validates a priority name; empty means normal
+func ParsePriority(s string) (string, error) {
+	switch s {
+	case "":
+		return PriorityNormal, nil
+	case PriorityLow, PriorityNormal, PriorityHigh:
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid priority %q: must be %s, %s or %s", s, PriorityLow, PriorityNormal, PriorityHigh)
+}
+
+// priorityRank orders priorities, highest first
+func priorityRank(p string) int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	}
+	return 1
+}
+
+// MaxAttempts is how often a queued run is dispatched and fails before it is
+// parked
+const MaxAttempts = 5
+
+// Entry is a run request waiting to be dispatched
+type Entry struct {
+	ID        string `json:"id"`
+	Task      string `json:"task"`
+	Instances int    `json:"instances"`
+	Untrusted bool   `json:"untrusted,omitempty"`
+	Agent     string `json:"agent,omitempty"`
+	Details   string `json:"details,omitempty"`
+	Source    string `json:"source,omitempty"`
+	SHA       string `json:"sha,omitempty"`
+	// Environment is the deployment environment of the run, if any
+	Environment string `json:"environment,omitempty"`
+	// EnvLock is the env_lock input reproducing a past run's environment
+	EnvLock string `json:"env_lock,omitempty"`
+	// Priority is low, normal (default) or high
+	Priority string `json:"priority,omitempty"`
+	// Canary starts the run with a single trial instance
+	Canary bool `json:"canary,omitempty"`
+	// LogLevel overrides the instance log level
+	LogLevel string `json:"log_level,omitempty"`
+	// Env holds environment variables set for every instance
+	Env map[string]string `json:"env,omitempty"`
+	// Issue is an existing issue the run is attached to instead of a new one
+	Issue int `json:"issue,omitempty"`
+	// CreatedIssue and CreatedRun are the coordination issue or state branch
+	// run an earlier dispatch created before its workflow failed to trigger;
+	// the retry reuses them and their trace instead of creating another
+	CreatedIssue int    `json:"created_issue,omitempty"`
+	CreatedRun   string `json:"created_run,omitempty"`
+	Trace        string `json:"trace,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	// Attempts and LastError record dispatches of the entry that failed
+	Attempts  int       `json:"attempts,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Store persists queued entries as JSON files in a directory
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the directory of the local run queue
+func DefaultDir() string {
+	return filepath.Join(".autonomous-dev", "queue")
+}
+
+// OutboxDir returns the directory of starts deferred while GitHub was
+// unreachable or no token was set
+func OutboxDir() string {
+	return filepath.Join(".autonomous-dev", "outbox")
+}
+
+// Open returns a store backed by dir. The directory is created on first write.
+func Open(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Add persists a new entry and assigns its ID
+func (s *Store) Add(e *Entry) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create queue directory: %w", err)
+	}
+
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	// Timestamp IDs keep file names sortable in creation order
+	e.ID = e.CreatedAt.UTC().Format("20060102T150405.000000000")
+	return s.write(e)
+}
+
+// Update rewrites an existing entry, e.g. to keep what a failed dispatch
+// created
+func (s *Store) Update(e *Entry) error {
+	if _, err := os.Stat(s.path(e.ID)); os.IsNotExist(err) {
+		return fmt.Errorf("queue entry %s not found", e.ID)
+	}
+	return s.write(e)
+}
+
+func (s *Store) write(e *Entry) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal queue entry: %w", err)
+	}
+
+	if err := os.WriteFile(s.path(e.ID), data, 0644); err != nil {
+		return fmt.Errorf("failed to write queue entry: %w", err)
+	}
+	return nil
+}
+
+// Parked reports whether the entry failed to dispatch MaxAttempts times; it
+// stays queued but is no longer dispatched until retried
+func (e *Entry) Parked() bool {
+	return e.Attempts >= MaxAttempts
+}
+
+// PriorityOf returns the priority of the entry, normal if unset
+func (e *Entry) PriorityOf() string {
+	if e.Priority == "" {
+		return PriorityNormal
+	}
+	return e.Priority
+}
+
+// Preempts reports whether the entry is dispatched before other
+func (e *Entry) Preempts(other *Entry) bool {
+	return priorityRank(e.PriorityOf()) < priorityRank(other.PriorityOf())
+}
+
+// List returns all entries in dispatch order: higher priorities first, then
+// oldest first
+func (s *Store) List() ([]*Entry, error) {
+	files, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queue directory: %w", err)
+	}
+
+	var entries []*Entry
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read queue entry: %w", err)
+		}
+
+		var e Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("failed to parse queue entry %s: %w", file.Name(), err)
+		}
+		entries = append(entries, &e)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Preempts(entries[j]) || entries[j].Preempts(entries[i]) {
+			return entries[i].Preempts(entries[j])
+		}
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+
+	return entries, nil
+}
+
+// Remove deletes an entry by ID
+func (s *Store) Remove(id string) error {
+	if err := os.Remove(s.path(id)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("queue entry %s not found", id)
+		}
+		return fmt.Errorf("failed to remove queue entry: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
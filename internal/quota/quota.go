@@ -0,0 +1,128 @@
+package quota
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/internal/github"
+)
+
+// activeStatuses are workflow run statuses that count as concurrent runs
+var activeStatuses = map[string]bool{
+	"queued":      true,
+	"in_progress": true,
+	"waiting":     true,
+	"requested":   true,
+	"pending":     true,
+}
+
+// Usage is the current run activity in the repository
+type Usage struct {
+	RunsToday      int
+	ConcurrentRuns int
+}
+
+// Decision is the outcome of a quota check
+type Decision struct {
+	Allowed bool
+	Reason  string
+}
+
+// Window is a daily time range in minutes since midnight.
+// Windows where End <= Start wrap past midnight (e.g. 22:00-06:00).
+type Window struct {
+	Start int
+	End   int
+}
+
+// ParseWindow parses a window such as "22:00-06:00"
+func ParseWindow(s string) (Window, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return Window{}, fmt.Errorf("invalid window %q (expected HH:MM-HH:MM)", s)
+	}
+
+	start, err := parseClock(parts[0])
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid window %q: %w", s, err)
+	}
+	end, err := parseClock(parts[1])
+	if err != nil {
+		return Window{}, fmt.Errorf("invalid window %q: %w", s, err)
+	}
+
+	return Window{Start: start, End: end}, nil
+}
+
+// Contains reports whether t falls inside the window
+func (w Window) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	// Wraps past midnight
+	return minute >= w.Start || minute < w.End
+}
+
+// MeasureUsage counts today's runs and currently active runs
+func MeasureUsage(client *github.Client, now time.Time) (Usage, error) {
+	runs, err := client.ListWorkflowRuns(100)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var usage Usage
+	for _, run := range runs {
+		if !run.CreatedAt.Before(startOfDay) {
+			usage.RunsToday++
+		}
+		if activeStatuses[run.Status] {
+			usage.ConcurrentRuns++
+		}
+	}
+
+	return usage, nil
+}
+
+// Check decides whether a new run may be dispatched now.
+// now must be in the time zone the windows are expressed in.
+func Check(cfg config.QuotasConfig, usage Usage, now time.Time) (Decision, error) {
+	if len(cfg.Windows) > 0 {
+		inWindow := false
+		for _, spec := range cfg.Windows {
+			window, err := ParseWindow(spec)
+			if err != nil {
+				return Decision{}, err
+			}
+			if window.Contains(now) {
+				inWindow = true
+				break
+			}
+		}
+		if !inWindow {
+			return Decision{Reason: fmt.Sprintf("outside allowed windows (%s)", strings.Join(cfg.Windows, ", "))}, nil
+		}
+	}
+
+	if cfg.MaxRunsPerDay > 0 && usage.RunsToday >= cfg.MaxRunsPerDay {
+		return Decision{Reason: fmt.Sprintf("daily run limit reached (%d/%d)", usage.RunsToday, cfg.MaxRunsPerDay)}, nil
+	}
+
+	if cfg.MaxConcurrentRuns > 0 && usage.ConcurrentRuns >= cfg.MaxConcurrentRuns {
+		return Decision{Reason: fmt.Sprintf("concurrent run limit reached (%d/%d)", usage.ConcurrentRuns, cfg.MaxConcurrentRuns)}, nil
+	}
+
+	return Decision{Allowed: true}, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
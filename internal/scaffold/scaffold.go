@@ -0,0 +1,93 @@
+package scaffold
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// stacks holds one directory of starter files per stack. Files carry a .tmpl
+// suffix so Go tooling ignores them (e.g. go.mod would split the module).
+//
+//go:embed all:stacks
+var stacks embed.FS
+
+// Data is passed to every scaffold file template
+type Data struct {
+	Owner string
+	Name  string
+}
+
+// descriptions summarize each stack for help output and the first run task
+var descriptions = map[string]string{
+	"go-api":     "Go HTTP API using net/http",
+	"node-api":   "Node.js HTTP API without dependencies",
+	"python-api": "Python HTTP API using the standard library",
+}
+
+// Stacks returns the names of all available stacks
+func Stacks() []string {
+	names := make([]string, 0, len(descriptions))
+	for name := range descriptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Description returns a one-line summary of a stack
+func Description(stack string) string {
+	return descriptions[stack]
+}
+
+// Validate returns an error for unknown stacks
+func Validate(stack string) error {
+	if _, ok := descriptions[stack]; !ok {
+		return fmt.Errorf("unknown stack %q (available: %s)", stack, strings.Join(Stacks(), ", "))
+	}
+	return nil
+}
+
+// Files renders the starter files of a stack, keyed by repository path
+func Files(stack string, data Data) (map[string][]byte, error) {
+	if err := Validate(stack); err != nil {
+		return nil, err
+	}
+
+	root := path.Join("stacks", stack)
+	files := map[string][]byte{}
+
+	err := fs.WalkDir(stacks, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		content, err := stacks.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		tmpl, err := template.New(p).Delims("[[", "]]").Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", p, err)
+		}
+
+		var out strings.Builder
+		if err := tmpl.Execute(&out, data); err != nil {
+			return fmt.Errorf("failed to render %s: %w", p, err)
+		}
+
+		rel := strings.TrimSuffix(strings.TrimPrefix(p, root+"/"), ".tmpl")
+		files[rel] = []byte(out.String())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
@@ -0,0 +1,168 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron is a parsed five-field cron expression (minute hour day-of-month
+// month day-of-week). Expressions are evaluated in UTC, like GitHub Actions
+// schedules.
+type Cron struct {
+	expr   string
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	anyDom bool
+	anyDow bool
+}
+
+// field bounds in cron order
+var fields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Parse parses a cron expression such as "0 3 * * 1-5". Fields accept *,
+// numbers, ranges (a-b), steps (*/n, a-b/n) and comma-separated lists.
+func Parse(expr string) (*Cron, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(parts))
+	}
+
+	sets := make([]uint64, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i].min, fields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %s: %w", expr, fields[i].name, err)
+		}
+		sets[i] = set
+	}
+
+	// Sunday is both 0 and 7
+	dow := sets[4]
+	if dow&(1<<7) != 0 {
+		dow |= 1
+	}
+
+	return &Cron{
+		expr:   strings.Join(parts, " "),
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    dow,
+		anyDom: strings.HasPrefix(parts[2], "*"),
+		anyDow: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// parseField returns the set of values a field matches as a bitmask
+func parseField(field string, min, max int) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		rng, step := item, 1
+		if r, s, ok := strings.Cut(item, "/"); ok {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step %q", s)
+			}
+			rng, step = r, n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(a); err != nil {
+				return 0, fmt.Errorf("invalid value %q", a)
+			}
+			if hi, err = strconv.Atoi(b); err != nil {
+				return 0, fmt.Errorf("invalid value %q", b)
+			}
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", rng)
+			}
+			lo, hi = n, n
+			// "5/15" runs from 5 to the end of the range
+			if step > 1 {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is outside %d-%d", rng, min, max)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// String returns the normalized expression
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Matches reports whether the expression fires in the minute of t
+func (c *Cron) Matches(t time.Time) bool {
+	t = t.UTC()
+	return c.minute&(1<<uint(t.Minute())) != 0 && c.hour&(1<<uint(t.Hour())) != 0 && c.dayMatches(t)
+}
+
+// dayMatches reports whether the expression fires on the day of t
+func (c *Cron) dayMatches(t time.Time) bool {
+	if c.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	// When both days are restricted either may match, as in standard cron
+	switch {
+	case c.anyDom && c.anyDow:
+		return true
+	case c.anyDom:
+		return dowMatch
+	case c.anyDow:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// Next returns the first time after t the expression fires, or the zero time
+// if it never does (e.g. "0 0 31 2 *")
+func (c *Cron) Next(t time.Time) time.Time {
+	next := t.UTC().Truncate(time.Minute).Add(time.Minute)
+	// Every combination of fields repeats within a leap year cycle
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		switch {
+		case !c.dayMatches(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, time.UTC)
+		case c.hour&(1<<uint(next.Hour())) == 0:
+			next = next.Truncate(time.Hour).Add(time.Hour)
+		case c.minute&(1<<uint(next.Minute())) == 0:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
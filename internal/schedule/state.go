@@ -0,0 +1,56 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// State records when each schedule last fired, by name
+type State map[string]time.Time
+
+// DefaultPath returns the file recording when schedules last fired
+func DefaultPath() string {
+	return filepath.Join(".autonomous-dev", "schedules.json")
+}
+
+// LoadState reads the state at path. A missing file is an empty state.
+func LoadState(path string) (State, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return State{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule state: %w", err)
+	}
+
+	state := State{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule state %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// Save writes the state to path
+func (s State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schedule state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write schedule state: %w", err)
+	}
+	return nil
+}
+
+// Due reports whether a schedule last checked at last has fired by now.
+// Firings missed while nothing was checking collapse into one.
+func Due(c *Cron, last, now time.Time) bool {
+	next := c.Next(last)
+	return !next.IsZero() && !next.After(now)
+}
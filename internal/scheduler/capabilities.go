@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/config"
+	"github.com/autonomous-dev/cli/pkg/protocol"
+)
+
+// Reassignment moves a pending task to another instance
+type Reassignment struct {
+	TaskID string
+	From   int
+	To     int
+}
+
+// Constraints returns why a probed runner is unfit for heavy tasks; none
+// means it is unconstrained
+func Constraints(caps *protocol.Capabilities, cfg config.CapabilitiesConfig) []string {
+	var reasons []string
+	if caps.MemoryMB > 0 && caps.MemoryMB < cfg.MinMemory() {
+		reasons = append(reasons, fmt.Sprintf("%d MB memory (< %d)", caps.MemoryMB, cfg.MinMemory()))
+	}
+	if caps.CPUs > 0 && caps.CPUs < cfg.MinCPU() {
+		reasons = append(reasons, fmt.Sprintf("%d CPU(s) (< %d)", caps.CPUs, cfg.MinCPU()))
+	}
+	if !caps.ProviderReachable {
+		reasons = append(reasons, "provider unreachable")
+	}
+	return reasons
+}
+
+// IsHeavy reports whether a task description mentions a heavy keyword
+func IsHeavy(description string, cfg config.CapabilitiesConfig) bool {
+	words := taskWords(description)
+	for _, keyword := range cfg.Keywords() {
+		if words[strings.ToLower(keyword)] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDeployable reports whether a task description mentions a deployment keyword
+func IsDeployable(description string, cfg config.DeploymentsConfig) bool {
+	words := taskWords(description)
+	for _, keyword := range cfg.Keywords {
+		if words[strings.ToLower(keyword)] {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanReassignments moves heavy tasks without a result away from constrained
+// instances, each to the unconstrained instance with the least pending work.
+// weigh returns a task's weight from its description (nil weighs every task
+// 1). Instances that have not reported capabilities are left alone.
+func PlanReassignments(snap *protocol.Snapshot, cfg config.CapabilitiesConfig, weigh func(description string) int) []Reassignment {
+	var capable []int
+	for id, caps := range snap.Capabilities {
+		if len(Constraints(caps, cfg)) == 0 {
+			capable = append(capable, id)
+		}
+	}
+	if len(capable) == 0 {
+		return nil
+	}
+	sort.Ints(capable)
+
+	var moving []*protocol.Assignment
+	for _, taskID := range snap.TaskIDs() {
+		a := snap.Assignments[taskID]
+		if _, done := snap.Results[taskID]; done {
+			continue
+		}
+		caps, probed := snap.Capabilities[a.InstanceID]
+		if probed && len(Constraints(caps, cfg)) > 0 && IsHeavy(a.Description, cfg) {
+			moving = append(moving, a)
+		}
+	}
+	if len(moving) == 0 {
+		return nil
+	}
+
+	if weigh == nil {
+		weigh = func(string) int { return 1 }
+	}
+	weights := map[string]int{}
+	pending := map[int]int{}
+	for taskID, a := range snap.Assignments {
+		if _, done := snap.Results[taskID]; !done {
+			weights[taskID] = weigh(a.Description)
+			pending[a.InstanceID] += weights[taskID]
+		}
+	}
+
+	plan := make([]Reassignment, 0, len(moving))
+	for _, a := range moving {
+		to := capable[0]
+		for _, id := range capable[1:] {
+			if pending[id] < pending[to] {
+				to = id
+			}
+		}
+		pending[a.InstanceID] -= weights[a.TaskID]
+		pending[to] += weights[a.TaskID]
+		plan = append(plan, Reassignment{TaskID: a.TaskID, From: a.InstanceID, To: to})
+	}
+	return plan
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/config"
+)
+
+// SelectAgent picks the agent for a task. An explicit name must match a
+// configured agent; otherwise the agent with the most skills mentioned in
+// the task is chosen. It returns nil when no agent matches.
+func SelectAgent(agents []config.Agent, task, name string) (*config.Agent, error) {
+	if name != "" {
+		for i := range agents {
+			if agents[i].Name == name {
+				return &agents[i], nil
+			}
+		}
+		return nil, fmt.Errorf("unknown agent %q", name)
+	}
+
+	words := taskWords(task)
+
+	var best *config.Agent
+	bestScore := 0
+	for i := range agents {
+		score := 0
+		for _, skill := range agents[i].Skills {
+			if words[strings.ToLower(skill)] {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = &agents[i], score
+		}
+	}
+
+	return best, nil
+}
+
+// MergeInputs layers explicit workflow inputs over the agent's defaults
+func MergeInputs(agent *config.Agent, inputs map[string]string) map[string]string {
+	merged := map[string]string{}
+	if agent != nil {
+		for name, value := range agent.Inputs {
+			merged[name] = value
+		}
+	}
+	for name, value := range inputs {
+		merged[name] = value
+	}
+	return merged
+}
+
+// taskWords returns the lower-cased words of a task, split on anything
+// that cannot be part of a skill name such as "unit-test"
+func taskWords(task string) map[string]bool {
+	fields := strings.FieldsFunc(strings.ToLower(task), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '+' || r == '#')
+	})
+
+	words := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		words[f] = true
+	}
+	return words
+}
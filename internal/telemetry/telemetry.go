@@ -0,0 +1,83 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Error classes. Error messages are never sent; only one of these classes.
+const (
+	ClassNone       = "none"
+	ClassUsage      = "usage"
+	ClassConfig     = "config"
+	ClassPermission = "permission"
+	ClassGitHub     = "github_api"
+	ClassNetwork    = "network"
+	ClassOther      = "other"
+)
+
+// Event is everything sent for one command invocation. It deliberately
+// holds no arguments, repository names, paths or error messages.
+type Event struct {
+	Command    string `json:"command"`
+	DurationMS int64  `json:"duration_ms"`
+	ErrorClass string `json:"error_class"`
+}
+
+// NewEvent describes a finished command
+func NewEvent(command string, duration time.Duration, errorClass string) Event {
+	if errorClass == "" {
+		errorClass = ClassNone
+	}
+	return Event{
+		Command:    command,
+		DurationMS: duration.Milliseconds(),
+		ErrorClass: errorClass,
+	}
+}
+
+// Payload returns the exact request body sent for an event
+func Payload(e Event) ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal telemetry event: %w", err)
+	}
+	return data, nil
+}
+
+// Sender posts events to a telemetry endpoint
+type Sender struct {
+	Endpoint string
+	client   *http.Client
+}
+
+// NewSender creates a sender. The short timeout keeps telemetry from
+// noticeably delaying command exit.
+func NewSender(endpoint string) *Sender {
+	return &Sender{
+		Endpoint: endpoint,
+		client:   &http.Client{Timeout: 2 * time.Second},
+	}
+}
+
+// Send posts an event
+func (s *Sender) Send(e Event) error {
+	payload, err := Payload(e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.Endpoint, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send telemetry: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package template
+
+// issueTemplate is the built-in body of the coordination issue
+const issueTemplate = `# Autonomous Development Task
+
+[[block "task" .]][[.Task]]
+[[end]]
+## Configuration
+[[block "configuration" .]]- Instances: [[.Instances]]
+- Repository: [[.Config.GitHub.Owner]]/[[.Config.GitHub.Repo]]
+[[with .Agent]]- Agent: [[.Name]]
+[[end]][[with .BaseSHA]]- Base commit: [[.]]
+[[end]][[with .Environment]]- Environment: [[.]]
+[[end]][[end]][[block "scope" .]][[with .Config.Paths]]
+## Scope
+Work only within these paths unless the task requires otherwise:
+[[range .]]- ` + "`[[.]]`" + `
+[[end]][[end]][[end]][[block "sections" .]][[with .Agent]][[range .Sections]]
+## [[.Title]]
+[[.Body]]
+[[end]][[end]][[end]][[block "changes" .]][[with .Changes]]
+## Changes Since Last Run
+[[.]][[end]][[end]]
+[[block "footer" .]]This issue will be used for P2P coordination between Claude Code instances.
+[[end]]`
+
+// IssueBody generates the coordination issue body for a task, including the
+// selected agent's extra sections and context changes, applying any
+// configured template overrides
+func IssueBody(data Data) (string, error) {
+	resolved, err := Resolve(IssueName, data.Config, data)
+	if err != nil {
+		return "", err
+	}
+	return resolved.Output, nil
+}
@@ -0,0 +1,205 @@
+package template
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+)
+
+// Template names that can be resolved and overridden
+const (
+	WorkflowName = "workflow"
+	IssueName    = "issue"
+)
+
+// Delimiters avoid clashing with GitHub Actions ${{ }} expressions
+const (
+	leftDelim  = "[["
+	rightDelim = "]]"
+)
+
+// builtins maps template names to their built-in content
+var builtins = map[string]string{
+	WorkflowName: workflowTemplate,
+	IssueName:    issueTemplate,
+}
+
+// Data is passed to every template
+type Data struct {
+	Config    *config.Config
+	Task      string
+	Instances int
+	// Agent is the agent selected for the task, if any
+	Agent *config.Agent
+	// Changes lists context files changed since the last run, as Markdown
+	Changes string
+	// BaseSHA is the commit every instance checks out
+	BaseSHA string
+	// Environment is the deployment environment the run affects, if any
+	Environment string
+}
+
+// Layer is one source that contributed to a resolved template
+type Layer struct {
+	Source string
+	Blocks []string
+}
+
+// Resolved is the final output of a template and the layers it came from
+type Resolved struct {
+	Name   string
+	Output string
+	Layers []Layer
+}
+
+// Names returns all template names that can be resolved
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OverridesDir returns the directory holding repository template overrides
+func OverridesDir() string {
+	return filepath.Join(".autonomous-dev", "overrides")
+}
+
+// Resolve renders a template by layering, in order, the built-in content,
+// the organization-level override fetched from templates.org_url, and the
+// repository override in .autonomous-dev/overrides/<name>.tmpl.
+// Override files are partials that redefine blocks with [[define "block"]].
+func Resolve(name string, cfg *config.Config, data Data) (*Resolved, error) {
+	builtin, ok := builtins[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+
+	tmpl, err := template.New(name).Delims(leftDelim, rightDelim).Funcs(funcs(cfg)).Parse(builtin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse built-in template %s: %w", name, err)
+	}
+
+	resolved := &Resolved{
+		Name:   name,
+		Layers: []Layer{{Source: "built-in", Blocks: blockNames(tmpl, name)}},
+	}
+
+	// Organization-level overrides
+	if cfg != nil && cfg.Templates.OrgURL != "" {
+		url := strings.TrimSuffix(cfg.Templates.OrgURL, "/") + "/" + name + ".tmpl"
+		content, err := fetchOverride(url)
+		if err != nil {
+			return nil, err
+		}
+		if content != "" {
+			if err := applyOverride(tmpl, resolved, url, content, cfg); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Repository overrides
+	path := filepath.Join(OverridesDir(), name+".tmpl")
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read override %s: %w", path, err)
+	}
+	if err == nil {
+		// Overrides edited on Windows may have CRLF line endings
+		normalized := strings.ReplaceAll(string(content), "\r\n", "\n")
+		if err := applyOverride(tmpl, resolved, path, normalized, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	var out strings.Builder
+	if err := tmpl.ExecuteTemplate(&out, name, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	resolved.Output = out.String()
+
+	return resolved, nil
+}
+
+// funcs are available to built-in templates and overrides
+func funcs(cfg *config.Config) template.FuncMap {
+	return template.FuncMap{
+		// input references a run parameter: the workflow_dispatch input, falling
+		// back to the repository_dispatch payload in dispatch coordination mode
+		"input": func(name string) string {
+			if cfg != nil && cfg.Coordination.Dispatch() {
+				return fmt.Sprintf("(inputs.%s || github.event.client_payload.%s)", name, name)
+			}
+			return "inputs." + name
+		},
+	}
+}
+
+// applyOverride parses an override partial into the template set
+func applyOverride(tmpl *template.Template, resolved *Resolved, source, content string, cfg *config.Config) error {
+	override, err := template.New(source).Delims(leftDelim, rightDelim).Funcs(funcs(cfg)).Parse(content)
+	if err != nil {
+		return fmt.Errorf("failed to parse override %s: %w", source, err)
+	}
+
+	var blocks []string
+	for _, t := range override.Templates() {
+		if t.Name() == source || t.Tree == nil {
+			continue
+		}
+		if _, err := tmpl.AddParseTree(t.Name(), t.Tree); err != nil {
+			return fmt.Errorf("failed to apply block %q from %s: %w", t.Name(), source, err)
+		}
+		blocks = append(blocks, t.Name())
+	}
+	sort.Strings(blocks)
+
+	resolved.Layers = append(resolved.Layers, Layer{Source: source, Blocks: blocks})
+	return nil
+}
+
+// fetchOverride downloads an organization override; a 404 means no override
+func fetchOverride(url string) (string, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch override %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch override %s: %s", url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read override %s: %w", url, err)
+	}
+	return string(body), nil
+}
+
+// blockNames lists the overridable blocks of a template set
+func blockNames(tmpl *template.Template, root string) []string {
+	var names []string
+	for _, t := range tmpl.Templates() {
+		if t.Name() != root {
+			names = append(names, t.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,104 @@
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/autonomous-dev/cli/internal/config"
+)
+
+// scheduleTemplate is the trigger workflow that starts recurring tasks on
+// their cron schedule. Each step creates the coordination issue and
+// dispatches the main workflow for it.
+const scheduleTemplate = `name: Autonomous Development Schedules
+# Generated by autonomous-dev schedule; edit schedules with
+# 'autonomous-dev schedule add/remove' rather than by hand
+
+on:
+  schedule:
+[[range .Crons]]    - cron: '[[.]]'
+[[end]]  workflow_dispatch:
+    inputs:
+      schedule:
+        description: 'Name of the schedule to start now'
+        required: true
+        type: string
+
+permissions:
+  actions: write
+  issues: write
+
+jobs:
+  start:
+    runs-on: ubuntu-latest
+    steps:
+[[range .Tasks]]      - name: Start [[.Name]]
+        if: github.event.schedule == '[[.Cron]]' || inputs.schedule == '[[.Name]]'
+        env:
+          GH_TOKEN: ${{ github.token }}
+          GH_REPO: ${{ github.repository }}
+          TITLE: [[quote .Task]]
+          BODY: |
+[[indent 12 .Body]]
+        run: |
+          url=$(gh issue create --title "$TITLE" --body "$BODY")
+          gh workflow run [[$.Workflow]] -f issue_number="${url##*/}" -f instance_count=[[.Instances]][[range $k, $v := .Inputs]] -f [[$k]]=[[quote $v]][[end]]
+[[end]]`
+
+// ScheduledTask is a schedule started by the trigger workflow, with the
+// coordination issue body rendered for it
+type ScheduledTask struct {
+	config.Schedule
+	Instances int
+	// Inputs are the agent's default workflow_dispatch inputs
+	Inputs map[string]string
+	Body   string
+}
+
+// ScheduleWorkflow generates the trigger workflow for scheduled tasks
+func ScheduleWorkflow(cfg *config.Config, tasks []ScheduledTask) (string, error) {
+	tmpl, err := template.New("schedule").Delims(leftDelim, rightDelim).Funcs(template.FuncMap{
+		// JSON strings are valid double-quoted YAML scalars
+		"quote": func(s string) string {
+			b, _ := json.Marshal(s)
+			return string(b)
+		},
+		"indent": func(n int, s string) string {
+			pad := strings.Repeat(" ", n)
+			lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+			for i, line := range lines {
+				if line != "" {
+					lines[i] = pad + line
+				}
+			}
+			return strings.Join(lines, "\n")
+		},
+	}).Parse(scheduleTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse schedule template: %w", err)
+	}
+
+	// Schedules sharing a cron expression share one trigger
+	var crons []string
+	seen := map[string]bool{}
+	for _, task := range tasks {
+		if !seen[task.Cron] {
+			seen[task.Cron] = true
+			crons = append(crons, task.Cron)
+		}
+	}
+
+	var out strings.Builder
+	err = tmpl.Execute(&out, map[string]interface{}{
+		"Crons":    crons,
+		"Tasks":    tasks,
+		"Workflow": path.Base(cfg.Workflow.File),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render schedule workflow: %w", err)
+	}
+	return out.String(), nil
+}
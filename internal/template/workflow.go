@@ -2,13 +2,19 @@ package template
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/autonomous-dev/cli/internal/config"
+	"gopkg.in/yaml.v3"
 )
 
-// WorkflowTemplate generates the GitHub Actions workflow YAML
-func WorkflowTemplate(cfg *config.Config) string {
-	return fmt.Sprintf(`name: Autonomous Development
+// workflowTemplate is the built-in GitHub Actions workflow.
+// Sections wrapped in [[block]] can be replaced by override files.
+const workflowTemplate = `name: Autonomous Development
+# autonomous-dev reads the issue, base commit and environment back from the
+# run title; keep "(issue #N)", "@ <sha>" and a trailing "→ <environment>"
+# when changing it
+run-name: ${{ format('Autonomous Development{0}{1}{2}{3}', inputs.issue_number && format(' (issue #{0})', inputs.issue_number) || '', [[input "trace_id"]] && format(' [{0}]', [[input "trace_id"]]) || '', [[input "base_sha"]] && format(' @ {0}', [[input "base_sha"]]) || '', [[input "environment"]] && format(' → {0}', [[input "environment"]]) || '') }}
 
 on:
   workflow_dispatch:
@@ -20,9 +26,66 @@ on:
       instance_count:
         description: 'Number of parallel instances'
         required: false
-        default: '%d'
+        default: '[[.Config.Instances.Default]]'
         type: string
+      untrusted:
+        description: 'Run on a fork without repo-write credentials'
+        required: false
+        default: 'false'
+        type: string
+      fork_repo:
+        description: 'Fork (owner/repo) used by untrusted runs'
+        required: false
+        default: ''
+        type: string
+      base_sha:
+        description: 'Commit every instance checks out (default: the dispatched ref)'
+        required: false
+        default: ''
+        type: string
+      environment:
+        description: 'Deployment environment whose protection rules gate the instances'
+        required: false
+        default: ''
+        type: string
+      env_lock:
+        description: 'Environment lock (JSON) of a past run to reproduce'
+        required: false
+        default: ''
+        type: string
+      canary:
+        description: 'Run a single canary instance on a representative subtask'
+        required: false
+        default: 'false'
+        type: string
+      first_instance:
+        description: 'First instance number (instances before it already ran as a canary)'
+        required: false
+        default: '1'
+        type: string
+      log_level:
+        description: 'Instance log level: debug, info, warn or error'
+        required: false
+        default: '[[.Config.Logs.InstanceLevel]]'
+        type: string
+      run_env:
+        description: 'Environment variables (JSON object) set for every instance of the run'
+        required: false
+        default: ''
+        type: string
+      trace_id:
+        description: 'Trace ID tying the run to its issue, logs and notifications'
+        required: false
+        default: ''
+        type: string
+[[block "inputs" .]][[end]][[if .Config.Coordination.Dispatch]]  repository_dispatch:
+    types: [autonomous-dev]
 
+# Coordination state is committed to the state branch
+permissions:
+  contents: write
+  pull-requests: write
+[[end]]
 jobs:
   setup:
     runs-on: ubuntu-latest
@@ -32,73 +95,366 @@ jobs:
       - name: Generate instance matrix
         id: set-matrix
         run: |
-          count=${{ inputs.instance_count }}
-          matrix=$(seq 1 $count | jq -R . | jq -s -c .)
+          count=${{ [[input "instance_count"]] }}
+          first=${{ [[input "first_instance"]] || 1 }}
+          matrix=$(seq $first $count | jq -R . | jq -s -c .)
           echo "matrix=$matrix" >> $GITHUB_OUTPUT
 
-  autonomous-dev:
-    needs: setup
+      # Records the issue even if run-name is changed
+      - name: Record issue number
+        if: inputs.issue_number != ''
+        run: echo "${{ inputs.issue_number }}" > issue-number.txt
+
+      - name: Upload issue number
+        if: inputs.issue_number != ''
+        uses: actions/upload-artifact@v4
+        with:
+          name: autonomous-dev-issue-${{ inputs.issue_number }}
+          path: issue-number.txt
+          retention-days: 90
+
+  # Waits for the environment's protection rules (required reviewers, wait
+  # timers) before any instance starts; skipped for runs without one
+  environment:
+    if: [[input "environment"]] != ''
     runs-on: ubuntu-latest
+    environment: ${{ [[input "environment"]] }}
+    steps:
+      - name: Approve instances
+        run: echo "Environment ${{ [[input "environment"]] }} approved"
+
+  # Trusted runs: the instances push their branches, open pull requests and
+  # report to the [[if .Config.Coordination.Dispatch]]state branch[[else]]issue[[end]] with the repository's token
+  autonomous-dev:
+    needs: [setup, environment]
+    if: ${{ !cancelled() && !failure() && [[input "untrusted"]] != 'true' }}
+    runs-on: [[block "runs-on" .]][[with .Config.Runners.Labels]][self-hosted[[range .]], [[.]][[end]]][[else]]${{ fromJson([[input "env_lock"]] || '{}').runner_label || 'ubuntu-latest' }}[[end]][[end]]
+    permissions:
+      contents: write
+      pull-requests: write
+[[- if not .Config.Coordination.Dispatch]]
+      issues: write
+[[- end]]
+    # Scripts assume bash; Windows runners use Git Bash instead of pwsh
+    defaults:
+      run:
+        shell: bash
     strategy:
       matrix:
         instance: ${{ fromJson(needs.setup.outputs.matrix) }}
-      max-parallel: %d
+      max-parallel: [[.Config.Workflow.Concurrency]]
 
     steps:
+      # Pinned to base_sha so all instances start from the same commit
       - name: Checkout repository
         uses: actions/checkout@v4
+        with:
+          ref: ${{ [[input "base_sha"]] }}
+[[template "instance-steps" .]]
+  # Untrusted runs execute fork code, so their instances only ever see the
+  # fork-scoped token and a repository token that can merely read; the
+  # report job audits, reports and labels from their uploaded output
+  autonomous-dev-untrusted:
+    name: autonomous-dev
+    needs: [setup, environment]
+    if: ${{ !cancelled() && !failure() && [[input "untrusted"]] == 'true' }}
+    runs-on: [[template "runs-on" .]]
+    permissions:
+      contents: read
+    defaults:
+      run:
+        shell: bash
+    strategy:
+      matrix:
+        instance: ${{ fromJson(needs.setup.outputs.matrix) }}
+      max-parallel: [[.Config.Workflow.Concurrency]]
+
+    steps:
+      - name: Checkout fork
+        uses: actions/checkout@v4
+        with:
+          repository: ${{ [[input "fork_repo"]] }}
+          token: ${{ secrets.[[or .Config.Untrusted.TokenSecret "FORK_TOKEN"]] }}
+          persist-credentials: false
+
+      # Without this check an empty secret would fall back to GITHUB_TOKEN below
+      - name: Verify fork credentials
+        env:
+          FORK_TOKEN: ${{ secrets.[[or .Config.Untrusted.TokenSecret "FORK_TOKEN"]] }}
+        run: |
+          if [ -z "$FORK_TOKEN" ]; then
+            echo "::error::Secret [[or .Config.Untrusted.TokenSecret "FORK_TOKEN"]] is required for untrusted runs"
+            exit 1
+          fi
+[[template "instance-steps" .]]
+  # Audits, archives and reports each instance's output without running fork
+  # code: it checks out only the reporter of the triggering commit and treats
+  # what the instance uploaded as untrusted data
+  report:
+    name: Report instance ${{ matrix.instance }}
+    needs: [setup, autonomous-dev, autonomous-dev-untrusted]
+    if: ${{ always() && (needs.autonomous-dev.result != 'skipped' || needs.autonomous-dev-untrusted.result != 'skipped') }}
+    runs-on: ubuntu-latest
+    permissions:
+      contents: [[if .Config.Coordination.Dispatch]]write[[else]]read[[end]]
+[[- if not .Config.Coordination.Dispatch]]
+      issues: write
+[[- end]]
+      pull-requests: write
+      # Deletes the unredacted instance output once the log is archived
+      actions: write
+    strategy:
+      fail-fast: false
+      matrix:
+        instance: ${{ fromJson(needs.setup.outputs.matrix) }}
+
+    steps:
+      - name: Checkout reporter
+        uses: actions/checkout@v4
+        with:
+          ref: ${{ github.sha }}
+          sparse-checkout: scripts
+          persist-credentials: false
+
+      - name: Download instance output
+        continue-on-error: true
+        uses: actions/download-artifact@v4
+        with:
+          name: autonomous-dev-output-${{ matrix.instance }}-attempt-${{ github.run_attempt }}
+          path: ${{ runner.temp }}/instance-output
+[[block "audit" .]][[if ne .Config.Audit.SecretsMode "off"]]
+      - name: Install secret scanner
+        continue-on-error: true
+        run: |
+          # Without gitleaks the audit falls back to built-in token patterns
+          version=8.18.4
+          dir="$RUNNER_TEMP/gitleaks"
+          mkdir -p "$dir"
+          curl -sSfL -o "$dir/gitleaks.tar.gz" "https://github.com/gitleaks/gitleaks/releases/download/v$version/gitleaks_${version}_linux_x64.tar.gz"
+          tar -xzf "$dir/gitleaks.tar.gz" -C "$dir"
+          echo "$dir" >> "$GITHUB_PATH"
+
+      - name: Audit for leaked secrets
+        id: audit
+        if: always()
+        env:
+          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+          GH_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+          INSTANCE_ID: ${{ matrix.instance }}
+          ISSUE_NUMBER: ${{ [[input "issue_number"]] }}
+[[- if .Config.Coordination.Dispatch]]
+          COORDINATION_MODE: dispatch
+          RUN_ID: ${{ github.event.client_payload.run_id }}
+          STATE_BRANCH: [[.Config.Coordination.Branch]]
+[[- end]]
+        run: |
+          source ./scripts/instance-status-reporter.sh
+          mkdir -p "$RUNNER_TEMP/instance-output"
+          if findings=$(audit_secrets "$RUNNER_TEMP/instance-output"); then
+            echo "No leaked secrets found"
+            echo "findings=0" >> "$GITHUB_OUTPUT"
+            exit 0
+          fi
+          count=$(printf '%s\n' "$findings" | wc -l | tr -d ' ')
+          echo "findings=$count" >> "$GITHUB_OUTPUT"
+          {
+            echo "### 🔒 Secret audit: instance $INSTANCE_ID"
+            echo
+            printf '%s\n' "$findings" | sed 's/^/- /'
+            echo
+            echo "Secrets were redacted from the archived instance log."
+          } >> "$GITHUB_STEP_SUMMARY"
+          echo "::warning::Secret audit found $count leaked secret(s)"
+          publish_comment "🔒 Instance $INSTANCE_ID: secret audit found $count leaked secret(s) ($(printf '%s\n' "$findings" | paste -sd ';' - | sed 's/;/; /g')); the instance log was redacted before archiving"
+[[end]][[end]]
+      - name: Archive instance log
+        if: always()
+        uses: actions/upload-artifact@v4
+        with:
+          name: autonomous-dev-instance-log-${{ matrix.instance }}-attempt-${{ github.run_attempt }}
+          path: ${{ runner.temp }}/instance-output/log
+          if-no-files-found: ignore
+
+      - name: Remove instance output
+        if: always()
+        env:
+          GH_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+        run: |
+          name="autonomous-dev-output-${{ matrix.instance }}-attempt-$GITHUB_RUN_ATTEMPT"
+          gh api "repos/$GITHUB_REPOSITORY/actions/runs/$GITHUB_RUN_ID/artifacts?name=$name" -q '.artifacts[].id' \
+            | while read -r id; do gh api -X DELETE "repos/$GITHUB_REPOSITORY/actions/artifacts/$id"; done
+[[block "report" .]]
+      - name: Report status
+        if: always()
+        env:
+          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+          GH_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+          INSTANCE_ID: ${{ matrix.instance }}
+          ISSUE_NUMBER: ${{ [[input "issue_number"]] }}
+[[- if .Config.Coordination.Dispatch]]
+          COORDINATION_MODE: dispatch
+          RUN_ID: ${{ github.event.client_payload.run_id }}
+          STATE_BRANCH: [[.Config.Coordination.Branch]]
+[[- end]]
+        run: |
+          source ./scripts/instance-status-reporter.sh
+          if [ "$(cat "$RUNNER_TEMP/instance-output/result" 2>/dev/null)" = "success" ]; then
+            publish_comment "✅ Instance $INSTANCE_ID: Success"
+          else
+            publish_comment "❌ Instance $INSTANCE_ID: Failed"
+          fi
+
+      - name: Label fork pull request
+        if: always() && [[input "untrusted"]] == 'true'
+        env:
+          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+          GH_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+          INSTANCE_ID: ${{ matrix.instance }}
+          ISSUE_NUMBER: ${{ [[input "issue_number"]] }}
+[[- if .Config.Coordination.Dispatch]]
+          COORDINATION_MODE: dispatch
+          RUN_ID: ${{ github.event.client_payload.run_id }}
+          STATE_BRANCH: [[.Config.Coordination.Branch]]
+[[- end]]
+          FORK_REPO: ${{ [[input "fork_repo"]] }}
+          BRANCH_PREFIX: [[.Config.Branches.InstancePrefix]]
+          REVIEW_LABEL: [[or .Config.Untrusted.ReviewLabel "needs-maintainer-review"]]
+        run: |
+          source ./scripts/instance-status-reporter.sh
+          label_fork_pull_request
+[[end]][[if eq .Config.Audit.SecretsMode "block"]]
+      - name: Block on leaked secrets
+        if: always() && steps.audit.outputs.findings != '' && steps.audit.outputs.findings != '0'
+        run: |
+          echo "::error::Secret audit found ${{ steps.audit.outputs.findings }} leaked secret(s); see the job summary"
+          exit 1
+[[end]][[define "instance-steps"]]
+      # Reproduce the toolchains of a locked run (start --env-lock)
+      - name: Pin Go
+        if: fromJson([[input "env_lock"]] || '{}').toolchains.go
+        uses: actions/setup-go@v5
+        with:
+          go-version: ${{ fromJson([[input "env_lock"]] || '{}').toolchains.go }}
+
+      - name: Pin Node.js
+        if: fromJson([[input "env_lock"]] || '{}').toolchains.node
+        uses: actions/setup-node@v4
+        with:
+          node-version: ${{ fromJson([[input "env_lock"]] || '{}').toolchains.node }}
 
+      - name: Pin Python
+        if: fromJson([[input "env_lock"]] || '{}').toolchains.python3
+        uses: actions/setup-python@v5
+        with:
+          python-version: ${{ fromJson([[input "env_lock"]] || '{}').toolchains.python3 }}
+
+      # Variables given with start --env, for every later step
+      - name: Apply run environment
+        if: [[input "run_env"]] != ''
+        env:
+          RUN_ENV: ${{ [[input "run_env"]] }}
+        run: |
+          delimiter="EOF_$(openssl rand -hex 8)"
+          echo "$RUN_ENV" | jq -r --arg d "$delimiter" 'to_entries[] | "\(.key)<<\($d)\n\(.value)\n\($d)"' >> "$GITHUB_ENV"
+          echo "Set $(echo "$RUN_ENV" | jq -r 'keys | join(", ")')"
+[[block "setup-steps" .]]
       - name: Setup Claude Code environment
         run: |
           echo "Instance ${{ matrix.instance }} starting..."
-          echo "Processing issue #${{ inputs.issue_number }}"
+          echo "Processing [[if .Config.Coordination.Dispatch]]run ${{ github.event.client_payload.run_id }}[[else]]issue #${{ inputs.issue_number }}[[end]]"
 
       - name: Setup status reporter
         run: |
           # Make status reporter executable
           chmod +x ./scripts/instance-status-reporter.sh
-
+[[end]]
       - name: Run autonomous development
         env:
-          GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}
+          GITHUB_TOKEN: ${{ [[input "untrusted"]] == 'true' && secrets.[[or .Config.Untrusted.TokenSecret "FORK_TOKEN"]] || secrets.GITHUB_TOKEN }}
+          UNTRUSTED: ${{ [[input "untrusted"]] }}
+          FORK_REPO: ${{ [[input "fork_repo"]] }}
+          UPSTREAM_REPO: ${{ github.repository }}
+          REVIEW_LABEL: [[or .Config.Untrusted.ReviewLabel "needs-maintainer-review"]]
           INSTANCE_ID: ${{ matrix.instance }}
-          ISSUE_NUMBER: ${{ inputs.issue_number }}
-          TOTAL_INSTANCES: ${{ inputs.instance_count }}
+          ISSUE_NUMBER: ${{ [[input "issue_number"]] }}
+          TOTAL_INSTANCES: ${{ [[input "instance_count"]] }}
+          ENV_LOCK: ${{ [[input "env_lock"]] }}
+          CANARY: ${{ [[input "canary"]] }}
+          LOG_LEVEL: ${{ [[input "log_level"]] || '[[.Config.Logs.InstanceLevel]]' }}
+          LOG_FORMAT: [[.Config.Logs.InstanceFormat]]
+          STATUS_TAIL: [[.Config.Logs.Status.TailLines]]
+          STATUS_PREVIEW_LINES: [[.Config.Logs.Status.PreviewLines]]
+          STATUS_PREVIEW_MAX_BYTES: [[.Config.Logs.Status.PreviewMaxBytes]]
+          STATUS_PREVIEW_INCLUDE: [[.Config.Logs.Status.IncludePolicy]]
+          STATUS_PREVIEW_TRUNCATE: [[.Config.Logs.Status.Truncation]]
+          TRACE_ID: ${{ [[input "trace_id"]] }}
+          # Besides the instances, only who dispatched the run may steer it
+          TRUSTED_AUTHORS: ${{ github.triggering_actor }}
+[[- if .Config.Coordination.Dispatch]]
+          COORDINATION_MODE: dispatch
+          RUN_ID: ${{ github.event.client_payload.run_id }}
+          STATE_BRANCH: [[.Config.Coordination.Branch]]
+[[- end]]
           ROLE: ${{ matrix.instance == 1 && 'leader' || 'worker' }}
-        run: |
-          # Source status reporter
+          BRANCH_PREFIX: [[.Config.Branches.InstancePrefix]]
+          PR_DRAFT: [[.Config.PRs.Draft]]
+          AUTO_MERGE: [[.Config.Branches.AutoMerge]]
+[[- with .Config.Provider.MaxTurns]]
+          MAX_TURNS: [[.]]
+[[- end]]
+[[- with .Config.Provider.MaxTokensPerInstance]]
+          MAX_TOKENS_PER_INSTANCE: [[.]]
+[[- end]]
+[[- range .Config.Requirements]]
+          [[.Name]]: [[.Expression]]
+[[- end]]
+[[block "env" .]][[end]]        run: |
+[[block "run" .]]          # Source status reporter
           source ./scripts/instance-status-reporter.sh
 
           # Start logging
           exec > >(tee -a /tmp/instance-$INSTANCE_ID.log)
           exec 2>&1
 
-          echo "🤖 Instance $INSTANCE_ID starting..."
-          echo "📋 Task: Issue #$ISSUE_NUMBER"
-          echo "👥 Total instances: $TOTAL_INSTANCES"
-          echo "🎭 Role: $ROLE"
+          log_event info init "Instance $INSTANCE_ID starting"
+          log_event info init "Task: issue #$ISSUE_NUMBER"
+          log_event info init "Total instances: $TOTAL_INSTANCES"
+          log_event info init "Role: $ROLE"
+          if [ "$CANARY" = "true" ]; then
+            log_event info init "Canary: attempting one representative subtask before the other instances start"
+          fi
+
+          # Continue from this instance's checkpoint when resuming a paused run
+          restore_checkpoint
+
+          # Use the model of a locked run and flag what could not be pinned
+          apply_env_lock
 
           # Report initial status
-          report_status "starting" "init" "Initializing instance" 0 "$(tail -10 /tmp/instance-$INSTANCE_ID.log)"
+          report_status "starting" "init" "Initializing instance" 0 "$(tail -n "$STATUS_TAIL" /tmp/instance-$INSTANCE_ID.log)"
+
+          # Probe toolchains, resources and provider egress so the
+          # coordinator can keep heavy tasks off constrained runners
+          report_capabilities || log_event warn init "Capability probe failed"
 
           # Leader: Wait for workers to start
           if [ "$ROLE" = "leader" ]; then
-            echo "👑 Acting as leader, waiting for workers..."
+            log_event info coordinate "Acting as leader, waiting for workers"
             sleep 5
 
             # Check worker instances
             check_workers
 
             # TODO: Distribute tasks based on worker availability
-            echo "📋 Distributing tasks to workers..."
+            log_event info coordinate "Distributing tasks to workers"
           fi
 
           # Worker: Wait for task assignment
           if [ "$ROLE" = "worker" ]; then
-            echo "👷 Acting as worker, waiting for task assignment..."
+            log_event info coordinate "Acting as worker, waiting for task assignment"
 
             # Report ready status
-            report_status "ready" "waiting" "Waiting for task assignment" 0 "$(tail -10 /tmp/instance-$INSTANCE_ID.log)"
+            report_status "ready" "waiting" "Waiting for task assignment" 0 "$(tail -n "$STATUS_TAIL" /tmp/instance-$INSTANCE_ID.log)"
 
             # TODO: Poll for task assignment from leader
             sleep 5
@@ -106,29 +462,114 @@ jobs:
 
           # Simulate work with progress reporting
           for progress in 25 50 75; do
-            echo "⏳ Progress: $progress%%"
-            report_status "in_progress" "task-$INSTANCE_ID" "Working on assigned task" $progress "$(tail -10 /tmp/instance-$INSTANCE_ID.log)"
+            # Checkpoint and idle while the run is paused
+            wait_while_paused
+            log_event info "task-$INSTANCE_ID" "Progress: $progress%"
+            report_status "in_progress" "task-$INSTANCE_ID" "Working on assigned task" $progress "$(tail -n "$STATUS_TAIL" /tmp/instance-$INSTANCE_ID.log)"
             sleep 5
           done
 
           # Report completion
-          echo "✅ Instance $INSTANCE_ID: Task completed"
-          report_status "completed" "task-$INSTANCE_ID" "Task completed successfully" 100 "$(tail -10 /tmp/instance-$INSTANCE_ID.log)"
+          log_event info "task-$INSTANCE_ID" "Instance $INSTANCE_ID: Task completed"
+          report_status "completed" "task-$INSTANCE_ID" "Task completed successfully" 100 "$(tail -n "$STATUS_TAIL" /tmp/instance-$INSTANCE_ID.log)"
 
           # Leader: Final check
           if [ "$ROLE" = "leader" ]; then
-            echo "👑 Leader final check..."
+            log_event info coordinate "Leader final check"
             check_workers
-            echo "✅ All workers completed"
+            log_event info coordinate "All workers completed"
           fi
-
-      - name: Report status
+[[end]]
+      # Fork code may have run in this job: what the report job needs leaves
+      # it only as an artifact, kept for a day at most
+      - name: Collect instance output
         if: always()
+        env:
+          GITHUB_TOKEN: ${{ [[input "untrusted"]] == 'true' && secrets.[[or .Config.Untrusted.TokenSecret "FORK_TOKEN"]] || secrets.GITHUB_TOKEN }}
+          INSTANCE_ID: ${{ matrix.instance }}
+          ISSUE_NUMBER: ${{ [[input "issue_number"]] }}
+[[- if .Config.Coordination.Dispatch]]
+          COORDINATION_MODE: dispatch
+          RUN_ID: ${{ github.event.client_payload.run_id }}
+          STATE_BRANCH: [[.Config.Coordination.Branch]]
+[[- end]]
+          BASE_SHA: ${{ [[input "base_sha"]] || github.sha }}
         run: |
-          if [ "${{ job.status }}" == "success" ]; then
-            gh issue comment ${{ inputs.issue_number }} --body "✅ Instance ${{ matrix.instance }}: Success"
-          else
-            gh issue comment ${{ inputs.issue_number }} --body "❌ Instance ${{ matrix.instance }}: Failed"
-          fi
-`, cfg.Instances.Default, cfg.Workflow.Concurrency)
+          out="$RUNNER_TEMP/instance-output"
+          mkdir -p "$out"
+          echo "${{ job.status }}" > "$out/result"
+          touch "/tmp/instance-$INSTANCE_ID.log"
+          cp "/tmp/instance-$INSTANCE_ID.log" "$out/log"
+[[- if ne .Config.Audit.SecretsMode "off"]]
+          source ./scripts/instance-status-reporter.sh
+          collect_audit_inputs "$out" "$BASE_SHA"
+[[- end]]
+
+      - name: Upload instance output
+        if: always()
+        uses: actions/upload-artifact@v4
+        with:
+          name: autonomous-dev-output-${{ matrix.instance }}-attempt-${{ github.run_attempt }}
+          path: ${{ runner.temp }}/instance-output
+          retention-days: 1
+[[end]]`
+
+// WorkflowTemplate generates the GitHub Actions workflow YAML,
+// applying any configured template overrides
+func WorkflowTemplate(cfg *config.Config) (string, error) {
+	resolved, err := Resolve(WorkflowName, cfg, Data{Config: cfg})
+	if err != nil {
+		return "", err
+	}
+	return resolved.Output, nil
+}
+
+// WorkflowEnv returns the sorted names of the variables the workflow sets in
+// its env blocks. Variables only set in dispatch mode or with provider
+// limits are included whether or not cfg enables them.
+func WorkflowEnv(cfg *config.Config) ([]string, error) {
+	full := *cfg
+	full.Coordination.Mode = config.CoordinationDispatch
+	full.Provider.MaxTurns = 1
+	full.Provider.MaxTokensPerInstance = 1
+
+	names := map[string]bool{}
+	for _, c := range []*config.Config{cfg, &full} {
+		content, err := WorkflowTemplate(c)
+		if err != nil {
+			return nil, err
+		}
+		var workflow struct {
+			Env  map[string]any `yaml:"env"`
+			Jobs map[string]struct {
+				Env   map[string]any `yaml:"env"`
+				Steps []struct {
+					Env map[string]any `yaml:"env"`
+				} `yaml:"steps"`
+			} `yaml:"jobs"`
+		}
+		if err := yaml.Unmarshal([]byte(content), &workflow); err != nil {
+			return nil, fmt.Errorf("failed to parse workflow: %w", err)
+		}
+
+		blocks := []map[string]any{workflow.Env}
+		for _, job := range workflow.Jobs {
+			blocks = append(blocks, job.Env)
+			for _, step := range job.Steps {
+				blocks = append(blocks, step.Env)
+			}
+		}
+		for _, block := range blocks {
+			for name := range block {
+				names[name] = true
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	return sorted, nil
 }
@@ -0,0 +1,78 @@
+package tracker
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Jira talks to the Jira REST API v2
+type Jira struct {
+	baseURL string
+	email   string
+	token   string
+}
+
+// NewJira creates a Jira tracker authenticating with email and API token
+func NewJira(baseURL, email, token string) *Jira {
+	return &Jira{baseURL: strings.TrimRight(baseURL, "/"), email: email, token: token}
+}
+
+// Fetch implements Tracker
+func (j *Jira) Fetch(key string) (*Ticket, error) {
+	req, err := j.request(http.MethodGet, "/rest/api/2/issue/"+url.PathEscape(key)+"?fields=summary,description", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary     string `json:"summary"`
+			Description string `json:"description"`
+		} `json:"fields"`
+	}
+	if err := doJSON(req, &issue); err != nil {
+		return nil, fmt.Errorf("failed to fetch Jira issue %s: %w", key, err)
+	}
+
+	return &Ticket{
+		Key:         issue.Key,
+		Title:       issue.Fields.Summary,
+		Description: issue.Fields.Description,
+		URL:         j.baseURL + "/browse/" + issue.Key,
+	}, nil
+}
+
+// Comment implements Tracker
+func (j *Jira) Comment(key, text string) error {
+	req, err := j.request(http.MethodPost, "/rest/api/2/issue/"+url.PathEscape(key)+"/comment", map[string]string{"body": text})
+	if err != nil {
+		return err
+	}
+	if err := doJSON(req, nil); err != nil {
+		return fmt.Errorf("failed to comment on Jira issue %s: %w", key, err)
+	}
+	return nil
+}
+
+func (j *Jira) request(method, path string, payload interface{}) (*http.Request, error) {
+	var body io.Reader
+	if payload != nil {
+		var err error
+		if body, err = jsonBody(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(method, j.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Jira request: %w", err)
+	}
+	if j.token != "" {
+		req.SetBasicAuth(j.email, j.token)
+	}
+	return req, nil
+}
@@ -0,0 +1,112 @@
+package tracker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// linearAPI is the Linear GraphQL endpoint
+const linearAPI = "https://api.linear.app/graphql"
+
+// Linear talks to the Linear GraphQL API
+type Linear struct {
+	apiKey string
+}
+
+// NewLinear creates a Linear tracker authenticating with a personal API key
+func NewLinear(apiKey string) *Linear {
+	return &Linear{apiKey: apiKey}
+}
+
+type linearIssue struct {
+	ID          string `json:"id"`
+	Identifier  string `json:"identifier"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+// Fetch implements Tracker. Keys are identifiers such as "ABC-45".
+func (l *Linear) Fetch(key string) (*Ticket, error) {
+	issue, err := l.issue(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Ticket{
+		Key:         issue.Identifier,
+		Title:       issue.Title,
+		Description: issue.Description,
+		URL:         issue.URL,
+	}, nil
+}
+
+// Comment implements Tracker
+func (l *Linear) Comment(key, text string) error {
+	issue, err := l.issue(key)
+	if err != nil {
+		return err
+	}
+
+	var data struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+	err = l.query(`mutation($issueId: String!, $body: String!) {
+  commentCreate(input: {issueId: $issueId, body: $body}) { success }
+}`, map[string]interface{}{"issueId": issue.ID, "body": text}, &data)
+	if err != nil {
+		return fmt.Errorf("failed to comment on Linear issue %s: %w", key, err)
+	}
+	if !data.CommentCreate.Success {
+		return fmt.Errorf("failed to comment on Linear issue %s", key)
+	}
+	return nil
+}
+
+func (l *Linear) issue(key string) (*linearIssue, error) {
+	var data struct {
+		Issue *linearIssue `json:"issue"`
+	}
+	err := l.query(`query($id: String!) {
+  issue(id: $id) { id identifier title description url }
+}`, map[string]interface{}{"id": key}, &data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Linear issue %s: %w", key, err)
+	}
+	if data.Issue == nil {
+		return nil, fmt.Errorf("Linear issue %s not found", key)
+	}
+	return data.Issue, nil
+}
+
+// query runs a GraphQL request and decodes its data into out
+func (l *Linear) query(query string, variables map[string]interface{}, out interface{}) error {
+	body, err := jsonBody(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, linearAPI, body)
+	if err != nil {
+		return fmt.Errorf("failed to create Linear request: %w", err)
+	}
+	req.Header.Set("Authorization", l.apiKey)
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := doJSON(req, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("%s", resp.Errors[0].Message)
+	}
+	if len(resp.Data) == 0 {
+		return fmt.Errorf("empty response")
+	}
+	return json.Unmarshal(resp.Data, out)
+}
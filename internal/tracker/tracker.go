@@ -0,0 +1,141 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/config"
+)
+
+// Ticket is an issue in an external tracker
+type Ticket struct {
+	// Ref is the reference the ticket was fetched with, e.g. "jira:PROJ-123"
+	Ref         string
+	Key         string
+	Title       string
+	Description string
+	URL         string
+}
+
+// Tracker reads tickets from and reports back to an external tracker
+type Tracker interface {
+	// Fetch returns a ticket by key
+	Fetch(key string) (*Ticket, error)
+	// Comment adds a comment to a ticket
+	Comment(key, text string) error
+}
+
+// Parse splits a reference such as "jira:PROJ-123" into tracker and key
+func Parse(ref string) (kind, key string, err error) {
+	kind, key, ok := strings.Cut(ref, ":")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid ticket reference %q (use jira:KEY or linear:KEY)", ref)
+	}
+	switch kind {
+	case "jira", "linear":
+		return kind, key, nil
+	default:
+		return "", "", fmt.Errorf("unknown tracker %q (use jira or linear)", kind)
+	}
+}
+
+// New returns the configured tracker of a kind
+func New(cfg config.TrackersConfig, kind string) (Tracker, error) {
+	switch kind {
+	case "jira":
+		if cfg.Jira.URL == "" {
+			return nil, fmt.Errorf("trackers.jira.url is not set")
+		}
+		return NewJira(cfg.Jira.URL, os.ExpandEnv(cfg.Jira.Email), os.ExpandEnv(cfg.Jira.Token)), nil
+	case "linear":
+		key := os.ExpandEnv(cfg.Linear.APIKey)
+		if key == "" {
+			return nil, fmt.Errorf("trackers.linear.api_key is not set")
+		}
+		return NewLinear(key), nil
+	default:
+		return nil, fmt.Errorf("unknown tracker %q", kind)
+	}
+}
+
+// Fetch resolves a reference with the configured trackers
+func Fetch(cfg config.TrackersConfig, ref string) (*Ticket, error) {
+	kind, key, err := Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	t, err := New(cfg, kind)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := t.Fetch(key)
+	if err != nil {
+		return nil, err
+	}
+	ticket.Ref = kind + ":" + ticket.Key
+	return ticket, nil
+}
+
+// Comment posts a comment to the ticket a reference points at
+func Comment(cfg config.TrackersConfig, ref, text string) error {
+	kind, key, err := Parse(ref)
+	if err != nil {
+		return err
+	}
+	t, err := New(cfg, kind)
+	if err != nil {
+		return err
+	}
+	return t.Comment(key, text)
+}
+
+// IssueDetails renders a ticket description with a backlink for the
+// coordination issue body
+func IssueDetails(t *Ticket) string {
+	var b strings.Builder
+	if desc := strings.TrimSpace(t.Description); desc != "" {
+		b.WriteString(desc)
+		b.WriteString("\n\n")
+	}
+	fmt.Fprintf(&b, "_Imported from [%s](%s)_", t.Key, t.URL)
+	return b.String()
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// doJSON sends a JSON request and decodes a JSON response into out (if non-nil)
+func doJSON(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	if req.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func jsonBody(v interface{}) (io.Reader, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
@@ -0,0 +1,126 @@
+package protocol
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/chunk"
+)
+
+// DefaultStateBranch is the state branch used when BranchChannel.Branch is
+// empty, as in the CLI's default dispatch.state_branch
+const DefaultStateBranch = "autonomous-dev-state"
+
+// postAttempts is how often BranchChannel.Post tries to commit a message
+// when concurrent commits to the state branch conflict
+const postAttempts = 3
+
+// BranchChannel is the channel of a run coordinated in dispatch mode: its
+// messages are files under runs/<RunID>/messages/ on the state branch, named
+// after the time they were posted. Messages are not filtered by author;
+// posting them needs push access to the repository.
+type BranchChannel struct {
+	Owner string
+	Repo  string
+	// Branch is the state branch; DefaultStateBranch if empty
+	Branch string
+	// RunID identifies the run on the state branch
+	RunID string
+	// Author ends the names of the message files posted, e.g. "instance-3";
+	// "external" if empty
+	Author string
+	// Token authenticates the requests; it needs read and write access to
+	// the repository's contents
+	Token string
+	// APIURL is the REST API root; DefaultAPIURL if empty
+	APIURL string
+	// HTTPClient sends the requests; a client with a 30s timeout if nil
+	HTTPClient *http.Client
+}
+
+// contentEntry is the part of a directory entry of the contents API the
+// channel reads
+type contentEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+// Bodies implements Channel. A run without messages yet has none.
+func (ch *BranchChannel) Bodies() ([]string, error) {
+	dir := "runs/" + ch.RunID + "/messages"
+	var entries []contentEntry
+	err := ch.do(http.MethodGet, ch.contentsURL(dir)+"?ref="+url.QueryEscape(ch.branch()), nil, &entries)
+	var status *statusError
+	if errors.As(err, &status) && status.code == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list messages of run %s: %w", ch.RunID, err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	var bodies []string
+	for _, entry := range entries {
+		if entry.Type != "file" {
+			continue
+		}
+		var content []byte
+		if err := ch.do(http.MethodGet, ch.contentsURL(entry.Path)+"?ref="+url.QueryEscape(ch.branch()), nil, &content); err != nil {
+			return nil, fmt.Errorf("failed to read message %s: %w", entry.Name, err)
+		}
+		bodies = append(bodies, string(content))
+	}
+	return chunk.Join(bodies), nil
+}
+
+// Post implements Channel. Concurrent commits to the state branch can
+// conflict; conflicting posts are retried.
+func (ch *BranchChannel) Post(body string) error {
+	author := ch.Author
+	if author == "" {
+		author = "external"
+	}
+	name := time.Now().UTC().Format("20060102T150405.000000000Z") + "-" + author + ".md"
+	request := map[string]string{
+		"message": "Post message to run " + ch.RunID,
+		"branch":  ch.branch(),
+		"content": base64.StdEncoding.EncodeToString([]byte(body)),
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = ch.do(http.MethodPut, ch.contentsURL("runs/"+ch.RunID+"/messages/"+name), request, nil)
+		var status *statusError
+		conflict := errors.As(err, &status) && (status.code == http.StatusConflict || status.code == http.StatusUnprocessableEntity)
+		if !conflict || attempt == postAttempts {
+			break
+		}
+		time.Sleep(time.Duration(attempt) * 2 * time.Second)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to post message to run %s on %s: %w", ch.RunID, ch.branch(), err)
+	}
+	return nil
+}
+
+func (ch *BranchChannel) do(method, url string, in, out any) error {
+	return do(ch.HTTPClient, ch.Token, method, url, in, out)
+}
+
+func (ch *BranchChannel) contentsURL(path string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/contents/%s", apiRoot(ch.APIURL), ch.Owner, ch.Repo, strings.TrimPrefix(path, "/"))
+}
+
+func (ch *BranchChannel) branch() string {
+	if ch.Branch == "" {
+		return DefaultStateBranch
+	}
+	return ch.Branch
+}
@@ -0,0 +1,104 @@
+package protocol
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeContents serves the parts of the contents API BranchChannel uses for
+// files on one branch
+type fakeContents struct {
+	mu     sync.Mutex
+	branch string
+	files  map[string]string
+}
+
+func (f *fakeContents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file := strings.TrimPrefix(r.URL.Path, "/repos/acme/app/contents/")
+	switch r.Method {
+	case http.MethodPut:
+		var req struct {
+			Branch  string `json:"branch"`
+			Content string `json:"content"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		content, err := base64.StdEncoding.DecodeString(req.Content)
+		if err != nil || req.Branch != f.branch {
+			http.Error(w, "bad request", http.StatusUnprocessableEntity)
+			return
+		}
+		f.files[file] = string(content)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}"))
+	case http.MethodGet:
+		if r.URL.Query().Get("ref") != f.branch {
+			http.NotFound(w, r)
+			return
+		}
+		if content, ok := f.files[file]; ok {
+			w.Write([]byte(content))
+			return
+		}
+		type entry struct {
+			Name string `json:"name"`
+			Path string `json:"path"`
+			Type string `json:"type"`
+		}
+		var entries []entry
+		for name := range f.files {
+			if path.Dir(name) == file {
+				entries = append(entries, entry{Name: path.Base(name), Path: name, Type: "file"})
+			}
+		}
+		if len(entries) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		// The API lists names in its own order; the channel must sort them
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name > entries[j].Name })
+		json.NewEncoder(w).Encode(entries)
+	}
+}
+
+func TestBranchChannelRoundTrip(t *testing.T) {
+	fake := &fakeContents{branch: DefaultStateBranch, files: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	channel := &BranchChannel{Owner: "acme", Repo: "app", RunID: "20240601T120000.000Z-ab12", Author: "instance-3", APIURL: server.URL}
+	bodies, err := channel.Bodies()
+	if err != nil || len(bodies) != 0 {
+		t.Fatalf("Bodies of a new run = %v, %v; want none", bodies, err)
+	}
+
+	client := NewClient(channel, 3)
+	if err := client.Post(&Vote{DecisionID: "db", Option: "postgres"}); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if err := client.Post(&Vote{DecisionID: "db", Option: "sqlite"}); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	for name := range fake.files {
+		if !strings.HasPrefix(name, "runs/"+channel.RunID+"/messages/") || !strings.HasSuffix(name, "-instance-3.md") {
+			t.Errorf("message posted as %s", name)
+		}
+	}
+
+	snap, errs, err := client.Snapshot()
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("Snapshot: %v %v", err, errs)
+	}
+	if v := snap.Votes["db"][3]; v == nil || v.Option != "sqlite" {
+		t.Errorf("latest vote = %+v, want sqlite", v)
+	}
+}
@@ -0,0 +1,136 @@
+package protocol
+
+import (
+	"fmt"
+	"time"
+)
+
+// Channel is where the messages of a run are exchanged, such as the comments
+// of its coordination issue
+type Channel interface {
+	// Bodies returns message bodies in chronological order, with messages
+	// split over several comments reassembled. Messages that steer the run
+	// (Kind.Steers) from authors who may not steer it are left out.
+	Bodies() ([]string, error)
+	// Post appends a message body, splitting it if the channel limits size
+	Post(body string) error
+}
+
+// Client reads and posts the messages of one instance of a run
+type Client struct {
+	channel    Channel
+	instanceID int
+}
+
+// NewClient returns a client posting as instanceID on channel. Instance IDs
+// start at 1; 0 is the CLI.
+func NewClient(channel Channel, instanceID int) *Client {
+	return &Client{channel: channel, instanceID: instanceID}
+}
+
+// InstanceID returns the instance the client posts as
+func (c *Client) InstanceID() int {
+	return c.instanceID
+}
+
+// Snapshot reads the run's messages. Malformed blocks are skipped and
+// returned as protocol errors; the error is set when the channel cannot be
+// read.
+func (c *Client) Snapshot() (*Snapshot, []error, error) {
+	bodies, err := c.channel.Bodies()
+	if err != nil {
+		return nil, nil, err
+	}
+	snap, errs := Collect(bodies)
+	return snap, errs, nil
+}
+
+// Post validates and posts a message as the client's instance. The instance
+// ID of statuses, results, capabilities and votes is filled in, as is the
+// heartbeat of statuses and the version of versioned messages left at 0.
+// Assignments are posted for the instance they assign to.
+func (c *Client) Post(msg Message) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	target := c.instanceID
+	switch m := msg.(type) {
+	case *Status:
+		m.InstanceID = c.instanceID
+		if m.Health.LastHeartbeat == "" {
+			m.Health.LastHeartbeat = now
+		}
+	case *Result:
+		m.InstanceID = c.instanceID
+		if m.Version == 0 {
+			m.Version = Version
+		}
+	case *Capabilities:
+		m.InstanceID = c.instanceID
+		if m.Version == 0 {
+			m.Version = Version
+		}
+		if m.ProbedAt == "" {
+			m.ProbedAt = now
+		}
+	case *Vote:
+		m.InstanceID = c.instanceID
+		if m.Version == 0 {
+			m.Version = Version
+		}
+	case *Assignment:
+		target = m.InstanceID
+		if m.Version == 0 {
+			m.Version = Version
+		}
+	}
+
+	body, err := Render(target, msg)
+	if err != nil {
+		return err
+	}
+	if err := c.channel.Post(body); err != nil {
+		return fmt.Errorf("failed to post %s: %w", msg.Kind(), err)
+	}
+	return nil
+}
+
+// PendingAssignments returns the tasks assigned to the client's instance
+// that have no result yet and were not cancelled, sorted by task ID
+func (c *Client) PendingAssignments() ([]*Assignment, error) {
+	snap, _, err := c.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	var pending []*Assignment
+	for _, taskID := range snap.TaskIDs() {
+		a := snap.Assignments[taskID]
+		if _, done := snap.Results[taskID]; done || a.InstanceID != c.instanceID {
+			continue
+		}
+		pending = append(pending, a)
+	}
+	return pending, nil
+}
+
+// Paused reports whether the run is paused; paused instances checkpoint
+// their work and wait until it is resumed
+func (c *Client) Paused() (bool, error) {
+	snap, _, err := c.Snapshot()
+	if err != nil {
+		return false, err
+	}
+	return snap.Paused(), nil
+}
+
+// Cancelled reports whether a task was dropped from the plan by a replan or
+// assigned to another instance, so the client's instance should stop it
+func (c *Client) Cancelled(taskID string) (bool, error) {
+	snap, _, err := c.Snapshot()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := snap.Cancelled[taskID]; ok {
+		return true, nil
+	}
+	a, ok := snap.Assignments[taskID]
+	return ok && a.InstanceID != c.instanceID, nil
+}
@@ -0,0 +1,65 @@
+package protocol
+
+import "fmt"
+
+// KindControl messages are posted by the CLI, as instance 0, to pause or
+// resume a run
+const KindControl Kind = "RUN_CONTROL"
+
+// Control actions
+const (
+	ActionPause  = "pause"
+	ActionResume = "resume"
+)
+
+// Pause modes
+const (
+	// PauseCheckpoint asks instances to commit their work and idle
+	PauseCheckpoint = "checkpoint"
+	// PauseCancel also cancels the workflow run once instances checkpointed;
+	// resume dispatches a new run that continues from the checkpoints
+	PauseCancel = "cancel"
+)
+
+// Control pauses or resumes a run
+type Control struct {
+	Version int    `json:"version" yaml:"version"`
+	Action  string `json:"action" yaml:"action"`
+	// Mode is the pause mode (pause only)
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	// RunID is the workflow run that was paused
+	RunID int64 `json:"run_id,omitempty" yaml:"run_id,omitempty"`
+	// Instances and BaseSHA let resume dispatch an equivalent run
+	Instances int    `json:"instances,omitempty" yaml:"instances,omitempty"`
+	BaseSHA   string `json:"base_sha,omitempty" yaml:"base_sha,omitempty"`
+	Reason    string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	IssuedAt  string `json:"issued_at" yaml:"issued_at"`
+}
+
+// Kind implements Message
+func (c *Control) Kind() Kind { return KindControl }
+
+// Validate implements Message
+func (c *Control) Validate() error {
+	if c.Version < 1 || c.Version > Version {
+		return fmt.Errorf("unsupported protocol version %d", c.Version)
+	}
+	switch c.Action {
+	case ActionPause:
+		if c.Mode != PauseCheckpoint && c.Mode != PauseCancel {
+			return fmt.Errorf("mode must be %s or %s, got %q", PauseCheckpoint, PauseCancel, c.Mode)
+		}
+	case ActionResume:
+	default:
+		return fmt.Errorf("action must be %s or %s, got %q", ActionPause, ActionResume, c.Action)
+	}
+	if c.Instances < 0 {
+		return fmt.Errorf("instances must not be negative")
+	}
+	return nil
+}
+
+// Paused reports whether the latest control message paused the run
+func (s *Snapshot) Paused() bool {
+	return s.Control != nil && s.Control.Action == ActionPause
+}
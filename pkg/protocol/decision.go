@@ -0,0 +1,176 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ReactionOptions maps comment reactions to decision options by position:
+// the first option is voted with +1, the second with -1, and so on
+var ReactionOptions = []string{"+1", "-1", "heart", "hooray", "rocket", "eyes", "laugh", "confused"}
+
+// Decision is posted by the leader to put a question to a vote
+type Decision struct {
+	Version    int      `json:"version" yaml:"version"`
+	DecisionID string   `json:"decision_id" yaml:"decision_id"`
+	InstanceID int      `json:"instance_id" yaml:"instance_id"`
+	Question   string   `json:"question" yaml:"question"`
+	Options    []string `json:"options" yaml:"options"`
+	// Quorum is the number of votes required; 0 means a majority of reporting instances
+	Quorum int `json:"quorum,omitempty" yaml:"quorum,omitempty"`
+}
+
+// Kind implements Message
+func (d *Decision) Kind() Kind { return KindDecision }
+
+// Validate implements Message
+func (d *Decision) Validate() error {
+	if d.Version < 1 || d.Version > Version {
+		return fmt.Errorf("unsupported protocol version %d", d.Version)
+	}
+	if d.DecisionID == "" {
+		return fmt.Errorf("decision_id is required")
+	}
+	if d.InstanceID < 1 {
+		return fmt.Errorf("instance_id must be positive")
+	}
+	if d.Question == "" {
+		return fmt.Errorf("question is required")
+	}
+	if len(d.Options) < 2 {
+		return fmt.Errorf("a decision needs at least 2 options")
+	}
+	if len(d.Options) > len(ReactionOptions) {
+		return fmt.Errorf("a decision supports at most %d options", len(ReactionOptions))
+	}
+	if d.Quorum < 0 {
+		return fmt.Errorf("quorum must not be negative")
+	}
+	return nil
+}
+
+// Vote is a structured reply casting an instance's vote on a decision
+type Vote struct {
+	Version    int    `json:"version" yaml:"version"`
+	DecisionID string `json:"decision_id" yaml:"decision_id"`
+	InstanceID int    `json:"instance_id" yaml:"instance_id"`
+	Option     string `json:"option" yaml:"option"`
+}
+
+// Kind implements Message
+func (v *Vote) Kind() Kind { return KindVote }
+
+// Validate implements Message
+func (v *Vote) Validate() error {
+	if v.Version < 1 || v.Version > Version {
+		return fmt.Errorf("unsupported protocol version %d", v.Version)
+	}
+	if v.DecisionID == "" {
+		return fmt.Errorf("decision_id is required")
+	}
+	if v.InstanceID < 1 {
+		return fmt.Errorf("instance_id must be positive")
+	}
+	if v.Option == "" {
+		return fmt.Errorf("option is required")
+	}
+	return nil
+}
+
+// Tally is the vote count of a decision
+type Tally struct {
+	Decision *Decision
+	// Counts holds votes per option
+	Counts map[string]int
+	// Votes is the number of valid votes cast
+	Votes int
+	// Quorum is the effective number of votes required
+	Quorum int
+	// Winner is the option with the most votes once quorum is reached; empty on ties
+	Winner string
+	// Invalid lists votes for options the decision does not offer
+	Invalid []string
+}
+
+// Reached reports whether enough votes were cast
+func (t *Tally) Reached() bool {
+	return t.Votes >= t.Quorum
+}
+
+// DecisionIDs returns the IDs of all decisions, sorted
+func (s *Snapshot) DecisionIDs() []string {
+	ids := make([]string, 0, len(s.Decisions))
+	for id := range s.Decisions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Tally counts the structured votes for a decision plus reaction votes.
+// reactions maps each reacting user to the reaction content they chose;
+// callers pass only users allowed to vote and leave out users voting both
+// ways. Reactions of ActionsBot never count: every instance posts as it and
+// votes with a Vote instead.
+func (s *Snapshot) Tally(decisionID string, reactions map[string]string) *Tally {
+	decision := s.Decisions[decisionID]
+	if decision == nil {
+		return nil
+	}
+
+	tally := &Tally{
+		Decision: decision,
+		Counts:   make(map[string]int, len(decision.Options)),
+		Quorum:   decision.Quorum,
+	}
+	if tally.Quorum == 0 {
+		tally.Quorum = len(s.Statuses)/2 + 1
+	}
+
+	valid := make(map[string]bool, len(decision.Options))
+	for _, option := range decision.Options {
+		valid[option] = true
+		tally.Counts[option] = 0
+	}
+
+	// Latest structured vote per instance
+	for _, vote := range s.Votes[decisionID] {
+		if !valid[vote.Option] {
+			tally.Invalid = append(tally.Invalid, fmt.Sprintf("instance %d voted for unknown option %q", vote.InstanceID, vote.Option))
+			continue
+		}
+		tally.Counts[vote.Option]++
+		tally.Votes++
+	}
+	sort.Strings(tally.Invalid)
+
+	// Reactions by position
+	for user, content := range reactions {
+		if loginKey(user) == loginKey(ActionsBot) {
+			continue
+		}
+		for i, reaction := range ReactionOptions {
+			if reaction == content && i < len(decision.Options) {
+				tally.Counts[decision.Options[i]]++
+				tally.Votes++
+			}
+		}
+	}
+
+	if tally.Reached() {
+		best, tied := 0, false
+		for _, option := range decision.Options {
+			switch n := tally.Counts[option]; {
+			case n > best:
+				best, tied, tally.Winner = n, false, option
+			case n == best && n > 0:
+				tied = true
+			}
+		}
+		if tied {
+			tally.Winner = ""
+		}
+	}
+
+	return tally
+}
@@ -0,0 +1,45 @@
+// Package protocol implements the coordination protocol instances of an
+// autonomous-dev run speak: the machine-readable message blocks they embed
+// in comments of the run's coordination issue, and a client to read and
+// post them.
+//
+// A message is rendered as a fenced JSON (or YAML) block between markers
+// naming its kind and instance:
+//
+//	<!-- INSTANCE_STATUS:START:2 -->
+//	```json
+//	{"instance_id": 2, "status": "in_progress", "role": "worker", ...}
+//	```
+//	<!-- INSTANCE_STATUS:END:2 -->
+//
+// Third-party workers, e.g. running on a self-hosted service, take part in
+// a run by posting the same messages as the bundled instance scripts:
+//
+//	channel := &protocol.IssueChannel{Owner: "acme", Repo: "app", Issue: 42, Token: os.Getenv("GITHUB_TOKEN")}
+//	worker := protocol.NewClient(channel, 3)
+//
+//	tasks, err := worker.PendingAssignments()
+//	if err != nil {
+//		return err
+//	}
+//	for _, task := range tasks {
+//		worker.Post(&protocol.Status{Status: "in_progress", Role: "worker",
+//			CurrentTask: protocol.CurrentTask{ID: task.TaskID, Description: task.Description}})
+//		// ... do the work, checking worker.Paused() and worker.Cancelled(task.TaskID)
+//		worker.Post(&protocol.Result{Version: protocol.Version, TaskID: task.TaskID, Outcome: "completed"})
+//	}
+//
+// Runs in dispatch mode keep their messages on the state branch instead;
+// use a BranchChannel with the run's ID there. Readers use Collect to fold
+// the messages of a run into a Snapshot.
+//
+// # Compatibility
+//
+// The package follows the semantic versioning of the module: exported
+// identifiers are not removed or changed incompatibly within a major
+// version. The wire format is versioned separately by Version, written into
+// every message that has a version field. Within a protocol version, new
+// message kinds and new optional fields may be added; readers must ignore
+// kinds and fields they do not know, as Collect does. A message that stops
+// being readable by older instances gets a new protocol version.
+package protocol
@@ -0,0 +1,85 @@
+package protocol_test
+
+import (
+	"fmt"
+
+	"github.com/autonomous-dev/cli/pkg/protocol"
+)
+
+// memoryChannel keeps the messages of a run in memory
+type memoryChannel struct {
+	bodies []string
+}
+
+func (ch *memoryChannel) Bodies() ([]string, error) { return ch.bodies, nil }
+
+func (ch *memoryChannel) Post(body string) error {
+	ch.bodies = append(ch.bodies, body)
+	return nil
+}
+
+func ExampleNewClient() {
+	channel := &memoryChannel{}
+	leader := protocol.NewClient(channel, 1)
+	worker := protocol.NewClient(channel, 2)
+
+	leader.Post(&protocol.Assignment{TaskID: "task-1", InstanceID: 2, Description: "Add the export API"})
+
+	tasks, _ := worker.PendingAssignments()
+	for _, task := range tasks {
+		fmt.Printf("instance %d works on %s: %s\n", worker.InstanceID(), task.TaskID, task.Description)
+		worker.Post(&protocol.Result{TaskID: task.TaskID, Outcome: "completed", Summary: "Added GET /export"})
+	}
+
+	tasks, _ = worker.PendingAssignments()
+	fmt.Println("pending:", len(tasks))
+	// Output:
+	// instance 2 works on task-1: Add the export API
+	// pending: 0
+}
+
+func ExampleRender() {
+	body, err := protocol.Render(2, &protocol.Vote{Version: protocol.Version, DecisionID: "db", InstanceID: 2, Option: "postgres"})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(body)
+	// Output:
+	// <!-- DECISION_VOTE:START:2 -->
+	// ```json
+	// {
+	//   "version": 1,
+	//   "decision_id": "db",
+	//   "instance_id": 2,
+	//   "option": "postgres"
+	// }
+	// ```
+	// <!-- DECISION_VOTE:END:2 -->
+}
+
+func ExampleCollect() {
+	bodies := []string{
+		"<!-- INSTANCE_STATUS:START:1 -->\n```json\n" +
+			`{"instance_id": 1, "status": "in_progress", "role": "leader", "current_task": {"id": "task-1", "progress": 40}}` +
+			"\n```\n<!-- INSTANCE_STATUS:END:1 -->",
+		"Comments may mix prose and blocks.\n\n<!-- INSTANCE_STATUS:START:1 -->\n```yaml\n" +
+			"instance_id: 1\nstatus: completed\nrole: leader\ncurrent_task:\n  id: task-1\n  progress: 100" +
+			"\n```\n<!-- INSTANCE_STATUS:END:1 -->",
+		"<!-- INSTANCE_STATUS:START:2 -->\n```json\n" +
+			`{"instance_id": 2, "status": "sleeping", "role": "worker"}` +
+			"\n```\n<!-- INSTANCE_STATUS:END:2 -->",
+	}
+
+	snap, errs := protocol.Collect(bodies)
+	for _, id := range snap.InstanceIDs() {
+		s := snap.Statuses[id]
+		fmt.Printf("instance %d: %s (%d%%)\n", id, s.Status, s.CurrentTask.Progress)
+	}
+	for _, err := range errs {
+		fmt.Println("skipped:", err)
+	}
+	// Output:
+	// instance 1: completed (100%)
+	// skipped: invalid INSTANCE_STATUS block from instance 2: unknown status "sleeping"
+}
@@ -0,0 +1,172 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/autonomous-dev/cli/internal/chunk"
+)
+
+// DefaultAPIURL is the GitHub REST API used when IssueChannel.APIURL is empty
+const DefaultAPIURL = "https://api.github.com"
+
+// commentsPerPage is the largest page of issue comments GitHub returns
+const commentsPerPage = 100
+
+// IssueChannel is the channel of a run coordinated on a GitHub issue: its
+// messages are the issue's comments. Oversized messages are split over
+// several comments the way the CLI and instance scripts read them back.
+// Messages that steer the run are only read from trusted authors (see
+// TrustedBodies).
+type IssueChannel struct {
+	Owner string
+	Repo  string
+	Issue int
+	// Token authenticates the requests; it needs read and write access to
+	// the repository's issues
+	Token string
+	// APIURL is the REST API root, e.g. https://github.example.com/api/v3
+	// on GitHub Enterprise Server; DefaultAPIURL if empty
+	APIURL string
+	// HTTPClient sends the requests; a client with a 30s timeout if nil
+	HTTPClient *http.Client
+	// Trusted are the logins, besides ActionsBot, that may assign tasks,
+	// replan and pause the run, such as the account the CLI posts with;
+	// the login of Token if empty
+	Trusted []string
+}
+
+// issueComment is the part of a GitHub issue comment the channel reads
+type issueComment struct {
+	Body string `json:"body"`
+	User *struct {
+		Login string `json:"login"`
+	} `json:"user,omitempty"`
+}
+
+// Bodies implements Channel
+func (ch *IssueChannel) Bodies() ([]string, error) {
+	trusted := ch.Trusted
+	if len(trusted) == 0 {
+		var user struct {
+			Login string `json:"login"`
+		}
+		if err := ch.do(http.MethodGet, apiRoot(ch.APIURL)+"/user", nil, &user); err != nil {
+			return nil, fmt.Errorf("failed to identify the token's user: %w", err)
+		}
+		trusted = []string{user.Login}
+	}
+
+	var authored []Comment
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments?per_page=%d&page=%d", apiRoot(ch.APIURL), ch.Owner, ch.Repo, ch.Issue, commentsPerPage, page)
+		var comments []issueComment
+		if err := ch.do(http.MethodGet, url, nil, &comments); err != nil {
+			return nil, fmt.Errorf("failed to read comments of issue #%d: %w", ch.Issue, err)
+		}
+		for _, c := range comments {
+			comment := Comment{Body: c.Body}
+			if c.User != nil {
+				comment.Author = c.User.Login
+			}
+			authored = append(authored, comment)
+		}
+		if len(comments) < commentsPerPage {
+			break
+		}
+	}
+	return TrustedBodies(authored, trusted...), nil
+}
+
+// Post implements Channel
+func (ch *IssueChannel) Post(body string) error {
+	url := fmt.Sprintf("%s/repos/%s/%s/issues/%d/comments", apiRoot(ch.APIURL), ch.Owner, ch.Repo, ch.Issue)
+	for _, part := range chunk.Split(body, chunk.MaxBody) {
+		if err := ch.do(http.MethodPost, url, issueComment{Body: part}, nil); err != nil {
+			return fmt.Errorf("failed to comment on issue #%d: %w", ch.Issue, err)
+		}
+	}
+	return nil
+}
+
+func (ch *IssueChannel) do(method, url string, in, out any) error {
+	return do(ch.HTTPClient, ch.Token, method, url, in, out)
+}
+
+// statusError is a response of the REST API with an error status
+type statusError struct {
+	method string
+	status string
+	code   int
+	msg    string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.method, e.status, e.msg)
+}
+
+// do sends a JSON request authenticated with token and decodes the JSON
+// response into out, if set. A *[]byte out receives the raw content of a
+// file instead.
+func do(client *http.Client, token, method, url string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	raw, isRaw := out.(*[]byte)
+	if isRaw {
+		req.Header.Set("Accept", "application/vnd.github.raw")
+	} else {
+		req.Header.Set("Accept", "application/vnd.github+json")
+	}
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{method: method, status: resp.Status, code: resp.StatusCode, msg: strings.TrimSpace(string(msg))}
+	}
+	if isRaw {
+		*raw, err = io.ReadAll(resp.Body)
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// apiRoot returns the REST API root, DefaultAPIURL if url is empty
+func apiRoot(url string) string {
+	if url == "" {
+		return DefaultAPIURL
+	}
+	return strings.TrimRight(url, "/")
+}
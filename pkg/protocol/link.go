@@ -0,0 +1,97 @@
+package protocol
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LinkKind is the role of an artifact created for a run
+type LinkKind string
+
+const (
+	LinkCoordination LinkKind = "coordination"
+	LinkChildIssue   LinkKind = "child-issue"
+	LinkInstancePR   LinkKind = "instance-pr"
+	LinkCIFailure    LinkKind = "ci-failure"
+	LinkRevert       LinkKind = "revert"
+)
+
+// Link is the breadcrumb marker every issue and pull request created for a
+// run carries in its body. It points back at the run's coordination issue,
+// or its state branch run in dispatch mode.
+type Link struct {
+	Kind LinkKind
+	// Issue is the coordination issue, or 0 in dispatch mode
+	Issue int
+	// RunID is the state branch run in dispatch mode
+	RunID string
+	// Instance is the instance that created the artifact, or 0 for the CLI
+	Instance int
+	// Trace is the run's trace ID, if it has one
+	Trace string
+}
+
+// linkPattern matches <!-- autonomous-dev:link kind=K issue=N run=R instance=I trace=T -->
+var linkPattern = regexp.MustCompile(`<!-- autonomous-dev:link ([^>]*?) -->`)
+
+// Marker renders the link as a hidden HTML comment
+func (l Link) Marker() string {
+	fields := []string{"kind=" + string(l.Kind)}
+	if l.Issue > 0 {
+		fields = append(fields, fmt.Sprintf("issue=%d", l.Issue))
+	}
+	if l.RunID != "" {
+		fields = append(fields, "run="+l.RunID)
+	}
+	if l.Instance > 0 {
+		fields = append(fields, fmt.Sprintf("instance=%d", l.Instance))
+	}
+	if l.Trace != "" {
+		fields = append(fields, "trace="+l.Trace)
+	}
+	return fmt.Sprintf("<!-- autonomous-dev:link %s -->", strings.Join(fields, " "))
+}
+
+// Of reports whether the link points at the coordination issue, or the state
+// branch run when issue is 0
+func (l Link) Of(issue int, runID string) bool {
+	if issue > 0 {
+		return l.Issue == issue
+	}
+	return runID != "" && l.RunID == runID
+}
+
+// ParseLink returns the link marker in a body, if any. Unknown fields are
+// ignored so markers can gain fields without breaking older readers.
+func ParseLink(body string) (*Link, bool) {
+	m := linkPattern.FindStringSubmatch(body)
+	if m == nil {
+		return nil, false
+	}
+
+	link := &Link{}
+	for _, field := range strings.Fields(m[1]) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "kind":
+			link.Kind = LinkKind(value)
+		case "issue":
+			link.Issue, _ = strconv.Atoi(value)
+		case "run":
+			link.RunID = value
+		case "instance":
+			link.Instance, _ = strconv.Atoi(value)
+		case "trace":
+			link.Trace = value
+		}
+	}
+	if link.Kind == "" {
+		return nil, false
+	}
+	return link, true
+}
@@ -0,0 +1,304 @@
+package protocol
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// Message is a coordination message that can be embedded in a comment
+type Message interface {
+	Kind() Kind
+	Validate() error
+}
+
+// Valid instance states reported in status messages
+var validStatuses = map[string]bool{
+	"starting":    true,
+	"ready":       true,
+	"in_progress": true,
+	"completed":   true,
+	"failed":      true,
+	"stale":       true,
+	"paused":      true,
+}
+
+// OutcomeLimitReached marks a task stopped by a provider limit (max turns or
+// tokens). The work so far is kept but needs a follow-up run to finish.
+const OutcomeLimitReached = "limit_reached"
+
+// Provider limits reported with OutcomeLimitReached
+const (
+	LimitMaxTurns  = "max_turns"
+	LimitMaxTokens = "max_tokens"
+)
+
+// Valid task outcomes reported in result messages
+var validOutcomes = map[string]bool{
+	"completed":         true,
+	"failed":            true,
+	"partial":           true,
+	OutcomeLimitReached: true,
+}
+
+// Status is the periodic heartbeat posted by each instance
+type Status struct {
+	InstanceID     int         `json:"instance_id" yaml:"instance_id"`
+	Status         string      `json:"status" yaml:"status"`
+	Role           string      `json:"role" yaml:"role"`
+	CurrentTask    CurrentTask `json:"current_task" yaml:"current_task"`
+	Health         Health      `json:"health" yaml:"health"`
+	LogsURL        string      `json:"logs_url,omitempty" yaml:"logs_url,omitempty"`
+	ConsolePreview []string    `json:"console_preview,omitempty" yaml:"console_preview,omitempty"`
+	// PreviewTruncation is how the console output was cut down to the
+	// preview (logs.status.truncate); empty in reports of older instances
+	PreviewTruncation string `json:"preview_truncation,omitempty" yaml:"preview_truncation,omitempty"`
+}
+
+// PreviewLine is one line of a console preview
+type PreviewLine struct {
+	Text string
+	// Number is the line's number in the reported output, with errors
+	// truncation; 0 otherwise
+	Number int
+	// Omitted is how many lines this marker line stands for; 0 for lines
+	// of output
+	Omitted int
+}
+
+var (
+	// previewOmittedPattern matches the line in place of omitted lines
+	previewOmittedPattern = regexp.MustCompile(`^\[\.\.\. (\d+) lines omitted \.\.\.\]$`)
+	// previewNumberPattern matches a line kept by errors truncation
+	previewNumberPattern = regexp.MustCompile(`^L(\d+): (.*)$`)
+)
+
+// Preview parses the console preview in the format of its truncation
+func (s *Status) Preview() []PreviewLine {
+	lines := make([]PreviewLine, 0, len(s.ConsolePreview))
+	for _, text := range s.ConsolePreview {
+		line := PreviewLine{Text: text}
+		if m := previewOmittedPattern.FindStringSubmatch(text); m != nil {
+			line.Omitted, _ = strconv.Atoi(m[1])
+		} else if m := previewNumberPattern.FindStringSubmatch(text); m != nil && s.PreviewTruncation == "errors" {
+			line.Number, _ = strconv.Atoi(m[1])
+			line.Text = m[2]
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// CurrentTask describes the task an instance is working on
+type CurrentTask struct {
+	ID          string `json:"id" yaml:"id"`
+	Description string `json:"description" yaml:"description"`
+	Progress    int    `json:"progress" yaml:"progress"`
+	StartedAt   string `json:"started_at" yaml:"started_at"`
+}
+
+// Health holds resource metrics of an instance
+type Health struct {
+	CPUUsage      float64 `json:"cpu_usage" yaml:"cpu_usage"`
+	MemoryMB      int     `json:"memory_mb" yaml:"memory_mb"`
+	LastHeartbeat string  `json:"last_heartbeat" yaml:"last_heartbeat"`
+}
+
+// Kind implements Message
+func (s *Status) Kind() Kind { return KindStatus }
+
+// Validate implements Message
+func (s *Status) Validate() error {
+	if s.InstanceID < 1 {
+		return fmt.Errorf("instance_id must be positive")
+	}
+	if !validStatuses[s.Status] {
+		return fmt.Errorf("unknown status %q", s.Status)
+	}
+	if s.Role != "leader" && s.Role != "worker" {
+		return fmt.Errorf("role must be leader or worker, got %q", s.Role)
+	}
+	if s.CurrentTask.Progress < 0 || s.CurrentTask.Progress > 100 {
+		return fmt.Errorf("progress must be between 0 and 100, got %d", s.CurrentTask.Progress)
+	}
+	return nil
+}
+
+// Capabilities is the probe an instance posts before doing work: its
+// toolchains, resources and whether it can reach the provider
+type Capabilities struct {
+	Version    int    `json:"version" yaml:"version"`
+	InstanceID int    `json:"instance_id" yaml:"instance_id"`
+	Runner     string `json:"runner,omitempty" yaml:"runner,omitempty"`
+	OS         string `json:"os,omitempty" yaml:"os,omitempty"`
+	Arch       string `json:"arch,omitempty" yaml:"arch,omitempty"`
+	// Image is the hosted runner image as ImageOS/ImageVersion
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+	CPUs  int    `json:"cpus" yaml:"cpus"`
+	// MemoryMB is the total memory of the runner
+	MemoryMB   int `json:"memory_mb" yaml:"memory_mb"`
+	DiskFreeMB int `json:"disk_free_mb,omitempty" yaml:"disk_free_mb,omitempty"`
+	// Toolchains maps tool names to their versions, e.g. "go": "1.22.1"
+	Toolchains        map[string]string `json:"toolchains,omitempty" yaml:"toolchains,omitempty"`
+	ProviderReachable bool              `json:"provider_reachable" yaml:"provider_reachable"`
+	ProviderLatencyMS int               `json:"provider_latency_ms,omitempty" yaml:"provider_latency_ms,omitempty"`
+	// Model and ProviderCLI are the model and agent CLI version the instance uses
+	Model       string `json:"model,omitempty" yaml:"model,omitempty"`
+	ProviderCLI string `json:"provider_cli,omitempty" yaml:"provider_cli,omitempty"`
+	ProbedAt    string `json:"probed_at" yaml:"probed_at"`
+}
+
+// Kind implements Message
+func (c *Capabilities) Kind() Kind { return KindCapability }
+
+// Validate implements Message
+func (c *Capabilities) Validate() error {
+	if c.Version < 1 || c.Version > Version {
+		return fmt.Errorf("unsupported protocol version %d", c.Version)
+	}
+	if c.InstanceID < 1 {
+		return fmt.Errorf("instance_id must be positive")
+	}
+	if c.CPUs < 0 || c.MemoryMB < 0 || c.DiskFreeMB < 0 {
+		return fmt.Errorf("resources must not be negative")
+	}
+	return nil
+}
+
+// Assignment is posted by the leader to hand a subtask to a worker
+type Assignment struct {
+	Version     int      `json:"version" yaml:"version"`
+	TaskID      string   `json:"task_id" yaml:"task_id"`
+	InstanceID  int      `json:"instance_id" yaml:"instance_id"`
+	Description string   `json:"description" yaml:"description"`
+	Agent       string   `json:"agent,omitempty" yaml:"agent,omitempty"`
+	Paths       []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	DependsOn   []string `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+}
+
+// Kind implements Message
+func (a *Assignment) Kind() Kind { return KindAssignment }
+
+// Validate implements Message
+func (a *Assignment) Validate() error {
+	if a.Version < 1 || a.Version > Version {
+		return fmt.Errorf("unsupported protocol version %d", a.Version)
+	}
+	if a.TaskID == "" {
+		return fmt.Errorf("task_id is required")
+	}
+	if a.InstanceID < 1 {
+		return fmt.Errorf("instance_id must be positive")
+	}
+	if a.Description == "" {
+		return fmt.Errorf("description is required")
+	}
+	return nil
+}
+
+// ResultSchema is the current version of the result contract. Results that
+// declare a schema must report everything downstream report and gate logic
+// relies on; results without one are accepted as before.
+const ResultSchema = 1
+
+// Result is posted by an instance when it finishes a subtask
+type Result struct {
+	Version int `json:"version" yaml:"version"`
+	// Schema is the result contract version the result follows, or 0
+	Schema       int      `json:"schema,omitempty" yaml:"schema,omitempty"`
+	TaskID       string   `json:"task_id" yaml:"task_id"`
+	InstanceID   int      `json:"instance_id" yaml:"instance_id"`
+	Outcome      string   `json:"outcome" yaml:"outcome"`
+	Branch       string   `json:"branch,omitempty" yaml:"branch,omitempty"`
+	PullRequests []int    `json:"pull_requests,omitempty" yaml:"pull_requests,omitempty"`
+	Summary      string   `json:"summary,omitempty" yaml:"summary,omitempty"`
+	FollowUps    []string `json:"follow_ups,omitempty" yaml:"follow_ups,omitempty"`
+	// Tests are the tests the instance ran on its branch
+	Tests *TestRun `json:"tests,omitempty" yaml:"tests,omitempty"`
+	// Coverage is the line coverage in percent, if measured
+	Coverage *float64 `json:"coverage,omitempty" yaml:"coverage,omitempty"`
+	Notes    string   `json:"notes,omitempty" yaml:"notes,omitempty"`
+	// Limit names the provider limit that stopped the task (limit_reached only)
+	Limit string `json:"limit,omitempty" yaml:"limit,omitempty"`
+	// CostUSD is the provider cost of the task, if the instance reports it
+	CostUSD float64 `json:"cost_usd,omitempty" yaml:"cost_usd,omitempty"`
+}
+
+// TestRun summarizes the tests an instance ran
+type TestRun struct {
+	Command string `json:"command" yaml:"command"`
+	Passed  int    `json:"passed" yaml:"passed"`
+	Failed  int    `json:"failed" yaml:"failed"`
+	Skipped int    `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+// Kind implements Message
+func (r *Result) Kind() Kind { return KindResult }
+
+// Validate implements Message
+func (r *Result) Validate() error {
+	if r.Version < 1 || r.Version > Version {
+		return fmt.Errorf("unsupported protocol version %d", r.Version)
+	}
+	if r.TaskID == "" {
+		return fmt.Errorf("task_id is required")
+	}
+	if r.InstanceID < 1 {
+		return fmt.Errorf("instance_id must be positive")
+	}
+	if !validOutcomes[r.Outcome] {
+		return fmt.Errorf("unknown outcome %q", r.Outcome)
+	}
+	if r.CostUSD < 0 {
+		return fmt.Errorf("cost_usd must not be negative")
+	}
+	if r.Limit != "" && r.Outcome != OutcomeLimitReached {
+		return fmt.Errorf("limit is only valid with outcome %s", OutcomeLimitReached)
+	}
+	if r.Schema != 0 {
+		return r.validateContract()
+	}
+	return nil
+}
+
+// validateContract checks a result against its declared result schema
+func (r *Result) validateContract() error {
+	if r.Schema < 1 || r.Schema > ResultSchema {
+		return fmt.Errorf("unsupported result schema %d", r.Schema)
+	}
+	if r.Outcome != "failed" && r.Branch == "" {
+		return fmt.Errorf("branch is required for outcome %s", r.Outcome)
+	}
+	for _, pr := range r.PullRequests {
+		if pr < 1 {
+			return fmt.Errorf("pull_requests must be positive numbers, got %d", pr)
+		}
+	}
+	if r.Outcome == "completed" && r.Tests == nil {
+		return fmt.Errorf("tests are required for outcome completed")
+	}
+	if t := r.Tests; t != nil {
+		if t.Command == "" {
+			return fmt.Errorf("tests.command is required")
+		}
+		if t.Passed < 0 || t.Failed < 0 || t.Skipped < 0 {
+			return fmt.Errorf("test counts must not be negative")
+		}
+		if r.Outcome == "completed" && t.Failed > 0 {
+			return fmt.Errorf("outcome completed reports %d failed test(s)", t.Failed)
+		}
+	}
+	if r.Coverage != nil && (*r.Coverage < 0 || *r.Coverage > 100) {
+		return fmt.Errorf("coverage must be between 0 and 100, got %g", *r.Coverage)
+	}
+	if r.Outcome == "partial" && len(r.FollowUps) == 0 {
+		return fmt.Errorf("follow_ups are required for outcome partial")
+	}
+	return nil
+}
+
+// NeedsFollowUp reports whether the task ended before finishing its work,
+// either partially or by exhausting a provider limit
+func (r *Result) NeedsFollowUp() bool {
+	return r.Outcome == "partial" || r.Outcome == OutcomeLimitReached
+}
@@ -0,0 +1,145 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// legacyStatusPattern matches the plain completion comments posted by older
+// workflows, e.g. "✅ Instance 2: Success"
+var legacyStatusPattern = regexp.MustCompile(`^(✅|❌) Instance (\d+): (Success|Failed)$`)
+
+// Migrate rewrites old-format coordination messages in a comment body into the
+// current structured protocol: plain completion comments gain an
+// INSTANCE_STATUS block, and message blocks written before protocol versioning
+// are re-rendered with the current version. at is when the comment was posted.
+// It reports whether the body changed.
+func Migrate(body string, at time.Time) (string, bool, error) {
+	if m := legacyStatusPattern.FindStringSubmatch(strings.TrimSpace(body)); m != nil {
+		block, err := legacyStatus(m, at)
+		if err != nil {
+			return body, false, err
+		}
+		return body + "\n\n" + block, true, nil
+	}
+
+	var migrateErr error
+	changed := false
+	migrated := blockPattern.ReplaceAllStringFunc(body, func(match string) string {
+		m := blockPattern.FindStringSubmatch(match)
+		if m[1] != m[5] || m[2] != m[6] || migrateErr != nil {
+			return match
+		}
+		block, ok, err := unversionedBlock(m)
+		if err != nil {
+			migrateErr = err
+			return match
+		}
+		if !ok {
+			return match
+		}
+		changed = true
+		return block
+	})
+	if migrateErr != nil {
+		return body, false, migrateErr
+	}
+	return migrated, changed, nil
+}
+
+func legacyStatus(m []string, at time.Time) (string, error) {
+	id, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", fmt.Errorf("invalid instance id %q: %w", m[2], err)
+	}
+
+	status := &Status{
+		InstanceID: id,
+		Status:     "completed",
+		Role:       "worker",
+		CurrentTask: CurrentTask{
+			Description: "Instance finished",
+			Progress:    100,
+		},
+		Health: Health{LastHeartbeat: at.UTC().Format(time.RFC3339)},
+	}
+	if id == 1 {
+		status.Role = "leader"
+	}
+	if m[3] == "Failed" {
+		status.Status = "failed"
+		status.CurrentTask.Description = "Instance failed"
+		status.CurrentTask.Progress = 0
+	}
+
+	return Render(id, status)
+}
+
+// unversionedBlock re-renders a versioned message kind that lacks a version
+func unversionedBlock(m []string) (string, bool, error) {
+	var msg Message
+	switch Kind(m[1]) {
+	case KindAssignment:
+		msg = &Assignment{}
+	case KindResult:
+		msg = &Result{}
+	case KindDecision:
+		msg = &Decision{}
+	case KindVote:
+		msg = &Vote{}
+	default:
+		return "", false, nil
+	}
+
+	var (
+		fields map[string]interface{}
+		err    error
+	)
+	data := []byte(m[4])
+	if m[3] == "json" {
+		err = json.Unmarshal(data, &fields)
+	} else {
+		err = yaml.Unmarshal(data, &fields)
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decode %s block: %w", m[1], err)
+	}
+	if _, ok := fields["version"]; ok {
+		return "", false, nil
+	}
+
+	if m[3] == "json" {
+		err = json.Unmarshal(data, msg)
+	} else {
+		err = yaml.Unmarshal(data, msg)
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to decode %s block: %w", m[1], err)
+	}
+	switch v := msg.(type) {
+	case *Assignment:
+		v.Version = Version
+	case *Result:
+		v.Version = Version
+	case *Decision:
+		v.Version = Version
+	case *Vote:
+		v.Version = Version
+	}
+
+	id, err := strconv.Atoi(m[2])
+	if err != nil {
+		return "", false, fmt.Errorf("invalid instance id %q: %w", m[2], err)
+	}
+	block, err := Render(id, msg)
+	if err != nil {
+		return "", false, err
+	}
+	return block, true, nil
+}
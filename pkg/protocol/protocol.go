@@ -0,0 +1,123 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kind identifies the type of a coordination message
+type Kind string
+
+const (
+	KindStatus     Kind = "INSTANCE_STATUS"
+	KindAssignment Kind = "TASK_ASSIGNMENT"
+	KindResult     Kind = "TASK_RESULT"
+	KindDecision   Kind = "DECISION"
+	KindVote       Kind = "DECISION_VOTE"
+	KindCapability Kind = "INSTANCE_CAPABILITIES"
+)
+
+// Format is the encoding of a message block
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// Version is the current protocol version written into new messages
+const Version = 1
+
+// Block is a machine-readable message embedded in an issue comment
+type Block struct {
+	Kind       Kind
+	InstanceID int
+	Format     Format
+	Data       []byte
+}
+
+// blockPattern matches <!-- KIND:START:ID --> ```fmt ... ``` <!-- KIND:END:ID -->
+var blockPattern = regexp.MustCompile("(?s)<!-- ([A-Z_]+):START:(\\d+) -->\\s*```(json|yaml|yml)\\s*\\n(.*?)\\n```\\s*<!-- ([A-Z_]+):END:(\\d+) -->")
+
+// Parse extracts all message blocks from a comment body.
+// Blocks with mismatched start/end markers are rejected.
+func Parse(body string) ([]Block, error) {
+	var blocks []Block
+
+	for _, m := range blockPattern.FindAllStringSubmatch(body, -1) {
+		if m[1] != m[5] || m[2] != m[6] {
+			return nil, fmt.Errorf("mismatched markers: %s:%s closed by %s:%s", m[1], m[2], m[5], m[6])
+		}
+
+		instanceID, err := strconv.Atoi(m[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid instance id %q: %w", m[2], err)
+		}
+
+		format := Format(m[3])
+		if m[3] == "yml" {
+			format = FormatYAML
+		}
+
+		blocks = append(blocks, Block{
+			Kind:       Kind(m[1]),
+			InstanceID: instanceID,
+			Format:     format,
+			Data:       []byte(m[4]),
+		})
+	}
+
+	return blocks, nil
+}
+
+// Decode unmarshals the block payload into v and validates it
+func (b *Block) Decode(v Message) error {
+	var err error
+	switch b.Format {
+	case FormatJSON:
+		err = json.Unmarshal(b.Data, v)
+	case FormatYAML:
+		err = yaml.Unmarshal(b.Data, v)
+	default:
+		return fmt.Errorf("unsupported block format: %s", b.Format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode %s block: %w", b.Kind, err)
+	}
+
+	if v.Kind() != b.Kind {
+		return fmt.Errorf("block kind %s does not match message kind %s", b.Kind, v.Kind())
+	}
+
+	if err := v.Validate(); err != nil {
+		return fmt.Errorf("invalid %s block from instance %d: %w", b.Kind, b.InstanceID, err)
+	}
+
+	return nil
+}
+
+// Render builds a comment body containing the message as a JSON block
+func Render(instanceID int, msg Message) (string, error) {
+	if err := msg.Validate(); err != nil {
+		return "", fmt.Errorf("invalid %s message: %w", msg.Kind(), err)
+	}
+
+	data, err := json.MarshalIndent(msg, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s message: %w", msg.Kind(), err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!-- %s:START:%d -->\n", msg.Kind(), instanceID)
+	b.WriteString("```json\n")
+	b.Write(data)
+	b.WriteString("\n```\n")
+	fmt.Fprintf(&b, "<!-- %s:END:%d -->", msg.Kind(), instanceID)
+
+	return b.String(), nil
+}
@@ -0,0 +1,300 @@
+package protocol
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// roundTripMessages holds a valid message of every kind with its fields set
+func roundTripMessages() []Message {
+	coverage := 87.5
+	return []Message{
+		&Status{InstanceID: 2, Status: "in_progress", Role: "worker",
+			CurrentTask:    CurrentTask{ID: "task-1", Description: "Add the export API", Progress: 40, StartedAt: "2024-06-01T12:00:00Z"},
+			Health:         Health{CPUUsage: 12.5, MemoryMB: 512, LastHeartbeat: "2024-06-01T12:05:00Z"},
+			ConsolePreview: []string{"ok  ./...", "L3: --- FAIL: TestExport"}, PreviewTruncation: "errors"},
+		&Capabilities{Version: Version, InstanceID: 2, OS: "Linux", CPUs: 4, MemoryMB: 16384,
+			Toolchains: map[string]string{"go": "1.22.1"}, ProviderReachable: true, ProbedAt: "2024-06-01T12:00:00Z"},
+		&Assignment{Version: Version, TaskID: "task-1", InstanceID: 2, Description: "Add the export API",
+			Agent: "backend", Paths: []string{"api/"}, DependsOn: []string{"task-0"}},
+		&Result{Version: Version, Schema: ResultSchema, TaskID: "task-1", InstanceID: 2, Outcome: "completed",
+			Branch: "autonomous/task-1", PullRequests: []int{7}, Tests: &TestRun{Command: "go test ./...", Passed: 12},
+			Coverage: &coverage, CostUSD: 0.42},
+		&Decision{Version: Version, DecisionID: "db", InstanceID: 1, Question: "Which database?", Options: []string{"postgres", "sqlite"}},
+		&Vote{Version: Version, DecisionID: "db", InstanceID: 2, Option: "postgres"},
+		&Control{Version: Version, Action: ActionPause, Mode: PauseCheckpoint, RunID: 99, IssuedAt: "2024-06-01T12:00:00Z"},
+		&Replan{Version: Version, Task: "- Add the export API", Cancelled: []string{"task-2"}, Instances: []int{2}, IssuedAt: "2024-06-01T12:00:00Z"},
+	}
+}
+
+func TestRenderParseRoundTrip(t *testing.T) {
+	for _, msg := range roundTripMessages() {
+		t.Run(string(msg.Kind()), func(t *testing.T) {
+			body, err := Render(3, msg)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			blocks, err := Parse("Some prose before.\n\n" + body + "\n\nAnd after.")
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(blocks) != 1 {
+				t.Fatalf("Parse returned %d blocks, want 1", len(blocks))
+			}
+			if blocks[0].Kind != msg.Kind() || blocks[0].InstanceID != 3 || blocks[0].Format != FormatJSON {
+				t.Errorf("block = %s:%d (%s), want %s:3 (json)", blocks[0].Kind, blocks[0].InstanceID, blocks[0].Format, msg.Kind())
+			}
+
+			decoded := reflect.New(reflect.TypeOf(msg).Elem()).Interface().(Message)
+			if err := blocks[0].Decode(decoded); err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, msg) {
+				t.Errorf("decoded %+v, want %+v", decoded, msg)
+			}
+		})
+	}
+}
+
+func TestRenderRejectsInvalid(t *testing.T) {
+	if _, err := Render(1, &Vote{Version: Version, DecisionID: "db", InstanceID: 1}); err == nil {
+		t.Error("Render accepted a vote without option")
+	}
+}
+
+func TestCollectRoundTrip(t *testing.T) {
+	var bodies []string
+	for _, msg := range roundTripMessages() {
+		body, err := Render(2, msg)
+		if err != nil {
+			t.Fatalf("Render %s: %v", msg.Kind(), err)
+		}
+		bodies = append(bodies, body)
+	}
+	// Messages may share a comment
+	bodies = append(bodies[:len(bodies)-2], strings.Join(bodies[len(bodies)-2:], "\n\n"))
+
+	snap, errs := Collect(bodies)
+	if len(errs) > 0 {
+		t.Fatalf("Collect errors: %v", errs)
+	}
+	msgs := roundTripMessages()
+	checks := []struct {
+		name      string
+		got, want any
+	}{
+		{"status", snap.Statuses[2], msgs[0]},
+		{"capabilities", snap.Capabilities[2], msgs[1]},
+		{"result", snap.Results["task-1"], msgs[3]},
+		{"decision", snap.Decisions["db"], msgs[4]},
+		{"vote", snap.Votes["db"][2], msgs[5]},
+		{"control", snap.Control, msgs[6]},
+		{"replan", snap.Replan, msgs[7]},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %+v, want %+v", c.name, c.got, c.want)
+		}
+	}
+	if a := snap.Assignments["task-1"]; !reflect.DeepEqual(a, msgs[2]) {
+		t.Errorf("assignment = %+v, want %+v", a, msgs[2])
+	}
+	if !snap.Paused() {
+		t.Error("snapshot is not paused")
+	}
+	if len(snap.Heartbeats[2]) != 1 {
+		t.Errorf("heartbeats = %v, want 1", snap.Heartbeats[2])
+	}
+}
+
+func TestCollectLaterMessagesWin(t *testing.T) {
+	render := func(msg Message) string {
+		body, err := Render(0, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+	bodies := []string{
+		render(&Assignment{Version: Version, TaskID: "task-1", InstanceID: 1, Description: "Export"}),
+		render(&Assignment{Version: Version, TaskID: "task-2", InstanceID: 1, Description: "Import"}),
+		render(&Replan{Version: Version, Task: "Import", Cancelled: []string{"task-1"}}),
+		render(&Assignment{Version: Version, TaskID: "task-2", InstanceID: 2, Description: "Import"}),
+		render(&Control{Version: Version, Action: ActionPause, Mode: PauseCheckpoint}),
+		render(&Control{Version: Version, Action: ActionResume}),
+	}
+
+	snap, errs := Collect(bodies)
+	if len(errs) > 0 {
+		t.Fatalf("Collect errors: %v", errs)
+	}
+	if _, ok := snap.Assignments["task-1"]; ok {
+		t.Error("cancelled task-1 is still assigned")
+	}
+	if _, ok := snap.Cancelled["task-1"]; !ok {
+		t.Error("task-1 is not cancelled")
+	}
+	if a := snap.Assignments["task-2"]; a == nil || a.InstanceID != 2 {
+		t.Errorf("task-2 assigned to %+v, want instance 2", a)
+	}
+	if snap.Paused() {
+		t.Error("resumed run is paused")
+	}
+}
+
+func TestParse(t *testing.T) {
+	block := func(start, format, data, end string) string {
+		return "<!-- " + start + " -->\n```" + format + "\n" + data + "\n```\n<!-- " + end + " -->"
+	}
+	tests := []struct {
+		name    string
+		body    string
+		want    []Block
+		wantErr bool
+	}{
+		{name: "no blocks", body: "Just a comment."},
+		{
+			name: "json block",
+			body: block("TASK_RESULT:START:2", "json", `{"task_id": "task-1"}`, "TASK_RESULT:END:2"),
+			want: []Block{{Kind: KindResult, InstanceID: 2, Format: FormatJSON, Data: []byte(`{"task_id": "task-1"}`)}},
+		},
+		{
+			name: "yml block",
+			body: block("DECISION_VOTE:START:3", "yml", "option: a", "DECISION_VOTE:END:3"),
+			want: []Block{{Kind: KindVote, InstanceID: 3, Format: FormatYAML, Data: []byte("option: a")}},
+		},
+		{
+			name: "several blocks with prose",
+			body: "Intro\n" + block("TASK_RESULT:START:1", "json", "{}", "TASK_RESULT:END:1") + "\nmiddle\n" +
+				block("INSTANCE_STATUS:START:1", "yaml", "status: ready", "INSTANCE_STATUS:END:1") + "\nOutro",
+			want: []Block{
+				{Kind: KindResult, InstanceID: 1, Format: FormatJSON, Data: []byte("{}")},
+				{Kind: KindStatus, InstanceID: 1, Format: FormatYAML, Data: []byte("status: ready")},
+			},
+		},
+		{
+			name: "multi-line payload",
+			body: block("TASK_ASSIGNMENT:START:2", "json", "{\n  \"task_id\": \"task-1\"\n}", "TASK_ASSIGNMENT:END:2"),
+			want: []Block{{Kind: KindAssignment, InstanceID: 2, Format: FormatJSON, Data: []byte("{\n  \"task_id\": \"task-1\"\n}")}},
+		},
+		{
+			name:    "mismatched kind",
+			body:    block("TASK_RESULT:START:1", "json", "{}", "TASK_ASSIGNMENT:END:1"),
+			wantErr: true,
+		},
+		{
+			name:    "mismatched instance",
+			body:    block("TASK_RESULT:START:1", "json", "{}", "TASK_RESULT:END:2"),
+			wantErr: true,
+		},
+		{
+			name: "missing END marker",
+			body: "<!-- TASK_RESULT:START:1 -->\n```json\n{}\n```\nNo end marker.",
+		},
+		{
+			name: "missing fence",
+			body: "<!-- TASK_RESULT:START:1 -->\n{}\n<!-- TASK_RESULT:END:1 -->",
+		},
+		{
+			name: "unsupported format",
+			body: block("TASK_RESULT:START:1", "xml", "<result/>", "TASK_RESULT:END:1"),
+		},
+		{
+			name: "negative instance",
+			body: block("TASK_RESULT:START:-1", "json", "{}", "TASK_RESULT:END:-1"),
+		},
+		{
+			// The inner block is taken as the payload of the outer one, up to
+			// the first closing fence, so the markers do not pair up
+			name:    "nested blocks",
+			body:    block("TASK_RESULT:START:1", "json", block("TASK_ASSIGNMENT:START:2", "json", "{}", "TASK_ASSIGNMENT:END:2"), "TASK_RESULT:END:1"),
+			wantErr: true,
+		},
+		{
+			// A block missing its END marker pairs with the next block's end
+			name:    "missing END before another block",
+			body:    "<!-- TASK_RESULT:START:1 -->\n```json\n{}\n```\n\n" + block("INSTANCE_STATUS:START:1", "json", "{}", "INSTANCE_STATUS:END:1"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := roundTripMessages()
+	tests := []struct {
+		name  string
+		msg   Message
+		valid bool
+	}{
+		{"status", valid[0], true},
+		{"status without instance", &Status{Status: "ready", Role: "worker"}, false},
+		{"status unknown state", &Status{InstanceID: 1, Status: "sleeping", Role: "worker"}, false},
+		{"status unknown role", &Status{InstanceID: 1, Status: "ready", Role: "observer"}, false},
+		{"status progress over 100", &Status{InstanceID: 1, Status: "ready", Role: "worker", CurrentTask: CurrentTask{Progress: 101}}, false},
+
+		{"capabilities", valid[1], true},
+		{"capabilities future version", &Capabilities{Version: Version + 1, InstanceID: 1}, false},
+		{"capabilities without instance", &Capabilities{Version: Version}, false},
+		{"capabilities negative resources", &Capabilities{Version: Version, InstanceID: 1, MemoryMB: -1}, false},
+
+		{"assignment", valid[2], true},
+		{"assignment without version", &Assignment{TaskID: "t", InstanceID: 1, Description: "d"}, false},
+		{"assignment without task", &Assignment{Version: Version, InstanceID: 1, Description: "d"}, false},
+		{"assignment without instance", &Assignment{Version: Version, TaskID: "t", Description: "d"}, false},
+		{"assignment without description", &Assignment{Version: Version, TaskID: "t", InstanceID: 1}, false},
+
+		{"result", valid[3], true},
+		{"result without schema", &Result{Version: Version, TaskID: "t", InstanceID: 1, Outcome: "completed"}, true},
+		{"result unknown outcome", &Result{Version: Version, TaskID: "t", InstanceID: 1, Outcome: "done"}, false},
+		{"result negative cost", &Result{Version: Version, TaskID: "t", InstanceID: 1, Outcome: "failed", CostUSD: -1}, false},
+		{"result limit without limit_reached", &Result{Version: Version, TaskID: "t", InstanceID: 1, Outcome: "failed", Limit: LimitMaxTurns}, false},
+		{"result limit_reached", &Result{Version: Version, TaskID: "t", InstanceID: 1, Outcome: OutcomeLimitReached, Limit: LimitMaxTokens}, true},
+		{"result schema without branch", &Result{Version: Version, Schema: ResultSchema, TaskID: "t", InstanceID: 1, Outcome: "failed"}, true},
+		{"result schema completed without tests", &Result{Version: Version, Schema: ResultSchema, TaskID: "t", InstanceID: 1, Outcome: "completed", Branch: "b"}, false},
+		{"result schema completed with failures", &Result{Version: Version, Schema: ResultSchema, TaskID: "t", InstanceID: 1, Outcome: "completed", Branch: "b",
+			Tests: &TestRun{Command: "go test", Failed: 1}}, false},
+		{"result schema partial without follow-ups", &Result{Version: Version, Schema: ResultSchema, TaskID: "t", InstanceID: 1, Outcome: "partial", Branch: "b"}, false},
+		{"result unsupported schema", &Result{Version: Version, Schema: ResultSchema + 1, TaskID: "t", InstanceID: 1, Outcome: "failed"}, false},
+
+		{"decision", valid[4], true},
+		{"decision one option", &Decision{Version: Version, DecisionID: "d", InstanceID: 1, Question: "q", Options: []string{"a"}}, false},
+		{"decision too many options", &Decision{Version: Version, DecisionID: "d", InstanceID: 1, Question: "q",
+			Options: make([]string, len(ReactionOptions)+1)}, false},
+		{"decision without question", &Decision{Version: Version, DecisionID: "d", InstanceID: 1, Options: []string{"a", "b"}}, false},
+		{"decision negative quorum", &Decision{Version: Version, DecisionID: "d", InstanceID: 1, Question: "q", Options: []string{"a", "b"}, Quorum: -1}, false},
+
+		{"vote", valid[5], true},
+		{"vote without decision", &Vote{Version: Version, InstanceID: 1, Option: "a"}, false},
+		{"vote without option", &Vote{Version: Version, DecisionID: "d", InstanceID: 1}, false},
+
+		{"control pause", valid[6], true},
+		{"control resume", &Control{Version: Version, Action: ActionResume}, true},
+		{"control pause without mode", &Control{Version: Version, Action: ActionPause}, false},
+		{"control unknown action", &Control{Version: Version, Action: "stop"}, false},
+		{"control negative instances", &Control{Version: Version, Action: ActionResume, Instances: -1}, false},
+
+		{"replan", valid[7], true},
+		{"replan without task", &Replan{Version: Version}, false},
+		{"replan invalid instance", &Replan{Version: Version, Task: "t", Instances: []int{0}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.Validate()
+			if (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
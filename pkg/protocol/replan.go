@@ -0,0 +1,56 @@
+package protocol
+
+import "fmt"
+
+// KindReplan messages are posted by the CLI, as instance 0, when the task of
+// a run changes while instances work on it
+const KindReplan Kind = "TASK_REPLAN"
+
+// Replan records a change of the task of a run. Cancelled tasks are dropped
+// from the plan; new subtasks are posted as assignments next to it.
+type Replan struct {
+	Version int `json:"version" yaml:"version"`
+	// Task is the updated task description
+	Task string `json:"task" yaml:"task"`
+	// Cancelled are the task IDs no longer part of the plan
+	Cancelled []string `json:"cancelled,omitempty" yaml:"cancelled,omitempty"`
+	// Updated are the kept task IDs whose description changed
+	Updated []string `json:"updated,omitempty" yaml:"updated,omitempty"`
+	// Added are the task IDs of new subtasks
+	Added []string `json:"added,omitempty" yaml:"added,omitempty"`
+	// Instances are the instances whose work changed
+	Instances []int  `json:"instances,omitempty" yaml:"instances,omitempty"`
+	Reason    string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	IssuedAt  string `json:"issued_at" yaml:"issued_at"`
+}
+
+// Kind implements Message
+func (r *Replan) Kind() Kind { return KindReplan }
+
+// Validate implements Message
+func (r *Replan) Validate() error {
+	if r.Version < 1 || r.Version > Version {
+		return fmt.Errorf("unsupported protocol version %d", r.Version)
+	}
+	if r.Task == "" {
+		return fmt.Errorf("task is required")
+	}
+	for _, id := range r.Instances {
+		if id < 1 {
+			return fmt.Errorf("instances must be positive, got %d", id)
+		}
+	}
+	return nil
+}
+
+// replan applies a replan to the snapshot: its cancelled tasks move from
+// Assignments to Cancelled unless they are assigned again later
+func (s *Snapshot) replan(r *Replan) {
+	s.Replan = r
+	for _, taskID := range r.Cancelled {
+		if a, ok := s.Assignments[taskID]; ok {
+			s.Cancelled[taskID] = a
+			delete(s.Assignments, taskID)
+		}
+	}
+}
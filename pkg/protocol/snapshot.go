@@ -0,0 +1,165 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Snapshot is the latest known coordination state of a run
+type Snapshot struct {
+	Statuses map[int]*Status
+	// Capabilities holds the latest capability probe of each instance
+	Capabilities map[int]*Capabilities
+	Assignments  map[string]*Assignment
+	// Cancelled holds the assignments a replan dropped from the plan
+	Cancelled map[string]*Assignment
+	Results   map[string]*Result
+	Decisions map[string]*Decision
+	// Votes holds the latest vote of each instance per decision
+	Votes map[string]map[int]*Vote
+	// Heartbeats holds every reported heartbeat per instance, in posting order
+	Heartbeats map[int][]time.Time
+	// Control is the latest pause or resume message, if any
+	Control *Control
+	// Replan is the latest change of the run's task, if any
+	Replan *Replan
+	// Rejected holds the latest malformed result of each instance that has
+	// not posted a valid result since
+	Rejected map[int]error
+}
+
+// Collect builds a snapshot from comment bodies in chronological order.
+// Later messages override earlier ones. Malformed blocks are skipped and
+// returned as errors so callers can surface protocol violations.
+func Collect(bodies []string) (*Snapshot, []error) {
+	snap := &Snapshot{
+		Statuses:     make(map[int]*Status),
+		Capabilities: make(map[int]*Capabilities),
+		Assignments:  make(map[string]*Assignment),
+		Cancelled:    make(map[string]*Assignment),
+		Results:      make(map[string]*Result),
+		Decisions:    make(map[string]*Decision),
+		Votes:        make(map[string]map[int]*Vote),
+		Heartbeats:   make(map[int][]time.Time),
+		Rejected:     make(map[int]error),
+	}
+	var errs []error
+
+	for _, body := range bodies {
+		blocks, err := Parse(body)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for i := range blocks {
+			block := &blocks[i]
+			switch block.Kind {
+			case KindStatus:
+				var s Status
+				if err := block.Decode(&s); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				snap.Statuses[s.InstanceID] = &s
+				if beat, err := time.Parse(time.RFC3339, s.Health.LastHeartbeat); err == nil {
+					snap.Heartbeats[s.InstanceID] = append(snap.Heartbeats[s.InstanceID], beat)
+				}
+			case KindCapability:
+				var c Capabilities
+				if err := block.Decode(&c); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				snap.Capabilities[c.InstanceID] = &c
+			case KindControl:
+				var c Control
+				if err := block.Decode(&c); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				snap.Control = &c
+			case KindReplan:
+				var r Replan
+				if err := block.Decode(&r); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				snap.replan(&r)
+			case KindAssignment:
+				var a Assignment
+				if err := block.Decode(&a); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				snap.Assignments[a.TaskID] = &a
+				delete(snap.Cancelled, a.TaskID)
+			case KindResult:
+				var r Result
+				if err := block.Decode(&r); err != nil {
+					errs = append(errs, err)
+					snap.Rejected[block.InstanceID] = err
+					continue
+				}
+				snap.Results[r.TaskID] = &r
+				delete(snap.Rejected, r.InstanceID)
+			case KindDecision:
+				var d Decision
+				if err := block.Decode(&d); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				snap.Decisions[d.DecisionID] = &d
+			case KindVote:
+				var v Vote
+				if err := block.Decode(&v); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if snap.Votes[v.DecisionID] == nil {
+					snap.Votes[v.DecisionID] = make(map[int]*Vote)
+				}
+				snap.Votes[v.DecisionID][v.InstanceID] = &v
+			}
+		}
+	}
+
+	return snap, errs
+}
+
+// RequireSchema rejects results that do not declare a result schema: they
+// are removed from Results, recorded in Rejected and returned as errors
+func (s *Snapshot) RequireSchema() []error {
+	var errs []error
+	for taskID, r := range s.Results {
+		if r.Schema != 0 {
+			continue
+		}
+		err := fmt.Errorf("invalid %s block from instance %d: task %s declares no result schema", KindResult, r.InstanceID, taskID)
+		s.Rejected[r.InstanceID] = err
+		delete(s.Results, taskID)
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// InstanceIDs returns the IDs of all instances that reported status, sorted
+func (s *Snapshot) InstanceIDs() []int {
+	ids := make([]int, 0, len(s.Statuses))
+	for id := range s.Statuses {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// TaskIDs returns the IDs of all assigned tasks, sorted
+func (s *Snapshot) TaskIDs() []string {
+	ids := make([]string, 0, len(s.Assignments))
+	for id := range s.Assignments {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,30 @@
+package protocol
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+)
+
+// tracePattern matches trace IDs, e.g. "tr-3f9a0c12b7e4"
+var tracePattern = regexp.MustCompile(`\btr-[0-9a-f]{12}\b`)
+
+// NewTraceID generates the ID that ties a run's issue, workflow run, logs,
+// notifications and history together
+func NewTraceID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return "tr-" + hex.EncodeToString(b)
+}
+
+// ValidTraceID reports whether s is a trace ID
+func ValidTraceID(s string) bool {
+	return s != "" && FindTraceID(s) == s
+}
+
+// FindTraceID returns the first trace ID in text, or "" if none
+func FindTraceID(text string) string {
+	return tracePattern.FindString(text)
+}
@@ -0,0 +1,67 @@
+package protocol
+
+import (
+	"strings"
+
+	"github.com/autonomous-dev/cli/internal/chunk"
+)
+
+// ActionsBot is the author of comments posted with a workflow's
+// GITHUB_TOKEN, as the run's instances do
+const ActionsBot = "github-actions[bot]"
+
+// Steers reports whether messages of the kind direct the run's work:
+// assignments, replans and pause or resume messages. Only trusted authors
+// may post them.
+func (k Kind) Steers() bool {
+	switch k {
+	case KindAssignment, KindReplan, KindControl:
+		return true
+	}
+	return false
+}
+
+// Comment is a comment carrying messages, with its author's login
+type Comment struct {
+	Author string
+	Body   string
+}
+
+// TrustedBodies returns the message bodies of comments in chronological
+// order, with messages split over several comments reassembled. Comments
+// of authors other than ActionsBot and trusted keep their messages, except
+// the ones that steer the run, and are never reassembled with other
+// comments. Logins match case-insensitively, with or without the "[bot]"
+// suffix GraphQL leaves out.
+func TrustedBodies(comments []Comment, trusted ...string) []string {
+	logins := map[string]bool{loginKey(ActionsBot): true}
+	for _, login := range trusted {
+		if login != "" {
+			logins[loginKey(login)] = true
+		}
+	}
+
+	bodies := make([]string, 0, len(comments))
+	for _, c := range comments {
+		body := c.Body
+		if !logins[loginKey(c.Author)] {
+			body = stripSteering(chunk.Unmark(body))
+		}
+		bodies = append(bodies, body)
+	}
+	return chunk.Join(bodies)
+}
+
+// stripSteering removes the blocks that steer the run from a body
+func stripSteering(body string) string {
+	return blockPattern.ReplaceAllStringFunc(body, func(block string) string {
+		if m := blockPattern.FindStringSubmatch(block); Kind(m[1]).Steers() || Kind(m[5]).Steers() {
+			return ""
+		}
+		return block
+	})
+}
+
+func loginKey(login string) string {
+	return strings.TrimSuffix(strings.ToLower(login), "[bot]")
+}
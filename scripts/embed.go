@@ -0,0 +1,21 @@
+// Package scripts embeds helper scripts that generated workflows run
+package scripts
+
+import _ "embed"
+
+// StatusReporter is instance-status-reporter.sh, sourced by workflow instances
+//
+//go:embed instance-status-reporter.sh
+var StatusReporter []byte
+
+// StatusReporterPath is where generated workflows expect the status reporter
+const StatusReporterPath = "scripts/instance-status-reporter.sh"
+
+// StatusReporterPowerShell is instance-status-reporter.ps1, the equivalent
+// for pwsh steps on Windows runners
+//
+//go:embed instance-status-reporter.ps1
+var StatusReporterPowerShell []byte
+
+// StatusReporterPowerShellPath is where the PowerShell status reporter is pushed
+const StatusReporterPowerShellPath = "scripts/instance-status-reporter.ps1"
@@ -0,0 +1,700 @@
+# Instance Status Reporter (PowerShell)
+# PowerShell equivalent of instance-status-reporter.sh for Windows runners.
+# Posts structured status updates to GitHub Issue for P2P coordination.
+# With COORDINATION_MODE=dispatch, messages are committed to the state branch
+# under runs/$RUN_ID/messages/ instead.
+#
+# Usage in a workflow step with `shell: pwsh`:
+#   . ./scripts/instance-status-reporter.ps1
+#   Send-Status "in_progress" "task-1" "Implement feature X" 50 (Get-Content work.log -Tail 100)
+
+$ErrorActionPreference = 'Stop'
+
+$script:InstanceId = if ($env:INSTANCE_ID) { [int]$env:INSTANCE_ID } else { 1 }
+$script:IssueNumber = $env:ISSUE_NUMBER
+$script:Role = if ($env:ROLE) { $env:ROLE } else { 'worker' }  # leader or worker
+$script:CoordinationMode = if ($env:COORDINATION_MODE) { $env:COORDINATION_MODE } else { 'issues' }  # issues or dispatch
+$script:RunId = $env:RUN_ID
+$script:StateBranch = if ($env:STATE_BRANCH) { $env:STATE_BRANCH } else { 'autonomous-dev-state' }
+$script:BranchPrefix = if ($env:BRANCH_PREFIX) { $env:BRANCH_PREFIX } else { 'autonomous/' }  # branches.prefix
+$script:PrDraft = $env:PR_DRAFT -eq 'true'  # prs.draft
+$script:AutoMerge = $env:AUTO_MERGE -eq 'true'  # branches.auto_merge
+$script:MaxTurns = $env:MAX_TURNS  # provider.max_turns (empty: provider default)
+$script:MaxTokensPerInstance = $env:MAX_TOKENS_PER_INSTANCE  # provider.max_tokens_per_instance
+$script:ProviderUrl = if ($env:PROVIDER_URL) { $env:PROVIDER_URL } else { 'https://api.anthropic.com' }  # probed for network egress
+$script:PreviewLines = if ($env:STATUS_PREVIEW_LINES) { [int]$env:STATUS_PREVIEW_LINES } else { 3 }  # logs.status.lines
+$script:PreviewMaxBytes = if ($env:STATUS_PREVIEW_MAX_BYTES) { [int]$env:STATUS_PREVIEW_MAX_BYTES } else { 2048 }  # logs.status.max_bytes
+$script:PreviewInclude = if ($env:STATUS_PREVIEW_INCLUDE) { $env:STATUS_PREVIEW_INCLUDE } else { 'always' }  # logs.status.include: always, failures or never
+$script:PreviewTruncate = if ($env:STATUS_PREVIEW_TRUNCATE) { $env:STATUS_PREVIEW_TRUNCATE } else { 'tail' }  # logs.status.truncate: tail, head_tail or errors
+$script:TrustedAuthors = @('github-actions[bot]') + @("$env:TRUSTED_AUTHORS" -split '\s+' | Where-Object { $_ })  # logins that may steer the run
+
+if ($script:CoordinationMode -eq 'dispatch') {
+  if (-not $script:RunId -or -not $env:GITHUB_TOKEN) {
+    throw 'RUN_ID and GITHUB_TOKEN required in dispatch mode'
+  }
+} elseif (-not $script:IssueNumber -or -not $env:GITHUB_TOKEN) {
+  throw 'ISSUE_NUMBER and GITHUB_TOKEN required'
+}
+
+# Post a coordination message (issue comment, or a file on the state branch)
+# bash: publish_comment
+function Publish-Comment([string]$Body) {
+  if ($script:CoordinationMode -ne 'dispatch') {
+    gh issue comment $script:IssueNumber --body $Body
+    return
+  }
+
+  # Timestamped names keep messages in posting order
+  $stamp = (Get-Date).ToUniversalTime().ToString('yyyyMMddTHHmmss.fffffffZ')
+  $path = "runs/$($script:RunId)/messages/$stamp-instance-$($script:InstanceId).md"
+  $request = @{
+    message = "Instance $($script:InstanceId) message"
+    branch  = $script:StateBranch
+    content = [Convert]::ToBase64String([Text.Encoding]::UTF8.GetBytes($Body))
+  } | ConvertTo-Json -Compress
+
+  # Concurrent commits to the branch can conflict; retry with backoff
+  foreach ($attempt in 1..3) {
+    $request | gh api --method PUT "/repos/$($env:GITHUB_REPOSITORY)/contents/$path" --input - | Out-Null
+    if ($LASTEXITCODE -eq 0) { return }
+    Start-Sleep -Seconds ($attempt * 2)
+  }
+
+  throw "failed to publish message to $($script:StateBranch)"
+}
+
+# Reassemble the messages of {login, body} comments that were split over
+# several comments (see internal/chunk): a split message takes the place of
+# its last piece. Only pieces of the same author are joined.
+# bash: join_chunks
+function Join-Chunks([object[]]$Comments) {
+  $groups = @{}
+  $keys = @{}
+  for ($i = 0; $i -lt $Comments.Count; $i++) {
+    $body = "$($Comments[$i].body)"
+    $m = [regex]::Match($body, '^<!-- CHUNK:([0-9a-f]+):(\d+)/(\d+) -->\n')
+    if (-not $m.Success) { continue }
+    $index = [int]$m.Groups[2].Value
+    $total = [int]$m.Groups[3].Value
+    if ($index -lt 1 -or $index -gt $total) { continue }
+
+    $piece = $body.Substring($m.Length)
+    if ($index -lt $total) {
+      $end = $piece.LastIndexOf("`n<!-- CHUNK:END -->", [StringComparison]::Ordinal)
+      if ($end -ge 0) { $piece = $piece.Substring(0, $end) }
+    }
+    $key = "$($Comments[$i].login):$($m.Groups[1].Value)"
+    if (-not $groups.ContainsKey($key)) {
+      $groups[$key] = @{ first = $Comments[$i]; pieces = @{}; last = 0 }
+    }
+    $groups[$key].pieces[$index] = $piece
+    $groups[$key].last = $i
+    $keys[$i] = $key
+  }
+
+  for ($i = 0; $i -lt $Comments.Count; $i++) {
+    if (-not $keys.ContainsKey($i)) { $Comments[$i]; continue }
+    $group = $groups[$keys[$i]]
+    if ($group.last -ne $i) { continue }
+    $joined = $group.first.PSObject.Copy()
+    $joined.body = ($group.pieces.Keys | Sort-Object | ForEach-Object { $group.pieces[$_] }) -join ''
+    $joined
+  }
+}
+
+# Return the comments of the coordination issue, or the messages of the state
+# branch run, as posted: {login, body} objects in posting order
+# bash: fetch_comments
+function Get-CommentItems {
+  if ($script:CoordinationMode -ne 'dispatch') {
+    $pages = gh api --paginate "/repos/$($env:GITHUB_REPOSITORY)/issues/$($script:IssueNumber)/comments" --jq '.[] | {login: .user.login, body: .body} | @json'
+    return @($pages | ForEach-Object { $_ | ConvertFrom-Json })
+  }
+
+  $files = gh api "/repos/$($env:GITHUB_REPOSITORY)/contents/runs/$($script:RunId)/messages?ref=$($script:StateBranch)" --jq '.[].path' 2>$null
+  return @($files | Sort-Object | ForEach-Object {
+    $body = (gh api -H 'Accept: application/vnd.github.raw' "/repos/$($env:GITHUB_REPOSITORY)/contents/$($_)?ref=$($script:StateBranch)") -join "`n"
+    [pscustomobject]@{ login = ''; body = $body }
+  })
+}
+
+# Return all coordination message bodies in posting order, split messages
+# reassembled. With -Steering, only those that may steer the run
+# (assignments, replans, pause and resume): comments of the instances and of
+# TRUSTED_AUTHORS. State branch messages need push access to post.
+# bash: list_comments, list_steering_comments
+function Get-Comments([switch]$Steering) {
+  $comments = Get-CommentItems
+  if ($Steering -and $script:CoordinationMode -ne 'dispatch') {
+    $comments = @($comments | Where-Object { $script:TrustedAuthors -contains $_.login })
+  }
+  return @(Join-Chunks $comments | ForEach-Object { $_.body })
+}
+
+# Return the task description of this run. An oversized issue body continues
+# in comments of the CLI's account (one of TRUSTED_AUTHORS).
+# bash: get_task
+function Get-Task {
+  if ($script:CoordinationMode -eq 'dispatch') {
+    return (gh api -H 'Accept: application/vnd.github.raw' "/repos/$($env:GITHUB_REPOSITORY)/contents/runs/$($script:RunId)/task.md?ref=$($script:StateBranch)") -join "`n"
+  }
+
+  # The issue may have been opened by someone else and adopted by the CLI,
+  # so its pieces are joined regardless of author
+  $issue = [pscustomobject]@{ login = ''; body = (gh issue view $script:IssueNumber --json body --jq .body) -join "`n"; task = $true }
+  $pieces = @(Get-CommentItems | Where-Object { $script:TrustedAuthors -contains $_.login } |
+    ForEach-Object { [pscustomobject]@{ login = ''; body = $_.body; task = $false } })
+  return (Join-Chunks (@($issue) + $pieces) | Where-Object { $_.task } | Select-Object -First 1).body
+}
+
+# Post a structured message block (kind, instance id, payload object)
+# bash: post_block
+function Send-Block([string]$Kind, [int]$TargetId, $Payload) {
+  $json = $Payload | ConvertTo-Json -Depth 10
+  $body = "<!-- ${Kind}:START:${TargetId} -->`n``````json`n$json`n```````n<!-- ${Kind}:END:${TargetId} -->"
+  Publish-Comment $body
+}
+
+# Report status to issue
+# bash: report_status
+# Cut console output down to the preview of a status report. Omitted lines
+# are replaced by one "[... N lines omitted ...]" line; with errors
+# truncation kept lines are numbered "L<n>: ".
+# bash: status_preview
+function Get-StatusPreview([string]$Status, [string[]]$ConsoleOutput) {
+  if ($script:PreviewInclude -eq 'never') { return @() }
+  if ($script:PreviewInclude -eq 'failures' -and $Status -ne 'failed') { return @() }
+  $out = @($ConsoleOutput)
+  if ($out.Count -eq 0) { return @() }
+
+  $lines = $script:PreviewLines
+  $kept = switch ($script:PreviewTruncate) {
+    'head_tail' {
+      if ($out.Count -le $lines) { $out; break }
+      $head = [int][Math]::Floor(($lines + 1) / 2)
+      $tail = [int][Math]::Floor($lines / 2)
+      $out | Select-Object -First $head
+      "[... $($out.Count - $head - $tail) lines omitted ...]"
+      if ($tail -gt 0) { $out | Select-Object -Last $tail }
+      break
+    }
+    'errors' {
+      # Lines matching an error with one line of context, the last ones first
+      $numbers = [System.Collections.Generic.SortedSet[int]]::new()
+      for ($i = 0; $i -lt $out.Count; $i++) {
+        if ($out[$i] -match 'error|fail|fatal|panic|exception') {
+          foreach ($j in ($i - 1)..($i + 1)) {
+            if ($j -ge 0 -and $j -lt $out.Count) { [void]$numbers.Add($j) }
+          }
+        }
+      }
+      if ($numbers.Count -eq 0) { $out | Select-Object -Last $lines; break }
+      $numbers | Select-Object -Last $lines | ForEach-Object { "L$($_ + 1): $($out[$_])" }
+      break
+    }
+    default { $out | Select-Object -Last $lines }
+  }
+
+  # Keep the last lines that fit in max bytes
+  $kept = @($kept | ForEach-Object { if ($_.Length -gt $script:PreviewMaxBytes) { $_.Substring(0, $script:PreviewMaxBytes) } else { $_ } })
+  $size = 0
+  $first = $kept.Count
+  for ($i = $kept.Count - 1; $i -ge 0; $i--) {
+    $size += [Text.Encoding]::UTF8.GetByteCount($kept[$i]) + 1
+    if ($size -gt $script:PreviewMaxBytes) { break }
+    $first = $i
+  }
+  $preview = @()
+  if ($first -gt 0) { $preview += "[... $first lines omitted ...]" }
+  if ($first -lt $kept.Count) { $preview += $kept[$first..($kept.Count - 1)] }
+  return $preview
+}
+
+function Send-Status([string]$Status, [string]$TaskId, [string]$TaskDescription, [int]$Progress, [string[]]$ConsoleOutput = @()) {
+  $now = (Get-Date).ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')
+  $os = Get-CimInstance Win32_OperatingSystem
+  $cpu = (Get-CimInstance Win32_Processor | Measure-Object -Property LoadPercentage -Average).Average
+
+  $payload = [ordered]@{
+    instance_id        = $script:InstanceId
+    status             = $Status
+    role               = $script:Role
+    current_task       = [ordered]@{
+      id          = $TaskId
+      description = $TaskDescription
+      progress    = $Progress
+      started_at  = $now
+    }
+    health             = [ordered]@{
+      cpu_usage      = [double]$cpu
+      memory_mb      = [int](($os.TotalVisibleMemorySize - $os.FreePhysicalMemory) / 1024)
+      last_heartbeat = $now
+    }
+    logs_url           = "https://github.com/$($env:GITHUB_REPOSITORY)/actions/runs/$($env:GITHUB_RUN_ID)/job/$($env:GITHUB_JOB)"
+    console_preview    = @(Get-StatusPreview $Status $ConsoleOutput)
+    preview_truncation = $script:PreviewTruncate
+  }
+
+  Send-Block 'INSTANCE_STATUS' $script:InstanceId $payload
+}
+
+# Report this runner's capabilities before doing work: toolchain versions,
+# CPUs, memory, free disk and whether the provider API is reachable
+# bash: report_capabilities
+function Send-Capabilities {
+  $toolchains = [ordered]@{}
+  foreach ($tool in 'go', 'node', 'python', 'java', 'rustc', 'docker') {
+    if (-not (Get-Command $tool -ErrorAction SilentlyContinue)) { continue }
+    $output = if ($tool -eq 'go') { go version } else { & $tool --version 2>&1 | Select-Object -First 1 }
+    if ("$output" -match '(\d+(\.\d+)+)') { $toolchains[$tool] = $Matches[1] }
+  }
+
+  $os = Get-CimInstance Win32_OperatingSystem
+  $disk = Get-PSDrive -Name (Get-Location).Drive.Name
+
+  # Any HTTP response proves egress; only connection failures count
+  $reachable = $false
+  $latency = 0
+  $watch = [Diagnostics.Stopwatch]::StartNew()
+  try {
+    Invoke-WebRequest -Uri $script:ProviderUrl -Method Head -TimeoutSec 10 -UseBasicParsing | Out-Null
+    $reachable = $true
+  } catch {
+    $reachable = $null -ne $_.Exception.Response
+  }
+  if ($reachable) { $latency = [int]$watch.ElapsedMilliseconds }
+
+  $payload = [ordered]@{
+    version             = 1
+    instance_id         = $script:InstanceId
+    runner              = "$env:RUNNER_NAME"
+    os                  = if ($env:RUNNER_OS) { $env:RUNNER_OS } else { 'Windows' }
+    arch                = if ($env:RUNNER_ARCH) { $env:RUNNER_ARCH } else { "$env:PROCESSOR_ARCHITECTURE" }
+    cpus                = [Environment]::ProcessorCount
+    memory_mb           = [int]($os.TotalVisibleMemorySize / 1024)
+    disk_free_mb        = [int]($disk.Free / 1MB)
+    toolchains          = $toolchains
+    provider_reachable  = $reachable
+    provider_latency_ms = $latency
+    probed_at           = (Get-Date).ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')
+  }
+  # Hosted runners name their image in ImageOS/ImageVersion
+  if ($env:ImageOS) { $payload.image = "$env:ImageOS/$env:ImageVersion" }
+  if ($env:ANTHROPIC_MODEL) { $payload.model = $env:ANTHROPIC_MODEL }
+  if (Get-Command claude -ErrorAction SilentlyContinue) { $payload.provider_cli = "$(claude --version | Select-Object -First 1)" }
+  Send-Block 'INSTANCE_CAPABILITIES' $script:InstanceId $payload
+}
+
+# Apply the environment lock of a reproduced run (start --env-lock): use its
+# model and warn about what the workflow could not pin
+# bash: apply_env_lock
+function Use-EnvLock {
+  if (-not $env:ENV_LOCK) { return }
+  $lock = $env:ENV_LOCK | ConvertFrom-Json
+
+  if ($lock.model) {
+    $env:ANTHROPIC_MODEL = $lock.model
+    Write-Host "🔒 Using locked model $($lock.model)"
+  }
+
+  # Hosted runners only select an image family, not a version
+  if ($lock.image -and $lock.image -ne "$env:ImageOS/$env:ImageVersion") {
+    Write-Host "::warning::Runner image $env:ImageOS/$env:ImageVersion differs from locked $($lock.image)"
+  }
+
+  if ($lock.toolchains) {
+    foreach ($tool in $lock.toolchains.PSObject.Properties) {
+      $current = ''
+      if (Get-Command $tool.Name -ErrorAction SilentlyContinue) {
+        $output = if ($tool.Name -eq 'go') { go version } else { & $tool.Name --version 2>&1 | Select-Object -First 1 }
+        if ("$output" -match '(\d+(\.\d+)+)') { $current = $Matches[1] }
+      }
+      if ($current -ne $tool.Value) {
+        Write-Host "::warning::$($tool.Name) $(if ($current) { $current } else { 'missing' }) differs from locked $($tool.Value)"
+      }
+    }
+  }
+}
+
+# Log a line at a level (debug, info, warn or error) for a step, as a JSON
+# object unless LOG_FORMAT is text; lines below LOG_LEVEL are dropped
+# bash: log_event
+function Write-LogEvent([string]$Level, [string]$Step, [string]$Message) {
+  $ranks = @{ debug = 0; info = 1; warn = 2; error = 3 }
+  $rank = if ($ranks.ContainsKey($Level)) { $ranks[$Level] } else { 1 }
+  $threshold = if ($env:LOG_LEVEL -and $ranks.ContainsKey($env:LOG_LEVEL)) { $ranks[$env:LOG_LEVEL] } else { 1 }
+  if ($rank -lt $threshold) { return }
+
+  if ($env:LOG_FORMAT -eq 'text') {
+    $prefix = if ($env:TRACE_ID) { "[$($env:TRACE_ID)] " } else { '' }
+    Write-Host "$prefix[$Level] ${Step}: $Message"
+    return
+  }
+  $entry = [ordered]@{
+    ts       = (Get-Date).ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ')
+    instance = $script:InstanceId
+    level    = $Level
+    step     = $Step
+    msg      = $Message
+  }
+  if ($env:TRACE_ID) { $entry.trace = $env:TRACE_ID }
+  Write-Host ($entry | ConvertTo-Json -Compress)
+}
+
+# Leader: assign a task to a worker
+# bash: post_assignment
+function Send-Assignment([int]$TargetId, [string]$TaskId, [string]$TaskDescription, [string]$Agent = '') {
+  $payload = [ordered]@{ version = 1; task_id = $TaskId; instance_id = $TargetId; description = $TaskDescription }
+  if ($Agent) { $payload.agent = $Agent }
+  Send-Block 'TASK_ASSIGNMENT' $TargetId $payload
+}
+
+# Report the outcome of a task (completed, failed, partial) and optionally
+# the provider cost in USD
+# bash: post_result
+function Send-Result([string]$TaskId, [string]$Outcome, [string]$Summary, [string]$Branch = '', [double]$CostUsd = 0) {
+  $payload = [ordered]@{ version = 1; task_id = $TaskId; instance_id = $script:InstanceId; outcome = $Outcome; summary = $Summary }
+  if ($Branch) { $payload.branch = $Branch }
+  if ($CostUsd -gt 0) { $payload.cost_usd = $CostUsd }
+  Send-Block 'TASK_RESULT' $script:InstanceId $payload
+}
+
+# Report a task result following the versioned result contract (schema 1).
+# Report holds branch, pull_requests, summary, tests, coverage, notes and follow_ups.
+# bash: post_result_report
+function Send-ResultReport([string]$TaskId, [string]$Outcome, [hashtable]$Report = @{}) {
+  $payload = [ordered]@{ version = 1; schema = 1; task_id = $TaskId; instance_id = $script:InstanceId; outcome = $Outcome }
+  foreach ($key in $Report.Keys) { $payload[$key] = $Report[$key] }
+  Send-Block 'TASK_RESULT' $script:InstanceId $payload
+}
+
+# Report a task stopped by a provider limit (max_turns or max_tokens)
+# bash: post_limit_reached
+function Send-LimitReached([string]$TaskId, [string]$Limit, [string]$Summary, [string]$Branch = '') {
+  $payload = [ordered]@{ version = 1; task_id = $TaskId; instance_id = $script:InstanceId; outcome = 'limit_reached'; limit = $Limit; summary = $Summary }
+  if ($Branch) { $payload.branch = $Branch }
+  Send-Block 'TASK_RESULT' $script:InstanceId $payload
+}
+
+# Return provider CLI arguments for the configured limits
+# bash: provider_limit_args
+function Get-ProviderLimitArgs {
+  if ($script:MaxTurns) { return @('--max-turns', $script:MaxTurns) }
+  return @()
+}
+
+# Leader: put a question to a vote
+# bash: post_decision
+function Send-Decision([string]$DecisionId, [string]$Question, [int]$Quorum, [string[]]$Options) {
+  $payload = [ordered]@{ version = 1; decision_id = $DecisionId; instance_id = $script:InstanceId; question = $Question; options = $Options; quorum = $Quorum }
+  Send-Block 'DECISION' $script:InstanceId $payload
+}
+
+# Vote on a decision with one of its options
+# bash: post_vote
+function Send-Vote([string]$DecisionId, [string]$Option) {
+  $payload = [ordered]@{ version = 1; decision_id = $DecisionId; instance_id = $script:InstanceId; option = $Option }
+  Send-Block 'DECISION_VOTE' $script:InstanceId $payload
+}
+
+# Return the payloads of a block kind for an instance, oldest first; blocks
+# that steer the run are only read from trusted authors
+# bash: get_blocks
+function Get-Blocks([string]$Kind, [int]$TargetId) {
+  $pattern = "(?s)<!-- ${Kind}:START:${TargetId} -->\s*``````(?:json)?\s*\n(.*?)\n``````"
+  $steering = $Kind -in @('TASK_ASSIGNMENT', 'TASK_REPLAN', 'RUN_CONTROL')
+  foreach ($body in Get-Comments -Steering:$steering) {
+    foreach ($match in [regex]::Matches($body, $pattern)) {
+      $match.Groups[1].Value | ConvertFrom-Json
+    }
+  }
+}
+
+# Worker: get the latest task assignment for this instance ($null if none)
+# bash: get_assignment
+function Get-Assignment {
+  Get-Blocks 'TASK_ASSIGNMENT' $script:InstanceId | Select-Object -Last 1
+}
+
+# Worker: $true if a task has since been assigned to another instance
+# (e.g. by status --rebalance), so this instance should stop working on it
+# bash: task_reassigned
+function Test-TaskReassigned([string]$TaskId) {
+  $pattern = "(?s)<!-- TASK_ASSIGNMENT:START:\d+ -->\s*``````(?:json)?\s*\n(.*?)\n``````"
+  $owner = $null
+  foreach ($body in Get-Comments -Steering) {
+    foreach ($match in [regex]::Matches($body, $pattern)) {
+      $assignment = $match.Groups[1].Value | ConvertFrom-Json
+      if ($assignment.task_id -eq $TaskId) { $owner = $assignment.instance_id }
+    }
+  }
+  return $null -ne $owner -and $owner -ne $script:InstanceId
+}
+
+# Worker: $true if 'autonomous-dev replan' dropped a task from the plan, so
+# this instance should stop working on it and take its next assignment
+# bash: task_cancelled
+function Test-TaskCancelled([string]$TaskId) {
+  foreach ($replan in Get-Blocks 'TASK_REPLAN' 0) {
+    if ($replan.cancelled -contains $TaskId) { return $true }
+  }
+  return $false
+}
+
+# Return the task as last updated by 'autonomous-dev replan' ($null if never)
+# bash: replanned_task
+function Get-ReplannedTask {
+  $replan = Get-Blocks 'TASK_REPLAN' 0 | Select-Object -Last 1
+  if ($replan) { return $replan.task }
+  return $null
+}
+
+# Return this instance's branch name (branches.prefix + instance-N)
+# bash: instance_branch
+function Get-InstanceBranch {
+  return "$($script:BranchPrefix)instance-$($script:InstanceId)"
+}
+
+# Return the latest run control action posted by the CLI (pause, resume or $null)
+# bash: run_control
+function Get-RunControl {
+  $control = Get-Blocks 'RUN_CONTROL' 0 | Select-Object -Last 1
+  if ($control) { return $control.action }
+  return $null
+}
+
+# $true if the run is paused (autonomous-dev pause)
+# bash: run_paused
+function Test-RunPaused {
+  return (Get-RunControl) -eq 'pause'
+}
+
+# Commit and push work in progress to this instance's branch
+# bash: checkpoint_work
+function Save-Checkpoint {
+  $branch = Get-InstanceBranch
+  git add -A
+  git -c user.name=autonomous-dev -c user.email=autonomous-dev@users.noreply.github.com commit --quiet -m "Checkpoint instance $($script:InstanceId) before pause"
+  git push --quiet --force origin "HEAD:refs/heads/$branch"
+}
+
+# Continue from the checkpoint of a paused run, if this run resumes one
+# bash: restore_checkpoint
+function Restore-Checkpoint {
+  if ((Get-RunControl) -ne 'resume') { return }
+  $branch = Get-InstanceBranch
+  git fetch --quiet origin "refs/heads/$branch"
+  if ($LASTEXITCODE -eq 0) {
+    git checkout --quiet -B $branch FETCH_HEAD
+    Write-Output "♻️  Restored checkpoint from $branch"
+  }
+}
+
+# Call between units of work: if the run is paused, checkpoint and idle until
+# it is resumed. Idle instances report a heartbeat every few polls.
+# bash: wait_while_paused
+function Wait-WhilePaused {
+  if (-not (Test-RunPaused)) { return }
+
+  Write-Output '⏸  Run paused, checkpointing...'
+  Save-Checkpoint
+  Send-Status 'paused' 'pause' 'Paused: waiting for resume' 0
+
+  $interval = if ($env:PAUSE_POLL_SECONDS) { [int]$env:PAUSE_POLL_SECONDS } else { 60 }
+  $polls = 0
+  while (Test-RunPaused) {
+    Start-Sleep -Seconds $interval
+    $polls++
+    if ($polls % 5 -eq 0) { Send-Status 'paused' 'pause' 'Paused: waiting for resume' 0 }
+  }
+  Write-Output '▶️  Run resumed'
+}
+
+# Return the hidden marker that links an artifact (pull request or issue) to
+# this run; kind is instance-pr, child-issue, ci-failure or revert
+# bash: link_marker
+function Get-LinkMarker([string]$Kind) {
+  $fields = "kind=$Kind"
+  if ($script:CoordinationMode -eq 'dispatch') {
+    $fields += " run=$($script:RunId)"
+  } else {
+    $fields += " issue=$($script:IssueNumber)"
+  }
+  $fields += " instance=$($script:InstanceId)"
+  if ($env:TRACE_ID) { $fields += " trace=$($env:TRACE_ID)" }
+  return "<!-- autonomous-dev:link $fields -->"
+}
+
+# Return the back-reference appended to bodies of artifacts created for this run
+# bash: link_breadcrumb
+function Get-LinkBreadcrumb([string]$Kind) {
+  $ref = if ($script:CoordinationMode -eq 'dispatch') { "Part of run $($script:RunId)" } else { "Part of #$($script:IssueNumber)" }
+  return "$ref`n$(Get-LinkMarker $Kind)"
+}
+
+# Open a pull request for a pushed branch, linked back to the run.
+# Untrusted runs push to the fork and open a fork PR that requires maintainer
+# review; Set-ForkPullRequestLabel labels it from a job that runs no fork code.
+# With PR_DRAFT=true the PR is a draft until 'autonomous-dev prs ready'; only
+# ready PRs get auto-merge with AUTO_MERGE=true.
+# bash: open_pull_request
+function New-PullRequest([string]$Branch, [string]$Title, [string]$Body) {
+  $Body = "$Body`n`n$(Get-LinkBreadcrumb 'instance-pr')"
+  $draft = if ($script:PrDraft) { @('--draft') } else { @() }
+  if ($env:UNTRUSTED -eq 'true') {
+    $forkOwner = ($env:FORK_REPO -split '/')[0]
+    gh pr create --repo $env:UPSTREAM_REPO --head "${forkOwner}:$Branch" --title $Title --body $Body @draft
+    return
+  }
+
+  gh pr create --head $Branch --title $Title --body $Body @draft
+  if ($script:AutoMerge -and -not $script:PrDraft) {
+    gh pr merge $Branch --auto --squash
+  }
+}
+
+# Label the pull request this instance opened from the fork in an untrusted
+# run for maintainer review (review label, default needs-maintainer-review)
+# bash: label_fork_pull_request
+function Set-ForkPullRequestLabel {
+  $forkOwner = ($env:FORK_REPO -split '/')[0]
+  $label = if ($env:REVIEW_LABEL) { $env:REVIEW_LABEL } else { 'needs-maintainer-review' }
+  $prs = gh pr list --repo $env:GITHUB_REPOSITORY --head (Get-InstanceBranch) --state open --json number,headRepositoryOwner | ConvertFrom-Json
+  foreach ($pr in @($prs | Where-Object { $_.headRepositoryOwner.login -eq $forkOwner })) {
+    gh pr edit $pr.number --repo $env:GITHUB_REPOSITORY --add-label $label
+  }
+}
+
+# Token patterns Invoke-SecretAudit looks for when gitleaks is not installed
+$script:SecretPatterns = 'gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}|sk-ant-[A-Za-z0-9_-]{20,}|AKIA[0-9A-Z]{16}|xox[abprs]-[A-Za-z0-9-]{10,}|-----BEGIN [A-Z ]*PRIVATE KEY-----'
+
+# Gather what Invoke-SecretAudit checks into Dir, which holds the instance log
+# as Dir/log: the changes since a base commit (Dir/changes), the names of the
+# changed files (Dir/files) and the values of local .env files found in
+# either (Dir/env-findings). Those values are redacted here, so they never
+# leave the runner; the audit itself runs in a job without the workspace.
+# bash: collect_audit_inputs
+function Save-AuditInputs([string]$Dir, [string]$Base = 'HEAD') {
+  $logPath = Join-Path $Dir 'log'
+  if (-not (Test-Path $logPath)) { New-Item -ItemType File -Path $logPath | Out-Null }
+  $log = Get-Content $logPath -Raw
+  if ($null -eq $log) { $log = '' }
+  $untracked = @(git ls-files --others --exclude-standard)
+  $changes = ((@(git diff $Base 2>$null) + @($untracked | ForEach-Object { Get-Content $_ -Raw -ErrorAction SilentlyContinue })) -join "`n")
+  $texts = @{ log = $log; changes = $changes }
+  Set-Content (Join-Path $Dir 'files') (@(git diff --name-only $Base 2>$null) + $untracked | Sort-Object -Unique)
+
+  # Neither may the values of local .env files appear anywhere
+  $examples = '.env.example', '.env.sample', '.env.template'
+  $findings = @()
+  $envFiles = Get-ChildItem -Path . -Filter '.env*' -File -Recurse -Depth 2 -Force |
+    Where-Object { $examples -notcontains $_.Name -and $_.FullName -notmatch '[\\/]\.git[\\/]' }
+  foreach ($file in $envFiles) {
+    foreach ($line in (Get-Content $file.FullName)) {
+      if ($line -notmatch '^(?:export )?([A-Za-z_][A-Za-z0-9_]*)=(.*)$') { continue }
+      $key = $Matches[1]
+      $value = $Matches[2].Trim('"').Trim("'")
+      if ($value.Length -lt 8) { continue }
+      foreach ($where in 'log', 'changes') {
+        if ($texts[$where].Contains($value)) {
+          $findings += "env-value:$key`t$where"
+          $texts[$where] = $texts[$where].Replace($value, '[REDACTED]')
+        }
+      }
+    }
+  }
+  Set-Content (Join-Path $Dir 'env-findings') $findings
+  Set-Content $logPath $texts.log -NoNewline
+  Set-Content (Join-Path $Dir 'changes') $texts.changes -NoNewline
+}
+
+# Audit the instance output gathered by Save-AuditInputs in Dir for leaked
+# secrets: token patterns found by gitleaks (built-in patterns without it),
+# .env files among the changes and the .env values found while gathering.
+# Returns one "<rule> in <where>" line per finding; secrets are replaced with
+# [REDACTED] in Dir/log so it can be archived. The output comes from the
+# instance and is not trusted.
+# bash: audit_secrets
+function Invoke-SecretAudit([string]$Dir) {
+  $work = Join-Path ([IO.Path]::GetTempPath()) ([Guid]::NewGuid())
+  $scan = Join-Path $work 'scan'
+  New-Item -ItemType Directory -Path $scan | Out-Null
+  $read = {
+    param($name)
+    $path = Join-Path $Dir $name
+    $content = if (Test-Path $path) { Get-Content $path -Raw }
+    if ($null -eq $content) { '' } else { $content }
+  }
+  $log = & $read 'log'
+  $texts = @{ log = $log; changes = (& $read 'changes') }
+  Set-Content (Join-Path $scan 'log') $texts.log -NoNewline
+  Set-Content (Join-Path $scan 'changes') $texts.changes -NoNewline
+
+  $found = [System.Collections.Generic.List[object]]::new()
+  if (Get-Command gitleaks -ErrorAction SilentlyContinue) {
+    $report = Join-Path $work 'report.json'
+    gitleaks detect --no-git --no-banner --exit-code 0 --source $scan --report-format json --report-path $report *> $null
+    foreach ($f in (Get-Content $report -Raw | ConvertFrom-Json)) {
+      $found.Add(@{ Rule = $f.RuleID; Where = (Split-Path $f.File -Leaf); Secret = $f.Secret })
+    }
+  } else {
+    foreach ($where in 'log', 'changes') {
+      foreach ($m in ([regex]::Matches($texts[$where], $script:SecretPatterns) | Select-Object -ExpandProperty Value -Unique)) {
+        $found.Add(@{ Rule = 'token-pattern'; Where = $where; Secret = $m })
+      }
+    }
+  }
+
+  # .env files must not be committed; their examples may. Names are
+  # reported, so only plain path characters are kept.
+  $examples = '.env.example', '.env.sample', '.env.template'
+  foreach ($path in ((& $read 'files') -split "`r?`n" | Where-Object { $_ })) {
+    $name = Split-Path $path -Leaf
+    if (($name -eq '.env' -or $name -like '.env.*') -and $examples -notcontains $name) {
+      $found.Add(@{ Rule = 'env-file'; Where = ($path -replace '[^A-Za-z0-9 ._/@+-]', ''); Secret = '' })
+    }
+  }
+
+  # The values themselves were redacted by the instance; lines not in its
+  # format are dropped
+  foreach ($line in ((& $read 'env-findings') -split "`r?`n")) {
+    if ($line -match '^(env-value:[A-Za-z_][A-Za-z0-9_]*)\t(log|changes)$') {
+      $found.Add(@{ Rule = $Matches[1]; Where = $Matches[2]; Secret = '' })
+    }
+  }
+  Remove-Item $work -Recurse -Force
+
+  $lines = foreach ($f in $found) {
+    $where = switch ($f.Where) { 'log' { 'instance log' } default { $f.Where } }
+    if ($f.Secret) { $log = $log.Replace($f.Secret, '[REDACTED]') }
+    "$($f.Rule) in $where"
+  }
+  if ($found.Count -gt 0) { Set-Content (Join-Path $Dir 'log') $log -NoNewline }
+  return @($lines | Sort-Object -Unique)
+}
+
+# Check if an instance is healthy: healthy, stale (no heartbeat for 5 minutes) or unknown
+# bash: check_instance_health
+function Get-InstanceHealth([int]$Id) {
+  $status = Get-Blocks 'INSTANCE_STATUS' $Id | Select-Object -Last 1
+  if (-not $status) { return 'unknown' }
+
+  $beat = [DateTime]::Parse($status.health.last_heartbeat).ToUniversalTime()
+  if (((Get-Date).ToUniversalTime() - $beat).TotalSeconds -gt 300) { return 'stale' }
+  return 'healthy'
+}
+
+# Leader: check all workers
+# bash: check_workers
+function Test-Workers {
+  $total = if ($env:TOTAL_INSTANCES) { [int]$env:TOTAL_INSTANCES } else { 5 }
+
+  Write-Output '🔍 Checking worker instances...'
+  foreach ($i in 2..$total) {
+    $health = Get-InstanceHealth $i
+    Write-Output "  Instance ${i}: $health"
+    if ($health -eq 'stale') {
+      Write-Output "  ⚠️  Instance $i is not responding, may need to reassign tasks"
+    }
+  }
+}
@@ -1,6 +1,8 @@
 #!/bin/bash
 # Instance Status Reporter
-# Posts structured status updates to GitHub Issue for P2P coordination
+# Posts structured status updates to GitHub Issue for P2P coordination.
+# With COORDINATION_MODE=dispatch, messages are committed to the state branch
+# under runs/$RUN_ID/messages/ instead.
 
 set -e
 
@@ -8,12 +10,139 @@ INSTANCE_ID="${INSTANCE_ID:-1}"
 ISSUE_NUMBER="${ISSUE_NUMBER:-}"
 GITHUB_TOKEN="${GITHUB_TOKEN:-}"
 ROLE="${ROLE:-worker}"  # leader or worker
+COORDINATION_MODE="${COORDINATION_MODE:-issues}"  # issues or dispatch
+RUN_ID="${RUN_ID:-}"
+STATE_BRANCH="${STATE_BRANCH:-autonomous-dev-state}"
+BRANCH_PREFIX="${BRANCH_PREFIX:-autonomous/}"  # branches.prefix
+PR_DRAFT="${PR_DRAFT:-false}"  # prs.draft
+AUTO_MERGE="${AUTO_MERGE:-false}"  # branches.auto_merge
+MAX_TURNS="${MAX_TURNS:-}"  # provider.max_turns (empty: provider default)
+MAX_TOKENS_PER_INSTANCE="${MAX_TOKENS_PER_INSTANCE:-}"  # provider.max_tokens_per_instance
+PROVIDER_URL="${PROVIDER_URL:-https://api.anthropic.com}"  # probed for network egress
+STATUS_PREVIEW_LINES="${STATUS_PREVIEW_LINES:-3}"  # logs.status.lines
+STATUS_PREVIEW_MAX_BYTES="${STATUS_PREVIEW_MAX_BYTES:-2048}"  # logs.status.max_bytes
+STATUS_PREVIEW_INCLUDE="${STATUS_PREVIEW_INCLUDE:-always}"  # logs.status.include: always, failures or never
+STATUS_PREVIEW_TRUNCATE="${STATUS_PREVIEW_TRUNCATE:-tail}"  # logs.status.truncate: tail, head_tail or errors
+TRUSTED_AUTHORS="${TRUSTED_AUTHORS:-}"  # logins besides github-actions[bot] that may steer the run
 
-if [ -z "$ISSUE_NUMBER" ] || [ -z "$GITHUB_TOKEN" ]; then
+if [ "$COORDINATION_MODE" = "dispatch" ]; then
+  if [ -z "$RUN_ID" ] || [ -z "$GITHUB_TOKEN" ]; then
+    echo "Error: RUN_ID and GITHUB_TOKEN required in dispatch mode"
+    exit 1
+  fi
+elif [ -z "$ISSUE_NUMBER" ] || [ -z "$GITHUB_TOKEN" ]; then
   echo "Error: ISSUE_NUMBER and GITHUB_TOKEN required"
   exit 1
 fi
 
+# Post a coordination message (issue comment, or a file on the state branch)
+publish_comment() {
+  local body="$1"
+
+  if [ "$COORDINATION_MODE" != "dispatch" ]; then
+    gh issue comment "$ISSUE_NUMBER" --body "$body"
+    return
+  fi
+
+  # Timestamped names keep messages in posting order
+  local path
+  path="runs/${RUN_ID}/messages/$(date -u +%Y%m%dT%H%M%S.%NZ)-instance-${INSTANCE_ID}.md"
+
+  # Concurrent commits to the branch can conflict; retry with backoff
+  local attempt
+  for attempt in 1 2 3; do
+    if printf '%s' "$body" | base64 -w0 \
+      | jq -R --arg branch "$STATE_BRANCH" --arg message "Instance $INSTANCE_ID message" \
+          '{message: $message, branch: $branch, content: .}' \
+      | gh api --method PUT "/repos/${GITHUB_REPOSITORY}/contents/${path}" --input - > /dev/null; then
+      return 0
+    fi
+    sleep $((attempt * 2))
+  done
+
+  echo "Error: failed to publish message to ${STATE_BRANCH}" >&2
+  return 1
+}
+
+# Reassemble the messages of a JSON array of comments that were split over
+# several comments (see internal/chunk): a split message takes the place of
+# its last piece. Only pieces of the same author are joined.
+join_chunks() {
+  jq '
+    [to_entries[] | .key as $pos | .value as $item
+      | ([$item.body // "" | capture("^<!-- CHUNK:(?<id>[0-9a-f]+):(?<i>[0-9]+)/(?<n>[0-9]+) -->\n(?<piece>[\\s\\S]*)$")] | .[0]) as $m
+      | {$pos, $item, m: (if $m and ($m.i | tonumber) >= 1 and ($m.i | tonumber) <= ($m.n | tonumber)
+          then $m + {key: (($item.user.login // "") + ":" + $m.id)} else null end)}]
+    | (map(select(.m)) | group_by(.m.key) | map({key: .[0].m.key, value: {
+        pos: (map(.pos) | max),
+        item: .[0].item,
+        body: (group_by(.m.i | tonumber) | map(last.m | if (.i | tonumber) < (.n | tonumber)
+          then ([.piece | capture("^(?<p>[\\s\\S]*)\n<!-- CHUNK:END -->")] | .[0].p) // .piece
+          else .piece end) | join(""))}}) | from_entries) as $groups
+    | map(if .m == null then .item
+        elif $groups[.m.key].pos == .pos then $groups[.m.key].item + {body: $groups[.m.key].body}
+        else empty end)'
+}
+
+# Keep the comments of the instances (github-actions[bot]) and of
+# TRUSTED_AUTHORS in a JSON array of comments
+trusted_comments() {
+  jq --arg trusted "github-actions[bot] $TRUSTED_AUTHORS" '
+    ($trusted | ascii_downcase | split(" ") | map(select(. != ""))) as $logins
+    | map(select((.user.login // "" | ascii_downcase) as $login | $logins | index($login)))'
+}
+
+# Print all coordination messages as a JSON array of {body: ...} objects,
+# split messages reassembled
+list_comments() {
+  fetch_comments | join_chunks
+}
+
+# Print the comments of the coordination issue, or the messages of the state
+# branch run, as posted
+fetch_comments() {
+  if [ "$COORDINATION_MODE" != "dispatch" ]; then
+    gh api --paginate "/repos/${GITHUB_REPOSITORY}/issues/${ISSUE_NUMBER}/comments" | jq -s 'add // []'
+    return
+  fi
+
+  gh api "/repos/${GITHUB_REPOSITORY}/contents/runs/${RUN_ID}/messages?ref=${STATE_BRANCH}" --jq '.[].path' 2> /dev/null \
+    | sort \
+    | while read -r file; do
+        gh api -H "Accept: application/vnd.github.raw" "/repos/${GITHUB_REPOSITORY}/contents/${file}?ref=${STATE_BRANCH}" \
+          | jq -Rs '{body: .}'
+      done \
+    | jq -s '.'
+}
+
+# Print the coordination messages that may steer the run (assignments,
+# replans, pause and resume): comments of the instances (github-actions[bot])
+# and of TRUSTED_AUTHORS. State branch messages need push access to post.
+list_steering_comments() {
+  if [ "$COORDINATION_MODE" = "dispatch" ]; then
+    list_comments
+    return
+  fi
+
+  fetch_comments | trusted_comments | join_chunks
+}
+
+# Print the task description of this run. An oversized issue body continues
+# in comments of the CLI's account (one of TRUSTED_AUTHORS).
+get_task() {
+  if [ "$COORDINATION_MODE" = "dispatch" ]; then
+    gh api -H "Accept: application/vnd.github.raw" "/repos/${GITHUB_REPOSITORY}/contents/runs/${RUN_ID}/task.md?ref=${STATE_BRANCH}"
+    return
+  fi
+
+  # The issue may have been opened by someone else and adopted by the CLI,
+  # so its pieces are joined regardless of author
+  {
+    gh api "/repos/${GITHUB_REPOSITORY}/issues/${ISSUE_NUMBER}" | jq '[{body: (.body // ""), task: true}]'
+    fetch_comments | trusted_comments | jq 'map({body})'
+  } | jq -s 'add' | join_chunks | jq -r '.[] | select(.task) | .body'
+}
+
 # Get current metrics
 get_cpu_usage() {
   top -bn1 | grep "Cpu(s)" | sed "s/.*, *\([0-9.]*\)%* id.*/\1/" | awk '{print 100 - $1}'
@@ -23,6 +152,70 @@ get_memory_mb() {
   free -m | awk 'NR==2{printf "%.0f", $3}'
 }
 
+# Cut console output down to the preview of a status report. Omitted lines
+# are replaced by one "[... N lines omitted ...]" line; with errors
+# truncation kept lines are numbered "L<n>: ".
+status_preview() {
+  local status="$1"
+  local output="$2"
+  local lines="$STATUS_PREVIEW_LINES"
+
+  case "$STATUS_PREVIEW_INCLUDE" in
+    never) return 0 ;;
+    failures) [ "$status" = "failed" ] || return 0 ;;
+  esac
+  if [ -z "$output" ]; then
+    return 0
+  fi
+
+  local total
+  total=$(printf '%s\n' "$output" | wc -l)
+  case "$STATUS_PREVIEW_TRUNCATE" in
+    head_tail)
+      if [ "$total" -le "$lines" ]; then
+        printf '%s\n' "$output"
+      else
+        local head=$(((lines + 1) / 2))
+        local tail=$((lines / 2))
+        printf '%s\n' "$output" | head -n "$head"
+        echo "[... $((total - head - tail)) lines omitted ...]"
+        if [ "$tail" -gt 0 ]; then
+          printf '%s\n' "$output" | tail -n "$tail"
+        fi
+      fi
+      ;;
+    errors)
+      # Lines matching an error with one line of context, the last ones first
+      local errors
+      errors=$(printf '%s\n' "$output" \
+        | grep -n -i -E -C1 'error|fail|fatal|panic|exception' \
+        | grep -v '^--$' \
+        | tail -n "$lines" \
+        | sed -E 's/^([0-9]+)[:-]/L\1: /' || true)
+      if [ -n "$errors" ]; then
+        printf '%s\n' "$errors"
+      else
+        printf '%s\n' "$output" | tail -n "$lines"
+      fi
+      ;;
+    *)
+      printf '%s\n' "$output" | tail -n "$lines"
+      ;;
+  esac | awk -v max="$STATUS_PREVIEW_MAX_BYTES" '
+    { line[NR] = substr($0, 1, max) }
+    END {
+      # Keep the last lines that fit in max bytes
+      size = 0; first = NR + 1
+      for (i = NR; i >= 1; i--) {
+        size += length(line[i]) + 1
+        if (size > max) break
+        first = i
+      }
+      if (first > 1) printf "[... %d lines omitted ...]\n", first - 1
+      for (i = first; i <= NR; i++) print line[i]
+    }'
+}
+
 # Report status to issue
 report_status() {
   local status="$1"
@@ -34,9 +227,9 @@ report_status() {
   # Get job URL
   local job_url="https://github.com/${GITHUB_REPOSITORY}/actions/runs/${GITHUB_RUN_ID}/job/${GITHUB_JOB}"
 
-  # Extract last 3 lines of console output
+  # Preview of the console output per logs.status
   local console_preview
-  console_preview=$(echo "$console_output" | tail -3 | jq -R . | jq -s .)
+  console_preview=$(status_preview "$status" "$console_output" | jq -R . | jq -s .)
 
   # Build JSON status
   local status_json
@@ -57,7 +250,8 @@ report_status() {
     "last_heartbeat": "$(date -u +%Y-%m-%dT%H:%M:%SZ)"
   },
   "logs_url": "$job_url",
-  "console_preview": $console_preview
+  "console_preview": $console_preview,
+  "preview_truncation": "$STATUS_PREVIEW_TRUNCATE"
 }
 EOF
 )
@@ -73,14 +267,591 @@ $status_json
 EOF
 )
 
-  gh issue comment "$ISSUE_NUMBER" --body "$comment_body"
+  publish_comment "$comment_body"
+}
+
+# Post a structured message block (kind, instance id, JSON payload)
+post_block() {
+  local kind="$1"
+  local target_id="$2"
+  local payload="$3"
+
+  local comment_body
+  comment_body=$(cat <<EOF
+<!-- ${kind}:START:${target_id} -->
+\`\`\`json
+$payload
+\`\`\`
+<!-- ${kind}:END:${target_id} -->
+EOF
+)
+
+  publish_comment "$comment_body"
+}
+
+# Print the version of a toolchain command
+tool_version() {
+  case "$1" in
+    go) go version | awk '{print $3}' | sed 's/^go//' ;;
+    *) "$1" --version 2>&1 | head -1 | grep -oE '[0-9]+(\.[0-9]+)+' | head -1 ;;
+  esac
+}
+
+# Report this runner's capabilities before doing work: toolchain versions,
+# CPUs, memory, free disk and whether the provider API is reachable
+report_capabilities() {
+  local toolchains="{}"
+  local tool
+  for tool in go node python3 java rustc docker; do
+    if command -v "$tool" >/dev/null 2>&1; then
+      toolchains=$(echo "$toolchains" | jq -c --arg tool "$tool" --arg version "$(tool_version "$tool")" '. + {($tool): $version}')
+    fi
+  done
+
+  local cpus memory_mb disk_mb
+  cpus=$(getconf _NPROCESSORS_ONLN 2>/dev/null || echo 0)
+  if command -v free >/dev/null 2>&1; then
+    memory_mb=$(free -m | awk 'NR==2{print $2}')
+  else
+    memory_mb=$(( $(sysctl -n hw.memsize 2>/dev/null || echo 0) / 1048576 ))
+  fi
+  disk_mb=$(df -Pm . | awk 'NR==2{print $4}')
+
+  # Any HTTP response proves egress; only connection failures count
+  local reachable=false latency_ms=0 seconds
+  if seconds=$(curl -s -o /dev/null -m 10 -w '%{time_total}' "$PROVIDER_URL"); then
+    reachable=true
+    latency_ms=$(awk -v t="$seconds" 'BEGIN{printf "%.0f", t * 1000}')
+  fi
+
+  # Hosted runners name their image in ImageOS/ImageVersion
+  local image=""
+  if [ -n "${ImageOS:-}" ]; then
+    image="$ImageOS/${ImageVersion:-}"
+  fi
+
+  local payload
+  payload=$(jq -n \
+    --argjson instance_id "$INSTANCE_ID" \
+    --arg runner "${RUNNER_NAME:-}" \
+    --arg os "${RUNNER_OS:-$(uname -s)}" \
+    --arg arch "${RUNNER_ARCH:-$(uname -m)}" \
+    --arg image "$image" \
+    --arg model "${ANTHROPIC_MODEL:-}" \
+    --arg provider_cli "$(claude --version 2>/dev/null | head -1)" \
+    --argjson cpus "${cpus:-0}" \
+    --argjson memory_mb "${memory_mb:-0}" \
+    --argjson disk_free_mb "${disk_mb:-0}" \
+    --argjson toolchains "$toolchains" \
+    --argjson reachable "$reachable" \
+    --argjson latency_ms "$latency_ms" \
+    --arg probed_at "$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+    '{version: 1, instance_id: $instance_id, runner: $runner, os: $os, cpus: $cpus,
+      memory_mb: $memory_mb, disk_free_mb: $disk_free_mb, toolchains: $toolchains,
+      provider_reachable: $reachable, provider_latency_ms: $latency_ms, probed_at: $probed_at,
+      arch: $arch, image: $image, model: $model, provider_cli: $provider_cli}
+     | with_entries(select(.value != ""))')
+
+  post_block "INSTANCE_CAPABILITIES" "$INSTANCE_ID" "$payload"
+}
+
+# Apply the environment lock of a reproduced run (start --env-lock): use its
+# model and warn about what the workflow could not pin
+apply_env_lock() {
+  [ -n "${ENV_LOCK:-}" ] || return 0
+
+  local model
+  model=$(echo "$ENV_LOCK" | jq -r '.model // empty')
+  if [ -n "$model" ]; then
+    export ANTHROPIC_MODEL="$model"
+    echo "🔒 Using locked model $model"
+  fi
+
+  # Hosted runners only select an image family, not a version
+  local image
+  image=$(echo "$ENV_LOCK" | jq -r '.image // empty')
+  if [ -n "$image" ] && [ "$image" != "${ImageOS:-}/${ImageVersion:-}" ]; then
+    echo "::warning::Runner image ${ImageOS:-unknown}/${ImageVersion:-unknown} differs from locked $image"
+  fi
+
+  local tool locked current
+  for tool in $(echo "$ENV_LOCK" | jq -r '.toolchains // {} | keys[]'); do
+    locked=$(echo "$ENV_LOCK" | jq -r --arg tool "$tool" '.toolchains[$tool]')
+    current=""
+    if command -v "$tool" >/dev/null 2>&1; then
+      current=$(tool_version "$tool")
+    fi
+    if [ "$current" != "$locked" ]; then
+      echo "::warning::$tool ${current:-missing} differs from locked $locked"
+    fi
+  done
+
+  local cli
+  cli=$(echo "$ENV_LOCK" | jq -r '.provider_cli // empty')
+  if [ -n "$cli" ] && [ "$(claude --version 2>/dev/null | head -1)" != "$cli" ]; then
+    echo "::warning::Agent CLI differs from locked $cli"
+  fi
+}
+
+# Rank of a log level; unknown levels rank as info
+log_level_rank() {
+  case "$1" in
+    debug) echo 0 ;;
+    warn) echo 2 ;;
+    error) echo 3 ;;
+    *) echo 1 ;;
+  esac
+}
+
+# Log a line at a level (debug, info, warn or error) for a step. Lines
+# below LOG_LEVEL (default info) are dropped. With LOG_FORMAT=json (default)
+# each line is a JSON object that 'autonomous-dev logs --level' filters and
+# health scoring counts errors from.
+log_event() {
+  local level="$1"
+  local step="$2"
+  local message="$3"
+
+  if [ "$(log_level_rank "$level")" -lt "$(log_level_rank "${LOG_LEVEL:-info}")" ]; then
+    return 0
+  fi
+
+  if [ "${LOG_FORMAT:-json}" = "json" ]; then
+    jq -cn \
+      --arg ts "$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+      --argjson instance "${INSTANCE_ID:-0}" \
+      --arg level "$level" \
+      --arg step "$step" \
+      --arg msg "$message" \
+      --arg trace "${TRACE_ID:-}" \
+      '{ts: $ts, instance: $instance, level: $level, step: $step, msg: $msg} + (if $trace != "" then {trace: $trace} else {} end)'
+  elif [ -n "${TRACE_ID:-}" ]; then
+    echo "[$TRACE_ID] [$level] $step: $message"
+  else
+    echo "[$level] $step: $message"
+  fi
+}
+
+# Leader: assign a task to a worker
+post_assignment() {
+  local target_id="$1"
+  local task_id="$2"
+  local task_desc="$3"
+  local agent="${4:-}"
+
+  local payload
+  payload=$(jq -n \
+    --argjson instance_id "$target_id" \
+    --arg task_id "$task_id" \
+    --arg description "$task_desc" \
+    --arg agent "$agent" \
+    '{version: 1, task_id: $task_id, instance_id: $instance_id, description: $description}
+     + (if $agent != "" then {agent: $agent} else {} end)')
+
+  post_block "TASK_ASSIGNMENT" "$target_id" "$payload"
+}
+
+# Report the outcome of a task (completed, failed, partial) and optionally
+# the provider cost in USD
+post_result() {
+  local task_id="$1"
+  local outcome="$2"
+  local summary="$3"
+  local branch="${4:-}"
+  local cost_usd="${5:-}"
+
+  local payload
+  payload=$(jq -n \
+    --argjson instance_id "$INSTANCE_ID" \
+    --arg task_id "$task_id" \
+    --arg outcome "$outcome" \
+    --arg summary "$summary" \
+    --arg branch "$branch" \
+    --arg cost_usd "$cost_usd" \
+    '{version: 1, task_id: $task_id, instance_id: $instance_id, outcome: $outcome, summary: $summary}
+     + (if $branch != "" then {branch: $branch} else {} end)
+     + (if $cost_usd != "" then {cost_usd: ($cost_usd | tonumber)} else {} end)')
+
+  post_block "TASK_RESULT" "$INSTANCE_ID" "$payload"
+}
+
+# Report a task result following the versioned result contract (schema 1).
+# report is a JSON object with branch, pull_requests, summary, tests
+# ({command, passed, failed, skipped}), coverage, notes and follow_ups;
+# completed results need branch and passing tests, partial ones follow_ups.
+# Results that break the contract are rejected and the instance is failed.
+post_result_report() {
+  local task_id="$1"
+  local outcome="$2"
+  local report="${3:-{\}}"
+
+  local payload
+  payload=$(jq -n \
+    --argjson instance_id "$INSTANCE_ID" \
+    --arg task_id "$task_id" \
+    --arg outcome "$outcome" \
+    --argjson report "$report" \
+    '$report + {version: 1, schema: 1, task_id: $task_id, instance_id: $instance_id, outcome: $outcome}')
+
+  post_block "TASK_RESULT" "$INSTANCE_ID" "$payload"
+}
+
+# Report a task stopped by a provider limit (max_turns or max_tokens).
+# The coordinator treats it as partial work that needs a follow-up run.
+post_limit_reached() {
+  local task_id="$1"
+  local limit="$2"
+  local summary="$3"
+  local branch="${4:-}"
+
+  local payload
+  payload=$(jq -n \
+    --argjson instance_id "$INSTANCE_ID" \
+    --arg task_id "$task_id" \
+    --arg limit "$limit" \
+    --arg summary "$summary" \
+    --arg branch "$branch" \
+    '{version: 1, task_id: $task_id, instance_id: $instance_id, outcome: "limit_reached", limit: $limit, summary: $summary}
+     + (if $branch != "" then {branch: $branch} else {} end)')
+
+  post_block "TASK_RESULT" "$INSTANCE_ID" "$payload"
+}
+
+# Print provider CLI arguments for the configured limits
+provider_limit_args() {
+  if [ -n "$MAX_TURNS" ]; then
+    printf -- '--max-turns %s\n' "$MAX_TURNS"
+  fi
+}
+
+# Leader: put a question to a vote (options are voted by structured reply or
+# by reacting with +1, -1, heart, hooray, rocket, eyes, laugh, confused in order)
+post_decision() {
+  local decision_id="$1"
+  local question="$2"
+  local quorum="$3"
+  shift 3
+
+  local payload
+  payload=$(jq -n \
+    --argjson instance_id "$INSTANCE_ID" \
+    --arg decision_id "$decision_id" \
+    --arg question "$question" \
+    --argjson quorum "${quorum:-0}" \
+    '{version: 1, decision_id: $decision_id, instance_id: $instance_id, question: $question, options: $ARGS.positional, quorum: $quorum}' \
+    --args "$@")
+
+  post_block "DECISION" "$INSTANCE_ID" "$payload"
+}
+
+# Cast this instance's vote on a decision (a later vote replaces an earlier one)
+post_vote() {
+  local decision_id="$1"
+  local option="$2"
+
+  local payload
+  payload=$(jq -n \
+    --argjson instance_id "$INSTANCE_ID" \
+    --arg decision_id "$decision_id" \
+    --arg option "$option" \
+    '{version: 1, decision_id: $decision_id, instance_id: $instance_id, option: $option}')
+
+  post_block "DECISION_VOTE" "$INSTANCE_ID" "$payload"
+}
+
+# Extract JSON payloads of a given block kind for an instance; blocks that
+# steer the run are only read from trusted authors
+get_blocks() {
+  local kind="$1"
+  local target_id="$2"
+  local list=list_comments
+  case "$kind" in
+    TASK_ASSIGNMENT|TASK_REPLAN|RUN_CONTROL) list=list_steering_comments ;;
+  esac
+
+  "$list" \
+    | jq -r ".[] | select(.body | contains(\"${kind}:START:${target_id} \")) | .body" \
+    | sed -n '/```json/,/```/p' \
+    | grep -v '```'
+}
+
+# Worker: get the latest task assignment for this instance (empty if none)
+get_assignment() {
+  get_blocks "TASK_ASSIGNMENT" "$INSTANCE_ID" | jq -s -c 'last // empty'
+}
+
+# Worker: succeed if a task has since been assigned to another instance
+# (e.g. by status --rebalance), so this instance should stop working on it
+task_reassigned() {
+  local task_id="$1"
+
+  local owner
+  owner=$(list_steering_comments \
+    | jq -r '.[] | select(.body | contains("TASK_ASSIGNMENT:START:")) | .body' \
+    | sed -n '/```json/,/```/p' \
+    | grep -v '```' \
+    | jq -s -r --arg task_id "$task_id" 'map(select(.task_id == $task_id)) | last | .instance_id // empty')
+  [ -n "$owner" ] && [ "$owner" != "$INSTANCE_ID" ]
+}
+
+# Worker: succeed if 'autonomous-dev replan' dropped a task from the plan,
+# so this instance should stop working on it and take its next assignment
+task_cancelled() {
+  local task_id="$1"
+
+  get_blocks "TASK_REPLAN" 0 \
+    | jq -s -e --arg task_id "$task_id" 'map(.cancelled // []) | add // [] | index($task_id) != null' >/dev/null
+}
+
+# Print the task as last updated by 'autonomous-dev replan' (empty if never)
+replanned_task() {
+  get_blocks "TASK_REPLAN" 0 | jq -s -r 'last | .task // empty'
+}
+
+# Print this instance's branch name (branches.prefix + instance-N)
+instance_branch() {
+  echo "${BRANCH_PREFIX}instance-${INSTANCE_ID}"
+}
+
+# Print the latest run control action posted by the CLI (pause, resume or empty)
+run_control() {
+  get_blocks "RUN_CONTROL" 0 | jq -s -r 'last | .action // empty'
+}
+
+# Succeed if the run is paused (autonomous-dev pause)
+run_paused() {
+  [ "$(run_control)" = "pause" ]
+}
+
+# Commit and push work in progress to this instance's branch
+checkpoint_work() {
+  local branch
+  branch=$(instance_branch)
+
+  git add -A
+  git -c user.name="autonomous-dev" -c user.email="autonomous-dev@users.noreply.github.com" \
+    commit --quiet -m "Checkpoint instance ${INSTANCE_ID} before pause" || true
+  git push --quiet --force origin "HEAD:refs/heads/${branch}"
+}
+
+# Continue from the checkpoint of a paused run, if this run resumes one
+restore_checkpoint() {
+  local branch
+  branch=$(instance_branch)
+
+  [ "$(run_control)" = "resume" ] || return 0
+  if git fetch --quiet origin "refs/heads/${branch}"; then
+    git checkout --quiet -B "$branch" FETCH_HEAD
+    echo "♻️  Restored checkpoint from $branch"
+  fi
+}
+
+# Call between units of work: if the run is paused, checkpoint and idle until
+# it is resumed. Idle instances report a heartbeat every few polls.
+wait_while_paused() {
+  run_paused || return 0
+
+  echo "⏸  Run paused, checkpointing..."
+  checkpoint_work
+  report_status "paused" "pause" "Paused: waiting for resume" 0 ""
+
+  local polls=0
+  while run_paused; do
+    sleep "${PAUSE_POLL_SECONDS:-60}"
+    polls=$((polls + 1))
+    if [ $((polls % 5)) -eq 0 ]; then
+      report_status "paused" "pause" "Paused: waiting for resume" 0 ""
+    fi
+  done
+  echo "▶️  Run resumed"
+}
+
+# Print the hidden marker that links an artifact (pull request or issue) to
+# this run; kind is instance-pr, child-issue, ci-failure or revert
+link_marker() {
+  local kind="$1"
+  local fields="kind=$kind"
+
+  if [ "$COORDINATION_MODE" = "dispatch" ]; then
+    fields="$fields run=$RUN_ID"
+  else
+    fields="$fields issue=$ISSUE_NUMBER"
+  fi
+  fields="$fields instance=$INSTANCE_ID"
+  if [ -n "${TRACE_ID:-}" ]; then
+    fields="$fields trace=$TRACE_ID"
+  fi
+  echo "<!-- autonomous-dev:link $fields -->"
+}
+
+# Print the back-reference appended to bodies of artifacts created for this run
+link_breadcrumb() {
+  local kind="$1"
+
+  if [ "$COORDINATION_MODE" = "dispatch" ]; then
+    echo "Part of run $RUN_ID"
+  else
+    echo "Part of #$ISSUE_NUMBER"
+  fi
+  link_marker "$kind"
+}
+
+# Open a pull request for a pushed branch, linked back to the run.
+# Untrusted runs push to the fork and open a fork PR that requires maintainer
+# review; label_fork_pull_request labels it from a job that runs no fork code.
+# With PR_DRAFT=true the PR is a draft until 'autonomous-dev prs ready'; only
+# ready PRs get auto-merge with AUTO_MERGE=true.
+open_pull_request() {
+  local branch="$1"
+  local title="$2"
+  local body
+  body="$3
+
+$(link_breadcrumb instance-pr)"
+  local draft=()
+  if [ "$PR_DRAFT" = "true" ]; then
+    draft=(--draft)
+  fi
+
+  if [ "${UNTRUSTED:-false}" = "true" ]; then
+    gh pr create \
+      --repo "$UPSTREAM_REPO" \
+      --head "${FORK_REPO%%/*}:$branch" \
+      --title "$title" \
+      --body "$body" \
+      "${draft[@]}"
+    return
+  fi
+
+  gh pr create --head "$branch" --title "$title" --body "$body" "${draft[@]}"
+  if [ "$AUTO_MERGE" = "true" ] && [ "$PR_DRAFT" != "true" ]; then
+    gh pr merge "$branch" --auto --squash
+  fi
+}
+
+# Label the pull request this instance opened from the fork in an untrusted
+# run for maintainer review (review label, default needs-maintainer-review)
+label_fork_pull_request() {
+  local branch number
+  branch=$(instance_branch)
+
+  gh pr list --repo "$GITHUB_REPOSITORY" --head "$branch" --state open --json number,headRepositoryOwner \
+    | jq -r --arg owner "${FORK_REPO%%/*}" '.[] | select(.headRepositoryOwner.login == $owner) | .number' \
+    | while IFS= read -r number; do
+        gh pr edit "$number" --repo "$GITHUB_REPOSITORY" --add-label "${REVIEW_LABEL:-needs-maintainer-review}"
+      done
+}
+
+# Token patterns audit_secrets looks for when gitleaks is not installed
+SECRET_PATTERNS='gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}|sk-ant-[A-Za-z0-9_-]{20,}|AKIA[0-9A-Z]{16}|xox[abprs]-[A-Za-z0-9-]{10,}|-----BEGIN [A-Z ]*PRIVATE KEY-----'
+
+# Gather what audit_secrets checks into dir, which holds the instance log as
+# dir/log: the changes since a base commit (dir/changes), the names of the
+# changed files (dir/files) and the values of local .env files found in
+# either (dir/env-findings). Those values are redacted here, so they never
+# leave the runner; the audit itself runs in a job without the workspace.
+collect_audit_inputs() {
+  local dir="$1"
+  local base="${2:-HEAD}"
+  touch "$dir/log"
+  {
+    git diff "$base" 2>/dev/null || true
+    git ls-files --others --exclude-standard -z | xargs -0 -r cat 2>/dev/null || true
+  } > "$dir/changes"
+  { git diff --name-only "$base" 2>/dev/null; git ls-files --others --exclude-standard; } | sort -u > "$dir/files"
+
+  # Neither may the values of local .env files appear anywhere
+  : > "$dir/env-findings"
+  local env_file key value file content
+  while IFS= read -r env_file; do
+    while IFS='=' read -r key value; do
+      value="${value%\"}"; value="${value#\"}"
+      value="${value%\'}"; value="${value#\'}"
+      if [ ${#value} -lt 8 ]; then
+        continue
+      fi
+      for file in log changes; do
+        if grep -qF -- "$value" "$dir/$file"; then
+          printf 'env-value:%s\t%s\n' "${key#export }" "$file" >> "$dir/env-findings"
+          content=$(cat "$dir/$file")
+          printf '%s\n' "${content//"$value"/[REDACTED]}" > "$dir/$file"
+        fi
+      done
+    done < <(grep -E '^(export )?[A-Za-z_][A-Za-z0-9_]*=' "$env_file" || true)
+  done < <(find . -maxdepth 3 -type f -name '.env*' ! -name '.env.example' ! -name '.env.sample' ! -name '.env.template' -not -path './.git/*')
+}
+
+# Audit the instance output gathered by collect_audit_inputs in dir for
+# leaked secrets: token patterns found by gitleaks (built-in patterns without
+# it), .env files among the changes and the .env values found while
+# gathering. Prints one "<rule> in <where>" line per finding and returns 1
+# when there are any. Secrets are replaced with [REDACTED] in dir/log so it
+# can be archived. The output comes from the instance and is not trusted.
+audit_secrets() {
+  local dir="$1"
+  local work
+  work=$(mktemp -d)
+  mkdir -p "$work/scan"
+  touch "$dir/log" "$dir/changes" "$dir/files" "$dir/env-findings"
+  cp "$dir/log" "$work/scan/log"
+  cp "$dir/changes" "$work/scan/changes"
+
+  # One "rule<TAB>where<TAB>secret" line per finding
+  local found="$work/found.tsv"
+  : > "$found"
+  local file
+  if command -v gitleaks >/dev/null 2>&1; then
+    gitleaks detect --no-git --no-banner --exit-code 0 \
+      --source "$work/scan" --report-format json --report-path "$work/report.json" >/dev/null 2>&1 || true
+    jq -r '.[] | [.RuleID, (.File | split("/") | last), .Secret] | @tsv' "$work/report.json" >> "$found" 2>/dev/null || true
+  else
+    for file in log changes; do
+      grep -oE "$SECRET_PATTERNS" "$work/scan/$file" | sort -u | while IFS= read -r secret; do
+        printf 'token-pattern\t%s\t%s\n' "$file" "$secret"
+      done >> "$found" || true
+    done
+  fi
+
+  # .env files must not be committed; their examples may. Names are
+  # reported, so only plain path characters are kept.
+  local path
+  while IFS= read -r path; do
+    case "${path##*/}" in
+      .env.example|.env.sample|.env.template) ;;
+      .env|.env.*) printf 'env-file\t%s\t\n' "$(printf '%s' "$path" | tr -cd '[:alnum:] ._/@+-')" >> "$found" ;;
+    esac
+  done < "$dir/files"
+
+  # The values themselves were redacted by the instance; lines not in its
+  # format are dropped
+  grep -E $'^env-value:[A-Za-z_][A-Za-z0-9_]*\t(log|changes)$' "$dir/env-findings" \
+    | sed $'s/$/\t/' >> "$found" || true
+
+  local count=0 rule where secret content
+  content=$(cat "$dir/log")
+  while IFS=$'\t' read -r rule where secret; do
+    count=$((count + 1))
+    case "$where" in
+      log) where="instance log" ;;
+      changes) where="changes" ;;
+    esac
+    echo "$rule in $where"
+    if [ -n "$secret" ]; then
+      content="${content//"$secret"/[REDACTED]}"
+    fi
+  done < <(sort -u "$found")
+  if [ "$count" -gt 0 ]; then
+    printf '%s\n' "$content" > "$dir/log"
+  fi
+  rm -rf "$work"
+  [ "$count" -eq 0 ]
 }
 
 # Read other instances' status
 get_other_instances_status() {
   # Fetch all comments
-  gh api "/repos/${GITHUB_REPOSITORY}/issues/${ISSUE_NUMBER}/comments" \
-    --jq '.[] | select(.body | contains("INSTANCE_STATUS")) | .body' \
+  list_comments \
+    | jq -r '.[] | select(.body | contains("INSTANCE_STATUS")) | .body' \
     | grep -A 20 "INSTANCE_STATUS:START" \
     | grep -v "INSTANCE_STATUS:START:$INSTANCE_ID" \
     | sed -n '/```json/,/```/p' \
@@ -138,10 +909,54 @@ if [ "$ROLE" = "leader" ]; then
 fi
 
 # Export functions
+export -f publish_comment
+export -f join_chunks
+export -f trusted_comments
+export -f list_comments
+export -f fetch_comments
+export -f list_steering_comments
+export -f get_task
+export -f status_preview
 export -f report_status
+export -f tool_version
+export -f report_capabilities
+export -f apply_env_lock
+export -f log_level_rank
+export -f log_event
+export -f task_reassigned
+export -f task_cancelled
+export -f replanned_task
 export -f get_other_instances_status
 export -f check_instance_health
+export -f post_block
+export -f post_assignment
+export -f post_result
+export -f post_result_report
+export -f post_limit_reached
+export -f provider_limit_args
+export -f get_blocks
+export -f get_assignment
+export -f post_decision
+export -f post_vote
+export -f link_marker
+export -f link_breadcrumb
+export -f open_pull_request
+export -f collect_audit_inputs
+export -f audit_secrets
+export -f label_fork_pull_request
+export -f instance_branch
+export -f run_control
+export -f run_paused
+export -f checkpoint_work
+export -f restore_checkpoint
+export -f wait_while_paused
 
 # Example usage in workflow:
 # source ./instance-status-reporter.sh
+# report_capabilities
+# log_event error "build" "go build failed"
 # report_status "in_progress" "task-1" "Implement feature X" 50 "$(tail -100 /tmp/work.log)"
+# post_assignment 2 "task-2" "Write API tests" "test-specialist"
+# post_result "task-2" "completed" "Added 12 tests" "$(instance_branch)"
+# post_decision "db-choice" "Which database?" 3 "postgres" "sqlite"
+# post_vote "db-choice" "postgres"